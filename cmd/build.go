@@ -166,6 +166,7 @@ func setupBuildSingleTarget(ctx *context.Context) {
 		goarch = runtime.GOARCH
 	}
 	log.WithField("reason", "single target is enabled").Warnf("building only for %s/%s", goos, goarch)
+	ctx.PartialBuild = true
 	if len(ctx.Config.Builds) == 0 {
 		ctx.Config.Builds = append(ctx.Config.Builds, config.Build{})
 	}
@@ -201,6 +202,7 @@ func setupBuildID(ctx *context.Context, ids []string) error {
 	}
 
 	ctx.Config.Builds = keep
+	ctx.PartialBuild = true
 	return nil
 }
 
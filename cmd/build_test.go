@@ -300,6 +300,7 @@ func TestBuildSingleTargetWithSpecificTargets(t *testing.T) {
 		Goos:   []string{"linux"},
 		Goarch: []string{"amd64"},
 	}, ctx.Config.Builds[0])
+	require.True(t, ctx.PartialBuild)
 }
 
 func TestBuildSingleTargetRemoveOtherOptions(t *testing.T) {
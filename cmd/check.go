@@ -7,6 +7,7 @@ import (
 	"github.com/caarlos0/ctrlc"
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/pipe/defaults"
+	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/spf13/cobra"
 )
@@ -41,6 +42,9 @@ func newCheckCmd() *checkCmd {
 
 			if err := ctrlc.Default.Run(ctx, func() error {
 				log.Info(boldStyle.Render("checking config..."))
+				if err := config.Validate(cfg); err != nil {
+					return err
+				}
 				return defaults.Pipe{}.Run(ctx)
 			}); err != nil {
 				log.WithError(err).Error(boldStyle.Render("config is invalid"))
@@ -30,6 +30,12 @@ func TestCheckConfigInvalid(t *testing.T) {
 	require.EqualError(t, cmd.cmd.Execute(), "invalid config: found 2 builds with the ID 'a', please fix your config")
 }
 
+func TestCheckConfigInvalidEnum(t *testing.T) {
+	cmd := newCheckCmd()
+	cmd.cmd.SetArgs([]string{"-f", "testdata/invalid_enum.yml"})
+	require.EqualError(t, cmd.cmd.Execute(), "invalid config: invalid configuration:\nchangelog.use: invalid value \"bitbucket\", must be one of: git, github, github-native, gitlab")
+}
+
 func TestCheckConfigInvalidQuiet(t *testing.T) {
 	cmd := newCheckCmd()
 	cmd.cmd.SetArgs([]string{"-f", "testdata/invalid.yml", "-q"})
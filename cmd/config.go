@@ -10,6 +10,17 @@ import (
 )
 
 func loadConfig(path string) (config.Project, error) {
+	proj, err := loadConfigFile(path)
+	if err != nil {
+		return proj, err
+	}
+	if err := config.ApplyEnvOverrides(&proj, os.Environ()); err != nil {
+		return proj, err
+	}
+	return proj, nil
+}
+
+func loadConfigFile(path string) (config.Project, error) {
 	if path == "-" {
 		log.Info("loading config from stdin")
 		return config.LoadReader(os.Stdin)
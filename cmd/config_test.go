@@ -46,3 +46,20 @@ func TestConfigFileFromStdin(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, config.Project{}, proj)
 }
+
+func TestConfigEnvOverrides(t *testing.T) {
+	setup(t)
+	t.Setenv("GORELEASER_RELEASE_DRAFT", "true")
+	t.Setenv("GORELEASER_DIST", "out")
+	proj, err := loadConfig("")
+	require.NoError(t, err)
+	require.True(t, proj.Release.Draft)
+	require.Equal(t, "out", proj.Dist)
+}
+
+func TestConfigEnvOverridesInvalid(t *testing.T) {
+	setup(t)
+	t.Setenv("GORELEASER_RELEASE_DRAFT", "nope")
+	_, err := loadConfig("")
+	require.ErrorContains(t, err, "invalid value for GORELEASER_RELEASE_DRAFT")
+}
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"errors"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/caarlos0/ctrlc"
@@ -35,10 +37,13 @@ type releaseOpts struct {
 	skipSign           bool
 	skipValidate       bool
 	skipAnnounce       bool
+	announceDryRun     bool
 	skipSBOMCataloging bool
 	skipDocker         bool
 	skipKo             bool
 	skipBefore         bool
+	only               []string
+	publishExisting    bool
 	clean              bool
 	rmDist             bool // deprecated
 	deprecated         bool
@@ -77,12 +82,15 @@ func newReleaseCmd() *releaseCmd {
 	cmd.Flags().BoolVar(&root.opts.snapshot, "snapshot", false, "Generate an unversioned snapshot release, skipping all validations and without publishing any artifacts (implies --skip-publish, --skip-announce and --skip-validate)")
 	cmd.Flags().BoolVar(&root.opts.skipPublish, "skip-publish", false, "Skips publishing artifacts (implies --skip-announce)")
 	cmd.Flags().BoolVar(&root.opts.skipAnnounce, "skip-announce", false, "Skips announcing releases (implies --skip-validate)")
+	cmd.Flags().BoolVar(&root.opts.announceDryRun, "announce-dry-run", false, "Renders announce messages and logs them instead of sending them (overrides the --skip-announce implied by --snapshot and --skip-publish)")
 	cmd.Flags().BoolVar(&root.opts.skipSign, "skip-sign", false, "Skips signing artifacts")
 	cmd.Flags().BoolVar(&root.opts.skipSBOMCataloging, "skip-sbom", false, "Skips cataloging artifacts")
 	cmd.Flags().BoolVar(&root.opts.skipDocker, "skip-docker", false, "Skips Docker Images/Manifests builds")
 	cmd.Flags().BoolVar(&root.opts.skipKo, "skip-ko", false, "Skips Ko builds")
 	cmd.Flags().BoolVar(&root.opts.skipBefore, "skip-before", false, "Skips global before hooks")
 	cmd.Flags().BoolVar(&root.opts.skipValidate, "skip-validate", false, "Skips git checks")
+	cmd.Flags().StringSliceVar(&root.opts.only, "only", nil, "Only run the pipes with the given ids, skipping all others (valid ids: "+strings.Join(pipeline.IDs(), ", ")+")")
+	cmd.Flags().BoolVar(&root.opts.publishExisting, "publish-existing", false, "Publishes the draft release created by a previous run, using the release id from the metadata.json file in the dist folder")
 	cmd.Flags().BoolVar(&root.opts.clean, "clean", false, "Removes the dist folder")
 	cmd.Flags().BoolVar(&root.opts.rmDist, "rm-dist", false, "Removes the dist folder")
 	cmd.Flags().IntVarP(&root.opts.parallelism, "parallelism", "p", 0, "Amount tasks to run concurrently (default: number of CPUs)")
@@ -105,8 +113,12 @@ func releaseProject(options releaseOpts) (*context.Context, error) {
 	ctx, cancel := context.NewWithTimeout(cfg, options.timeout)
 	defer cancel()
 	setupReleaseContext(ctx, options)
+	pipes, err := releasePipeline(options)
+	if err != nil {
+		return ctx, err
+	}
 	return ctx, ctrlc.Default.Run(ctx, func() error {
-		for _, pipe := range pipeline.Pipeline {
+		for _, pipe := range pipes {
 			if err := skip.Maybe(
 				pipe,
 				logging.Log(
@@ -121,6 +133,16 @@ func releaseProject(options releaseOpts) (*context.Context, error) {
 	})
 }
 
+func releasePipeline(options releaseOpts) ([]pipeline.Piper, error) {
+	if !options.publishExisting {
+		return pipeline.Only(pipeline.Pipeline, options.only)
+	}
+	if len(options.only) > 0 {
+		return nil, errors.New("--only can't be used together with --publish-existing")
+	}
+	return pipeline.PublishDraftPipeline, nil
+}
+
 func setupReleaseContext(ctx *context.Context, options releaseOpts) {
 	ctx.Deprecated = options.deprecated // test only
 	ctx.Parallelism = runtime.NumCPU()
@@ -140,13 +162,15 @@ func setupReleaseContext(ctx *context.Context, options releaseOpts) {
 		ctx.Snapshot = true
 	}
 	ctx.SkipPublish = ctx.Snapshot || options.skipPublish
-	ctx.SkipAnnounce = ctx.Snapshot || options.skipPublish || options.skipAnnounce
+	ctx.AnnounceDryRun = options.announceDryRun
+	ctx.SkipAnnounce = options.skipAnnounce || (!ctx.AnnounceDryRun && (ctx.Snapshot || options.skipPublish))
 	ctx.SkipValidate = ctx.Snapshot || options.skipValidate
 	ctx.SkipSign = options.skipSign
 	ctx.SkipSBOMCataloging = options.skipSBOMCataloging
 	ctx.SkipDocker = options.skipDocker
 	ctx.SkipKo = options.skipKo
 	ctx.SkipBefore = options.skipBefore
+	ctx.Only = options.only
 	ctx.Clean = options.clean || options.rmDist
 
 	if options.rmDist {
@@ -53,6 +53,30 @@ func TestReleaseBrokenProject(t *testing.T) {
 	require.EqualError(t, cmd.cmd.Execute(), "failed to parse dir: .: main.go:1:1: expected 'package', found not")
 }
 
+func TestReleaseInvalidOnly(t *testing.T) {
+	setup(t)
+	cmd := newReleaseCmd()
+	cmd.cmd.SetArgs([]string{"--snapshot", "--only=nope"})
+	require.ErrorContains(t, cmd.cmd.Execute(), `invalid pipe id "nope"`)
+}
+
+func TestReleasePublishExisting(t *testing.T) {
+	t.Run("no metadata", func(t *testing.T) {
+		setup(t)
+		t.Setenv("GITHUB_TOKEN", "fake")
+		cmd := newReleaseCmd()
+		cmd.cmd.SetArgs([]string{"--publish-existing"})
+		require.ErrorContains(t, cmd.cmd.Execute(), "failed to read metadata")
+	})
+
+	t.Run("with only", func(t *testing.T) {
+		setup(t)
+		cmd := newReleaseCmd()
+		cmd.cmd.SetArgs([]string{"--publish-existing", "--only=build"})
+		require.EqualError(t, cmd.cmd.Execute(), "--only can't be used together with --publish-existing")
+	})
+}
+
 func TestReleaseFlags(t *testing.T) {
 	setup := func(tb testing.TB, opts releaseOpts) *context.Context {
 		tb.Helper()
@@ -83,6 +107,31 @@ func TestReleaseFlags(t *testing.T) {
 		require.True(t, ctx.SkipAnnounce)
 	})
 
+	t.Run("announce dry-run", func(t *testing.T) {
+		ctx := setup(t, releaseOpts{
+			skipPublish:    true,
+			announceDryRun: true,
+		})
+		require.True(t, ctx.SkipPublish)
+		require.True(t, ctx.AnnounceDryRun)
+		require.False(t, ctx.SkipAnnounce)
+	})
+
+	t.Run("announce dry-run with skip announce", func(t *testing.T) {
+		ctx := setup(t, releaseOpts{
+			skipAnnounce:   true,
+			announceDryRun: true,
+		})
+		require.True(t, ctx.SkipAnnounce)
+	})
+
+	t.Run("only", func(t *testing.T) {
+		ctx := setup(t, releaseOpts{
+			only: []string{"build", "docker"},
+		})
+		require.Equal(t, []string{"build", "docker"}, ctx.Only)
+	})
+
 	t.Run("parallelism", func(t *testing.T) {
 		require.Equal(t, 1, setup(t, releaseOpts{
 			parallelism: 1,
@@ -20,6 +20,15 @@ import (
 func Eval(template *tmpl.Template, rlcp bool, files []config.File) ([]config.File, error) {
 	var result []config.File
 	for _, f := range files {
+		include, err := shouldInclude(template, f)
+		if err != nil {
+			return result, err
+		}
+		if !include {
+			log.WithField("src", f.Source).Debug("skipping file: if evaluated to false")
+			continue
+		}
+
 		replaced, err := template.Apply(f.Source)
 		if err != nil {
 			return result, fmt.Errorf("failed to apply template %s: %w", f.Source, err)
@@ -30,23 +39,14 @@ func Eval(template *tmpl.Template, rlcp bool, files []config.File) ([]config.Fil
 			return result, fmt.Errorf("globbing failed for pattern %s: %w", replaced, err)
 		}
 
-		f.Info.Owner, err = template.Apply(f.Info.Owner)
+		excluded, err := evalExcludes(template, f.Exclude)
 		if err != nil {
-			return result, fmt.Errorf("failed to apply template %s: %w", f.Info.Owner, err)
+			return result, err
 		}
-		f.Info.Group, err = template.Apply(f.Info.Group)
-		if err != nil {
-			return result, fmt.Errorf("failed to apply template %s: %w", f.Info.Group, err)
-		}
-		f.Info.MTime, err = template.Apply(f.Info.MTime)
+
+		f.Info, err = EvalInfo(template, f.Info)
 		if err != nil {
-			return result, fmt.Errorf("failed to apply template %s: %w", f.Info.MTime, err)
-		}
-		if f.Info.MTime != "" {
-			f.Info.ParsedMTime, err = time.Parse(time.RFC3339Nano, f.Info.MTime)
-			if err != nil {
-				return result, fmt.Errorf("failed to parse %s: %w", f.Info.MTime, err)
-			}
+			return result, err
 		}
 
 		// the prefix may not be a complete path or may use glob patterns, in that case use the parent directory
@@ -56,6 +56,10 @@ func Eval(template *tmpl.Template, rlcp bool, files []config.File) ([]config.Fil
 		}
 
 		for _, file := range files {
+			if _, ok := excluded[file]; ok {
+				log.WithField("src", file).Debug("skipping file: excluded")
+				continue
+			}
 			dst, err := destinationFor(f, prefix, file, rlcp)
 			if err != nil {
 				return nil, err
@@ -75,6 +79,68 @@ func Eval(template *tmpl.Template, rlcp bool, files []config.File) ([]config.Fil
 	return unique(result), nil
 }
 
+// EvalInfo applies the templates of the given file info, parsing its mtime.
+func EvalInfo(template *tmpl.Template, info config.FileInfo) (config.FileInfo, error) {
+	var err error
+	info.Owner, err = template.Apply(info.Owner)
+	if err != nil {
+		return info, fmt.Errorf("failed to apply template %s: %w", info.Owner, err)
+	}
+	info.Group, err = template.Apply(info.Group)
+	if err != nil {
+		return info, fmt.Errorf("failed to apply template %s: %w", info.Group, err)
+	}
+	info.MTime, err = template.Apply(info.MTime)
+	if err != nil {
+		return info, fmt.Errorf("failed to apply template %s: %w", info.MTime, err)
+	}
+	if info.MTime != "" {
+		info.ParsedMTime, err = time.Parse(time.RFC3339Nano, info.MTime)
+		if err != nil {
+			return info, fmt.Errorf("failed to parse %s: %w", info.MTime, err)
+		}
+	}
+	return info, nil
+}
+
+// shouldInclude evaluates the file's `if` template, if any.
+// Only a "true" result means the file should be included.
+func shouldInclude(template *tmpl.Template, f config.File) (bool, error) {
+	if f.If == "" {
+		return true, nil
+	}
+	include, err := template.Bool(f.If)
+	if err != nil {
+		return false, fmt.Errorf("failed to apply template %s: %w", f.If, err)
+	}
+	return include, nil
+}
+
+// evalExcludes globs all the given exclude patterns and returns the set of
+// files matched by any of them.
+func evalExcludes(template *tmpl.Template, patterns []string) (map[string]struct{}, error) {
+	result := map[string]struct{}{}
+	for _, pattern := range patterns {
+		replaced, err := template.Apply(pattern)
+		if err != nil {
+			return result, fmt.Errorf("failed to apply template %s: %w", pattern, err)
+		}
+
+		files, err := fileglob.Glob(replaced)
+		if errors.Is(err, fs.ErrNotExist) {
+			// nothing to exclude
+			continue
+		}
+		if err != nil {
+			return result, fmt.Errorf("globbing failed for exclude pattern %s: %w", replaced, err)
+		}
+		for _, file := range files {
+			result[file] = struct{}{}
+		}
+	}
+	return result, nil
+}
+
 // remove duplicates
 func unique(in []config.File) []config.File {
 	var result []config.File
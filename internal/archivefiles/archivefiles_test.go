@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/config"
@@ -14,7 +15,7 @@ import (
 func TestEval(t *testing.T) {
 	now := time.Now().Truncate(time.Second)
 	ctx := context.New(config.Project{
-		Env: []string{"OWNER=carlos", "FOLDER=d"},
+		Env: []string{"OWNER=carlos", "FOLDER=d", "EXCLUDE=guide"},
 	})
 	ctx.Git.CommitDate = now
 	tmpl := tmpl.New(ctx)
@@ -55,6 +56,114 @@ func TestEval(t *testing.T) {
 		testlib.RequireTemplateError(t, err)
 	})
 
+	t.Run("conditional by os", func(t *testing.T) {
+		files := []config.File{
+			{
+				Source:      "./testdata/a/a.txt",
+				Destination: "always",
+				StripParent: true,
+			},
+			{
+				Source:      "./testdata/a/b/a.txt",
+				Destination: "windows",
+				StripParent: true,
+				If:          `{{ eq .Os "windows" }}`,
+			},
+			{
+				Source:      "./testdata/a/b/c/d.txt",
+				Destination: "never",
+				StripParent: true,
+				If:          `{{ if eq .Os "windows" }}yes{{ end }}`,
+			},
+		}
+
+		linux, err := Eval(tmpl.WithArtifact(&artifact.Artifact{Goos: "linux"}), false, files)
+		require.NoError(t, err)
+		require.Equal(t, []config.File{
+			{
+				Source:      "testdata/a/a.txt",
+				Destination: "always/a.txt",
+			},
+		}, linux)
+
+		windows, err := Eval(tmpl.WithArtifact(&artifact.Artifact{Goos: "windows"}), false, files)
+		require.NoError(t, err)
+		require.Equal(t, []config.File{
+			{
+				Source:      "testdata/a/a.txt",
+				Destination: "always/a.txt",
+			},
+			{
+				Source:      "testdata/a/b/a.txt",
+				Destination: "windows/a.txt",
+			},
+		}, windows)
+	})
+
+	t.Run("conditional template error", func(t *testing.T) {
+		_, err := Eval(tmpl, false, []config.File{
+			{
+				Source: "./testdata/a/a.txt",
+				If:     "{{ .Env.NOPE }}",
+			},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+
+	t.Run("exclude", func(t *testing.T) {
+		result, err := Eval(tmpl, true, []config.File{
+			{
+				Source:      "./testdata/docs/**/*",
+				Destination: "docs",
+				Exclude:     []string{"./testdata/docs/**/*.tmp"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []config.File{
+			{Source: "testdata/docs/guide/start.md", Destination: "docs/guide/start.md"},
+			{Source: "testdata/docs/index.md", Destination: "docs/index.md"},
+		}, result)
+	})
+
+	t.Run("exclude directory", func(t *testing.T) {
+		result, err := Eval(tmpl, true, []config.File{
+			{
+				Source:      "./testdata/docs",
+				Destination: "docs",
+				Exclude:     []string{"./testdata/docs/{{ .Env.EXCLUDE }}"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []config.File{
+			{Source: "testdata/docs/index.md", Destination: "docs/index.md"},
+			{Source: "testdata/docs/index.tmp", Destination: "docs/index.tmp"},
+		}, result)
+	})
+
+	t.Run("exclude nothing matches", func(t *testing.T) {
+		result, err := Eval(tmpl, true, []config.File{
+			{
+				Source:      "./testdata/docs/*.md",
+				Destination: "docs",
+				Exclude:     []string{"./testdata/docs/nope.md"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []config.File{
+			{Source: "testdata/docs/index.md", Destination: "docs/index.md"},
+		}, result)
+	})
+
+	t.Run("exclude template error", func(t *testing.T) {
+		_, err := Eval(tmpl, true, []config.File{
+			{
+				Source:  "./testdata/docs/**/*",
+				Exclude: []string{"{{ .Env.NOPE }}"},
+			},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+
 	t.Run("templated info", func(t *testing.T) {
 		result, err := Eval(tmpl, false, []config.File{
 			{
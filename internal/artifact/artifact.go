@@ -18,6 +18,7 @@ import (
 	"sync"
 
 	"github.com/caarlos0/log"
+	"lukechampine.com/blake3"
 )
 
 // Type defines the type of an artifact.
@@ -74,6 +75,14 @@ const (
 	CArchive
 	// CShared is a C shared library, generated via a CGo build with buildmode=c-shared.
 	CShared
+	// BrewCask is an uploadable homebrew cask file.
+	BrewCask
+	// WingetManifest is an uploadable winget manifest file.
+	WingetManifest
+	// Nixpkg is an uploadable nix derivation or flake file.
+	Nixpkg
+	// Attestation is an attestation file, e.g. an in-toto provenance.
+	Attestation
 )
 
 func (t Type) String() string {
@@ -104,10 +113,18 @@ func (t Type) String() string {
 		return "Source"
 	case BrewTap:
 		return "Brew Tap"
+	case BrewCask:
+		return "Brew Cask"
 	case KrewPluginManifest:
 		return "Krew Plugin Manifest"
 	case ScoopManifest:
 		return "Scoop Manifest"
+	case WingetManifest:
+		return "Winget Manifest"
+	case Nixpkg:
+		return "Nixpkg"
+	case Attestation:
+		return "Attestation"
 	case SBOM:
 		return "SBOM"
 	case PkgBuild:
@@ -128,16 +145,19 @@ func (t Type) String() string {
 }
 
 const (
-	ExtraID        = "ID"
-	ExtraBinary    = "Binary"
-	ExtraExt       = "Ext"
-	ExtraBuilds    = "Builds"
-	ExtraFormat    = "Format"
-	ExtraWrappedIn = "WrappedIn"
-	ExtraBinaries  = "Binaries"
-	ExtraRefresh   = "Refresh"
-	ExtraReplaces  = "Replaces"
-	ExtraDigest    = "Digest"
+	ExtraID         = "ID"
+	ExtraBinary     = "Binary"
+	ExtraExt        = "Ext"
+	ExtraBuilds     = "Builds"
+	ExtraFormat     = "Format"
+	ExtraWrappedIn  = "WrappedIn"
+	ExtraBinaries   = "Binaries"
+	ExtraRefresh    = "Refresh"
+	ExtraReplaces   = "Replaces"
+	ExtraDigest     = "Digest"
+	ExtraSkipUpload = "SkipUpload"
+	ExtraChecksum   = "Checksum"
+	ExtraURL        = "URL"
 )
 
 // Extras represents the extra fields in an artifact.
@@ -220,30 +240,45 @@ func (a Artifact) Checksum(algorithm string) (string, error) {
 		return "", fmt.Errorf("failed to checksum: %w", err)
 	}
 	defer file.Close()
-	var h hash.Hash
+	h, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(h, file); err != nil {
+		return "", fmt.Errorf("failed to checksum: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ValidateChecksumAlgorithm returns an error if the given algorithm is not
+// supported by Checksum.
+func ValidateChecksumAlgorithm(algorithm string) error {
+	_, err := newHash(algorithm)
+	return err
+}
+
+func newHash(algorithm string) (hash.Hash, error) {
 	switch algorithm {
 	case "crc32":
-		h = crc32.NewIEEE()
+		return crc32.NewIEEE(), nil
 	case "md5":
-		h = md5.New()
+		return md5.New(), nil
 	case "sha224":
-		h = sha256.New224()
+		return sha256.New224(), nil
 	case "sha384":
-		h = sha512.New384()
+		return sha512.New384(), nil
 	case "sha256":
-		h = sha256.New()
+		return sha256.New(), nil
 	case "sha1":
-		h = sha1.New()
+		return sha1.New(), nil
 	case "sha512":
-		h = sha512.New()
+		return sha512.New(), nil
+	case "blake3":
+		return blake3.New(32, nil), nil
 	default:
-		return "", fmt.Errorf("invalid algorithm: %s", algorithm)
+		return nil, fmt.Errorf("invalid algorithm: %s", algorithm)
 	}
-
-	if _, err := io.Copy(h, file); err != nil {
-		return "", fmt.Errorf("failed to checksum: %w", err)
-	}
-	return hex.EncodeToString(h.Sum(nil)), nil
 }
 
 var noRefresh = func() error { return nil }
@@ -364,6 +399,12 @@ func OnlyReplacingUnibins(a *Artifact) bool {
 	return ExtraOr(*a, ExtraReplaces, true)
 }
 
+// OnlyUploadable removes artifacts that were explicitly marked as not to be
+// uploaded.
+func OnlyUploadable(a *Artifact) bool {
+	return !ExtraOr(*a, ExtraSkipUpload, false)
+}
+
 // ByGoos is a predefined filter that filters by the given goos.
 func ByGoos(s string) Filter {
 	return func(a *Artifact) bool {
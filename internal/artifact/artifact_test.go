@@ -48,6 +48,19 @@ func TestAdd(t *testing.T) {
 	require.Len(t, artifacts.List(), 4)
 }
 
+func TestOnlyUploadable(t *testing.T) {
+	artifacts := New()
+	artifacts.Add(&Artifact{Name: "foo", Type: Attestation})
+	artifacts.Add(&Artifact{Name: "bar", Type: Attestation, Extra: map[string]interface{}{ExtraSkipUpload: false}})
+	artifacts.Add(&Artifact{Name: "baz", Type: Attestation, Extra: map[string]interface{}{ExtraSkipUpload: true}})
+
+	var names []string
+	for _, a := range artifacts.Filter(OnlyUploadable).List() {
+		names = append(names, a.Name)
+	}
+	require.ElementsMatch(t, []string{"foo", "bar"}, names)
+}
+
 func TestFilter(t *testing.T) {
 	data := []*Artifact{
 		{
@@ -329,6 +342,7 @@ func TestChecksum(t *testing.T) {
 		"md5":    "80a751fde577028640c419000e33eba6",
 		"sha224": "e191edf06005712583518ced92cc2ac2fac8d6e4623b021a50736a91",
 		"sha384": "597493a6cf1289757524e54dfd6f68b332c7214a716a3358911ef5c09907adc8a654a18c1d721e183b0025f996f6e246",
+		"blake3": "25352ae068b54a93ef60942c085da23a321a7f7985193910221cd4fffff5ffb6",
 	} {
 		t.Run(algo, func(t *testing.T) {
 			sum, err := artifact.Checksum(algo)
@@ -360,6 +374,13 @@ func TestInvalidAlgorithm(t *testing.T) {
 	require.Empty(t, sum)
 }
 
+func TestValidateChecksumAlgorithm(t *testing.T) {
+	for _, algo := range []string{"crc32", "md5", "sha224", "sha384", "sha256", "sha1", "sha512", "blake3"} {
+		require.NoError(t, ValidateChecksumAlgorithm(algo), algo)
+	}
+	require.EqualError(t, ValidateChecksumAlgorithm("blake2"), `invalid algorithm: blake2`)
+}
+
 func TestExtra(t *testing.T) {
 	a := Artifact{
 		Extra: map[string]interface{}{
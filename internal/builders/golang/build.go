@@ -48,6 +48,20 @@ func (*Builder) WithDefaults(build config.Build) (config.Build, error) {
 	if build.Main == "" {
 		build.Main = "."
 	}
+	if build.VersionInfo.Enabled {
+		if build.VersionInfo.Binary == "" {
+			build.VersionInfo.Binary = "goversioninfo"
+		}
+		if build.VersionInfo.ProductName == "" {
+			build.VersionInfo.ProductName = "{{ .ProjectName }}"
+		}
+		if build.VersionInfo.FileVersion == "" {
+			build.VersionInfo.FileVersion = "{{ .Version }}"
+		}
+		if build.VersionInfo.ProductVersion == "" {
+			build.VersionInfo.ProductVersion = "{{ .Version }}"
+		}
+	}
 	if len(build.Ldflags) == 0 {
 		build.Ldflags = []string{"-s -w -X main.version={{.Version}} -X main.commit={{.Commit}} -X main.date={{.Date}} -X main.builtBy=goreleaser"}
 	}
@@ -192,6 +206,14 @@ func (*Builder) Build(ctx *context.Context, build config.Build, options api.Opti
 		"GOAMD64="+options.Goamd64,
 	)
 
+	cacheDir, err := buildCacheDir(ctx, build)
+	if err != nil {
+		return err
+	}
+	if cacheDir != "" {
+		env = append(env, "GOCACHE="+cacheDir)
+	}
+
 	if len(testEnvs) > 0 {
 		a.Extra["testEnvs"] = testEnvs
 	}
@@ -201,6 +223,12 @@ func (*Builder) Build(ctx *context.Context, build config.Build, options api.Opti
 		return err
 	}
 
+	cleanup, err := embedVersionInfo(ctx, build, options, a)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
 	if err := run(ctx, cmd, env, build.Dir); err != nil {
 		return fmt.Errorf("failed to build for %s: %w", options.Target, err)
 	}
@@ -225,6 +253,31 @@ func (*Builder) Build(ctx *context.Context, build config.Build, options api.Opti
 	return nil
 }
 
+// buildCacheDir returns the absolute path of the build cache directory to use
+// for all the targets of the given build, creating it if needed.
+// It returns an empty string if no build cache dir is set.
+func buildCacheDir(ctx *context.Context, build config.Build) (string, error) {
+	if build.BuildCacheDir == "" {
+		return "", nil
+	}
+	dir, err := tmpl.New(ctx).Apply(build.BuildCacheDir)
+	if err != nil {
+		return "", err
+	}
+	if dir == "" {
+		return "", nil
+	}
+	// go requires GOCACHE to be an absolute path.
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("invalid build cache dir: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create build cache dir: %w", err)
+	}
+	return dir, nil
+}
+
 func withOverrides(ctx *context.Context, build config.Build, options api.Options) (config.BuildDetails, error) {
 	optsTarget := options.Goos + options.Goarch + options.Goarm + options.Gomips + options.Goamd64
 	for _, o := range build.BuildDetailsOverrides {
@@ -261,19 +314,19 @@ func buildGoBuildLine(ctx *context.Context, build config.Build, details config.B
 	// tags, ldflags, and buildmode, should only appear once, warning only to avoid a breaking change
 	validateUniqueFlags(details)
 
-	flags, err := processFlags(ctx, artifact, env, details.Flags, "")
+	flags, err := processFlags(ctx, artifact, options, env, details.Flags, "")
 	if err != nil {
 		return cmd, err
 	}
 	cmd = append(cmd, flags...)
 
-	asmflags, err := processFlags(ctx, artifact, env, details.Asmflags, "-asmflags=")
+	asmflags, err := processFlags(ctx, artifact, options, env, details.Asmflags, "-asmflags=")
 	if err != nil {
 		return cmd, err
 	}
 	cmd = append(cmd, asmflags...)
 
-	gcflags, err := processFlags(ctx, artifact, env, details.Gcflags, "-gcflags=")
+	gcflags, err := processFlags(ctx, artifact, options, env, details.Gcflags, "-gcflags=")
 	if err != nil {
 		return cmd, err
 	}
@@ -281,7 +334,7 @@ func buildGoBuildLine(ctx *context.Context, build config.Build, details config.B
 
 	// tags is not a repeatable flag
 	if len(details.Tags) > 0 {
-		tags, err := processFlags(ctx, artifact, env, details.Tags, "")
+		tags, err := processFlags(ctx, artifact, options, env, details.Tags, "")
 		if err != nil {
 			return cmd, err
 		}
@@ -291,7 +344,7 @@ func buildGoBuildLine(ctx *context.Context, build config.Build, details config.B
 	// ldflags is not a repeatable flag
 	if len(details.Ldflags) > 0 {
 		// flag prefix is skipped because ldflags need to output a single string
-		ldflags, err := processFlags(ctx, artifact, env, details.Ldflags, "")
+		ldflags, err := processFlags(ctx, artifact, options, env, details.Ldflags, "")
 		if err != nil {
 			return cmd, err
 		}
@@ -321,10 +374,10 @@ func validateUniqueFlags(details config.BuildDetails) {
 	}
 }
 
-func processFlags(ctx *context.Context, a *artifact.Artifact, env, flags []string, flagPrefix string) ([]string, error) {
+func processFlags(ctx *context.Context, a *artifact.Artifact, options api.Options, env, flags []string, flagPrefix string) ([]string, error) {
 	processed := make([]string, 0, len(flags))
 	for _, rawFlag := range flags {
-		flag, err := processFlag(ctx, a, env, rawFlag)
+		flag, err := processFlag(ctx, a, options, env, rawFlag)
 		if err != nil {
 			return nil, err
 		}
@@ -333,8 +386,10 @@ func processFlags(ctx *context.Context, a *artifact.Artifact, env, flags []strin
 	return processed, nil
 }
 
-func processFlag(ctx *context.Context, a *artifact.Artifact, env []string, rawFlag string) (string, error) {
-	return tmpl.New(ctx).WithEnvS(env).WithArtifact(a).Apply(rawFlag)
+// processFlag applies the given flag template against the current target,
+// so things like `.Target` and `.Arch` render differently for each target.
+func processFlag(ctx *context.Context, a *artifact.Artifact, options api.Options, env []string, rawFlag string) (string, error) {
+	return tmpl.New(ctx).WithEnvS(env).WithBuildOptions(options).WithArtifact(a).Apply(rawFlag)
 }
 
 func run(ctx *context.Context, command, env []string, dir string) error {
@@ -355,6 +355,7 @@ func TestBuild(t *testing.T) {
 					"linux_amd64",
 					"darwin_amd64",
 					"windows_amd64",
+					"windows_arm64",
 					"linux_arm_6",
 					"js_wasm",
 					"linux_mips_softfloat",
@@ -506,6 +507,19 @@ func TestBuild(t *testing.T) {
 				"testEnvs":           []string{"TEST_T=w"},
 			},
 		},
+		{
+			Name:   "bin/foo-v5.6.7.exe",
+			Path:   filepath.Join(folder, "dist", "windows_arm64", "bin", "foo-v5.6.7.exe"),
+			Goos:   "windows",
+			Goarch: "arm64",
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				artifact.ExtraExt:    ".exe",
+				artifact.ExtraBinary: "foo-v5.6.7",
+				artifact.ExtraID:     "foo",
+				"testEnvs":           []string{"TEST_T=w"},
+			},
+		},
 		{
 			Name:   "bin/foo-v5.6.7.wasm",
 			Path:   filepath.Join(folder, "dist", "js_wasm", "bin", "foo-v5.6.7.wasm"),
@@ -998,6 +1012,7 @@ func TestProcessFlags(t *testing.T) {
 		"{{.Arm}}",
 		"{{.Binary}}",
 		"{{.ArtifactName}}",
+		"{{.Target}}",
 	}
 
 	expected := []string{
@@ -1008,11 +1023,19 @@ func TestProcessFlags(t *testing.T) {
 		"-testflag=7",
 		"-testflag=binary",
 		"-testflag=name",
+		"-testflag=darwin_amd64_v1",
+	}
+
+	options := api.Options{
+		Target:  "darwin_amd64_v1",
+		Goos:    "darwin",
+		Goarch:  "amd64",
+		Goamd64: "v1",
 	}
 
-	flags, err := processFlags(ctx, artifact, []string{}, source, "-testflag=")
+	flags, err := processFlags(ctx, artifact, options, []string{}, source, "-testflag=")
 	require.NoError(t, err)
-	require.Len(t, flags, 7)
+	require.Len(t, flags, 8)
 	require.Equal(t, expected, flags)
 }
 
@@ -1023,11 +1046,78 @@ func TestProcessFlagsInvalid(t *testing.T) {
 		"{{.Version}",
 	}
 
-	flags, err := processFlags(ctx, &artifact.Artifact{}, []string{}, source, "-testflag=")
+	flags, err := processFlags(ctx, &artifact.Artifact{}, api.Options{}, []string{}, source, "-testflag=")
 	testlib.RequireTemplateError(t, err)
 	require.Nil(t, flags)
 }
 
+func TestLdFlagsPerTarget(t *testing.T) {
+	build := config.Build{
+		Main:     ".",
+		Binary:   "foo",
+		GoBinary: "go",
+		Command:  "build",
+		BuildDetails: config.BuildDetails{
+			Ldflags: []string{
+				"{{ if not .IsSnapshot }}-s -w{{ end }}",
+				"-X main.arch={{ .Arch }} -X main.target={{ .Target }}",
+				"{{ with .Arm }}-X main.arm={{ . }}{{ end }}",
+				"{{ with .Mips }}-X main.mips={{ . }}{{ end }}",
+				"{{ with .Amd64 }}-X main.amd64={{ . }}{{ end }}",
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		options  api.Options
+		snapshot bool
+		expected string
+	}{
+		{
+			options:  api.Options{Target: "linux_amd64_v3", Goos: "linux", Goarch: "amd64", Goamd64: "v3"},
+			expected: "-ldflags=-s -w -X main.arch=amd64 -X main.target=linux_amd64_v3   -X main.amd64=v3",
+		},
+		{
+			options:  api.Options{Target: "linux_arm64", Goos: "linux", Goarch: "arm64"},
+			expected: "-ldflags=-s -w -X main.arch=arm64 -X main.target=linux_arm64   ",
+		},
+		{
+			options:  api.Options{Target: "linux_arm_6", Goos: "linux", Goarch: "arm", Goarm: "6"},
+			expected: "-ldflags=-s -w -X main.arch=arm -X main.target=linux_arm_6 -X main.arm=6  ",
+		},
+		{
+			options:  api.Options{Target: "linux_mips_softfloat", Goos: "linux", Goarch: "mips", Gomips: "softfloat"},
+			expected: "-ldflags=-s -w -X main.arch=mips -X main.target=linux_mips_softfloat  -X main.mips=softfloat ",
+		},
+		{
+			options:  api.Options{Target: "linux_arm_7", Goos: "linux", Goarch: "arm", Goarm: "7"},
+			snapshot: true,
+			expected: "-ldflags= -X main.arch=arm -X main.target=linux_arm_7 -X main.arm=7  ",
+		},
+	} {
+		t.Run(tt.options.Target, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Builds: []config.Build{build},
+			})
+			ctx.Snapshot = tt.snapshot
+
+			options := tt.options
+			options.Path = "foo"
+			a := &artifact.Artifact{
+				Goos:    options.Goos,
+				Goarch:  options.Goarch,
+				Goarm:   options.Goarm,
+				Gomips:  options.Gomips,
+				Goamd64: options.Goamd64,
+			}
+
+			line, err := buildGoBuildLine(ctx, build, build.BuildDetails, options, a, []string{})
+			require.NoError(t, err)
+			require.Contains(t, line, tt.expected)
+		})
+	}
+}
+
 func TestBuildModTimestamp(t *testing.T) {
 	// round to seconds since this will be a unix timestamp
 	modTime := time.Now().AddDate(-1, 0, 0).Round(1 * time.Second).UTC()
@@ -1099,6 +1189,117 @@ func TestBuildModTimestamp(t *testing.T) {
 	}
 }
 
+func TestBuildCacheDir(t *testing.T) {
+	targets := []string{
+		"linux_amd64",
+		"linux_arm64",
+		"darwin_amd64",
+		"darwin_arm64",
+		"windows_amd64",
+		"windows_386",
+	}
+
+	t.Run("propagated to every target", func(t *testing.T) {
+		folder := testlib.Mktmp(t)
+		writeGoodMain(t, folder)
+
+		// fake go binary that records the GOCACHE it was called with.
+		logFile := filepath.Join(folder, "gocache.log")
+		gobin := filepath.Join(folder, "fakego")
+		require.NoError(t, os.WriteFile(
+			gobin,
+			[]byte(fmt.Sprintf("#!/bin/sh\necho \"$GOCACHE\" >> %s\n", logFile)),
+			0o755,
+		))
+
+		ctx := context.New(config.Project{
+			Builds: []config.Build{
+				{
+					ID:            "foo",
+					Binary:        "foo",
+					Targets:       targets,
+					GoBinary:      gobin,
+					Command:       "build",
+					BuildCacheDir: "{{ .Env.CACHE_DIR }}",
+				},
+			},
+		})
+		ctx.Env["CACHE_DIR"] = "cache"
+		build := ctx.Config.Builds[0]
+		for _, target := range targets {
+			parts := strings.Split(target, "_")
+			require.NoError(t, Default.Build(ctx, build, api.Options{
+				Target: target,
+				Name:   "foo",
+				Path:   filepath.Join(folder, "dist", target, "foo"),
+				Goos:   parts[0],
+				Goarch: parts[1],
+			}))
+		}
+
+		bts, err := os.ReadFile(logFile)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(bts)), "\n")
+		require.Len(t, lines, len(targets))
+		for _, line := range lines {
+			require.Equal(t, filepath.Join(folder, "cache"), line)
+		}
+		require.DirExists(t, filepath.Join(folder, "cache"))
+	})
+
+	t.Run("warm cache", func(t *testing.T) {
+		folder := testlib.Mktmp(t)
+		writeGoodMain(t, folder)
+		cache := filepath.Join(folder, "cache")
+		ctx := context.New(config.Project{
+			Builds: []config.Build{
+				{
+					ID:            "foo",
+					Binary:        "foo",
+					Targets:       targets[:2],
+					GoBinary:      "go",
+					Command:       "build",
+					BuildCacheDir: cache,
+					BuildDetails: config.BuildDetails{
+						Env: []string{"GO111MODULE=off"},
+					},
+				},
+			},
+		})
+		build := ctx.Config.Builds[0]
+		for _, target := range build.Targets {
+			parts := strings.Split(target, "_")
+			require.NoError(t, Default.Build(ctx, build, api.Options{
+				Target: target,
+				Name:   "foo",
+				Path:   filepath.Join(folder, "dist", target, "foo"),
+				Goos:   parts[0],
+				Goarch: parts[1],
+			}))
+		}
+		entries, err := os.ReadDir(cache)
+		require.NoError(t, err)
+		require.NotEmpty(t, entries, "go should have populated the build cache")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		folder := testlib.Mktmp(t)
+		writeGoodMain(t, folder)
+		ctx := context.New(config.Project{})
+		err := Default.Build(ctx, config.Build{
+			Targets:       targets[:1],
+			GoBinary:      "go",
+			Command:       "build",
+			BuildCacheDir: "{{ .Nope }",
+		}, api.Options{
+			Target: targets[0],
+			Name:   "foo",
+			Path:   filepath.Join(folder, "dist", "foo"),
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
 func TestBuildGoBuildLine(t *testing.T) {
 	requireEqualCmd := func(tb testing.TB, build config.Build, expected []string) {
 		tb.Helper()
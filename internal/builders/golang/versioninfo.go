@@ -0,0 +1,211 @@
+package golang
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	api "github.com/goreleaser/goreleaser/pkg/build"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// versionInfoFile is the goversioninfo JSON configuration file format.
+type versionInfoFile struct {
+	FixedFileInfo  fixedFileInfo  `json:"FixedFileInfo"`
+	StringFileInfo stringFileInfo `json:"StringFileInfo"`
+	VarFileInfo    varFileInfo    `json:"VarFileInfo"`
+	IconPath       string         `json:"IconPath"`
+}
+
+type fixedFileInfo struct {
+	FileVersion    fileVersion `json:"FileVersion"`
+	ProductVersion fileVersion `json:"ProductVersion"`
+	FileFlagsMask  string      `json:"FileFlagsMask"`
+	FileFlags      string      `json:"FileFlags"`
+	FileOS         string      `json:"FileOS"`
+	FileType       string      `json:"FileType"`
+	FileSubType    string      `json:"FileSubType"`
+}
+
+type fileVersion struct {
+	Major int `json:"Major"`
+	Minor int `json:"Minor"`
+	Patch int `json:"Patch"`
+	Build int `json:"Build"`
+}
+
+type stringFileInfo struct {
+	CompanyName      string `json:"CompanyName"`
+	FileDescription  string `json:"FileDescription"`
+	FileVersion      string `json:"FileVersion"`
+	InternalName     string `json:"InternalName"`
+	LegalCopyright   string `json:"LegalCopyright"`
+	OriginalFilename string `json:"OriginalFilename"`
+	ProductName      string `json:"ProductName"`
+	ProductVersion   string `json:"ProductVersion"`
+}
+
+type varFileInfo struct {
+	Translation translation `json:"Translation"`
+}
+
+type translation struct {
+	LangID    string `json:"LangID"`
+	CharsetID string `json:"CharsetID"`
+}
+
+// sysoLocks prevents concurrent builds targeting the same architecture from
+// writing the same .syso file at the same time.
+// nolint: gochecknoglobals
+var sysoLocks sync.Map
+
+// embedVersionInfo generates the windows resource (.syso) file with the
+// version info for the given windows target in the main package directory,
+// so go build links it into the binary.
+// The returned function must be called after go build to remove the
+// generated .syso file.
+func embedVersionInfo(ctx *context.Context, build config.Build, options api.Options, a *artifact.Artifact) (func(), error) {
+	noop := func() {}
+	if !build.VersionInfo.Enabled || options.Goos != "windows" {
+		return noop, nil
+	}
+
+	if build.UnproxiedMain != "" {
+		return noop, fmt.Errorf("versioninfo can't be used together with gomod.proxy")
+	}
+
+	info, err := versionInfoFor(ctx, build.VersionInfo, a)
+	if err != nil {
+		return noop, err
+	}
+
+	// keep the json config next to the binary, for debugging purposes.
+	jsonPath := strings.TrimSuffix(options.Path, options.Ext) + ".versioninfo.json"
+	bts, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return noop, fmt.Errorf("failed to generate versioninfo: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(jsonPath), 0o755); err != nil {
+		return noop, fmt.Errorf("failed to generate versioninfo: %w", err)
+	}
+	if err := os.WriteFile(jsonPath, bts, 0o644); err != nil {
+		return noop, fmt.Errorf("failed to generate versioninfo: %w", err)
+	}
+
+	syso, err := sysoPath(build, options.Goarch)
+	if err != nil {
+		return noop, err
+	}
+	mu, _ := sysoLocks.LoadOrStore(syso, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	cleanup := func() {
+		if err := os.Remove(syso); err != nil && !os.IsNotExist(err) {
+			log.WithError(err).Warnf("failed to remove %s", syso)
+		}
+		mu.(*sync.Mutex).Unlock()
+	}
+
+	cmd := versionInfoCommand(build.VersionInfo.Binary, options.Goarch, jsonPath, syso)
+	if err := run(ctx, cmd, ctx.Env.Strings(), ""); err != nil {
+		cleanup()
+		return noop, fmt.Errorf("failed to generate versioninfo for %s: %w", options.Target, err)
+	}
+	return cleanup, nil
+}
+
+func versionInfoFor(ctx *context.Context, conf config.VersionInfo, a *artifact.Artifact) (versionInfoFile, error) {
+	t := tmpl.New(ctx).WithArtifact(a)
+	fields := []*string{
+		&conf.CompanyName,
+		&conf.ProductName,
+		&conf.FileDescription,
+		&conf.LegalCopyright,
+		&conf.FileVersion,
+		&conf.ProductVersion,
+		&conf.Icon,
+	}
+	for _, field := range fields {
+		s, err := t.Apply(*field)
+		if err != nil {
+			return versionInfoFile{}, err
+		}
+		*field = s
+	}
+
+	if conf.Icon != "" {
+		icon, err := filepath.Abs(conf.Icon)
+		if err != nil {
+			return versionInfoFile{}, fmt.Errorf("invalid versioninfo icon: %w", err)
+		}
+		conf.Icon = icon
+	}
+
+	version := fileVersion{
+		Major: int(ctx.Semver.Major),
+		Minor: int(ctx.Semver.Minor),
+		Patch: int(ctx.Semver.Patch),
+	}
+	return versionInfoFile{
+		FixedFileInfo: fixedFileInfo{
+			FileVersion:    version,
+			ProductVersion: version,
+			FileFlagsMask:  "3f",
+			FileFlags:      "00",
+			FileOS:         "040004",
+			FileType:       "01",
+			FileSubType:    "00",
+		},
+		StringFileInfo: stringFileInfo{
+			CompanyName:      conf.CompanyName,
+			FileDescription:  conf.FileDescription,
+			FileVersion:      conf.FileVersion,
+			InternalName:     artifact.ExtraOr(*a, artifact.ExtraBinary, ""),
+			LegalCopyright:   conf.LegalCopyright,
+			OriginalFilename: filepath.Base(a.Path),
+			ProductName:      conf.ProductName,
+			ProductVersion:   conf.ProductVersion,
+		},
+		VarFileInfo: varFileInfo{
+			Translation: translation{
+				LangID:    "0409",
+				CharsetID: "04B0",
+			},
+		},
+		IconPath: conf.Icon,
+	}, nil
+}
+
+// sysoPath returns the path of the .syso file for the given build and goarch.
+// The file is named after the goarch so go build only links it when building
+// for that target.
+func sysoPath(build config.Build, goarch string) (string, error) {
+	dir := filepath.Join(build.Dir, build.Main)
+	if strings.HasSuffix(dir, ".go") {
+		dir = filepath.Dir(dir)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate versioninfo: %w", err)
+	}
+	return filepath.Join(dir, "goreleaser_windows_"+goarch+".syso"), nil
+}
+
+func versionInfoCommand(binary, goarch, input, output string) []string {
+	cmd := []string{binary}
+	switch goarch {
+	case "amd64":
+		cmd = append(cmd, "-64")
+	case "arm":
+		cmd = append(cmd, "-arm")
+	case "arm64":
+		cmd = append(cmd, "-arm", "-64")
+	}
+	return append(cmd, "-o", output, input)
+}
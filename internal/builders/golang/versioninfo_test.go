@@ -0,0 +1,216 @@
+package golang
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	api "github.com/goreleaser/goreleaser/pkg/build"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionInfoDefaults(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		build, err := Default.WithDefaults(config.Build{})
+		require.NoError(t, err)
+		require.Equal(t, config.VersionInfo{}, build.VersionInfo)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		build, err := Default.WithDefaults(config.Build{
+			VersionInfo: config.VersionInfo{
+				Enabled: true,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, config.VersionInfo{
+			Enabled:        true,
+			Binary:         "goversioninfo",
+			ProductName:    "{{ .ProjectName }}",
+			FileVersion:    "{{ .Version }}",
+			ProductVersion: "{{ .Version }}",
+		}, build.VersionInfo)
+	})
+}
+
+func TestVersionInfoFor(t *testing.T) {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+	})
+	ctx.Version = "1.2.3"
+	ctx.Semver = context.Semver{Major: 1, Minor: 2, Patch: 3}
+	a := &artifact.Artifact{
+		Name:   "foo.exe",
+		Path:   "dist/foo_windows_amd64_v1/foo.exe",
+		Goos:   "windows",
+		Goarch: "amd64",
+		Extra: map[string]interface{}{
+			artifact.ExtraBinary: "foo",
+		},
+	}
+
+	info, err := versionInfoFor(ctx, config.VersionInfo{
+		CompanyName:     "Acme Inc",
+		ProductName:     "{{ .ProjectName }}",
+		FileDescription: "foo for {{ .Arch }}",
+		LegalCopyright:  "Copyright Acme Inc",
+		FileVersion:     "{{ .Version }}",
+		ProductVersion:  "v{{ .Version }}",
+		Icon:            "foo.ico",
+	}, a)
+	require.NoError(t, err)
+
+	icon, err := filepath.Abs("foo.ico")
+	require.NoError(t, err)
+	require.Equal(t, icon, info.IconPath)
+	require.Equal(t, stringFileInfo{
+		CompanyName:      "Acme Inc",
+		FileDescription:  "foo for amd64",
+		FileVersion:      "1.2.3",
+		InternalName:     "foo",
+		LegalCopyright:   "Copyright Acme Inc",
+		OriginalFilename: "foo.exe",
+		ProductName:      "foo",
+		ProductVersion:   "v1.2.3",
+	}, info.StringFileInfo)
+	require.Equal(t, fileVersion{Major: 1, Minor: 2, Patch: 3}, info.FixedFileInfo.FileVersion)
+	require.Equal(t, fileVersion{Major: 1, Minor: 2, Patch: 3}, info.FixedFileInfo.ProductVersion)
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := versionInfoFor(ctx, config.VersionInfo{
+			CompanyName: "{{ .Nope }}",
+		}, a)
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestVersionInfoCommand(t *testing.T) {
+	for goarch, expected := range map[string][]string{
+		"386":   {"goversioninfo", "-o", "out.syso", "in.json"},
+		"amd64": {"goversioninfo", "-64", "-o", "out.syso", "in.json"},
+		"arm":   {"goversioninfo", "-arm", "-o", "out.syso", "in.json"},
+		"arm64": {"goversioninfo", "-arm", "-64", "-o", "out.syso", "in.json"},
+	} {
+		t.Run(goarch, func(t *testing.T) {
+			require.Equal(t, expected, versionInfoCommand("goversioninfo", goarch, "in.json", "out.syso"))
+		})
+	}
+}
+
+func TestSysoPath(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	for name, build := range map[string]config.Build{
+		"dir":       {Dir: ".", Main: "./cmd/foo"},
+		"main file": {Dir: ".", Main: "./cmd/foo/main.go"},
+		"sub dir":   {Dir: "cmd", Main: "foo"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path, err := sysoPath(build, "arm64")
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(wd, "cmd", "foo", "goreleaser_windows_arm64.syso"), path)
+		})
+	}
+}
+
+func TestEmbedVersionInfo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as goversioninfo")
+	}
+	folder := testlib.Mktmp(t)
+	fakeBin := filepath.Join(folder, "fakeversioninfo")
+	require.NoError(t, os.WriteFile(fakeBin, []byte(`#!/bin/sh
+while [ $# -gt 0 ]; do
+	if [ "$1" = "-o" ]; then
+		out="$2"
+	fi
+	shift
+done
+echo "fake resource" > "$out"
+`), 0o755))
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+	})
+	ctx.Version = "1.2.3"
+	build, err := Default.WithDefaults(config.Build{
+		ID:     "foo",
+		Binary: "foo",
+		VersionInfo: config.VersionInfo{
+			Enabled:     true,
+			Binary:      fakeBin,
+			CompanyName: "Acme Inc",
+		},
+	})
+	require.NoError(t, err)
+
+	options := api.Options{
+		Name:   "foo.exe",
+		Path:   filepath.Join(folder, "dist", "foo_windows_arm64", "foo.exe"),
+		Ext:    ".exe",
+		Target: "windows_arm64",
+		Goos:   "windows",
+		Goarch: "arm64",
+	}
+	a := &artifact.Artifact{
+		Name:   options.Name,
+		Path:   options.Path,
+		Goos:   options.Goos,
+		Goarch: options.Goarch,
+		Extra: map[string]interface{}{
+			artifact.ExtraBinary: "foo",
+		},
+	}
+
+	t.Run("windows", func(t *testing.T) {
+		cleanup, err := embedVersionInfo(ctx, build, options, a)
+		require.NoError(t, err)
+
+		syso := filepath.Join(folder, "goreleaser_windows_arm64.syso")
+		require.FileExists(t, syso)
+
+		bts, err := os.ReadFile(filepath.Join(folder, "dist", "foo_windows_arm64", "foo.versioninfo.json"))
+		require.NoError(t, err)
+		var info versionInfoFile
+		require.NoError(t, json.Unmarshal(bts, &info))
+		require.Equal(t, "Acme Inc", info.StringFileInfo.CompanyName)
+		require.Equal(t, "foo", info.StringFileInfo.ProductName)
+		require.Equal(t, "1.2.3", info.StringFileInfo.FileVersion)
+		require.Equal(t, "1.2.3", info.StringFileInfo.ProductVersion)
+		require.Equal(t, "foo.exe", info.StringFileInfo.OriginalFilename)
+
+		cleanup()
+		require.NoFileExists(t, syso)
+	})
+
+	t.Run("not windows", func(t *testing.T) {
+		opts := options
+		opts.Goos = "linux"
+		opts.Target = "linux_arm64"
+		cleanup, err := embedVersionInfo(ctx, build, opts, a)
+		require.NoError(t, err)
+		cleanup()
+		require.NoFileExists(t, filepath.Join(folder, "goreleaser_windows_arm64.syso"))
+	})
+
+	t.Run("gomod proxy", func(t *testing.T) {
+		b := build
+		b.UnproxiedMain = "."
+		_, err := embedVersionInfo(ctx, b, options, a)
+		require.EqualError(t, err, "versioninfo can't be used together with gomod.proxy")
+	})
+
+	t.Run("failing binary", func(t *testing.T) {
+		b := build
+		b.VersionInfo.Binary = "false"
+		_, err := embedVersionInfo(ctx, b, options, a)
+		require.ErrorContains(t, err, "failed to generate versioninfo for windows_arm64")
+		require.NoFileExists(t, filepath.Join(folder, "goreleaser_windows_arm64.syso"))
+	})
+}
@@ -0,0 +1,175 @@
+package tinygo
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	api "github.com/goreleaser/goreleaser/pkg/build"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Default builder instance.
+// nolint: gochecknoglobals
+var Default = &Builder{}
+
+// nolint: gochecknoinits
+func init() {
+	api.Register("tinygo", Default)
+}
+
+// targets maps the supported GoReleaser targets to TinyGo's -target.
+var targets = map[string]string{
+	"js_wasm":   "wasm",
+	"wasi_wasm": "wasi",
+}
+
+// Builder is tinygo builder.
+type Builder struct{}
+
+// WithDefaults sets the defaults for a tinygo build and returns it.
+func (*Builder) WithDefaults(build config.Build) (config.Build, error) {
+	if build.GoBinary == "" {
+		build.GoBinary = "tinygo"
+	}
+	if build.Command == "" {
+		build.Command = "build"
+	}
+	if build.Dir == "" {
+		build.Dir = "."
+	}
+	if build.Main == "" {
+		build.Main = "."
+	}
+	if len(build.Targets) == 0 {
+		build.Targets = []string{"wasi_wasm"}
+	}
+	for _, target := range build.Targets {
+		if _, ok := targets[target]; !ok {
+			return build, fmt.Errorf("invalid tinygo target: %s, valid options are: %s", target, strings.Join(validTargets(), ", "))
+		}
+	}
+	if len(build.Goos) > 0 || len(build.Goarch) > 0 {
+		log.WithField("id", build.ID).Warn("goos and goarch are ignored by the tinygo builder, use targets instead")
+	}
+	return build, nil
+}
+
+func validTargets() []string {
+	result := make([]string, 0, len(targets))
+	for target := range targets {
+		result = append(result, target)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Build builds a tinygo build.
+func (*Builder) Build(ctx *context.Context, build config.Build, options api.Options) error {
+	if _, err := exec.LookPath(build.GoBinary); err != nil {
+		return fmt.Errorf("tinygo binary not found: %w", err)
+	}
+
+	a := &artifact.Artifact{
+		Type:   artifact.Binary,
+		Path:   options.Path,
+		Name:   options.Name,
+		Goos:   options.Goos,
+		Goarch: options.Goarch,
+		Extra: map[string]interface{}{
+			artifact.ExtraBinary: strings.TrimSuffix(options.Name, options.Ext),
+			artifact.ExtraExt:    options.Ext,
+			artifact.ExtraID:     build.ID,
+		},
+	}
+
+	env := []string{}
+	env = append(env, ctx.Env.Strings()...)
+	for _, e := range build.Env {
+		ee, err := tmpl.New(ctx).WithEnvS(env).WithBuildOptions(options).WithArtifact(a).Apply(e)
+		if err != nil {
+			return err
+		}
+		log.Debugf("env %q evaluated to %q", e, ee)
+		if ee != "" {
+			env = append(env, ee)
+		}
+	}
+
+	cmd, err := buildLine(ctx, build, options, a, env)
+	if err != nil {
+		return err
+	}
+
+	if err := run(ctx, cmd, env, build.Dir); err != nil {
+		return fmt.Errorf("failed to build for %s: %w", options.Target, err)
+	}
+
+	ctx.Artifacts.Add(a)
+	return nil
+}
+
+func buildLine(ctx *context.Context, build config.Build, options api.Options, a *artifact.Artifact, env []string) ([]string, error) {
+	target, ok := targets[options.Target]
+	if !ok {
+		return nil, fmt.Errorf("invalid tinygo target: %s", options.Target)
+	}
+
+	cmd := []string{build.GoBinary, build.Command, "-target=" + target}
+
+	t := tmpl.New(ctx).WithEnvS(env).WithBuildOptions(options).WithArtifact(a)
+	for _, rawFlag := range build.Flags {
+		flag, err := t.Apply(rawFlag)
+		if err != nil {
+			return nil, err
+		}
+		cmd = append(cmd, flag)
+	}
+
+	// tags is not a repeatable flag
+	if len(build.Tags) > 0 {
+		tags := make([]string, 0, len(build.Tags))
+		for _, rawTag := range build.Tags {
+			tag, err := t.Apply(rawTag)
+			if err != nil {
+				return nil, err
+			}
+			tags = append(tags, tag)
+		}
+		cmd = append(cmd, "-tags="+strings.Join(tags, " "))
+	}
+
+	// ldflags is not a repeatable flag
+	if len(build.Ldflags) > 0 {
+		ldflags := make([]string, 0, len(build.Ldflags))
+		for _, rawFlag := range build.Ldflags {
+			flag, err := t.Apply(rawFlag)
+			if err != nil {
+				return nil, err
+			}
+			ldflags = append(ldflags, flag)
+		}
+		cmd = append(cmd, "-ldflags="+strings.Join(ldflags, " "))
+	}
+
+	cmd = append(cmd, "-o", options.Path, build.Main)
+	return cmd, nil
+}
+
+func run(ctx *context.Context, command, env []string, dir string) error {
+	/* #nosec */
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	log := log.WithField("env", env).WithField("cmd", command)
+	cmd.Env = env
+	cmd.Dir = dir
+	log.Debug("running")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, string(out))
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package tinygo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	api "github.com/goreleaser/goreleaser/pkg/build"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDefaults(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		build, err := Default.WithDefaults(config.Build{})
+		require.NoError(t, err)
+		require.Equal(t, "tinygo", build.GoBinary)
+		require.Equal(t, "build", build.Command)
+		require.Equal(t, ".", build.Dir)
+		require.Equal(t, ".", build.Main)
+		require.Equal(t, []string{"wasi_wasm"}, build.Targets)
+		require.Empty(t, build.Ldflags)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		build, err := Default.WithDefaults(config.Build{
+			GoBinary: "tinygo0.26",
+			Targets:  []string{"js_wasm", "wasi_wasm"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "tinygo0.26", build.GoBinary)
+		require.Equal(t, []string{"js_wasm", "wasi_wasm"}, build.Targets)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, err := Default.WithDefaults(config.Build{
+			Targets: []string{"linux_amd64"},
+		})
+		require.EqualError(t, err, "invalid tinygo target: linux_amd64, valid options are: js_wasm, wasi_wasm")
+	})
+}
+
+func TestBuildLine(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Version = "1.2.3"
+	build := config.Build{
+		GoBinary: "tinygo",
+		Command:  "build",
+		Main:     "./cmd/foo",
+		BuildDetails: config.BuildDetails{
+			Flags:   []string{"-opt=z", "-no-debug"},
+			Tags:    []string{"tag1", "{{ .Os }}"},
+			Ldflags: []string{"-X main.version={{ .Version }}", "-X main.target={{ .Target }}"},
+		},
+	}
+
+	for target, expected := range map[string]string{
+		"wasi_wasm": "-target=wasi",
+		"js_wasm":   "-target=wasm",
+	} {
+		t.Run(target, func(t *testing.T) {
+			options := api.Options{
+				Target: target,
+				Path:   "dist/foo.wasm",
+				Name:   "foo.wasm",
+				Ext:    ".wasm",
+				Goos:   "wasi",
+				Goarch: "wasm",
+			}
+			line, err := buildLine(ctx, build, options, &artifact.Artifact{Goos: "wasi", Goarch: "wasm"}, []string{})
+			require.NoError(t, err)
+			require.Equal(t, []string{
+				"tinygo", "build", expected,
+				"-opt=z", "-no-debug",
+				"-tags=tag1 wasi",
+				"-ldflags=-X main.version=1.2.3 -X main.target=" + target,
+				"-o", "dist/foo.wasm", "./cmd/foo",
+			}, line)
+		})
+	}
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, err := buildLine(ctx, build, api.Options{Target: "linux_amd64"}, &artifact.Artifact{}, []string{})
+		require.EqualError(t, err, "invalid tinygo target: linux_amd64")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		build := build
+		build.Ldflags = []string{"{{ .Nope }"}
+		_, err := buildLine(ctx, build, api.Options{Target: "wasi_wasm"}, &artifact.Artifact{}, []string{})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestBuild(t *testing.T) {
+	folder := testlib.Mktmp(t)
+
+	// fake tinygo binary that writes whatever is passed to -o.
+	bin := filepath.Join(folder, "faketinygo")
+	require.NoError(t, os.WriteFile(
+		bin,
+		[]byte("#!/bin/sh\nwhile [ \"$1\" != \"-o\" ]; do shift; done\necho \"$FOO\" > \"$2\"\n"),
+		0o755,
+	))
+
+	ctx := context.New(config.Project{})
+	ctx.Env["BAR"] = "bar"
+	build := config.Build{
+		ID:       "foo",
+		GoBinary: bin,
+		Command:  "build",
+		Dir:      ".",
+		Main:     ".",
+		BuildDetails: config.BuildDetails{
+			Env: []string{"FOO={{ .Env.BAR }}"},
+		},
+	}
+	path := filepath.Join(folder, "foo.wasm")
+	require.NoError(t, Default.Build(ctx, build, api.Options{
+		Target: "wasi_wasm",
+		Name:   "foo.wasm",
+		Path:   path,
+		Ext:    ".wasm",
+		Goos:   "wasi",
+		Goarch: "wasm",
+	}))
+
+	bts, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, "bar\n", string(bts))
+
+	bins := ctx.Artifacts.Filter(artifact.ByType(artifact.Binary)).List()
+	require.Len(t, bins, 1)
+	require.Equal(t, "foo.wasm", bins[0].Name)
+	require.Equal(t, path, bins[0].Path)
+	require.Equal(t, "wasi", bins[0].Goos)
+	require.Equal(t, "wasm", bins[0].Goarch)
+	require.Equal(t, "foo", bins[0].Extra[artifact.ExtraBinary])
+	require.Equal(t, ".wasm", bins[0].Extra[artifact.ExtraExt])
+	require.Equal(t, "foo", bins[0].Extra[artifact.ExtraID])
+}
+
+func TestBuildFailed(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	bin := filepath.Join(folder, "faketinygo")
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho nope\nexit 1\n"), 0o755))
+
+	ctx := context.New(config.Project{})
+	err := Default.Build(ctx, config.Build{
+		GoBinary: bin,
+		Command:  "build",
+		Main:     ".",
+	}, api.Options{
+		Target: "js_wasm",
+		Path:   filepath.Join(folder, "foo.wasm"),
+	})
+	require.ErrorContains(t, err, "failed to build for js_wasm")
+	require.ErrorContains(t, err, "nope")
+	require.Empty(t, ctx.Artifacts.List())
+}
+
+func TestBuildNoTinyGo(t *testing.T) {
+	ctx := context.New(config.Project{})
+	err := Default.Build(ctx, config.Build{
+		GoBinary: "tinygo-does-not-exist",
+	}, api.Options{
+		Target: "wasi_wasm",
+	})
+	require.ErrorContains(t, err, `tinygo binary not found: exec: "tinygo-does-not-exist"`)
+	require.Empty(t, ctx.Artifacts.List())
+}
@@ -0,0 +1,2 @@
+// Package tinygo provides a Builder implementation for TinyGo.
+package tinygo
@@ -3,6 +3,7 @@ package client
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/caarlos0/log"
@@ -45,6 +46,7 @@ func (r Repo) String() string {
 // Client interface.
 type Client interface {
 	CloseMilestone(ctx *context.Context, repo Repo, title string) (err error)
+	CreateMilestone(ctx *context.Context, repo Repo, title string) (err error)
 	CreateRelease(ctx *context.Context, body string) (releaseID string, err error)
 	ReleaseURLTemplate(ctx *context.Context) (string, error)
 	CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo Repo, content []byte, path, message string) (err error)
@@ -57,6 +59,28 @@ type Client interface {
 type GitHubClient interface {
 	Client
 	GenerateReleaseNotes(ctx *context.Context, repo Repo, prev, current string) (string, error)
+	CommitAuthors(ctx *context.Context, repo Repo, prev, current string) (map[string]string, error)
+}
+
+// PullRequestOpener is a client that can open pull requests.
+type PullRequestOpener interface {
+	OpenPullRequest(ctx *context.Context, base, head Repo, title, body string) error
+}
+
+// GraphQLClient is a client that can run GitHub GraphQL queries.
+type GraphQLClient interface {
+	GraphQL(ctx *context.Context, query string, variables map[string]interface{}, result interface{}) error
+}
+
+// ReleasePublisher is a client that can publish existing draft releases.
+type ReleasePublisher interface {
+	PublishRelease(ctx *context.Context, releaseID string) error
+}
+
+// ReleaseAssetDownloader is a client that can download the assets uploaded
+// to a release.
+type ReleaseAssetDownloader interface {
+	DownloadReleaseAsset(ctx *context.Context, releaseID, name string) (io.ReadCloser, error)
 }
 
 // New creates a new client depending on the token type.
@@ -27,10 +27,28 @@ func TemplateRef(apply func(s string) (string, error), ref config.RepoRef) (conf
 	if err != nil {
 		return ref, err
 	}
+	gitURL, err := apply(ref.Git.URL)
+	if err != nil {
+		return ref, err
+	}
+	privateKey, err := apply(ref.Git.PrivateKey)
+	if err != nil {
+		return ref, err
+	}
+	knownHosts, err := apply(ref.Git.KnownHosts)
+	if err != nil {
+		return ref, err
+	}
 	return config.RepoRef{
 		Owner:  owner,
 		Name:   name,
 		Token:  ref.Token,
 		Branch: branch,
+		Git: config.GitRepoRef{
+			URL:        gitURL,
+			SSHCommand: ref.Git.SSHCommand,
+			PrivateKey: privateKey,
+			KnownHosts: knownHosts,
+		},
 	}, nil
 }
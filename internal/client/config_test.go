@@ -33,6 +33,11 @@ func TestTemplateRef(t *testing.T) {
 		Name:   "name",
 		Branch: "branch",
 		Token:  "token",
+		Git: config.GitRepoRef{
+			URL:        "url",
+			SSHCommand: "ssh",
+			PrivateKey: "key",
+		},
 	}
 	t.Run("success", func(t *testing.T) {
 		ref, err := TemplateRef(func(s string) (string, error) {
@@ -72,4 +77,32 @@ func TestTemplateRef(t *testing.T) {
 		}, expected)
 		require.Error(t, err)
 	})
+	t.Run("fail git url", func(t *testing.T) {
+		_, err := TemplateRef(func(s string) (string, error) {
+			if s == "token" || s == "url" {
+				return "", fmt.Errorf("nope")
+			}
+			return s, nil
+		}, expected)
+		require.Error(t, err)
+	})
+	t.Run("fail private key", func(t *testing.T) {
+		_, err := TemplateRef(func(s string) (string, error) {
+			if s == "token" || s == "key" {
+				return "", fmt.Errorf("nope")
+			}
+			return s, nil
+		}, expected)
+		require.Error(t, err)
+	})
+	t.Run("ssh command is not templated", func(t *testing.T) {
+		ref, err := TemplateRef(func(s string) (string, error) {
+			if s == "ssh" {
+				return "", fmt.Errorf("nope")
+			}
+			return s, nil
+		}, expected)
+		require.NoError(t, err)
+		require.Equal(t, "ssh", ref.Git.SSHCommand)
+	})
 }
@@ -0,0 +1,392 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"golang.org/x/crypto/ssh"
+)
+
+// DefaultGitSSHCommand is the SSH command used to push to git repositories
+// when none is set.
+const DefaultGitSSHCommand = "ssh -i {{ .KeyPath }} -o StrictHostKeyChecking=accept-new -F /dev/null"
+
+// KnownHostsGitSSHCommand is the SSH command used instead of the default one
+// when a known_hosts file is set, only accepting the host keys listed in it.
+const KnownHostsGitSSHCommand = "ssh -i {{ .KeyPath }} -o UserKnownHostsFile={{ .KnownHostsPath }} -o StrictHostKeyChecking=yes -F /dev/null"
+
+// ErrSigningRequiresGit happens when commit signing is enabled for a repo
+// without a git URL, as commits made through the APIs can't be signed.
+var ErrSigningRequiresGit = errors.New("commit_author.signing requires git.url to be set")
+
+// ErrPassphraseRequiresOpenPGP happens when a signing passphrase is set for a
+// key that isn't an OpenPGP one.
+var ErrPassphraseRequiresOpenPGP = errors.New("commit_author.signing.passphrase is only supported with the openpgp format")
+
+// FileCreator is a client that can create files in a repository.
+type FileCreator interface {
+	CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo Repo, content []byte, path, message string) (err error)
+}
+
+// CheckSigning checks that the given commit author can sign the commits made
+// to the given ref.
+func CheckSigning(author config.CommitAuthor, ref config.RepoRef) error {
+	if !author.Signing.Enabled {
+		return nil
+	}
+	if ref.Git.URL == "" {
+		return ErrSigningRequiresGit
+	}
+	if author.Signing.Passphrase != "" && !isOpenPGP(author.Signing) {
+		return ErrPassphraseRequiresOpenPGP
+	}
+	return nil
+}
+
+// NewIfGitURL returns a client that pushes the files with git over SSH if
+// the given ref has a git URL set, and the given client otherwise.
+func NewIfGitURL(cli FileCreator, ref config.RepoRef) FileCreator {
+	if ref.Git.URL == "" {
+		return cli
+	}
+	log.WithField("url", ref.Git.URL).Debug("using git client")
+	return &gitClient{ref: ref.Git}
+}
+
+// gitClient creates files by cloning a repository over SSH, committing the
+// changes and pushing them back.
+type gitClient struct {
+	ref config.GitRepoRef
+}
+
+var _ FileCreator = &gitClient{}
+
+func (c *gitClient) CreateFile(
+	ctx *context.Context,
+	commitAuthor config.CommitAuthor,
+	repo Repo,
+	content []byte,
+	path,
+	message string,
+) error {
+	key, cleanup, err := KeyPath(c.ref.PrivateKey, "git.private_key")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	knownHosts, cleanupKnownHosts, err := KnownHostsPath(c.ref.KnownHosts, "git.known_hosts")
+	if err != nil {
+		return err
+	}
+	defer cleanupKnownHosts()
+
+	signing, cleanupSigning, err := SigningCmds(ctx, commitAuthor.Signing)
+	if err != nil {
+		return err
+	}
+	defer cleanupSigning()
+
+	sshcmd, err := GitSSHCommand(ctx, c.ref.SSHCommand, key, knownHosts)
+	if err != nil {
+		return err
+	}
+	// the whole environment is needed so git can find the signing keys.
+	env := append(ctx.Env.Strings(), "GIT_SSH_COMMAND="+sshcmd)
+
+	parent := filepath.Join(ctx.Config.Dist, "git")
+	if err := os.MkdirAll(parent, 0o755); err != nil {
+		return err
+	}
+	cwd, err := os.MkdirTemp(parent, "repo-")
+	if err != nil {
+		return err
+	}
+
+	clone := []string{"clone", c.ref.URL, "."}
+	if repo.Branch != "" {
+		clone = append(clone, "--branch", repo.Branch)
+	}
+	if err := RunGitCmds(ctx, cwd, env, append([][]string{
+		clone,
+		{"config", "--local", "user.name", commitAuthor.Name},
+		{"config", "--local", "user.email", commitAuthor.Email},
+	}, signing...)); err != nil {
+		return fmt.Errorf("failed to setup local repo %q: %w", c.ref.URL, err)
+	}
+
+	location := filepath.Join(cwd, path)
+	if err := os.MkdirAll(filepath.Dir(location), 0o755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.WriteFile(location, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := RunGitCmds(ctx, cwd, env, [][]string{
+		{"add", "-A", "."},
+	}); err != nil {
+		return fmt.Errorf("failed to push %q to %q: %w", path, c.ref.URL, err)
+	}
+	status, err := git.Clean(git.RunWithEnv(ctx, env, "-C", cwd, "status", "--porcelain"))
+	if err != nil {
+		return fmt.Errorf("failed to push %q to %q: %w", path, c.ref.URL, err)
+	}
+	if status == "" {
+		log.WithField("repo", c.ref.URL).WithField("file", path).Info("nothing to push")
+		return nil
+	}
+
+	log.WithField("repo", c.ref.URL).WithField("file", path).Info("pushing")
+	if err := RunGitCmds(ctx, cwd, env, [][]string{
+		{"commit", "-m", message},
+		{"push", "origin", "HEAD"},
+	}); err != nil {
+		return fmt.Errorf("failed to push %q to %q: %w", path, c.ref.URL, err)
+	}
+	return nil
+}
+
+// SigningCmds returns the git commands that set up commit signing with the
+// given settings, after checking the signing key is available.
+// If a passphrase is set, the signing program is wrapped so it reads the
+// passphrase from a temporary file.
+// The returned function removes it, and must always be called once the
+// commits are made.
+func SigningCmds(ctx *context.Context, signing config.CommitSigning) ([][]string, func(), error) {
+	noop := func() {}
+	if err := checkSigningKey(ctx, signing); err != nil {
+		return nil, noop, err
+	}
+	if !signing.Enabled || signing.Passphrase == "" {
+		return signingCmds(signing), noop, nil
+	}
+	if !isOpenPGP(signing) {
+		return nil, noop, ErrPassphraseRequiresOpenPGP
+	}
+	program, cleanup, err := passphraseProgram(signing)
+	if err != nil {
+		return nil, noop, err
+	}
+	signing.Program = program
+	return signingCmds(signing), cleanup, nil
+}
+
+// passphraseProgram writes a wrapper around the signing program that feeds
+// it the signing passphrase, returning the path of the wrapper.
+func passphraseProgram(signing config.CommitSigning) (string, func(), error) {
+	dir, err := os.MkdirTemp("", "signing-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to store signing passphrase: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(dir) }
+
+	passphrase := filepath.Join(dir, "passphrase")
+	if err := os.WriteFile(passphrase, []byte(signing.Passphrase), 0o600); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to store signing passphrase: %w", err)
+	}
+
+	program := signing.Program
+	if program == "" {
+		program = "gpg"
+	}
+	wrapper := filepath.Join(dir, "gpg")
+	script := fmt.Sprintf(
+		"#!/bin/sh\nexec %s --batch --pinentry-mode loopback --passphrase-file %s \"$@\"\n",
+		shellQuote(program), shellQuote(passphrase),
+	)
+	// nolint: gosec
+	if err := os.WriteFile(wrapper, []byte(script), 0o700); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("failed to store signing passphrase: %w", err)
+	}
+	return wrapper, cleanup, nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func isOpenPGP(signing config.CommitSigning) bool {
+	return signing.Format == "" || signing.Format == "openpgp"
+}
+
+// signingCmds returns the git commands that set up commit signing with the
+// given settings.
+func signingCmds(signing config.CommitSigning) [][]string {
+	if !signing.Enabled {
+		return [][]string{{"config", "--local", "commit.gpgSign", "false"}}
+	}
+	cmds := [][]string{{"config", "--local", "commit.gpgSign", "true"}}
+	if signing.Key != "" {
+		cmds = append(cmds, []string{"config", "--local", "user.signingKey", signing.Key})
+	}
+	if signing.Format != "" {
+		cmds = append(cmds, []string{"config", "--local", "gpg.format", signing.Format})
+	}
+	if signing.Program != "" {
+		cmds = append(cmds, []string{"config", "--local", signingProgramConfig(signing.Format), signing.Program})
+	}
+	return cmds
+}
+
+// signingProgramConfig returns the git setting holding the signing program
+// for the given format.
+func signingProgramConfig(format string) string {
+	if format == "" {
+		format = "openpgp"
+	}
+	return "gpg." + format + ".program"
+}
+
+// checkSigningKey ensures the OpenPGP key used to sign commits is available,
+// so we fail before committing anything.
+// Keys of other formats are checked by git when committing.
+func checkSigningKey(ctx *context.Context, signing config.CommitSigning) error {
+	if !signing.Enabled || !isOpenPGP(signing) {
+		return nil
+	}
+	program := signing.Program
+	if program == "" {
+		program = "gpg"
+	}
+	args := []string{"--batch", "--list-secret-keys"}
+	if signing.Key != "" {
+		args = append(args, signing.Key)
+	}
+	/* #nosec */
+	cmd := exec.CommandContext(ctx, program, args...)
+	cmd.Env = ctx.Env.Strings()
+	out, err := cmd.CombinedOutput()
+	if err != nil || strings.TrimSpace(string(out)) == "" {
+		return fmt.Errorf("commit signing key %q is not available: %s", signing.Key, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// GitSSHCommand returns the command to be set as GIT_SSH_COMMAND, given the
+// paths of the private key and of the known_hosts file, if any.
+func GitSSHCommand(ctx *context.Context, cmd, key, knownHosts string) (string, error) {
+	if cmd == "" {
+		cmd = DefaultGitSSHCommand
+	}
+	if cmd == DefaultGitSSHCommand && knownHosts != "" {
+		cmd = KnownHostsGitSSHCommand
+	}
+	return tmpl.New(ctx).WithExtraFields(tmpl.Fields{
+		"KeyPath":        key,
+		"KnownHostsPath": knownHosts,
+	}).Apply(cmd)
+}
+
+// KeyPath returns the path of the given private key, writing it to a
+// temporary file if the key itself was given instead of a path.
+// The option is the name of the setting the key comes from, used in the
+// error messages.
+// The returned function removes that temporary file, and must always be
+// called once the key is no longer needed.
+func KeyPath(key, option string) (string, func(), error) {
+	noop := func() {}
+	if key == "" {
+		return "", noop, fmt.Errorf("%s is empty", option)
+	}
+
+	path := key
+	cleanup := noop
+	if _, err := ssh.ParsePrivateKey([]byte(key)); err == nil {
+		// the key needs to EOF at an empty line.
+		if !strings.HasSuffix(key, "\n") {
+			key += "\n"
+		}
+		path, cleanup, err = tempFile("id_*", key)
+		if err != nil {
+			return "", noop, fmt.Errorf("failed to store private key: %w", err)
+		}
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("could not stat %s: %w", option, err)
+	}
+
+	// in any case, ensure the key has the correct permissions.
+	if err := os.Chmod(path, 0o600); err != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("failed to ensure %s permissions: %w", option, err)
+	}
+
+	return path, cleanup, nil
+}
+
+// KnownHostsPath returns the path of the given known_hosts file, writing it
+// to a temporary file if its contents were given instead of a path.
+// An empty known_hosts results in an empty path.
+// The returned function removes that temporary file, and must always be
+// called once the file is no longer needed.
+func KnownHostsPath(hosts, option string) (string, func(), error) {
+	noop := func() {}
+	if hosts == "" {
+		return "", noop, nil
+	}
+
+	if _, _, _, _, _, err := ssh.ParseKnownHosts([]byte(hosts)); err == nil {
+		if !strings.HasSuffix(hosts, "\n") {
+			hosts += "\n"
+		}
+		path, cleanup, err := tempFile("known_hosts_*", hosts)
+		if err != nil {
+			return "", noop, fmt.Errorf("failed to store known hosts: %w", err)
+		}
+		return path, cleanup, nil
+	}
+
+	if _, err := os.Stat(hosts); err != nil {
+		return "", noop, fmt.Errorf("could not stat %s: %w", option, err)
+	}
+	return hosts, noop, nil
+}
+
+// tempFile writes the given content to a new temporary file, returning its
+// path and a function removing it.
+func tempFile(pattern, content string) (string, func(), error) {
+	noop := func() {}
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", noop, err
+	}
+	defer f.Close()
+	cleanup := func() { _ = os.Remove(f.Name()) }
+
+	if _, err := io.WriteString(f, content); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return f.Name(), cleanup, nil
+}
+
+// RunGitCmds runs the given git commands in the given directory, stopping at
+// the first failure.
+func RunGitCmds(ctx *context.Context, cwd string, env []string, cmds [][]string) error {
+	for _, cmd := range cmds {
+		args := append([]string{"-C", cwd}, cmd...)
+		if _, err := git.Clean(git.RunWithEnv(ctx, env, args...)); err != nil {
+			return fmt.Errorf("%q failed: %w", strings.Join(cmd, " "), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,388 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/keygen"
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIfGitURL(t *testing.T) {
+	mock := NewMock()
+	require.Equal(t, mock, NewIfGitURL(mock, config.RepoRef{}))
+	require.IsType(t, &gitClient{}, NewIfGitURL(mock, config.RepoRef{
+		Git: config.GitRepoRef{URL: "git@github.com:foo/homebrew-tap.git"},
+	}))
+}
+
+func TestGitSSHCommand(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Env["KNOWN_HOSTS"] = "/tmp/known_hosts"
+
+	t.Run("default", func(t *testing.T) {
+		cmd, err := GitSSHCommand(ctx, "", "/tmp/id", "")
+		require.NoError(t, err)
+		require.Equal(t, "ssh -i /tmp/id -o StrictHostKeyChecking=accept-new -F /dev/null", cmd)
+	})
+
+	t.Run("default with known hosts", func(t *testing.T) {
+		cmd, err := GitSSHCommand(ctx, DefaultGitSSHCommand, "/tmp/id", "/tmp/known_hosts")
+		require.NoError(t, err)
+		require.Equal(t, "ssh -i /tmp/id -o UserKnownHostsFile=/tmp/known_hosts -o StrictHostKeyChecking=yes -F /dev/null", cmd)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		cmd, err := GitSSHCommand(ctx, "ssh -i {{ .KeyPath }} -o UserKnownHostsFile={{ .Env.KNOWN_HOSTS }}", "/tmp/id", "")
+		require.NoError(t, err)
+		require.Equal(t, "ssh -i /tmp/id -o UserKnownHostsFile=/tmp/known_hosts", cmd)
+	})
+
+	t.Run("custom with known hosts", func(t *testing.T) {
+		cmd, err := GitSSHCommand(ctx, "ssh -i {{ .KeyPath }} -o UserKnownHostsFile={{ .KnownHostsPath }}", "/tmp/id", "/tmp/hosts")
+		require.NoError(t, err)
+		require.Equal(t, "ssh -i /tmp/id -o UserKnownHostsFile=/tmp/hosts", cmd)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := GitSSHCommand(ctx, "{{ .Nope }", "/tmp/id", "")
+		require.Error(t, err)
+	})
+}
+
+func TestGitKeyPath(t *testing.T) {
+	t.Run("with valid path", func(t *testing.T) {
+		path := makeKey(t, keygen.Ed25519)
+		result, cleanup, err := KeyPath(path, "git.private_key")
+		require.NoError(t, err)
+		require.Equal(t, path, result)
+		cleanup()
+		require.FileExists(t, path)
+	})
+	t.Run("with invalid path", func(t *testing.T) {
+		result, _, err := KeyPath("testdata/nope", "git.private_key")
+		require.EqualError(t, err, `could not stat git.private_key: stat testdata/nope: no such file or directory`)
+		require.Equal(t, "", result)
+	})
+	t.Run("with key", func(t *testing.T) {
+		for _, algo := range []keygen.KeyType{keygen.Ed25519, keygen.RSA} {
+			t.Run(string(algo), func(t *testing.T) {
+				path := makeKey(t, algo)
+				bts, err := os.ReadFile(path)
+				require.NoError(t, err)
+
+				result, cleanup, err := KeyPath(strings.TrimSpace(string(bts)), "git.private_key")
+				require.NoError(t, err)
+
+				resultbts, err := os.ReadFile(result)
+				require.NoError(t, err)
+				require.Equal(t, string(bts), string(resultbts))
+
+				cleanup()
+				require.NoFileExists(t, result)
+			})
+		}
+	})
+	t.Run("empty", func(t *testing.T) {
+		result, _, err := KeyPath("", "aur.private_key")
+		require.EqualError(t, err, `aur.private_key is empty`)
+		require.Equal(t, "", result)
+	})
+}
+
+func TestKnownHostsPath(t *testing.T) {
+	const hosts = "github.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl"
+
+	t.Run("empty", func(t *testing.T) {
+		result, cleanup, err := KnownHostsPath("", "git.known_hosts")
+		require.NoError(t, err)
+		require.Empty(t, result)
+		cleanup()
+	})
+	t.Run("with valid path", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "known_hosts")
+		require.NoError(t, os.WriteFile(path, []byte(hosts+"\n"), 0o644))
+		result, cleanup, err := KnownHostsPath(path, "git.known_hosts")
+		require.NoError(t, err)
+		require.Equal(t, path, result)
+		cleanup()
+		require.FileExists(t, path)
+	})
+	t.Run("with invalid path", func(t *testing.T) {
+		_, _, err := KnownHostsPath("testdata/nope", "git.known_hosts")
+		require.EqualError(t, err, `could not stat git.known_hosts: stat testdata/nope: no such file or directory`)
+	})
+	t.Run("with contents", func(t *testing.T) {
+		result, cleanup, err := KnownHostsPath(hosts, "git.known_hosts")
+		require.NoError(t, err)
+		bts, err := os.ReadFile(result)
+		require.NoError(t, err)
+		require.Equal(t, hosts+"\n", string(bts))
+		cleanup()
+		require.NoFileExists(t, result)
+	})
+}
+
+func TestGitClientCreateFile(t *testing.T) {
+	url := makeBareRepo(t)
+	ctx := context.New(config.Project{
+		Dist: t.TempDir(),
+	})
+	author := config.CommitAuthor{
+		Name:  "Foo",
+		Email: "foo@bar.com",
+	}
+	cli := NewIfGitURL(NewMock(), config.RepoRef{
+		Git: config.GitRepoRef{
+			URL:        url,
+			PrivateKey: makeKey(t, keygen.Ed25519),
+		},
+	})
+
+	require.NoError(t, cli.CreateFile(ctx, author, Repo{}, []byte("fake formula"), "Formula/foo.rb", "brew formula update"))
+	require.NoError(t, cli.CreateFile(ctx, author, Repo{}, []byte("updated formula"), "Formula/foo.rb", "brew formula update again"))
+	// no changes, nothing is committed
+	require.NoError(t, cli.CreateFile(ctx, author, Repo{}, []byte("updated formula"), "Formula/foo.rb", "brew formula update yet again"))
+
+	dir := t.TempDir()
+	_, err := git.Run(ctx, "-C", dir, "clone", url, "repo")
+	require.NoError(t, err)
+	bts, err := os.ReadFile(filepath.Join(dir, "repo", "Formula", "foo.rb"))
+	require.NoError(t, err)
+	require.Equal(t, "updated formula", string(bts))
+
+	log, err := git.Run(ctx, "-C", filepath.Join(dir, "repo"), "log", "--pretty=%an <%ae> %s")
+	require.NoError(t, err)
+	require.Equal(t, "Foo <foo@bar.com> brew formula update again\nFoo <foo@bar.com> brew formula update\n", log)
+
+	t.Run("invalid branch", func(t *testing.T) {
+		err := cli.CreateFile(ctx, author, Repo{Branch: "nope"}, []byte("fake formula"), "Formula/foo.rb", "brew formula update")
+		require.ErrorContains(t, err, "failed to setup local repo")
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		err := NewIfGitURL(NewMock(), config.RepoRef{
+			Git: config.GitRepoRef{URL: url},
+		}).CreateFile(ctx, author, Repo{}, []byte("fake formula"), "Formula/foo.rb", "brew formula update")
+		require.EqualError(t, err, "git.private_key is empty")
+	})
+}
+
+func TestCheckSigning(t *testing.T) {
+	signing := config.CommitAuthor{
+		Signing: config.CommitSigning{Enabled: true},
+	}
+	require.NoError(t, CheckSigning(config.CommitAuthor{}, config.RepoRef{}))
+	require.NoError(t, CheckSigning(signing, config.RepoRef{
+		Git: config.GitRepoRef{URL: "git@github.com:foo/homebrew-tap.git"},
+	}))
+	require.ErrorIs(t, CheckSigning(signing, config.RepoRef{}), ErrSigningRequiresGit)
+
+	signing.Signing.Passphrase = "secret"
+	signing.Signing.Format = "ssh"
+	require.ErrorIs(t, CheckSigning(signing, config.RepoRef{
+		Git: config.GitRepoRef{URL: "git@github.com:foo/homebrew-tap.git"},
+	}), ErrPassphraseRequiresOpenPGP)
+}
+
+func TestSigningCmds(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		require.Equal(t, [][]string{
+			{"config", "--local", "commit.gpgSign", "false"},
+		}, signingCmds(config.CommitSigning{Key: "ABCDEF"}))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		require.Equal(t, [][]string{
+			{"config", "--local", "commit.gpgSign", "true"},
+		}, signingCmds(config.CommitSigning{Enabled: true}))
+	})
+
+	t.Run("all set", func(t *testing.T) {
+		require.Equal(t, [][]string{
+			{"config", "--local", "commit.gpgSign", "true"},
+			{"config", "--local", "user.signingKey", "ABCDEF"},
+			{"config", "--local", "gpg.format", "ssh"},
+			{"config", "--local", "gpg.ssh.program", "/usr/bin/ssh-keygen"},
+		}, signingCmds(config.CommitSigning{
+			Enabled: true,
+			Key:     "ABCDEF",
+			Format:  "ssh",
+			Program: "/usr/bin/ssh-keygen",
+		}))
+	})
+
+	t.Run("program", func(t *testing.T) {
+		require.Equal(t, [][]string{
+			{"config", "--local", "commit.gpgSign", "true"},
+			{"config", "--local", "gpg.openpgp.program", "gpg2"},
+		}, signingCmds(config.CommitSigning{
+			Enabled: true,
+			Program: "gpg2",
+		}))
+	})
+}
+
+func TestCheckSigningKey(t *testing.T) {
+	gpg, log := fakeGPG(t)
+	ctx := context.New(config.Project{})
+
+	t.Run("disabled", func(t *testing.T) {
+		require.NoError(t, checkSigningKey(ctx, config.CommitSigning{Program: "nope"}))
+	})
+
+	t.Run("other format", func(t *testing.T) {
+		require.NoError(t, checkSigningKey(ctx, config.CommitSigning{
+			Enabled: true,
+			Format:  "ssh",
+			Program: "nope",
+		}))
+	})
+
+	t.Run("available", func(t *testing.T) {
+		require.NoError(t, checkSigningKey(ctx, config.CommitSigning{
+			Enabled: true,
+			Key:     "ABCDEF",
+			Program: gpg,
+		}))
+		bts, err := os.ReadFile(log)
+		require.NoError(t, err)
+		require.Contains(t, string(bts), "--batch --list-secret-keys ABCDEF")
+	})
+
+	t.Run("not available", func(t *testing.T) {
+		require.EqualError(t, checkSigningKey(ctx, config.CommitSigning{
+			Enabled: true,
+			Key:     "NOPE",
+			Program: gpg,
+		}), `commit signing key "NOPE" is not available: gpg: error reading key: No secret key`)
+	})
+
+	t.Run("program not found", func(t *testing.T) {
+		require.ErrorContains(t, checkSigningKey(ctx, config.CommitSigning{
+			Enabled: true,
+			Key:     "ABCDEF",
+			Program: filepath.Join(t.TempDir(), "nope"),
+		}), `commit signing key "ABCDEF" is not available`)
+	})
+}
+
+func TestGitClientCreateFileSigned(t *testing.T) {
+	gpg, log := fakeGPG(t)
+	url := makeBareRepo(t)
+	ctx := context.New(config.Project{
+		Dist: t.TempDir(),
+	})
+	author := config.CommitAuthor{
+		Name:  "Foo",
+		Email: "foo@bar.com",
+		Signing: config.CommitSigning{
+			Enabled: true,
+			Key:     "ABCDEF",
+			Program: gpg,
+		},
+	}
+	cli := NewIfGitURL(NewMock(), config.RepoRef{
+		Git: config.GitRepoRef{
+			URL:        url,
+			PrivateKey: makeKey(t, keygen.Ed25519),
+		},
+	})
+
+	require.NoError(t, cli.CreateFile(ctx, author, Repo{}, []byte("fake formula"), "Formula/foo.rb", "brew formula update"))
+
+	commit, err := git.Run(ctx, "-C", url, "cat-file", "commit", "HEAD")
+	require.NoError(t, err)
+	require.Contains(t, commit, "gpgsig -----BEGIN PGP SIGNATURE-----")
+
+	bts, err := os.ReadFile(log)
+	require.NoError(t, err)
+	require.Contains(t, string(bts), "-bsau ABCDEF")
+
+	t.Run("passphrase", func(t *testing.T) {
+		author := author
+		author.Signing.Passphrase = "secret"
+		require.NoError(t, cli.CreateFile(ctx, author, Repo{}, []byte("new formula"), "Formula/foo.rb", "brew formula update"))
+
+		bts, err := os.ReadFile(log)
+		require.NoError(t, err)
+		match := regexp.MustCompile(`--batch --pinentry-mode loopback --passphrase-file (\S+)`).FindStringSubmatch(string(bts))
+		require.Len(t, match, 2)
+		require.NoFileExists(t, match[1])
+	})
+
+	t.Run("key not available", func(t *testing.T) {
+		author := author
+		author.Signing.Key = "NOPE"
+		err := cli.CreateFile(ctx, author, Repo{}, []byte("other formula"), "Formula/foo.rb", "brew formula update")
+		require.ErrorContains(t, err, `commit signing key "NOPE" is not available`)
+
+		commit, err := git.Run(ctx, "-C", url, "log", "--oneline")
+		require.NoError(t, err)
+		require.Len(t, strings.Split(strings.TrimSpace(commit), "\n"), 2)
+	})
+}
+
+// fakeGPG writes a script that behaves like gpg, knowing only the ABCDEF
+// key, and returns its path and the path of the file its arguments are
+// logged to.
+func fakeGPG(tb testing.TB) (string, string) {
+	tb.Helper()
+	if runtime.GOOS == "windows" {
+		tb.Skip("uses a shell script as gpg")
+	}
+	dir := tb.TempDir()
+	log := filepath.Join(dir, "gpg.log")
+	path := filepath.Join(dir, "gpg")
+	require.NoError(tb, os.WriteFile(path, []byte(`#!/bin/sh
+echo "$@" >> "`+log+`"
+case "$*" in
+*"--list-secret-keys ABCDEF")
+	echo "sec   ed25519 2023-01-01 [SC]"
+	;;
+*--list-secret-keys*)
+	echo "gpg: error reading key: No secret key" >&2
+	exit 2
+	;;
+*)
+	cat > /dev/null
+	printf '\n[GNUPG:] SIG_CREATED D 22 8 00 1 ABCDEF\n' >&2
+	printf -- '-----BEGIN PGP SIGNATURE-----\n\nZmFrZQ==\n-----END PGP SIGNATURE-----\n'
+	;;
+esac
+`), 0o755))
+	return path, log
+}
+
+func makeBareRepo(tb testing.TB) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	_, err := git.Run(
+		context.New(config.Project{}),
+		"-C", dir,
+		"-c", "init.defaultBranch=master",
+		"init",
+		"--bare",
+		".",
+	)
+	require.NoError(tb, err)
+	return dir
+}
+
+func makeKey(tb testing.TB, algo keygen.KeyType) string {
+	tb.Helper()
+
+	dir := tb.TempDir()
+	filepath := filepath.Join(dir, "id")
+	_, err := keygen.NewWithWrite(filepath, nil, algo)
+	require.NoError(tb, err)
+	return fmt.Sprintf("%s_%s", filepath, algo)
+}
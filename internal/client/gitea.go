@@ -1,7 +1,6 @@
 package client
 
 import (
-	"crypto/tls"
 	"encoding/base64"
 	"fmt"
 	"net/http"
@@ -45,12 +44,13 @@ func NewGitea(ctx *context.Context, token string) (Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	tlsConfig, err := newTLSConfig(ctx.Config.GiteaURLs.SkipTLSVerify, ctx.Config.GiteaURLs.CACert)
+	if err != nil {
+		return nil, err
+	}
 	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{
-			// nolint: gosec
-			InsecureSkipVerify: ctx.Config.GiteaURLs.SkipTLSVerify,
-		},
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
 	}
 	httpClient := &http.Client{Transport: transport}
 	client, err := gitea.NewClient(instanceURL,
@@ -87,6 +87,14 @@ func (c *giteaClient) CloseMilestone(ctx *context.Context, repo Repo, title stri
 	return err
 }
 
+// CreateMilestone creates an open milestone with the given title.
+func (c *giteaClient) CreateMilestone(ctx *context.Context, repo Repo, title string) error {
+	_, _, err := c.client.CreateMilestone(repo.Owner, repo.Name, gitea.CreateMilestoneOption{
+		Title: title,
+	})
+	return err
+}
+
 func (c *giteaClient) GetDefaultBranch(ctx *context.Context, repo Repo) (string, error) {
 	projectID := repo.String()
 	p, res, err := c.client.GetRepo(repo.Owner, repo.Name)
@@ -269,6 +277,26 @@ func (c *giteaClient) CreateRelease(ctx *context.Context, body string) (string,
 	return strconv.FormatInt(release.ID, 10), nil
 }
 
+// PublishRelease publishes the existing draft release with the given id.
+func (c *giteaClient) PublishRelease(ctx *context.Context, releaseID string) error {
+	id, err := strconv.ParseInt(releaseID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid release id %q: %w", releaseID, err)
+	}
+	draft := false
+	release, _, err := c.client.EditRelease(
+		ctx.Config.Release.Gitea.Owner,
+		ctx.Config.Release.Gitea.Name,
+		id,
+		gitea.EditReleaseOption{IsDraft: &draft},
+	)
+	if err != nil {
+		return fmt.Errorf("could not publish release: %w", err)
+	}
+	ctx.ReleaseURL = release.HTMLURL
+	return nil
+}
+
 func (c *giteaClient) ReleaseURLTemplate(ctx *context.Context) (string, error) {
 	downloadURL, err := tmpl.New(ctx).Apply(ctx.Config.GiteaURLs.Download)
 	if err != nil {
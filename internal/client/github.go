@@ -1,12 +1,13 @@
 package client
 
 import (
-	"crypto/tls"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
-	"reflect"
 	"strconv"
 	"strings"
 
@@ -32,20 +33,19 @@ func NewGitHub(ctx *context.Context, token string) (GitHubClient, error) {
 	)
 
 	httpClient := oauth2.NewClient(ctx, ts)
-	base := httpClient.Transport.(*oauth2.Transport).Base
-	if base == nil || reflect.ValueOf(base).IsNil() {
-		base = http.DefaultTransport
-	}
-	// nolint: gosec
-	base.(*http.Transport).TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: ctx.Config.GitHubURLs.SkipTLSVerify,
+	tlsConfig, err := newTLSConfig(ctx.Config.GitHubURLs.SkipTLSVerify, ctx.Config.GitHubURLs.CACert)
+	if err != nil {
+		return &githubClient{}, err
 	}
-	base.(*http.Transport).Proxy = http.ProxyFromEnvironment
+	// clone the default transport so the TLS settings don't leak into
+	// every other client sharing it.
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = tlsConfig
+	base.Proxy = http.ProxyFromEnvironment
 	httpClient.Transport.(*oauth2.Transport).Base = base
 
 	client := github.NewClient(httpClient)
-	err := overrideGitHubClientAPI(ctx, client)
-	if err != nil {
+	if err := overrideGitHubClientAPI(ctx, client); err != nil {
 		return &githubClient{}, err
 	}
 
@@ -53,11 +53,15 @@ func NewGitHub(ctx *context.Context, token string) (GitHubClient, error) {
 }
 
 func (c *githubClient) GenerateReleaseNotes(ctx *context.Context, repo Repo, prev, current string) (string, error) {
-	notes, _, err := c.client.Repositories.GenerateReleaseNotes(ctx, repo.Owner, repo.Name, &github.GenerateNotesOptions{
+	notes, res, err := c.client.Repositories.GenerateReleaseNotes(ctx, repo.Owner, repo.Name, &github.GenerateNotesOptions{
 		TagName:         current,
 		PreviousTagName: github.String(prev),
 	})
 	if err != nil {
+		// older GitHub Enterprise versions don't have this API.
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			return "", fmt.Errorf("%w: %v", ErrNotImplemented, err)
+		}
 		return "", err
 	}
 	return notes.Body, err
@@ -89,6 +93,34 @@ func (c *githubClient) Changelog(ctx *context.Context, repo Repo, prev, current
 	return strings.Join(log, "\n"), nil
 }
 
+// CommitAuthors returns the GitHub usernames of the authors of the commits
+// between prev and current, indexed by their lowercased commit emails.
+func (c *githubClient) CommitAuthors(ctx *context.Context, repo Repo, prev, current string) (map[string]string, error) {
+	authors := map[string]string{}
+	opts := &github.ListOptions{PerPage: 100}
+
+	for {
+		result, resp, err := c.client.Repositories.CompareCommits(ctx, repo.Owner, repo.Name, prev, current, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range result.Commits {
+			email := commit.GetCommit().GetAuthor().GetEmail()
+			login := commit.GetAuthor().GetLogin()
+			if email == "" || login == "" {
+				continue
+			}
+			authors[strings.ToLower(email)] = login
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return authors, nil
+}
+
 // GetDefaultBranch returns the default branch of a github repo
 func (c *githubClient) GetDefaultBranch(ctx *context.Context, repo Repo) (string, error) {
 	p, res, err := c.client.Repositories.Get(ctx, repo.Owner, repo.Name)
@@ -103,6 +135,85 @@ func (c *githubClient) GetDefaultBranch(ctx *context.Context, repo Repo) (string
 	return p.GetDefaultBranch(), nil
 }
 
+// OpenPullRequest opens a pull request from head into base.
+// If either branch is not set, the repository default branch is used.
+func (c *githubClient) OpenPullRequest(ctx *context.Context, base, head Repo, title, body string) error {
+	var err error
+	if base.Branch == "" {
+		base.Branch, err = c.GetDefaultBranch(ctx, base)
+		if err != nil {
+			return fmt.Errorf("failed to get base branch: %w", err)
+		}
+	}
+	if head.Branch == "" {
+		head.Branch, err = c.GetDefaultBranch(ctx, head)
+		if err != nil {
+			return fmt.Errorf("failed to get head branch: %w", err)
+		}
+	}
+
+	headRef := head.Branch
+	if head.Owner != base.Owner {
+		headRef = head.Owner + ":" + head.Branch
+	}
+
+	log.WithField("base", base.String()+":"+base.Branch).
+		WithField("head", headRef).
+		Info("opening pull request")
+	pr, res, err := c.client.PullRequests.Create(ctx, base.Owner, base.Name, &github.NewPullRequest{
+		Title: github.String(title),
+		Head:  github.String(headRef),
+		Base:  github.String(base.Branch),
+		Body:  github.String(body),
+	})
+	if err != nil {
+		if res != nil && res.StatusCode == http.StatusUnprocessableEntity {
+			log.WithField("err", err.Error()).Warn("pull request validation failed, it might already exist")
+			return nil
+		}
+		return fmt.Errorf("failed to open pull request: %w", err)
+	}
+	log.WithField("url", pr.GetHTMLURL()).Info("pull request opened")
+	return nil
+}
+
+// GraphQL runs the given query against the GraphQL API, decoding the
+// returned data into result.
+func (c *githubClient) GraphQL(ctx *context.Context, query string, variables map[string]interface{}, result interface{}) error {
+	req, err := c.client.NewRequest(http.MethodPost, graphQLURL(c.client.BaseURL), map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return fmt.Errorf("failed new request: %w", err)
+	}
+
+	var response struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, req, &response); err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		var msgs []string
+		for _, e := range response.Errors {
+			msgs = append(msgs, e.Message)
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return json.Unmarshal(response.Data, result)
+}
+
+// graphQLURL returns the GraphQL endpoint for the given REST API URL.
+// GitHub Enterprise serves the REST API at /api/v3 and GraphQL at
+// /api/graphql, while github.com serves both at the root.
+func graphQLURL(api *url.URL) string {
+	return strings.TrimSuffix(strings.TrimSuffix(api.String(), "/"), "/v3") + "/graphql"
+}
+
 // CloseMilestone closes a given milestone.
 func (c *githubClient) CloseMilestone(ctx *context.Context, repo Repo, title string) error {
 	milestone, err := c.getMilestoneByTitle(ctx, repo, title)
@@ -128,6 +239,17 @@ func (c *githubClient) CloseMilestone(ctx *context.Context, repo Repo, title str
 	return err
 }
 
+// CreateMilestone creates an open milestone with the given title.
+func (c *githubClient) CreateMilestone(ctx *context.Context, repo Repo, title string) error {
+	_, _, err := c.client.Issues.CreateMilestone(
+		ctx,
+		repo.Owner,
+		repo.Name,
+		&github.Milestone{Title: github.String(title)},
+	)
+	return err
+}
+
 func (c *githubClient) CreateFile(
 	ctx *context.Context,
 	commitAuthor config.CommitAuthor,
@@ -222,6 +344,20 @@ func (c *githubClient) CreateRelease(ctx *context.Context, body string) (string,
 		Prerelease: github.Bool(ctx.PreRelease),
 	}
 
+	if latest := strings.TrimSpace(ctx.Config.Release.MakeLatest); latest != "" {
+		latest, err := tmpl.New(ctx).Apply(latest)
+		if err != nil {
+			return "", err
+		}
+		switch latest {
+		case "":
+		case "true", "false", "legacy":
+			data.MakeLatest = github.String(latest)
+		default:
+			return "", fmt.Errorf("invalid release.make_latest: %s, valid options are: true, false, legacy", latest)
+		}
+	}
+
 	if ctx.Config.Release.DiscussionCategoryName != "" {
 		data.DiscussionCategoryName = github.String(ctx.Config.Release.DiscussionCategoryName)
 	}
@@ -290,6 +426,54 @@ func (c *githubClient) updateRelease(ctx *context.Context, id int64, data *githu
 	return release, err
 }
 
+// PublishRelease publishes the existing draft release with the given id.
+func (c *githubClient) PublishRelease(ctx *context.Context, releaseID string) error {
+	id, err := strconv.ParseInt(releaseID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid release id %q: %w", releaseID, err)
+	}
+	release, err := c.updateRelease(ctx, id, &github.RepositoryRelease{
+		Draft: github.Bool(false),
+	})
+	if err != nil {
+		return fmt.Errorf("could not publish release: %w", err)
+	}
+	ctx.ReleaseURL = release.GetHTMLURL()
+	return nil
+}
+
+// DownloadReleaseAsset downloads the asset with the given name from the
+// release with the given id.
+func (c *githubClient) DownloadReleaseAsset(ctx *context.Context, releaseID, name string) (io.ReadCloser, error) {
+	id, err := strconv.ParseInt(releaseID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid release id %q: %w", releaseID, err)
+	}
+	owner := ctx.Config.Release.GitHub.Owner
+	repo := ctx.Config.Release.GitHub.Name
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		assets, resp, err := c.client.Repositories.ListReleaseAssets(ctx, owner, repo, id, opts)
+		if err != nil {
+			return nil, fmt.Errorf("could not list release assets: %w", err)
+		}
+		for _, asset := range assets {
+			if asset.GetName() != name {
+				continue
+			}
+			rc, _, err := c.client.Repositories.DownloadReleaseAsset(ctx, owner, repo, asset.GetID(), c.client.Client())
+			if err != nil {
+				return nil, fmt.Errorf("could not download %s: %w", name, err)
+			}
+			return rc, nil
+		}
+		if resp.NextPage == 0 {
+			return nil, fmt.Errorf("%s not found in release %s", name, releaseID)
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
 func (c *githubClient) ReleaseURLTemplate(ctx *context.Context) (string, error) {
 	downloadURL, err := tmpl.New(ctx).Apply(ctx.Config.GitHubURLs.Download)
 	if err != nil {
@@ -1,15 +1,19 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
 	"text/template"
 
+	"github.com/google/go-github/v50/github"
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
@@ -201,6 +205,111 @@ func TestGitHubCreateReleaseWrongNameTemplate(t *testing.T) {
 	require.EqualError(t, err, `template: tmpl:1: unclosed action`)
 }
 
+func TestGitHubCreateReleaseMakeLatest(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		latest   string
+		existing bool
+		expected *string
+	}{
+		{"default", "", false, nil},
+		{"true", "true", false, github.String("true")},
+		{"false", "false", false, github.String("false")},
+		{"legacy", "legacy", false, github.String("legacy")},
+		{"template", "{{ if .Prerelease }}false{{ else }}true{{ end }}", false, github.String("false")},
+		{"update", "false", true, github.String("false")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload github.RepositoryRelease
+			var requested bool
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+
+				if r.URL.Path == "/repos/someone/something/releases/tags/v1.0.0-rc1" {
+					if !tt.existing {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					fmt.Fprint(w, `{"id": 1, "tag_name": "v1.0.0-rc1"}`)
+					return
+				}
+
+				expectedMethod := http.MethodPost
+				expectedPath := "/repos/someone/something/releases"
+				if tt.existing {
+					expectedMethod = http.MethodPatch
+					expectedPath += "/1"
+				}
+				if r.Method == expectedMethod && r.URL.Path == expectedPath {
+					requested = true
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+					fmt.Fprint(w, `{"id": 1}`)
+					return
+				}
+
+				t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer srv.Close()
+
+			ctx := context.New(config.Project{
+				GitHubURLs: config.GitHubURLs{
+					API: srv.URL + "/",
+				},
+				Release: config.Release{
+					GitHub: config.Repo{
+						Owner: "someone",
+						Name:  "something",
+					},
+					NameTemplate: "{{ .Tag }}",
+					MakeLatest:   tt.latest,
+				},
+			})
+			ctx.Git.CurrentTag = "v1.0.0-rc1"
+			ctx.Semver.Prerelease = "rc1"
+			ctx.PreRelease = true
+
+			client, err := NewGitHub(ctx, "test-token")
+			require.NoError(t, err)
+
+			id, err := client.CreateRelease(ctx, "notes")
+			require.NoError(t, err)
+			require.Equal(t, "1", id)
+			require.True(t, requested)
+			require.Equal(t, tt.expected, payload.MakeLatest)
+			require.True(t, payload.GetPrerelease())
+		})
+	}
+}
+
+func TestGitHubCreateReleaseMakeLatestErrors(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Release: config.Release{
+				MakeLatest: "maybe",
+			},
+		})
+		client, err := NewGitHub(ctx, ctx.Token)
+		require.NoError(t, err)
+
+		_, err = client.CreateRelease(ctx, "")
+		require.EqualError(t, err, "invalid release.make_latest: maybe, valid options are: true, false, legacy")
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Release: config.Release{
+				MakeLatest: "{{ .Nope }}",
+			},
+		})
+		client, err := NewGitHub(ctx, ctx.Token)
+		require.NoError(t, err)
+
+		_, err = client.CreateRelease(ctx, "")
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
 func TestGithubGetDefaultBranch(t *testing.T) {
 	totalRequests := 0
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -292,6 +401,42 @@ func TestChangelog(t *testing.T) {
 	require.Equal(t, "6dcb09b5b57875f334f61aebed695e2e4193db5e: Fix all the bugs (@octocat)", log)
 }
 
+func TestCommitAuthors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		if r.URL.Path == "/repos/someone/something/compare/v1.0.0...v1.1.0" {
+			fmt.Fprint(w, `{"commits":[
+				{"sha":"1","commit":{"author":{"name":"Carlos","email":"Carlos@Example.com"}},"author":{"login":"caarlos0"}},
+				{"sha":"2","commit":{"author":{"name":"Bot","email":"bot@example.com"}}},
+				{"sha":"3","commit":{"author":{"name":"Octo","email":"octo@example.com"}},"author":{"login":"octocat"}}
+			]}`)
+			return
+		}
+		t.Errorf("unexpected request: %s", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{
+			API: srv.URL + "/",
+		},
+	})
+	client, err := NewGitHub(ctx, "test-token")
+	require.NoError(t, err)
+	repo := Repo{
+		Owner: "someone",
+		Name:  "something",
+	}
+
+	authors, err := client.CommitAuthors(ctx, repo, "v1.0.0", "v1.1.0")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"carlos@example.com": "caarlos0",
+		"octo@example.com":   "octocat",
+	}, authors)
+}
+
 func TestReleaseNotes(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer r.Body.Close()
@@ -353,3 +498,112 @@ func TestCloseMilestone(t *testing.T) {
 
 	require.NoError(t, client.CloseMilestone(ctx, repo, "v1.13.0"))
 }
+
+func TestOpenPullRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		if r.URL.Path == "/repos/someone/something" {
+			fmt.Fprint(w, `{"default_branch": "main"}`)
+			return
+		}
+
+		if r.URL.Path == "/repos/upstream/something/pulls" {
+			bts, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"title":"new version","head":"someone:main","base":"master","body":""}`, string(bts))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"html_url": "https://github.com/upstream/something/pull/1"}`)
+			return
+		}
+
+		t.Error("unexpected request: " + r.URL.Path)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{
+			API: srv.URL + "/",
+		},
+	})
+	client, err := NewGitHub(ctx, "test-token")
+	require.NoError(t, err)
+
+	base := Repo{
+		Owner:  "upstream",
+		Name:   "something",
+		Branch: "master",
+	}
+	head := Repo{
+		Owner: "someone",
+		Name:  "something",
+	}
+	require.NoError(t, client.(PullRequestOpener).OpenPullRequest(ctx, base, head, "new version", ""))
+}
+
+func TestGraphQL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+
+		if r.URL.Path == "/api/graphql" {
+			require.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+			bts, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.JSONEq(t, `{"query":"query { viewer { login } }","variables":{"foo":"bar"}}`, string(bts))
+			fmt.Fprint(w, `{"data":{"viewer":{"login":"someone"}}}`)
+			return
+		}
+
+		t.Error("unexpected request: " + r.URL.Path)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{
+			API: srv.URL + "/api/v3/",
+		},
+	})
+	client, err := NewGitHub(ctx, "test-token")
+	require.NoError(t, err)
+
+	var result struct {
+		Viewer struct {
+			Login string `json:"login"`
+		} `json:"viewer"`
+	}
+	require.NoError(t, client.(GraphQLClient).GraphQL(ctx, "query { viewer { login } }", map[string]interface{}{"foo": "bar"}, &result))
+	require.Equal(t, "someone", result.Viewer.Login)
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		fmt.Fprint(w, `{"errors":[{"message":"foo"},{"message":"bar"}]}`)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{
+			API: srv.URL + "/",
+		},
+	})
+	client, err := NewGitHub(ctx, "test-token")
+	require.NoError(t, err)
+
+	var result struct{}
+	require.EqualError(t, client.(GraphQLClient).GraphQL(ctx, "query { viewer { login } }", nil, &result), "foo; bar")
+}
+
+func TestGraphQLURL(t *testing.T) {
+	for api, expected := range map[string]string{
+		"https://api.github.com/":            "https://api.github.com/graphql",
+		"https://github.example.com/api/v3/": "https://github.example.com/api/graphql",
+		"https://github.example.com/api/v3":  "https://github.example.com/api/graphql",
+	} {
+		t.Run(api, func(t *testing.T) {
+			u, err := url.Parse(api)
+			require.NoError(t, err)
+			require.Equal(t, expected, graphQLURL(u))
+		})
+	}
+}
@@ -1,11 +1,11 @@
 package client
 
 import (
-	"crypto/tls"
 	"fmt"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/caarlos0/log"
@@ -24,12 +24,13 @@ type gitlabClient struct {
 
 // NewGitLab returns a gitlab client implementation.
 func NewGitLab(ctx *context.Context, token string) (Client, error) {
+	tlsConfig, err := newTLSConfig(ctx.Config.GitLabURLs.SkipTLSVerify, ctx.Config.GitLabURLs.CACert)
+	if err != nil {
+		return &gitlabClient{}, err
+	}
 	transport := &http.Transport{
-		Proxy: http.ProxyFromEnvironment,
-		TLSClientConfig: &tls.Config{
-			// nolint: gosec
-			InsecureSkipVerify: ctx.Config.GitLabURLs.SkipTLSVerify,
-		},
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: tlsConfig,
 	}
 	options := []gitlab.ClientOptionFunc{
 		gitlab.WithHTTPClient(&http.Client{
@@ -46,7 +47,6 @@ func NewGitLab(ctx *context.Context, token string) (Client, error) {
 	}
 
 	var client *gitlab.Client
-	var err error
 	if checkUseJobToken(*ctx, token) {
 		client, err = gitlab.NewJobClient(token, options...)
 	} else {
@@ -126,6 +126,15 @@ func (c *gitlabClient) CloseMilestone(ctx *context.Context, repo Repo, title str
 	return err
 }
 
+// CreateMilestone creates an active milestone with the given title.
+func (c *gitlabClient) CreateMilestone(ctx *context.Context, repo Repo, title string) error {
+	_, _, err := c.client.Milestones.CreateMilestone(
+		repo.String(),
+		&gitlab.CreateMilestoneOptions{Title: &title},
+	)
+	return err
+}
+
 // CreateFile gets a file in the repository at a given path
 // and updates if it exists or creates it for later pipes in the pipeline.
 func (c *gitlabClient) CreateFile(
@@ -374,6 +383,11 @@ func (c *gitlabClient) Upload(
 	artifact *artifact.Artifact,
 	file *os.File,
 ) error {
+	linkType, err := linkTypeFor(ctx.Config.Release.GitLabLinkTypes, artifact.Name)
+	if err != nil {
+		return err
+	}
+
 	// create new template and apply name field
 	gitlabName, err := tmpl.New(ctx).Apply(ctx.Config.Release.GitLab.Name)
 	if err != nil {
@@ -450,6 +464,7 @@ func (c *gitlabClient) Upload(
 			Name:     &name,
 			URL:      &linkURL,
 			FilePath: &filename,
+			LinkType: gitlab.LinkType(linkType),
 		})
 	if err != nil {
 		return RetriableError{err}
@@ -516,3 +531,73 @@ func checkUseJobToken(ctx context.Context, token string) bool {
 	}
 	return false
 }
+
+// ValidateGitLabLinkTypes checks the patterns and link types of the given
+// release.gitlab_link_types setting.
+func ValidateGitLabLinkTypes(types map[string]string) error {
+	for _, pattern := range sortedPatterns(types) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid gitlab link type pattern %q: %w", pattern, err)
+		}
+		switch linkType := gitlab.LinkTypeValue(types[pattern]); linkType {
+		case gitlab.OtherLinkType, gitlab.PackageLinkType, gitlab.ImageLinkType, gitlab.RunbookLinkType:
+		default:
+			return fmt.Errorf("invalid gitlab link type %q for %q, valid options are: other, package, image, runbook", linkType, pattern)
+		}
+	}
+	return nil
+}
+
+// linkTypeFor returns the release link type for the given asset name, based
+// on the patterns in the given map that match it.
+// When several patterns match, the most specific one wins, i.e. the one with
+// the most literal characters, e.g. `foo_*.tar.gz` wins over `*.tar.gz`.
+// Ties go to the first one in alphabetical order.
+// Defaults to "other".
+// The given types are expected to be validated with ValidateGitLabLinkTypes.
+func linkTypeFor(types map[string]string, name string) (gitlab.LinkTypeValue, error) {
+	linkType := gitlab.OtherLinkType
+	best := -1
+	for _, pattern := range sortedPatterns(types) {
+		ok, err := filepath.Match(pattern, name)
+		if err != nil {
+			return "", fmt.Errorf("invalid gitlab link type pattern %q: %w", pattern, err)
+		}
+		if score := patternScore(pattern); ok && score > best {
+			best = score
+			linkType = gitlab.LinkTypeValue(types[pattern])
+		}
+	}
+	return linkType, nil
+}
+
+// patternScore returns the number of literal characters in the given
+// pattern.
+// Wildcards and character classes don't count.
+func patternScore(pattern string) int {
+	score := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '*', '?':
+		case '[':
+			for i < len(pattern) && pattern[i] != ']' {
+				i++
+			}
+		case '\\':
+			i++
+			score++
+		default:
+			score++
+		}
+	}
+	return score
+}
+
+func sortedPatterns(types map[string]string) []string {
+	patterns := make([]string, 0, len(types))
+	for pattern := range types {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
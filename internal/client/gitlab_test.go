@@ -255,6 +255,106 @@ func TestGitLabURLsDownloadTemplate(t *testing.T) {
 	}
 }
 
+func TestGitLabUploadLinkType(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		types    map[string]string
+		artifact string
+		expected string
+	}{
+		{"default", nil, "foo.tar.gz", "other"},
+		{"no match", map[string]string{"*.deb": "package"}, "foo.tar.gz", "other"},
+		{"package", map[string]string{"*.deb": "package", "*.rpm": "package"}, "foo.rpm", "package"},
+		{"image", map[string]string{"*.sbom": "other", "*.tar": "image"}, "foo.tar", "image"},
+		{"most specific pattern wins", map[string]string{"*": "package", "foo*.md": "runbook", "*.md": "other"}, "foo.md", "runbook"},
+		{"most specific pattern wins regardless of order", map[string]string{"*.tar.gz": "package", "a*": "image"}, "a.tar.gz", "package"},
+		{"ties go to the first sorted pattern", map[string]string{"foo*": "runbook", "*.md": "other"}, "foo.md", "other"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var linkType string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				defer r.Body.Close()
+
+				if !strings.Contains(r.URL.Path, "assets/links") {
+					_, _ = io.Copy(io.Discard, r.Body)
+					fmt.Fprint(w, "{}")
+					return
+				}
+
+				reqBody := map[string]interface{}{}
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+				linkType, _ = reqBody["link_type"].(string)
+				fmt.Fprint(w, "{}")
+			}))
+			defer srv.Close()
+
+			ctx := context.New(config.Project{
+				ProjectName: "projectname",
+				Release: config.Release{
+					GitLab: config.Repo{
+						Owner: "test",
+						Name:  "test",
+					},
+					GitLabLinkTypes: tt.types,
+				},
+				GitLabURLs: config.GitLabURLs{
+					API: srv.URL,
+				},
+			})
+			ctx.Version = "v1.0.0"
+
+			tmpFile, err := os.CreateTemp(t.TempDir(), "")
+			require.NoError(t, err)
+
+			client, err := NewGitLab(ctx, ctx.Token)
+			require.NoError(t, err)
+
+			require.NoError(t, client.Upload(ctx, "1234", &artifact.Artifact{Name: tt.artifact, Path: "some-path"}, tmpFile))
+			require.Equal(t, tt.expected, linkType)
+		})
+	}
+}
+
+func TestPatternScore(t *testing.T) {
+	for pattern, expected := range map[string]int{
+		"*":            0,
+		"*.deb":        4,
+		"foo_*.tar.gz": 11,
+		"foo?.md":      6,
+		"foo[0-9].md":  6,
+		`foo\*.md`:     7,
+	} {
+		t.Run(pattern, func(t *testing.T) {
+			require.Equal(t, expected, patternScore(pattern))
+		})
+	}
+}
+
+func TestValidateGitLabLinkTypes(t *testing.T) {
+	for name, tt := range map[string]struct {
+		types map[string]string
+		err   string
+	}{
+		"invalid type": {
+			types: map[string]string{"*.tar.gz": "binary"},
+			err:   `invalid gitlab link type "binary" for "*.tar.gz", valid options are: other, package, image, runbook`,
+		},
+		"invalid pattern": {
+			types: map[string]string{"[": "package"},
+			err:   `invalid gitlab link type pattern "[": syntax error in pattern`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.EqualError(t, ValidateGitLabLinkTypes(tt.types), tt.err)
+		})
+	}
+
+	require.NoError(t, ValidateGitLabLinkTypes(map[string]string{
+		"*.deb": "package",
+		"*.png": "image",
+	}))
+}
+
 func TestGitLabCreateReleaseUknownHost(t *testing.T) {
 	ctx := context.New(config.Project{
 		Release: config.Release{
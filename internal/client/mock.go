@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 	"sync"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -13,8 +14,11 @@ import (
 )
 
 var (
-	_ Client       = &Mock{}
-	_ GitHubClient = &Mock{}
+	_ Client                 = &Mock{}
+	_ GitHubClient           = &Mock{}
+	_ PullRequestOpener      = &Mock{}
+	_ ReleasePublisher       = &Mock{}
+	_ ReleaseAssetDownloader = &Mock{}
 )
 
 func NewMock() *Mock {
@@ -22,22 +26,35 @@ func NewMock() *Mock {
 }
 
 type Mock struct {
-	CreatedFile          bool
-	Content              string
-	Path                 string
-	FailToCreateRelease  bool
-	FailToUpload         bool
-	CreatedRelease       bool
-	UploadedFile         bool
-	UploadedFileNames    []string
-	UploadedFilePaths    map[string]string
-	FailFirstUpload      bool
-	Lock                 sync.Mutex
-	ClosedMilestone      string
-	FailToCloseMilestone bool
-	Changes              string
-	ReleaseNotes         string
-	ReleaseNotesParams   []string
+	CreatedFile           bool
+	Content               string
+	Path                  string
+	CommitAuthor          config.CommitAuthor
+	CommitMessage         string
+	FailToCreateRelease   bool
+	FailToUpload          bool
+	CreatedRelease        bool
+	UploadedFile          bool
+	UploadedFileNames     []string
+	UploadedFilePaths     map[string]string
+	FailFirstUpload       bool
+	Lock                  sync.Mutex
+	ClosedMilestone       string
+	FailToCloseMilestone  bool
+	MissingMilestone      bool
+	CreatedMilestone      string
+	FailToCreateMilestone bool
+	Changes               string
+	ReleaseNotes          string
+	ReleaseNotesParams    []string
+	Authors               map[string]string
+	OpenedPullRequest     bool
+	PullRequestBase       Repo
+	PullRequestHead       Repo
+	PullRequestTitle      string
+	PublishedReleaseID    string
+	FailToPublishRelease  bool
+	ReleaseAssets         map[string]string
 }
 
 func (c *Mock) Changelog(ctx *context.Context, repo Repo, prev, current string) (string, error) {
@@ -55,16 +72,37 @@ func (c *Mock) GenerateReleaseNotes(ctx *context.Context, repo Repo, prev, curre
 	return "", ErrNotImplemented
 }
 
+func (c *Mock) CommitAuthors(ctx *context.Context, repo Repo, prev, current string) (map[string]string, error) {
+	if c.Authors != nil {
+		return c.Authors, nil
+	}
+	return nil, ErrNotImplemented
+}
+
 func (c *Mock) CloseMilestone(ctx *context.Context, repo Repo, title string) error {
 	if c.FailToCloseMilestone {
 		return errors.New("milestone failed")
 	}
 
+	if c.MissingMilestone && c.CreatedMilestone != title {
+		return ErrNoMilestoneFound{Title: title}
+	}
+
 	c.ClosedMilestone = title
 
 	return nil
 }
 
+func (c *Mock) CreateMilestone(ctx *context.Context, repo Repo, title string) error {
+	if c.FailToCreateMilestone {
+		return errors.New("failed to create milestone")
+	}
+
+	c.CreatedMilestone = title
+
+	return nil
+}
+
 func (c *Mock) GetDefaultBranch(ctx *context.Context, repo Repo) (string, error) {
 	return "", ErrNotImplemented
 }
@@ -85,9 +123,35 @@ func (c *Mock) CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor
 	c.CreatedFile = true
 	c.Content = string(content)
 	c.Path = path
+	c.CommitAuthor = commitAuthor
+	c.CommitMessage = msg
 	return nil
 }
 
+func (c *Mock) OpenPullRequest(ctx *context.Context, base, head Repo, title, body string) error {
+	c.OpenedPullRequest = true
+	c.PullRequestBase = base
+	c.PullRequestHead = head
+	c.PullRequestTitle = title
+	return nil
+}
+
+func (c *Mock) PublishRelease(ctx *context.Context, releaseID string) error {
+	if c.FailToPublishRelease {
+		return errors.New("release failed")
+	}
+	c.PublishedReleaseID = releaseID
+	return nil
+}
+
+func (c *Mock) DownloadReleaseAsset(ctx *context.Context, releaseID, name string) (io.ReadCloser, error) {
+	content, ok := c.ReleaseAssets[name]
+	if !ok {
+		return nil, fmt.Errorf("%s not found in release %s", name, releaseID)
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
 func (c *Mock) Upload(ctx *context.Context, releaseID string, artifact *artifact.Artifact, file *os.File) error {
 	c.Lock.Lock()
 	defer c.Lock.Unlock()
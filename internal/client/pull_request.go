@@ -0,0 +1,53 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// ErrPullRequestNotImplemented happens when a pull request should be opened,
+// but the current client can't open them.
+var ErrPullRequestNotImplemented = errors.New("the current scm client does not support opening pull requests")
+
+// ErrNoPullRequestBase happens when the pull request base is only partially
+// set.
+var ErrNoPullRequestBase = errors.New("pull_request.base requires both owner and name")
+
+// DefaultPullRequestBase sets the given owner and name as the base of the
+// given pull request, if it is enabled and has no base set.
+func DefaultPullRequestBase(pr *config.PullRequest, owner, name string) error {
+	if !pr.Enabled {
+		return nil
+	}
+	if pr.Base.Owner == "" && pr.Base.Name == "" {
+		pr.Base.Owner = owner
+		pr.Base.Name = name
+	}
+	if pr.Base.Owner == "" || pr.Base.Name == "" {
+		return ErrNoPullRequestBase
+	}
+	return nil
+}
+
+// OpenPullRequest opens a pull request from the given head into the
+// templated base of the given pull request, if it is enabled.
+func OpenPullRequest(ctx *context.Context, cli Client, pr config.PullRequest, head Repo, title string) error {
+	if !pr.Enabled {
+		return nil
+	}
+
+	opener, ok := cli.(PullRequestOpener)
+	if !ok {
+		return ErrPullRequestNotImplemented
+	}
+
+	base, err := TemplateRef(tmpl.New(ctx).Apply, pr.Base)
+	if err != nil {
+		return err
+	}
+
+	return opener.OpenPullRequest(ctx, RepoFromRef(base), head, title, "")
+}
@@ -0,0 +1,81 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultPullRequestBase(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		pr := config.PullRequest{}
+		require.NoError(t, DefaultPullRequestBase(&pr, "foo", "bar"))
+		require.Empty(t, pr.Base)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		pr := config.PullRequest{Enabled: true}
+		require.NoError(t, DefaultPullRequestBase(&pr, "foo", "bar"))
+		require.Equal(t, config.RepoRef{Owner: "foo", Name: "bar"}, pr.Base)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		pr := config.PullRequest{
+			Enabled: true,
+			Base:    config.RepoRef{Owner: "me", Name: "fork", Branch: "main"},
+		}
+		require.NoError(t, DefaultPullRequestBase(&pr, "foo", "bar"))
+		require.Equal(t, config.RepoRef{Owner: "me", Name: "fork", Branch: "main"}, pr.Base)
+	})
+
+	t.Run("partially set", func(t *testing.T) {
+		pr := config.PullRequest{
+			Enabled: true,
+			Base:    config.RepoRef{Owner: "me"},
+		}
+		require.ErrorIs(t, DefaultPullRequestBase(&pr, "foo", "bar"), ErrNoPullRequestBase)
+	})
+}
+
+func TestOpenPullRequest(t *testing.T) {
+	ctx := context.New(config.Project{ProjectName: "foo"})
+	pr := config.PullRequest{
+		Enabled: true,
+		Base: config.RepoRef{
+			Owner:  "{{ .ProjectName }}",
+			Name:   "index",
+			Branch: "main",
+		},
+	}
+	head := Repo{Owner: "me", Name: "index"}
+
+	t.Run("enabled", func(t *testing.T) {
+		cli := NewMock()
+		require.NoError(t, OpenPullRequest(ctx, cli, pr, head, "title"))
+		require.True(t, cli.OpenedPullRequest)
+		require.Equal(t, Repo{Owner: "foo", Name: "index", Branch: "main"}, cli.PullRequestBase)
+		require.Equal(t, head, cli.PullRequestHead)
+		require.Equal(t, "title", cli.PullRequestTitle)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cli := NewMock()
+		require.NoError(t, OpenPullRequest(ctx, cli, config.PullRequest{}, head, "title"))
+		require.False(t, cli.OpenedPullRequest)
+	})
+
+	t.Run("not implemented", func(t *testing.T) {
+		cli := struct{ Client }{NewMock()}
+		require.ErrorIs(t, OpenPullRequest(ctx, cli, pr, head, "title"), ErrPullRequestNotImplemented)
+	})
+
+	t.Run("invalid base template", func(t *testing.T) {
+		pr := pr
+		pr.Base.Owner = "{{ .Nope }"
+		cli := NewMock()
+		require.Error(t, OpenPullRequest(ctx, cli, pr, head, "title"))
+		require.False(t, cli.OpenedPullRequest)
+	})
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/caarlos0/log"
+)
+
+// insecureWarning makes sure the TLS verification warning is logged only once,
+// as clients are created many times during a release.
+var insecureWarning sync.Once
+
+// newTLSConfig creates the TLS configuration used by the SCM clients,
+// optionally trusting the certificates in the given PEM file on top of the
+// system ones.
+func newTLSConfig(skipVerify bool, caCert string) (*tls.Config, error) {
+	if skipVerify {
+		insecureWarning.Do(func() {
+			log.Warn("TLS certificate verification is disabled, your connection is insecure and susceptible to man-in-the-middle attacks")
+		})
+	}
+
+	// nolint: gosec
+	cfg := &tls.Config{
+		InsecureSkipVerify: skipVerify,
+	}
+	if caCert == "" {
+		return cfg, nil
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	bts, err := os.ReadFile(caCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ca_cert: %w", err)
+	}
+	if !pool.AppendCertsFromPEM(bts) {
+		return nil, fmt.Errorf("failed to read ca_cert: no valid certificates found in %s", caCert)
+	}
+	cfg.RootCAs = pool
+	return cfg, nil
+}
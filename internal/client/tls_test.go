@@ -0,0 +1,178 @@
+package client
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func newTLSServer(tb testing.TB) (*httptest.Server, string) {
+	tb.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		if strings.HasSuffix(r.URL.Path, "api/v1/version") {
+			fmt.Fprint(w, "{\"version\":\"1.12.0\"}")
+			return
+		}
+		fmt.Fprint(w, "{}")
+	}))
+	tb.Cleanup(srv.Close)
+
+	path := filepath.Join(tb.TempDir(), "ca.pem")
+	require.NoError(tb, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}), 0o644))
+	return srv, path
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		cfg, err := newTLSConfig(false, "")
+		require.NoError(t, err)
+		require.False(t, cfg.InsecureSkipVerify)
+		require.Nil(t, cfg.RootCAs)
+	})
+
+	t.Run("skip verify", func(t *testing.T) {
+		cfg, err := newTLSConfig(true, "")
+		require.NoError(t, err)
+		require.True(t, cfg.InsecureSkipVerify)
+	})
+
+	t.Run("missing ca cert", func(t *testing.T) {
+		_, err := newTLSConfig(false, filepath.Join(t.TempDir(), "nope.pem"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("invalid ca cert", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(path, []byte("not a cert"), 0o644))
+		_, err := newTLSConfig(false, path)
+		require.EqualError(t, err, "failed to read ca_cert: no valid certificates found in "+path)
+	})
+}
+
+func TestGiteaCustomCA(t *testing.T) {
+	srv, ca := newTLSServer(t)
+
+	t.Run("with ca", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			GiteaURLs: config.GiteaURLs{
+				API:    srv.URL,
+				CACert: ca,
+			},
+		})
+		_, err := NewGitea(ctx, "test-token")
+		require.NoError(t, err)
+	})
+
+	t.Run("without ca", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			GiteaURLs: config.GiteaURLs{
+				API: srv.URL,
+			},
+		})
+		_, err := NewGitea(ctx, "test-token")
+		require.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("skip verify", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			GiteaURLs: config.GiteaURLs{
+				API:           srv.URL,
+				SkipTLSVerify: true,
+			},
+		})
+		_, err := NewGitea(ctx, "test-token")
+		require.NoError(t, err)
+	})
+}
+
+func TestGitLabCustomCA(t *testing.T) {
+	srv, ca := newTLSServer(t)
+	repo := Repo{Owner: "someone", Name: "something"}
+
+	t.Run("with ca", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			GitLabURLs: config.GitLabURLs{
+				API:    srv.URL,
+				CACert: ca,
+			},
+		})
+		client, err := NewGitLab(ctx, "test-token")
+		require.NoError(t, err)
+		_, err = client.Changelog(ctx, repo, "v1.0.0", "v1.1.0")
+		require.NoError(t, err)
+	})
+
+	t.Run("without ca", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			GitLabURLs: config.GitLabURLs{
+				API: srv.URL,
+			},
+		})
+		client, err := NewGitLab(ctx, "test-token")
+		require.NoError(t, err)
+		_, err = client.Changelog(ctx, repo, "v1.0.0", "v1.1.0")
+		require.ErrorContains(t, err, "certificate")
+	})
+}
+
+func TestGitHubCustomCA(t *testing.T) {
+	srv, ca := newTLSServer(t)
+	repo := Repo{Owner: "someone", Name: "something"}
+
+	t.Run("without ca", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			GitHubURLs: config.GitHubURLs{
+				API: srv.URL + "/",
+			},
+		})
+		client, err := NewGitHub(ctx, "test-token")
+		require.NoError(t, err)
+		_, err = client.Changelog(ctx, repo, "v1.0.0", "v1.1.0")
+		require.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("with ca", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			GitHubURLs: config.GitHubURLs{
+				API:    srv.URL + "/",
+				CACert: ca,
+			},
+		})
+		client, err := NewGitHub(ctx, "test-token")
+		require.NoError(t, err)
+		_, err = client.Changelog(ctx, repo, "v1.0.0", "v1.1.0")
+		require.NoError(t, err)
+	})
+}
+
+func TestNewClientsInvalidCA(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nope.pem")
+
+	_, err := NewGitHub(context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{CACert: path},
+	}), "test-token")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = NewGitLab(context.New(config.Project{
+		GitLabURLs: config.GitLabURLs{CACert: path},
+	}), "test-token")
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = NewGitea(context.New(config.Project{
+		GiteaURLs: config.GiteaURLs{API: "https://gitea.com", CACert: path},
+	}), "test-token")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -22,6 +22,15 @@ func Get(ctx *context.Context, og config.CommitAuthor) (config.CommitAuthor, err
 		return author, err
 	}
 	author.Email, err = tmpl.New(ctx).Apply(og.Email)
+	if err != nil {
+		return author, err
+	}
+	author.Signing = og.Signing
+	author.Signing.Key, err = tmpl.New(ctx).Apply(og.Signing.Key)
+	if err != nil {
+		return author, err
+	}
+	author.Signing.Passphrase, err = tmpl.New(ctx).Apply(og.Signing.Passphrase)
 	return author, err
 }
 
@@ -23,6 +23,54 @@ func TestGet(t *testing.T) {
 		}, author)
 	})
 
+	t.Run("signing", func(t *testing.T) {
+		author, err := Get(context.New(config.Project{
+			Env: []string{"KEY=ABCDEF", "PASSPHRASE=secret"},
+		}), config.CommitAuthor{
+			Name:  "foo",
+			Email: "foo@bar",
+			Signing: config.CommitSigning{
+				Enabled:    true,
+				Key:        "{{.Env.KEY}}",
+				Program:    "gpg2",
+				Passphrase: "{{.Env.PASSPHRASE}}",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, config.CommitSigning{
+			Enabled:    true,
+			Key:        "ABCDEF",
+			Program:    "gpg2",
+			Passphrase: "secret",
+		}, author.Signing)
+	})
+
+	t.Run("invalid signing key tmpl", func(t *testing.T) {
+		_, err := Get(
+			context.New(config.Project{}),
+			config.CommitAuthor{
+				Name:  "a",
+				Email: "a",
+				Signing: config.CommitSigning{
+					Key: "{{.Env.NOPE}}",
+				},
+			})
+		require.Error(t, err)
+	})
+
+	t.Run("invalid signing passphrase tmpl", func(t *testing.T) {
+		_, err := Get(
+			context.New(config.Project{}),
+			config.CommitAuthor{
+				Name:  "a",
+				Email: "a",
+				Signing: config.CommitSigning{
+					Passphrase: "{{.Env.NOPE}}",
+				},
+			})
+		require.Error(t, err)
+	})
+
 	t.Run("invalid name tmpl", func(t *testing.T) {
 		_, err := Get(
 			context.New(config.Project{}),
@@ -17,6 +17,12 @@ func IsRepo(ctx context.Context) bool {
 	return err == nil && strings.TrimSpace(out) == "true"
 }
 
+// IsShallow returns true if current folder is a shallow clone.
+func IsShallow(ctx context.Context) bool {
+	out, err := Run(ctx, "rev-parse", "--is-shallow-repository")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
 func RunWithEnv(ctx context.Context, env []string, args ...string) (string, error) {
 	extraArgs := []string{
 		"-c", "log.showSignature=false",
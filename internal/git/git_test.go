@@ -55,6 +55,18 @@ func TestRepo(t *testing.T) {
 	require.False(t, git.IsRepo(ctx), os.TempDir()+" folder should be a git repo")
 }
 
+func TestShallow(t *testing.T) {
+	ctx := context.Background()
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitCommit(t, "second")
+	require.False(t, git.IsShallow(ctx))
+
+	testlib.GitShallowClone(t, folder)
+	require.True(t, git.IsShallow(ctx))
+}
+
 func TestClean(t *testing.T) {
 	ctx := context.Background()
 
@@ -2,12 +2,14 @@
 package http
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
 	h "net/http"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -25,6 +27,9 @@ const (
 	ModeBinary = "binary"
 	// ModeArchive uploads release archives.
 	ModeArchive = "archive"
+
+	// partRetries is how many times a part of a chunked upload is tried.
+	partRetries = 3
 )
 
 type asset struct {
@@ -112,6 +117,20 @@ func CheckConfig(ctx *context.Context, upload *config.Upload, kind string) error
 		return misconfigured(kind, upload, "no certificate could be added from the specified trusted_certificates configuration")
 	}
 
+	if upload.PartSize < 0 {
+		return misconfigured(kind, upload, "'part_size' must be positive")
+	}
+
+	if _, err := filepath.Match(upload.Glob, ""); err != nil {
+		return misconfigured(kind, upload, fmt.Sprintf("invalid glob %q", upload.Glob))
+	}
+
+	for name, algorithm := range upload.ChecksumHeaders {
+		if err := artifact.ValidateChecksumAlgorithm(algorithm); err != nil {
+			return misconfigured(kind, upload, fmt.Sprintf("invalid algorithm %q for checksum header %q", algorithm, name))
+		}
+	}
+
 	if upload.ClientX509Cert != "" && upload.ClientX509Key == "" {
 		return misconfigured(kind, upload, "'client_x509_key' must be set when 'client_x509_cert' is set")
 	}
@@ -157,41 +176,10 @@ func Upload(ctx *context.Context, uploads []config.Upload, kind string, check Re
 	// Handle every configured upload
 	for _, upload := range uploads {
 		upload := upload
-		filters := []artifact.Filter{}
-		if upload.Checksum {
-			filters = append(filters, artifact.ByType(artifact.Checksum))
-		}
-		if upload.Signature {
-			filters = append(filters, artifact.ByType(artifact.Signature), artifact.ByType(artifact.Certificate))
-		}
-		// We support two different modes
-		//	- "archive": Upload all artifacts
-		//	- "binary": Upload only the raw binaries
-		switch v := strings.ToLower(upload.Mode); v {
-		case ModeArchive:
-			// TODO: should we add source archives here too?
-			filters = append(filters,
-				artifact.ByType(artifact.UploadableArchive),
-				artifact.ByType(artifact.LinuxPackage),
-			)
-		case ModeBinary:
-			filters = append(filters, artifact.ByType(artifact.UploadableBinary))
-		default:
-			err := fmt.Errorf("%s: mode \"%s\" not supported", kind, v)
-			log.WithFields(log.Fields{
-				kind:   upload.Name,
-				"mode": v,
-			}).Error(err.Error())
+		filter, err := filterFor(&upload, kind)
+		if err != nil {
 			return err
 		}
-
-		filter := artifact.Or(filters...)
-		if len(upload.IDs) > 0 {
-			filter = artifact.And(filter, artifact.ByIDs(upload.IDs...))
-		}
-		if len(upload.Exts) > 0 {
-			filter = artifact.And(filter, artifact.ByExt(upload.Exts...))
-		}
 		if err := uploadWithFilter(ctx, &upload, filter, kind, check); err != nil {
 			return err
 		}
@@ -200,6 +188,72 @@ func Upload(ctx *context.Context, uploads []config.Upload, kind string, check Re
 	return nil
 }
 
+// filterFor returns the filter selecting the artifacts of the given upload.
+func filterFor(upload *config.Upload, kind string) (artifact.Filter, error) {
+	filters := []artifact.Filter{}
+	if upload.Checksum {
+		filters = append(filters, artifact.ByType(artifact.Checksum))
+	}
+	if upload.Signature {
+		filters = append(filters, artifact.ByType(artifact.Signature), artifact.ByType(artifact.Certificate))
+	}
+	// We support two different modes
+	//	- "archive": Upload all artifacts
+	//	- "binary": Upload only the raw binaries
+	switch v := strings.ToLower(upload.Mode); v {
+	case ModeArchive:
+		// TODO: should we add source archives here too?
+		filters = append(filters,
+			artifact.ByType(artifact.UploadableArchive),
+			artifact.ByType(artifact.LinuxPackage),
+		)
+	case ModeBinary:
+		filters = append(filters, artifact.ByType(artifact.UploadableBinary))
+	default:
+		err := fmt.Errorf("%s: mode \"%s\" not supported", kind, v)
+		log.WithFields(log.Fields{
+			kind:   upload.Name,
+			"mode": v,
+		}).Error(err.Error())
+		return nil, err
+	}
+	// other uploadable files, like installers, are only uploaded when
+	// explicitly selected by their extension or name.
+	if len(upload.Exts) > 0 || upload.Glob != "" {
+		filters = append(filters, artifact.ByType(artifact.UploadableFile))
+	}
+
+	filter := artifact.Or(filters...)
+	if len(upload.IDs) > 0 {
+		filter = artifact.And(filter, artifact.ByIDs(upload.IDs...))
+	}
+	if len(upload.Exts) > 0 {
+		filter = artifact.And(filter, artifact.ByExt(upload.Exts...))
+	}
+	if len(upload.Goos) > 0 {
+		goos := make([]artifact.Filter, 0, len(upload.Goos))
+		for _, s := range upload.Goos {
+			goos = append(goos, artifact.ByGoos(s))
+		}
+		filter = artifact.And(filter, artifact.Or(goos...))
+	}
+	if upload.Glob != "" {
+		if _, err := filepath.Match(upload.Glob, ""); err != nil {
+			return nil, fmt.Errorf("%s: invalid glob %q: %w", kind, upload.Glob, err)
+		}
+		filter = artifact.And(filter, byGlob(upload.Glob))
+	}
+	return filter, nil
+}
+
+// byGlob filters the artifacts whose name match the given glob.
+func byGlob(glob string) artifact.Filter {
+	return func(a *artifact.Artifact) bool {
+		ok, _ := filepath.Match(glob, a.Name)
+		return ok
+	}
+}
+
 func uploadWithFilter(ctx *context.Context, upload *config.Upload, filter artifact.Filter, kind string, check ResponseChecker) error {
 	artifacts := ctx.Artifacts.Filter(filter).List()
 	log.Debugf("will upload %d artifacts", len(artifacts))
@@ -268,6 +322,13 @@ func uploadAsset(ctx *context.Context, upload *config.Upload, artifact *artifact
 		}
 		headers[upload.ChecksumHeader] = sum
 	}
+	for name, algorithm := range upload.ChecksumHeaders {
+		sum, err := artifact.Checksum(algorithm)
+		if err != nil {
+			return err
+		}
+		headers[name] = sum
+	}
 
 	res, err := uploadAssetToServer(ctx, upload, targetURL, username, secret, headers, asset, check)
 	if err != nil {
@@ -291,6 +352,10 @@ func uploadAsset(ctx *context.Context, upload *config.Upload, artifact *artifact
 
 // uploadAssetToServer uploads the asset file to target.
 func uploadAssetToServer(ctx *context.Context, upload *config.Upload, target, username, secret string, headers map[string]string, a *asset, check ResponseChecker) (*h.Response, error) {
+	if upload.PartSize > 0 && a.Size > upload.PartSize {
+		return uploadAssetInParts(ctx, upload, target, username, secret, headers, a, check)
+	}
+
 	req, err := newUploadRequest(ctx, upload.Method, target, username, secret, headers, a)
 	if err != nil {
 		return nil, err
@@ -299,6 +364,73 @@ func uploadAssetToServer(ctx *context.Context, upload *config.Upload, target, us
 	return executeHTTPRequest(ctx, upload, req, check)
 }
 
+// uploadAssetInParts uploads the asset file to target in parts of at most
+// upload.PartSize bytes, each one with its Content-Range header.
+// Failed parts are retried up to partRetries times, without restarting the
+// whole upload.
+func uploadAssetInParts(ctx *context.Context, upload *config.Upload, target, username, secret string, headers map[string]string, a *asset, check ResponseChecker) (*h.Response, error) {
+	buf := make([]byte, upload.PartSize)
+	var res *h.Response
+	for start := int64(0); start < a.Size; start += upload.PartSize {
+		size := upload.PartSize
+		if rest := a.Size - start; rest < size {
+			size = rest
+		}
+		part := buf[:size]
+		if _, err := io.ReadFull(a.ReadCloser, part); err != nil {
+			return nil, fmt.Errorf("failed to read part: %w", err)
+		}
+
+		partHeaders := map[string]string{
+			"Content-Range": fmt.Sprintf("bytes %d-%d/%d", start, start+size-1, a.Size),
+		}
+		for k, v := range headers {
+			partHeaders[k] = v
+		}
+
+		var err error
+		res, err = uploadPart(ctx, upload, target, username, secret, partHeaders, part, check)
+		if err != nil {
+			return res, err
+		}
+		if start+size < a.Size {
+			if err := res.Body.Close(); err != nil {
+				log.WithError(err).Warn("failed to close response body")
+			}
+		}
+	}
+	return res, nil
+}
+
+// uploadPart uploads a single part of an asset, retrying it on failure.
+func uploadPart(ctx *context.Context, upload *config.Upload, target, username, secret string, headers map[string]string, part []byte, check ResponseChecker) (*h.Response, error) {
+	var err error
+	for try := 1; try <= partRetries; try++ {
+		var req *h.Request
+		req, err = newUploadRequest(ctx, upload.Method, target, username, secret, headers, &asset{
+			ReadCloser: io.NopCloser(bytes.NewReader(part)),
+			Size:       int64(len(part)),
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var res *h.Response
+		res, err = executeHTTPRequest(ctx, upload, req, check)
+		if err == nil {
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.WithError(err).WithFields(log.Fields{
+			"range": headers["Content-Range"],
+			"try":   try,
+		}).Warn("failed to upload part")
+	}
+	return nil, fmt.Errorf("failed to upload part %s after %d tries: %w", headers["Content-Range"], partRetries, err)
+}
+
 // newUploadRequest creates a new h.Request for uploading.
 func newUploadRequest(ctx *context.Context, method, target, username, secret string, headers map[string]string, a *asset) (*h.Request, error) {
 	req, err := h.NewRequestWithContext(ctx, method, target, a.ReadCloser)
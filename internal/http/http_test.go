@@ -102,6 +102,11 @@ func TestCheckConfig(t *testing.T) {
 		{"mode missing", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe"}, "test"}, true},
 		{"mode invalid", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: "blabla"}, "test"}, true},
 		{"cert invalid", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeBinary, TrustedCerts: "bad cert!"}, "test"}, true},
+		{"part size invalid", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeArchive, PartSize: -1}, "test"}, true},
+		{"glob", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeArchive, Glob: "*.msi"}, "test"}, false},
+		{"glob invalid", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeArchive, Glob: "[a-"}, "test"}, true},
+		{"checksum headers", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeBinary, ChecksumHeaders: map[string]string{"X-Checksum-Sha1": "sha1"}}, "test"}, false},
+		{"checksum headers invalid algorithm", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeBinary, ChecksumHeaders: map[string]string{"X-Checksum-Sha1": "nope"}}, "test"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -503,6 +508,45 @@ func TestUpload(t *testing.T) {
 			},
 			checks(check{"/blah/2.1.0/a.ubi", "u2", "x", content, map[string]string{"-x-sha256": "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269"}}),
 		},
+		{
+			"checksumheaders", true, true, false, false,
+			func(s *httptest.Server) (*context.Context, config.Upload) {
+				return ctx, config.Upload{
+					Mode:           ModeBinary,
+					Name:           "a",
+					Target:         s.URL + "/myrepo/{{ .Version }}/{{ .Os }}/{{ .Arch }}{{ if .Arm }}v{{ .Arm }}{{ end }}/",
+					Username:       "u2",
+					ChecksumHeader: "X-Checksum-Sha256",
+					ChecksumHeaders: map[string]string{
+						"X-Checksum-Sha1": "sha1",
+						"X-Checksum-Md5":  "md5",
+					},
+					TrustedCerts: cert(s),
+				}
+			},
+			checks(check{"/myrepo/2.1.0/Linux/amd64/a.ubi", "u2", "x", content, map[string]string{
+				"X-Checksum-Sha256": "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269",
+				"X-Checksum-Sha1":   "bfb7759a67daeb65410490b4d98bb9da7d1ea2ce",
+				"X-Checksum-Md5":    "80a751fde577028640c419000e33eba6",
+			}}),
+		},
+		{
+			"layout-with-artifact-name", true, true, false, false,
+			func(s *httptest.Server) (*context.Context, config.Upload) {
+				return ctx, config.Upload{
+					Mode:               ModeArchive,
+					Name:               "a",
+					Target:             s.URL + "/myrepo/{{ .Version }}/{{ .Os }}/{{ .Arch }}/{{ .ArtifactName }}",
+					CustomArtifactName: true,
+					Username:           "u2",
+					TrustedCerts:       cert(s),
+				}
+			},
+			checks(
+				check{"/myrepo/2.1.0/linux/amd64/a.deb", "u2", "x", content, map[string]string{}},
+				check{"/myrepo/2.1.0/linux/amd64/a.tar", "u2", "x", content, map[string]string{}},
+			),
+		},
 		{
 			"custom-headers", true, true, false, false,
 			func(s *httptest.Server) (*context.Context, config.Upload) {
@@ -657,3 +701,226 @@ func cert(srv *httptest.Server) string {
 	}
 	return string(pem.EncodeToMemory(block))
 }
+
+func TestFilterFor(t *testing.T) {
+	artifacts := artifact.New()
+	for _, a := range []struct {
+		name string
+		goos string
+		id   string
+		typ  artifact.Type
+	}{
+		{"foo_linux_amd64.tar.gz", "linux", "foo", artifact.UploadableArchive},
+		{"foo_darwin_amd64.tar.gz", "darwin", "foo", artifact.UploadableArchive},
+		{"foo_windows_amd64.zip", "windows", "foo", artifact.UploadableArchive},
+		{"foo_windows_amd64.msi", "windows", "installer", artifact.UploadableFile},
+		{"foo_linux_amd64.deb", "linux", "pkg", artifact.LinuxPackage},
+		{"foo_linux_amd64", "linux", "foo", artifact.UploadableBinary},
+		{"checksums.txt", "", "", artifact.Checksum},
+	} {
+		artifacts.Add(&artifact.Artifact{
+			Name: a.name,
+			Goos: a.goos,
+			Type: a.typ,
+			Extra: map[string]interface{}{
+				artifact.ExtraID: a.id,
+			},
+		})
+	}
+
+	for name, tt := range map[string]struct {
+		upload   config.Upload
+		expected []string
+	}{
+		"archive": {
+			upload: config.Upload{Mode: ModeArchive},
+			expected: []string{
+				"foo_linux_amd64.tar.gz",
+				"foo_darwin_amd64.tar.gz",
+				"foo_windows_amd64.zip",
+				"foo_linux_amd64.deb",
+			},
+		},
+		"goos": {
+			upload: config.Upload{Mode: ModeArchive, Goos: []string{"darwin", "windows"}},
+			expected: []string{
+				"foo_darwin_amd64.tar.gz",
+				"foo_windows_amd64.zip",
+			},
+		},
+
+		"glob": {
+			upload:   config.Upload{Mode: ModeArchive, Glob: "*.msi"},
+			expected: []string{"foo_windows_amd64.msi"},
+		},
+		"glob with checksum": {
+			upload:   config.Upload{Mode: ModeArchive, Checksum: true, Glob: "*.txt"},
+			expected: []string{"checksums.txt"},
+		},
+		"ids and goos": {
+			upload:   config.Upload{Mode: ModeArchive, IDs: []string{"foo"}, Goos: []string{"windows"}},
+			expected: []string{"foo_windows_amd64.zip"},
+		},
+		"ids, goos and glob": {
+			upload: config.Upload{
+				Mode: ModeArchive,
+				IDs:  []string{"foo", "installer"},
+				Goos: []string{"windows"},
+				Glob: "foo_*.msi",
+			},
+			expected: []string{"foo_windows_amd64.msi"},
+		},
+		"binary": {
+			upload:   config.Upload{Mode: ModeBinary, Goos: []string{"linux"}, Glob: "foo_*"},
+			expected: []string{"foo_linux_amd64"},
+		},
+		"no match": {
+			upload: config.Upload{Mode: ModeBinary, Goos: []string{"windows"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			filter, err := filterFor(&tt.upload, "test")
+			require.NoError(t, err)
+			var names []string
+			for _, a := range artifacts.Filter(filter).List() {
+				names = append(names, a.Name)
+			}
+			require.ElementsMatch(t, tt.expected, names)
+		})
+	}
+
+	t.Run("invalid glob", func(t *testing.T) {
+		_, err := filterFor(&config.Upload{Mode: ModeArchive, Glob: "[a-"}, "test")
+		require.ErrorIs(t, err, filepath.ErrBadPattern)
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		_, err := filterFor(&config.Upload{Mode: "nope"}, "test")
+		require.EqualError(t, err, `test: mode "nope" not supported`)
+	})
+}
+
+func TestUploadInParts(t *testing.T) {
+	content := []byte("the quick brown fox jumps over the lazy dog")
+
+	newContext := func(tb testing.TB) *context.Context {
+		tb.Helper()
+		file := filepath.Join(tb.TempDir(), "a.tar")
+		require.NoError(tb, os.WriteFile(file, content, 0o644))
+		ctx := context.New(config.Project{ProjectName: "blah"})
+		ctx.Version = "2.1.0"
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: "a.tar",
+			Path: file,
+			Type: artifact.UploadableArchive,
+		})
+		return ctx
+	}
+
+	var is2xx ResponseChecker = func(r *h.Response) error {
+		if r.StatusCode/100 == 2 {
+			return nil
+		}
+		return fmt.Errorf("unexpected http status code: %v", r.StatusCode)
+	}
+
+	t.Run("retry failed part", func(t *testing.T) {
+		var m sync.Mutex
+		var ranges []string
+		tries := map[string]int{}
+		assembled := make([]byte, len(content))
+		srv := httptest.NewServer(h.HandlerFunc(func(w h.ResponseWriter, r *h.Request) {
+			m.Lock()
+			defer m.Unlock()
+			rng := r.Header.Get("Content-Range")
+			tries[rng]++
+			// fail the first try of the second part
+			if rng == "bytes 10-19/43" && tries[rng] == 1 {
+				w.WriteHeader(h.StatusInternalServerError)
+				return
+			}
+			var start, end, total int
+			_, err := fmt.Sscanf(rng, "bytes %d-%d/%d", &start, &end, &total)
+			require.NoError(t, err)
+			require.Equal(t, len(content), total)
+			bts, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.Len(t, bts, end-start+1)
+			require.Equal(t, "/blah/2.1.0/a.tar", r.RequestURI)
+			copy(assembled[start:], bts)
+			ranges = append(ranges, rng)
+			w.WriteHeader(h.StatusCreated)
+		}))
+		defer srv.Close()
+
+		ctx := newContext(t)
+		require.NoError(t, Upload(ctx, []config.Upload{{
+			Method:   h.MethodPut,
+			Mode:     ModeArchive,
+			Name:     "a",
+			Target:   srv.URL + "/{{.ProjectName}}/{{.Version}}/",
+			PartSize: 10,
+		}}, "test", is2xx))
+
+		require.Equal(t, content, assembled)
+		require.Equal(t, []string{
+			"bytes 0-9/43",
+			"bytes 10-19/43",
+			"bytes 20-29/43",
+			"bytes 30-39/43",
+			"bytes 40-42/43",
+		}, ranges)
+		require.Equal(t, 2, tries["bytes 10-19/43"])
+		require.Equal(t, 1, tries["bytes 0-9/43"])
+	})
+
+	t.Run("part keeps failing", func(t *testing.T) {
+		var m sync.Mutex
+		tries := map[string]int{}
+		srv := httptest.NewServer(h.HandlerFunc(func(w h.ResponseWriter, r *h.Request) {
+			m.Lock()
+			defer m.Unlock()
+			rng := r.Header.Get("Content-Range")
+			tries[rng]++
+			if rng == "bytes 20-39/43" {
+				w.WriteHeader(h.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(h.StatusCreated)
+		}))
+		defer srv.Close()
+
+		ctx := newContext(t)
+		err := Upload(ctx, []config.Upload{{
+			Method:   h.MethodPut,
+			Mode:     ModeArchive,
+			Name:     "a",
+			Target:   srv.URL + "/{{.ProjectName}}/{{.Version}}/",
+			PartSize: 20,
+		}}, "test", is2xx)
+		require.ErrorContains(t, err, "failed to upload part bytes 20-39/43 after 3 tries: unexpected http status code: 500")
+		require.Equal(t, map[string]int{
+			"bytes 0-19/43":  1,
+			"bytes 20-39/43": 3,
+		}, tries)
+	})
+
+	t.Run("smaller than part size", func(t *testing.T) {
+		var ranges []string
+		srv := httptest.NewServer(h.HandlerFunc(func(w h.ResponseWriter, r *h.Request) {
+			ranges = append(ranges, r.Header.Get("Content-Range"))
+			w.WriteHeader(h.StatusCreated)
+		}))
+		defer srv.Close()
+
+		ctx := newContext(t)
+		require.NoError(t, Upload(ctx, []config.Upload{{
+			Method:   h.MethodPut,
+			Mode:     ModeArchive,
+			Name:     "a",
+			Target:   srv.URL + "/{{.ProjectName}}/{{.Version}}/",
+			PartSize: 1024,
+		}}, "test", is2xx))
+		require.Equal(t, []string{""}, ranges)
+	})
+}
@@ -0,0 +1,128 @@
+// Package httpclient provides the HTTP clients used by pipes that talk to
+// external services, such as the announcers.
+package httpclient
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/caarlos0/log"
+)
+
+// New returns a new HTTP client based on the default transport, optionally
+// skipping TLS verification and authenticating with the given client
+// certificate and key files.
+func New(skipTLSVerify bool, certFile, keyFile string) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: skipTLSVerify, // #nosec
+	}
+
+	if certFile != "" || keyFile != "" {
+		if certFile == "" || keyFile == "" {
+			return nil, errors.New("client_cert and client_key must be set together")
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// WithRetries wraps the given client transport so requests failing with a
+// 429 or 5xx status are retried up to the given amount of times, waiting
+// backoff, 2*backoff, 4*backoff and so on between attempts.
+// The Retry-After header of 429 responses is honored if present.
+func WithRetries(client *http.Client, retries int, backoff time.Duration) *http.Client {
+	if retries <= 0 {
+		return client
+	}
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &http.Client{
+		Transport: retryTransport{
+			next:    next,
+			retries: retries,
+			backoff: backoff,
+		},
+		CheckRedirect: client.CheckRedirect,
+		Jar:           client.Jar,
+		Timeout:       client.Timeout,
+	}
+}
+
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := t.next.RoundTrip(req)
+		if err != nil || attempt >= t.retries || !shouldRetry(resp.StatusCode) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			// body can't be rewound, so the request can't be retried.
+			return resp, nil
+		}
+
+		wait := t.backoff << attempt
+		if d, ok := retryAfter(resp); ok {
+			wait = d
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		log.Warnf("request to %s failed with status %s, retrying in %s", req.URL.Host, resp.Status, wait)
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+	}
+}
+
+func shouldRetry(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
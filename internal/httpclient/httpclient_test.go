@@ -0,0 +1,125 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("no cert", func(t *testing.T) {
+		client, err := New(true, "", "")
+		require.NoError(t, err)
+		tlsConfig := client.Transport.(*http.Transport).TLSClientConfig
+		require.True(t, tlsConfig.InsecureSkipVerify)
+		require.Empty(t, tlsConfig.Certificates)
+	})
+
+	cert, key := testlib.ClientCert(t)
+
+	t.Run("cert", func(t *testing.T) {
+		client, err := New(false, cert, key)
+		require.NoError(t, err)
+		tlsConfig := client.Transport.(*http.Transport).TLSClientConfig
+		require.False(t, tlsConfig.InsecureSkipVerify)
+		require.Len(t, tlsConfig.Certificates, 1)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := New(false, cert, "")
+		require.EqualError(t, err, "client_cert and client_key must be set together")
+	})
+
+	t.Run("missing cert", func(t *testing.T) {
+		_, err := New(false, "", key)
+		require.EqualError(t, err, "client_cert and client_key must be set together")
+	})
+
+	t.Run("invalid cert", func(t *testing.T) {
+		_, err := New(false, "testdata/nope.pem", key)
+		require.EqualError(t, err, "failed to load client certificate: open testdata/nope.pem: no such file or directory")
+	})
+}
+
+func TestWithRetries(t *testing.T) {
+	newServer := func(tb testing.TB, failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+		tb.Helper()
+		var calls int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer r.Body.Close()
+			body, err := io.ReadAll(r.Body)
+			require.NoError(tb, err)
+			require.Equal(tb, "hello", string(body))
+			if atomic.AddInt32(&calls, 1) <= failures {
+				for k, v := range header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(status)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		tb.Cleanup(srv.Close)
+		return srv, &calls
+	}
+
+	post := func(tb testing.TB, client *http.Client, url string) *http.Response {
+		tb.Helper()
+		resp, err := client.Post(url, "text/plain", strings.NewReader("hello"))
+		require.NoError(tb, err)
+		require.NoError(tb, resp.Body.Close())
+		return resp
+	}
+
+	t.Run("fails twice then succeeds", func(t *testing.T) {
+		srv, calls := newServer(t, 2, http.StatusBadGateway, nil)
+		client := WithRetries(http.DefaultClient, 2, time.Millisecond)
+		resp := post(t, client, srv.URL)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(calls))
+	})
+
+	t.Run("not enough retries", func(t *testing.T) {
+		srv, calls := newServer(t, 2, http.StatusInternalServerError, nil)
+		client := WithRetries(http.DefaultClient, 1, time.Millisecond)
+		resp := post(t, client, srv.URL)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Equal(t, int32(2), atomic.LoadInt32(calls))
+	})
+
+	t.Run("no retries", func(t *testing.T) {
+		srv, calls := newServer(t, 2, http.StatusInternalServerError, nil)
+		client := WithRetries(http.DefaultClient, 0, time.Millisecond)
+		require.Equal(t, http.DefaultClient, client)
+		resp := post(t, client, srv.URL)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(calls))
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		srv, calls := newServer(t, 2, http.StatusBadRequest, nil)
+		client := WithRetries(http.DefaultClient, 2, time.Millisecond)
+		resp := post(t, client, srv.URL)
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		require.Equal(t, int32(1), atomic.LoadInt32(calls))
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		srv, calls := newServer(t, 1, http.StatusTooManyRequests, http.Header{
+			"Retry-After": []string{"1"},
+		})
+		client := WithRetries(http.DefaultClient, 1, time.Millisecond)
+		start := time.Now()
+		resp := post(t, client, srv.URL)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, int32(2), atomic.LoadInt32(calls))
+		require.GreaterOrEqual(t, time.Since(start), time.Second)
+	})
+}
@@ -4,13 +4,17 @@
 package archive
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/archivefiles"
@@ -59,6 +63,9 @@ func (Pipe) Default(ctx *context.Context) error {
 		if archive.ID == "" {
 			archive.ID = "default"
 		}
+		if err := validateFormats(*archive); err != nil {
+			return err
+		}
 		if !archive.RLCP && archive.Format != "binary" && len(archive.Files) > 0 {
 			deprecate.NoticeCustom(ctx, "archives.rlcp", "`{{ .Property }}` will be the default soon, check {{ .URL }} for more info")
 		}
@@ -86,6 +93,34 @@ func (Pipe) Default(ctx *context.Context) error {
 	return ids.Validate()
 }
 
+// validFormats are the formats an archive can have.
+var validFormats = append(archive.Formats(), "binary")
+
+func validateFormats(archive config.Archive) error {
+	formats := []string{archive.Format}
+	for _, override := range archive.FormatOverrides {
+		formats = append(formats, override.Format)
+	}
+	for _, format := range formats {
+		if !isValidFormat(format) {
+			return fmt.Errorf("invalid archive format: %s, valid options are %v", format, validFormats)
+		}
+	}
+	if archive.CompressionLevel < 0 || archive.CompressionLevel > 22 {
+		return fmt.Errorf("invalid archive compression_level: %d, must be between 0 (the format default) and 22", archive.CompressionLevel)
+	}
+	return nil
+}
+
+func isValidFormat(format string) bool {
+	for _, f := range validFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
 // Run the pipe.
 func (Pipe) Run(ctx *context.Context) error {
 	g := semerrgroup.New(ctx.Parallelism)
@@ -112,7 +147,7 @@ func (Pipe) Run(ctx *context.Context) error {
 		for group, artifacts := range artifacts {
 			log.Debugf("group %s has %d binaries", group, len(artifacts))
 			artifacts := artifacts
-			if packageFormat(archive, artifacts[0].Goos) == "binary" {
+			if packageFormat(archive, artifacts[0]) == "binary" {
 				g.Go(func() error {
 					return skip(ctx, archive, artifacts)
 				})
@@ -147,7 +182,7 @@ func createMeta(ctx *context.Context, arch config.Archive) error {
 func create(ctx *context.Context, arch config.Archive, binaries []*artifact.Artifact) error {
 	// nolint:staticcheck
 	template := tmpl.New(ctx).WithArtifactReplacements(binaries[0], arch.Replacements)
-	format := packageFormat(arch, binaries[0].Goos)
+	format := packageFormat(arch, binaries[0])
 	return doCreate(ctx, arch, binaries, format, template)
 }
 
@@ -181,13 +216,17 @@ func doCreate(ctx *context.Context, arch config.Archive, binaries []*artifact.Ar
 	if err != nil {
 		return err
 	}
-	a, err := archive.New(archiveFile, format)
+	a, err := archive.NewWithCompressionLevel(archiveFile, format, arch.CompressionLevel)
 	if err != nil {
 		return err
 	}
 	a = NewEnhancedArchive(a, wrap)
 	defer a.Close()
 
+	mtime, err := sourceDateEpoch(ctx)
+	if err != nil {
+		return err
+	}
 	files, err := archivefiles.Eval(template, arch.RLCP, arch.Files)
 	if err != nil {
 		return fmt.Errorf("failed to find files to archive: %w", err)
@@ -195,6 +234,20 @@ func doCreate(ctx *context.Context, arch config.Archive, binaries []*artifact.Ar
 	if arch.Meta && len(files) == 0 {
 		return fmt.Errorf("no files found")
 	}
+	buildsInfo, err := archivefiles.EvalInfo(template, arch.BuildsInfo)
+	if err != nil {
+		return err
+	}
+	if !mtime.IsZero() {
+		for i := range files {
+			if files[i].Info.ParsedMTime.IsZero() {
+				files[i].Info.ParsedMTime = mtime
+			}
+		}
+		if buildsInfo.ParsedMTime.IsZero() {
+			buildsInfo.ParsedMTime = mtime
+		}
+	}
 	for _, f := range files {
 		if err = a.Add(f); err != nil {
 			return fmt.Errorf("failed to add: '%s' -> '%s': %w", f.Source, f.Destination, err)
@@ -206,15 +259,29 @@ func doCreate(ctx *context.Context, arch config.Archive, binaries []*artifact.Ar
 		if arch.StripParentBinaryFolder {
 			dst = filepath.Base(dst)
 		}
-		if err := a.Add(config.File{
+		f := config.File{
 			Source:      binary.Path,
 			Destination: dst,
-			Info:        arch.BuildsInfo,
-		}); err != nil {
+			Info:        buildsInfo,
+		}
+		if err := a.Add(f); err != nil {
 			return fmt.Errorf("failed to add: '%s' -> '%s': %w", binary.Path, dst, err)
 		}
+		files = append(files, f)
 		bins = append(bins, binary.Name)
 	}
+	if format == "zip" {
+		warnSymlinks(files)
+	}
+	if arch.IncludeManifest {
+		manifestMTime := ctx.Git.CommitDate
+		if !mtime.IsZero() {
+			manifestMTime = mtime
+		}
+		if err := addManifest(a, format, files, manifestMTime); err != nil {
+			return fmt.Errorf("failed to add manifest: %w", err)
+		}
+	}
 	art := &artifact.Artifact{
 		Type: artifact.UploadableArchive,
 		Name: folder + "." + format,
@@ -240,6 +307,109 @@ func doCreate(ctx *context.Context, arch config.Archive, binaries []*artifact.Ar
 	return nil
 }
 
+// warnSymlinks warns about symlinks being added to zip archives.
+// They are added as symlink entries, same as in tar archives, but not all
+// unzip implementations support them, Windows' one included.
+func warnSymlinks(files []config.File) {
+	for _, f := range files {
+		info, err := os.Lstat(f.Source)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		log.WithField("file", f.Destination).
+			Warn("zip archives can't represent symlinks portably, some tools will extract it as a regular file containing the link target, consider using a tar format instead")
+	}
+}
+
+const manifestName = "manifest.json"
+
+// manifest describes the files inside an archive.
+type manifest struct {
+	Files []manifestFile `json:"files"`
+}
+
+type manifestFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+	Mode string `json:"mode"`
+}
+
+// addManifest adds a manifest.json file describing the given files to the
+// archive.
+// The files are sorted by path, so the manifest is reproducible.
+func addManifest(a archive.Archive, format string, files []config.File, mtime time.Time) error {
+	if format == "gz" {
+		log.Warn("gz archives can only contain a single file, not adding a manifest")
+		return nil
+	}
+
+	m := manifest{Files: make([]manifestFile, 0, len(files))}
+	for _, f := range files {
+		info, err := os.Lstat(f.Source)
+		if err != nil {
+			return err
+		}
+		mode := info.Mode()
+		if f.Info.Mode != 0 {
+			mode = f.Info.Mode
+		}
+		var size int64
+		if info.Mode().IsRegular() {
+			size = info.Size()
+		}
+		m.Files = append(m.Files, manifestFile{
+			Path: filepath.ToSlash(f.Destination),
+			Size: size,
+			Mode: fmt.Sprintf("%04o", mode.Perm()),
+		})
+	}
+	sort.Slice(m.Files, func(i, j int) bool {
+		return m.Files[i].Path < m.Files[j].Path
+	})
+
+	bts, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "goreleaser-archive-manifest")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bts); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return a.Add(config.File{
+		Source:      tmp.Name(),
+		Destination: manifestName,
+		Info: config.FileInfo{
+			Mode:        0o644,
+			ParsedMTime: mtime,
+		},
+	})
+}
+
+// sourceDateEpoch returns the time set in the SOURCE_DATE_EPOCH environment
+// variable, if any.
+// See https://reproducible-builds.org/specs/source-date-epoch/
+func sourceDateEpoch(ctx *context.Context) (time.Time, error) {
+	epoch := ctx.Env["SOURCE_DATE_EPOCH"]
+	if epoch == "" {
+		return time.Time{}, nil
+	}
+	sec, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH: %w", err)
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
+
 func wrapFolder(a config.Archive) string {
 	switch a.WrapInDirectory {
 	case "true":
@@ -285,13 +455,47 @@ func skip(ctx *context.Context, archive config.Archive, binaries []*artifact.Art
 	return nil
 }
 
-func packageFormat(archive config.Archive, platform string) string {
+// packageFormat returns the format to use for the given artifact, taking the
+// format overrides into account.
+// When several overrides match, the most specific one wins, e.g. one matching
+// both goos and goarch wins over one matching only goos. Ties go to the first
+// one declared.
+func packageFormat(archive config.Archive, art *artifact.Artifact) string {
+	format := archive.Format
+	best := -1
 	for _, override := range archive.FormatOverrides {
-		if strings.HasPrefix(platform, override.Goos) {
-			return override.Format
+		score := overrideScore(override, art)
+		if score > best {
+			best = score
+			format = override.Format
+		}
+	}
+	return format
+}
+
+// overrideScore returns how many of the override fields match the given
+// artifact, or -1 if any of the fields set doesn't match.
+func overrideScore(override config.FormatOverride, art *artifact.Artifact) int {
+	if !strings.HasPrefix(art.Goos, override.Goos) {
+		return -1
+	}
+	score := 0
+	if override.Goos != "" {
+		score++
+	}
+	if override.Goarch != "" {
+		if override.Goarch != art.Goarch {
+			return -1
+		}
+		score++
+	}
+	if override.Goarm != "" {
+		if override.Goarm != art.Goarm {
+			return -1
 		}
+		score++
 	}
-	return archive.Format
+	return score
 }
 
 // NewEnhancedArchive enhances a pre-existing archive.Archive instance
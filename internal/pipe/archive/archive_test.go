@@ -9,12 +9,14 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/archive"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/require"
 )
 
@@ -843,8 +845,41 @@ func TestFormatFor(t *testing.T) {
 			},
 		},
 	}
-	require.Equal(t, "zip", packageFormat(ctx.Config.Archives[0], "windows"))
-	require.Equal(t, "tar.gz", packageFormat(ctx.Config.Archives[0], "linux"))
+	require.Equal(t, "zip", packageFormat(ctx.Config.Archives[0], &artifact.Artifact{Goos: "windows", Goarch: "amd64"}))
+	require.Equal(t, "tar.gz", packageFormat(ctx.Config.Archives[0], &artifact.Artifact{Goos: "linux", Goarch: "amd64"}))
+}
+
+func TestFormatForSpecificity(t *testing.T) {
+	archive := config.Archive{
+		Format: "tar.gz",
+		FormatOverrides: []config.FormatOverride{
+			{Goos: "windows", Format: "zip"},
+			{Goos: "windows", Goarch: "amd64", Format: "tar.xz"},
+			{Goos: "linux", Goarch: "arm", Format: "tar"},
+			{Goos: "linux", Goarch: "arm", Goarm: "7", Format: "zip"},
+			{Goarch: "arm64", Format: "binary"},
+			{Goos: "darwin", Format: "zip"},
+			{Goos: "darwin", Format: "tar"},
+		},
+	}
+	for _, tt := range []struct {
+		art    artifact.Artifact
+		expect string
+	}{
+		{artifact.Artifact{Goos: "windows", Goarch: "arm64"}, "zip"},
+		{artifact.Artifact{Goos: "windows", Goarch: "386"}, "zip"},
+		{artifact.Artifact{Goos: "windows", Goarch: "amd64"}, "tar.xz"},
+		{artifact.Artifact{Goos: "linux", Goarch: "arm", Goarm: "6"}, "tar"},
+		{artifact.Artifact{Goos: "linux", Goarch: "arm", Goarm: "7"}, "zip"},
+		{artifact.Artifact{Goos: "linux", Goarch: "arm64"}, "binary"},
+		{artifact.Artifact{Goos: "linux", Goarch: "amd64"}, "tar.gz"},
+		{artifact.Artifact{Goos: "darwin", Goarch: "amd64"}, "zip"},
+	} {
+		tt := tt
+		t.Run(tt.art.Goos+tt.art.Goarch+tt.art.Goarm, func(t *testing.T) {
+			require.Equal(t, tt.expect, packageFormat(archive, &tt.art))
+		})
+	}
 }
 
 func TestBinaryOverride(t *testing.T) {
@@ -1148,3 +1183,408 @@ func TestInvalidFormat(t *testing.T) {
 	})
 	require.EqualError(t, Pipe{}.Run(ctx), "invalid archive format: 7z")
 }
+
+func TestRunPipeWithManifest(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "darwinamd64"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dist, "darwinamd64", "mybin"), []byte("fake binary"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "README.md"), []byte("readme"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "LICENSE.darwin"), []byte("license for darwin"), 0o644))
+
+	for _, format := range []string{"tar.gz", "zip"} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.New(
+				config.Project{
+					Dist: dist,
+					Archives: []config.Archive{
+						{
+							Builds:          []string{"default"},
+							NameTemplate:    "manifest_{{ .Os }}",
+							WrapInDirectory: "true",
+							Format:          format,
+							IncludeManifest: true,
+							Files: []config.File{
+								{Source: "README.md"},
+								{Source: "LICENSE.{{ .Os }}", Info: config.FileInfo{Mode: 0o600}},
+							},
+						},
+					},
+				},
+			)
+			ctx.Git.CurrentTag = "v0.0.1"
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Goos:   "darwin",
+				Goarch: "amd64",
+				Name:   "mybin",
+				Path:   filepath.Join("dist", "darwinamd64", "mybin"),
+				Type:   artifact.Binary,
+				Extra: map[string]interface{}{
+					artifact.ExtraBinary: "mybin",
+					artifact.ExtraID:     "default",
+				},
+			})
+			require.NoError(t, Pipe{}.Run(ctx))
+
+			path := filepath.Join(dist, "manifest_darwin."+format)
+			var bts []byte
+			if format == "zip" {
+				require.Contains(t, zipFiles(t, path), "manifest_darwin/manifest.json")
+				bts = zipContent(t, path, "manifest_darwin/manifest.json")
+			} else {
+				require.Contains(t, tarFiles(t, path), "manifest_darwin/manifest.json")
+				bts = tarContent(t, path, "manifest_darwin/manifest.json")
+			}
+			require.JSONEq(t, `{
+				"files": [
+					{"path": "LICENSE.darwin", "size": 18, "mode": "0600"},
+					{"path": "README.md", "size": 6, "mode": "0644"},
+					{"path": "mybin", "size": 11, "mode": "0755"}
+				]
+			}`, string(bts))
+		})
+	}
+}
+
+func tarContent(t *testing.T, path, name string) []byte {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	defer gr.Close()
+	r := tar.NewReader(gr)
+	for {
+		next, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		if next.Name == name {
+			bts, err := io.ReadAll(r)
+			require.NoError(t, err)
+			return bts
+		}
+	}
+	t.Fatalf("%s not found in %s", name, path)
+	return nil
+}
+
+func zipContent(t *testing.T, path, name string) []byte {
+	t.Helper()
+	r, err := zip.OpenReader(path)
+	require.NoError(t, err)
+	defer r.Close()
+	f, err := r.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	bts, err := io.ReadAll(f)
+	require.NoError(t, err)
+	return bts
+}
+
+func TestRunPipeTarZst(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "linuxamd64"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dist, "linuxamd64", "mybin"), []byte("fake binary"), 0o755))
+	ctx := context.New(
+		config.Project{
+			Dist: dist,
+			Archives: []config.Archive{
+				{
+					Builds:           []string{"default"},
+					NameTemplate:     "foo_{{ .Os }}",
+					Format:           "tar.zst",
+					CompressionLevel: 19,
+				},
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "v0.0.1"
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Goos:   "linux",
+		Goarch: "amd64",
+		Name:   "mybin",
+		Path:   filepath.Join("dist", "linuxamd64", "mybin"),
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			artifact.ExtraBinary: "mybin",
+			artifact.ExtraID:     "default",
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	archives := ctx.Artifacts.Filter(artifact.ByType(artifact.UploadableArchive)).List()
+	require.Len(t, archives, 1)
+	require.Equal(t, "foo_linux.tar.zst", archives[0].Name)
+	require.Equal(t, "tar.zst", archives[0].Format())
+
+	f, err := os.Open(archives[0].Path)
+	require.NoError(t, err)
+	defer f.Close()
+	zr, err := zstd.NewReader(f)
+	require.NoError(t, err)
+	defer zr.Close()
+	h, err := tar.NewReader(zr).Next()
+	require.NoError(t, err)
+	require.Equal(t, "mybin", h.Name)
+}
+
+func TestDefaultInvalidFormat(t *testing.T) {
+	for name, archive := range map[string]config.Archive{
+		"format": {Format: "tar.bz2"},
+		"override": {
+			FormatOverrides: []config.FormatOverride{
+				{Goos: "windows", Format: "7z"},
+			},
+		},
+		"level": {Format: "tar.zst", CompressionLevel: 23},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Archives: []config.Archive{archive},
+			})
+			require.ErrorContains(t, Pipe{}.Default(ctx), "invalid archive")
+		})
+	}
+
+	t.Run("messages", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Archives: []config.Archive{{Format: "tar.bz2"}},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), "invalid archive format: tar.bz2, valid options are [gz tar tar.gz tar.xz tar.zst zip binary]")
+
+		ctx = context.New(config.Project{
+			Archives: []config.Archive{{Format: "tar.zst", CompressionLevel: -1}},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), "invalid archive compression_level: -1, must be between 0 (the format default) and 22")
+	})
+}
+
+func TestRunPipeSymlinks(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "linuxamd64"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dist, "linuxamd64", "mybin"), []byte("fake binary"), 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(folder, "lib"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "lib", "libfoo.so.1"), []byte("fake lib"), 0o644))
+	require.NoError(t, os.Symlink("libfoo.so.1", filepath.Join(folder, "lib", "libfoo.so")))
+
+	for _, format := range []string{"tar.gz", "zip"} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.New(
+				config.Project{
+					Dist: dist,
+					Archives: []config.Archive{
+						{
+							Builds:       []string{"default"},
+							NameTemplate: "symlinks_{{ .Os }}",
+							Format:       format,
+							Files: []config.File{
+								{Source: "lib/*"},
+							},
+						},
+					},
+				},
+			)
+			ctx.Git.CurrentTag = "v0.0.1"
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Goos:   "linux",
+				Goarch: "amd64",
+				Name:   "mybin",
+				Path:   filepath.Join("dist", "linuxamd64", "mybin"),
+				Type:   artifact.Binary,
+				Extra: map[string]interface{}{
+					artifact.ExtraBinary: "mybin",
+					artifact.ExtraID:     "default",
+				},
+			})
+			require.NoError(t, Pipe{}.Run(ctx))
+
+			path := filepath.Join(dist, "symlinks_linux."+format)
+			if format == "zip" {
+				r, err := zip.OpenReader(path)
+				require.NoError(t, err)
+				defer r.Close()
+				for _, zf := range r.File {
+					if zf.Name != "lib/libfoo.so" {
+						continue
+					}
+					require.Equal(t, os.ModeSymlink, zf.Mode()&os.ModeSymlink)
+				}
+				require.Equal(t, []byte("libfoo.so.1"), zipContent(t, path, "lib/libfoo.so"))
+				return
+			}
+
+			link := tarInfo(t, path, "lib/libfoo.so")
+			require.NotNil(t, link)
+			require.Equal(t, byte(tar.TypeSymlink), link.Typeflag)
+			require.Equal(t, "libfoo.so.1", link.Linkname)
+			require.Zero(t, link.Size)
+
+			lib := tarInfo(t, path, "lib/libfoo.so.1")
+			require.NotNil(t, lib)
+			require.Equal(t, byte(tar.TypeReg), lib.Typeflag)
+			require.Equal(t, []byte("fake lib"), tarContent(t, path, "lib/libfoo.so.1"))
+		})
+	}
+}
+
+func TestRunPipeSourceDateEpoch(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	require.NoError(t, os.Mkdir(filepath.Join(folder, "bin"), 0o755))
+	binPath := filepath.Join(folder, "bin", "mybin")
+	readmePath := filepath.Join(folder, "README.md")
+	require.NoError(t, os.WriteFile(binPath, []byte("fake binary"), 0o755))
+	require.NoError(t, os.WriteFile(readmePath, []byte("readme"), 0o644))
+
+	epoch := time.Unix(1640995200, 0).UTC()
+	run := func(tb testing.TB, dist, format string, mtime time.Time) string {
+		tb.Helper()
+		// change the mtime of the sources between runs
+		require.NoError(tb, os.Chtimes(binPath, mtime, mtime))
+		require.NoError(tb, os.Chtimes(readmePath, mtime, mtime))
+
+		ctx := context.New(config.Project{
+			Dist: dist,
+			Archives: []config.Archive{
+				{
+					Builds:          []string{"default"},
+					NameTemplate:    "epoch",
+					WrapInDirectory: "true",
+					Format:          format,
+					IncludeManifest: true,
+					Files: []config.File{
+						{Source: "README.md"},
+					},
+				},
+			},
+		})
+		ctx.Env["SOURCE_DATE_EPOCH"] = fmt.Sprintf("%d", epoch.Unix())
+		ctx.Git.CommitDate = mtime
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Goos:   "linux",
+			Goarch: "amd64",
+			Name:   "mybin",
+			Path:   binPath,
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				artifact.ExtraBinary: "mybin",
+				artifact.ExtraID:     "default",
+			},
+		})
+		require.NoError(tb, Pipe{}.Run(ctx))
+		return filepath.Join(dist, "epoch."+format)
+	}
+
+	for _, format := range []string{"tar.gz", "tar", "zip"} {
+		t.Run(format, func(t *testing.T) {
+			first := run(t, filepath.Join(folder, "dist1-"+format), format, time.Now().Add(-time.Hour))
+			second := run(t, filepath.Join(folder, "dist2-"+format), format, time.Now())
+
+			bts1, err := os.ReadFile(first)
+			require.NoError(t, err)
+			bts2, err := os.ReadFile(second)
+			require.NoError(t, err)
+			require.Equal(t, bts1, bts2, "archives should be byte-identical")
+
+			if format == "zip" {
+				r, err := zip.OpenReader(first)
+				require.NoError(t, err)
+				defer r.Close()
+				for _, f := range r.File {
+					require.True(t, epoch.Equal(f.Modified), "%s: %s", f.Name, f.Modified)
+				}
+				return
+			}
+			if format == "tar.gz" {
+				for _, name := range []string{"epoch/README.md", "epoch/mybin", "epoch/manifest.json"} {
+					require.True(t, epoch.Equal(tarInfo(t, first, name).ModTime), name)
+				}
+			}
+		})
+	}
+}
+
+func TestRunPipeSourceDateEpochExplicitMTime(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	binPath := filepath.Join(folder, "mybin")
+	require.NoError(t, os.WriteFile(binPath, []byte("fake binary"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "README.md"), []byte("readme"), 0o644))
+
+	ctx := context.New(config.Project{
+		Dist: dist,
+		Archives: []config.Archive{
+			{
+				Builds:       []string{"default"},
+				NameTemplate: "mtime",
+				Format:       "tar.gz",
+				BuildsInfo: config.FileInfo{
+					MTime: "{{ .CommitDate }}",
+				},
+				Files: []config.File{
+					{
+						Source: "README.md",
+						Info:   config.FileInfo{MTime: "2008-01-02T15:04:05Z"},
+					},
+				},
+			},
+		},
+	})
+	ctx.Env["SOURCE_DATE_EPOCH"] = "1640995200"
+	ctx.Git.CommitDate = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Goos:   "linux",
+		Goarch: "amd64",
+		Name:   "mybin",
+		Path:   binPath,
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			artifact.ExtraBinary: "mybin",
+			artifact.ExtraID:     "default",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	path := filepath.Join(dist, "mtime.tar.gz")
+	// explicit mtimes have precedence over SOURCE_DATE_EPOCH
+	require.True(t, ctx.Git.CommitDate.Equal(tarInfo(t, path, "mybin").ModTime))
+	require.True(t, time.Date(2008, 1, 2, 15, 4, 5, 0, time.UTC).Equal(tarInfo(t, path, "README.md").ModTime))
+}
+
+func TestRunPipeInvalidSourceDateEpoch(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	ctx := context.New(config.Project{
+		Dist: dist,
+		Archives: []config.Archive{
+			{
+				Builds:       []string{"default"},
+				NameTemplate: "foo",
+				Format:       "tar.gz",
+			},
+		},
+	})
+	ctx.Env["SOURCE_DATE_EPOCH"] = "not a number"
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Goos:   "linux",
+		Goarch: "amd64",
+		Name:   "mybin",
+		Path:   filepath.Join(folder, "mybin"),
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			artifact.ExtraBinary: "mybin",
+			artifact.ExtraID:     "default",
+		},
+	})
+	require.ErrorContains(t, Pipe{}.Run(ctx), "invalid SOURCE_DATE_EPOCH")
+}
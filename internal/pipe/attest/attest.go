@@ -0,0 +1,183 @@
+// Package attest implements a Pipe that creates attestations, such as in-toto
+// provenance, for the built artifacts.
+package attest
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/ids"
+	"github.com/goreleaser/goreleaser/internal/semerrgroup"
+	"github.com/goreleaser/goreleaser/internal/signer"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// ErrNoPredicate happens when an attestation has no predicate set.
+var ErrNoPredicate = errors.New("attestations: predicate is required")
+
+// Pipe that attests common artifacts.
+type Pipe struct{}
+
+func (Pipe) String() string { return "attesting artifacts" }
+func (Pipe) Skip(ctx *context.Context) bool {
+	return ctx.SkipSign || len(ctx.Config.Attestations) == 0
+}
+
+// Default sets the Pipes defaults.
+func (Pipe) Default(ctx *context.Context) error {
+	ids := ids.New("attestations")
+	for i := range ctx.Config.Attestations {
+		cfg := &ctx.Config.Attestations[i]
+		if cfg.Cmd == "" {
+			cfg.Cmd = "cosign"
+		}
+		if cfg.Output == "" {
+			cfg.Output = "${artifact}.intoto.jsonl"
+		}
+		if len(cfg.Args) == 0 && filepath.Base(cfg.Cmd) == "cosign" {
+			cfg.Args = []string{
+				"attest-blob",
+				"--key=$key",
+				"--predicate=$predicate",
+				"--type=slsaprovenance",
+				"--output-attestation=$output",
+				"--yes",
+				"$artifact",
+			}
+		}
+		if cfg.Artifacts == "" {
+			cfg.Artifacts = "binary"
+		}
+		if cfg.ID == "" {
+			cfg.ID = "default"
+		}
+		if cfg.Predicate == "" {
+			return ErrNoPredicate
+		}
+		ids.Inc(cfg.ID)
+	}
+	return ids.Validate()
+}
+
+// Run executes the Pipe.
+func (Pipe) Run(ctx *context.Context) error {
+	g := semerrgroup.New(ctx.Parallelism)
+	for i := range ctx.Config.Attestations {
+		cfg := ctx.Config.Attestations[i]
+		g.Go(func() error {
+			filter, err := filterFor(cfg)
+			if err != nil {
+				return err
+			}
+			for _, a := range ctx.Artifacts.Filter(filter).List() {
+				att, err := attestOne(ctx, cfg, a)
+				if err != nil {
+					return err
+				}
+				ctx.Artifacts.Add(att)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func filterFor(cfg config.Attestation) (artifact.Filter, error) {
+	var filters []artifact.Filter
+	switch cfg.Artifacts {
+	case "checksum":
+		filters = append(filters, artifact.ByType(artifact.Checksum))
+		if len(cfg.IDs) > 0 {
+			log.Warn("when artifacts is `checksum`, `ids` has no effect. ignoring")
+		}
+	case "source":
+		filters = append(filters, artifact.ByType(artifact.UploadableSourceArchive))
+		if len(cfg.IDs) > 0 {
+			log.Warn("when artifacts is `source`, `ids` has no effect. ignoring")
+		}
+	case "all":
+		filters = append(filters, artifact.Or(
+			artifact.ByType(artifact.UploadableArchive),
+			artifact.ByType(artifact.UploadableBinary),
+			artifact.ByType(artifact.UploadableSourceArchive),
+			artifact.ByType(artifact.Checksum),
+			artifact.ByType(artifact.LinuxPackage),
+			artifact.ByType(artifact.SBOM),
+		))
+	case "archive":
+		filters = append(filters, artifact.ByType(artifact.UploadableArchive))
+	case "binary":
+		filters = append(filters, artifact.ByType(artifact.UploadableBinary))
+	case "sbom":
+		filters = append(filters, artifact.ByType(artifact.SBOM))
+	case "package":
+		filters = append(filters, artifact.ByType(artifact.LinuxPackage))
+	default:
+		return nil, fmt.Errorf("invalid list of artifacts to attest: %s", cfg.Artifacts)
+	}
+
+	if len(cfg.IDs) > 0 {
+		filters = append(filters, artifact.ByIDs(cfg.IDs...))
+	}
+	return artifact.And(filters...), nil
+}
+
+func attestOne(ctx *context.Context, cfg config.Attestation, art *artifact.Artifact) (*artifact.Artifact, error) {
+	env, err := signer.Env(ctx, art, cfg.Env)
+	if err != nil {
+		return nil, fmt.Errorf("attest failed: %s: %w", art.Name, err)
+	}
+
+	output, err := signer.Path(ctx, env, cfg.Output)
+	if err != nil {
+		return nil, fmt.Errorf("attest failed: %s: %w", art.Name, err)
+	}
+	env["output"] = output
+
+	predicate, err := signer.Apply(ctx, env, cfg.Predicate)
+	if err != nil {
+		return nil, fmt.Errorf("attest failed: %s: %w", art.Name, err)
+	}
+	env["predicate"] = predicate
+
+	key, err := signer.KeyPath(ctx, env, cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("attest failed: %s: %w", art.Name, err)
+	}
+	env["key"] = key
+
+	args, err := signer.Args(ctx, env, cfg.Args)
+	if err != nil {
+		return nil, fmt.Errorf("attest failed: %s: %w", art.Name, err)
+	}
+
+	fields := log.Fields{"cmd": cfg.Cmd, "artifact": art.Name, "attestation": output}
+	log.WithFields(fields).Info("attesting")
+	if err := signer.Run(ctx, env, signer.Command{
+		Name:      "attest",
+		Cmd:       cfg.Cmd,
+		Stdin:     cfg.Stdin,
+		StdinFile: cfg.StdinFile,
+	}, args, fields); err != nil {
+		return nil, err
+	}
+
+	// re-execute the output template using the artifact name instead of its
+	// path, so we get the actual attestation file name.
+	env["artifact"] = art.Name
+	name, _ := signer.Apply(ctx, env, cfg.Output) // could never error as it passed the previous check
+
+	return &artifact.Artifact{
+		Type: artifact.Attestation,
+		Name: filepath.Base(name),
+		Path: output,
+		Extra: map[string]interface{}{
+			artifact.ExtraID:         cfg.ID,
+			artifact.ExtraSkipUpload: cfg.SkipUpload,
+		},
+	}, nil
+}
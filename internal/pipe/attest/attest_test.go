@@ -0,0 +1,246 @@
+package attest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescription(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
+	})
+
+	t.Run("skip sign", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Attestations: []config.Attestation{{}},
+		})
+		ctx.SkipSign = true
+		require.True(t, Pipe{}.Skip(ctx))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Attestations: []config.Attestation{{}},
+		})
+		require.False(t, Pipe{}.Skip(ctx))
+	})
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{
+		Attestations: []config.Attestation{{Predicate: "provenance.json"}},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, config.Attestation{
+		ID:        "default",
+		Cmd:       "cosign",
+		Output:    "${artifact}.intoto.jsonl",
+		Predicate: "provenance.json",
+		Args: []string{
+			"attest-blob",
+			"--key=$key",
+			"--predicate=$predicate",
+			"--type=slsaprovenance",
+			"--output-attestation=$output",
+			"--yes",
+			"$artifact",
+		},
+		Artifacts: "binary",
+	}, ctx.Config.Attestations[0])
+}
+
+func TestDefaultCustomCmd(t *testing.T) {
+	ctx := context.New(config.Project{
+		Attestations: []config.Attestation{{Cmd: "witness", Predicate: "provenance.json"}},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Empty(t, ctx.Config.Attestations[0].Args)
+}
+
+func TestDefaultDuplicatedIDs(t *testing.T) {
+	ctx := context.New(config.Project{
+		Attestations: []config.Attestation{
+			{Predicate: "provenance.json"},
+			{Predicate: "provenance.json"},
+		},
+	})
+	require.EqualError(t, Pipe{}.Default(ctx), "found 2 attestations with the ID 'default', please fix your config")
+}
+
+func TestDefaultNoPredicate(t *testing.T) {
+	ctx := context.New(config.Project{
+		Attestations: []config.Attestation{{}},
+	})
+	require.ErrorIs(t, Pipe{}.Default(ctx), ErrNoPredicate)
+}
+
+func newContext(tb testing.TB, attestations ...config.Attestation) *context.Context {
+	tb.Helper()
+	testlib.CheckPath(tb, "sh")
+
+	for i := range attestations {
+		if attestations[i].Predicate == "" {
+			attestations[i].Predicate = "provenance.json"
+		}
+	}
+	dist := tb.TempDir()
+	ctx := context.New(config.Project{
+		Dist:         dist,
+		Attestations: attestations,
+	})
+	ctx.Version = "1.0.0"
+	require.NoError(tb, Pipe{}.Default(ctx))
+
+	for _, name := range []string{"foo", "bar"} {
+		path := filepath.Join(dist, name)
+		require.NoError(tb, os.WriteFile(path, []byte(name), 0o644))
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: name,
+			Path: path,
+			Type: artifact.UploadableBinary,
+			Extra: map[string]interface{}{
+				artifact.ExtraID: name,
+			},
+		})
+	}
+	path := filepath.Join(dist, "checksums.txt")
+	require.NoError(tb, os.WriteFile(path, []byte("checksums"), 0o644))
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "checksums.txt",
+		Path: path,
+		Type: artifact.Checksum,
+	})
+	return ctx
+}
+
+func TestRun(t *testing.T) {
+	key := filepath.Join(t.TempDir(), "cosign.key")
+	require.NoError(t, os.WriteFile(key, []byte("key"), 0o600))
+	predicate := filepath.Join(t.TempDir(), "provenance.json")
+	require.NoError(t, os.WriteFile(predicate, []byte("{}"), 0o644))
+
+	ctx := newContext(t, config.Attestation{
+		Cmd: "sh",
+		Args: []string{
+			"-c",
+			"echo artifact=${artifact} key=${key} predicate=${predicate} env=${FOO} > ${output}",
+		},
+		Key:       key,
+		Predicate: predicate,
+		Env:       []string{"FOO={{ .Version }}"},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	attestations := ctx.Artifacts.Filter(artifact.ByType(artifact.Attestation)).List()
+	require.Len(t, attestations, 2)
+
+	var names []string
+	for _, att := range attestations {
+		names = append(names, att.Name)
+		require.Equal(t, "default", att.ID())
+		require.Equal(t, filepath.Join(ctx.Config.Dist, att.Name), att.Path)
+		require.False(t, artifact.ExtraOr(*att, artifact.ExtraSkipUpload, true))
+
+		bts, err := os.ReadFile(att.Path)
+		require.NoError(t, err)
+		binary := filepath.Join(ctx.Config.Dist, att.Name[:3])
+		require.Equal(t, "artifact="+binary+" key="+key+" predicate="+predicate+" env=1.0.0\n", string(bts))
+	}
+	require.ElementsMatch(t, []string{"foo.intoto.jsonl", "bar.intoto.jsonl"}, names)
+}
+
+func TestRunFiltered(t *testing.T) {
+	for name, tt := range map[string]struct {
+		attestation config.Attestation
+		expected    []string
+	}{
+		"ids": {
+			attestation: config.Attestation{IDs: []string{"foo"}},
+			expected:    []string{"foo.att"},
+		},
+		"checksum": {
+			attestation: config.Attestation{Artifacts: "checksum"},
+			expected:    []string{"checksums.txt.att"},
+		},
+		"all": {
+			attestation: config.Attestation{Artifacts: "all"},
+			expected:    []string{"foo.att", "bar.att", "checksums.txt.att"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			tt.attestation.Cmd = "sh"
+			tt.attestation.Args = []string{"-c", "echo attested > $output"}
+			tt.attestation.Output = "${artifact}.att"
+			tt.attestation.SkipUpload = true
+			ctx := newContext(t, tt.attestation)
+			require.NoError(t, Pipe{}.Run(ctx))
+
+			var names []string
+			for _, att := range ctx.Artifacts.Filter(artifact.ByType(artifact.Attestation)).List() {
+				names = append(names, att.Name)
+				require.True(t, artifact.ExtraOr(*att, artifact.ExtraSkipUpload, false))
+			}
+			require.ElementsMatch(t, tt.expected, names)
+			require.Empty(t, ctx.Artifacts.Filter(artifact.And(
+				artifact.ByType(artifact.Attestation),
+				artifact.OnlyUploadable,
+			)).List())
+		})
+	}
+}
+
+func TestRunKMSKey(t *testing.T) {
+	ctx := newContext(t, config.Attestation{
+		Cmd:  "sh",
+		Args: []string{"-c", "echo ${key} > ${output}"},
+		Key:  "awskms:///alias/goreleaser",
+		IDs:  []string{"foo"},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	bts, err := os.ReadFile(filepath.Join(ctx.Config.Dist, "foo.intoto.jsonl"))
+	require.NoError(t, err)
+	require.Equal(t, "awskms:///alias/goreleaser\n", string(bts))
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Run("invalid artifacts", func(t *testing.T) {
+		ctx := newContext(t, config.Attestation{Artifacts: "docker"})
+		require.EqualError(t, Pipe{}.Run(ctx), "invalid list of artifacts to attest: docker")
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		ctx := newContext(t, config.Attestation{
+			Cmd: "sh",
+			Key: filepath.Join(t.TempDir(), "nope.key"),
+		})
+		require.ErrorIs(t, Pipe{}.Run(ctx), os.ErrNotExist)
+	})
+
+	t.Run("command failed", func(t *testing.T) {
+		ctx := newContext(t, config.Attestation{
+			Cmd:  "sh",
+			Args: []string{"-c", "echo nope && exit 1"},
+		})
+		require.ErrorContains(t, Pipe{}.Run(ctx), "attest: sh failed: exit status 1: nope")
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		ctx := newContext(t, config.Attestation{
+			Cmd:    "sh",
+			Output: "{{ .Nope }}",
+		})
+		testlib.RequireTemplateError(t, Pipe{}.Run(ctx))
+	})
+}
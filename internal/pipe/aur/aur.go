@@ -5,7 +5,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"sort"
@@ -16,18 +15,15 @@ import (
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/commitauthor"
-	"github.com/goreleaser/goreleaser/internal/git"
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
-	"golang.org/x/crypto/ssh"
 )
 
 const (
-	aurExtra          = "AURConfig"
-	defaultSSHCommand = "ssh -i {{ .KeyPath }} -o StrictHostKeyChecking=accept-new -F /dev/null"
-	defaultCommitMsg  = "Update to {{ .Tag }}"
+	aurExtra         = "AURConfig"
+	defaultCommitMsg = "Update to {{ .Tag }}"
 )
 
 var ErrNoArchivesFound = errors.New("no linux archives found")
@@ -43,6 +39,11 @@ func (Pipe) Default(ctx *context.Context) error {
 		pkg := &ctx.Config.AURs[i]
 
 		pkg.CommitAuthor = commitauthor.Default(pkg.CommitAuthor)
+		if err := client.CheckSigning(pkg.CommitAuthor, config.RepoRef{
+			Git: config.GitRepoRef{URL: pkg.GitURL},
+		}); err != nil {
+			return err
+		}
 		if pkg.CommitMessageTemplate == "" {
 			pkg.CommitMessageTemplate = defaultCommitMsg
 		}
@@ -62,11 +63,20 @@ func (Pipe) Default(ctx *context.Context) error {
 			pkg.Rel = "1"
 		}
 		if pkg.GitSSHCommand == "" {
-			pkg.GitSSHCommand = defaultSSHCommand
+			pkg.GitSSHCommand = client.DefaultGitSSHCommand
 		}
 		if pkg.Goamd64 == "" {
 			pkg.Goamd64 = "v1"
 		}
+		for j := range pkg.Files {
+			file := &pkg.Files[j]
+			if file.Source == "" || file.Destination == "" {
+				return fmt.Errorf("aur: files must have both src and dst set")
+			}
+			if file.Mode == 0 {
+				file.Mode = 0o644
+			}
+		}
 	}
 
 	return nil
@@ -148,6 +158,13 @@ func doRun(ctx *context.Context, aur config.AUR, cl client.Client) error {
 		}
 		log.Warnf("guessing package to be %q", pkg)
 	}
+	lines, err := installFiles(ctx, aur.Files)
+	if err != nil {
+		return err
+	}
+	if len(lines) > 0 {
+		pkg = strings.TrimSpace(strings.Join(append([]string{pkg}, lines...), "\n"))
+	}
 	aur.Package = pkg
 
 	for _, info := range []struct {
@@ -195,6 +212,28 @@ func doRun(ctx *context.Context, aur config.AUR, cl client.Client) error {
 	return nil
 }
 
+// installFiles returns the package() install lines for the given extra files.
+func installFiles(ctx *context.Context, files []config.AURFile) ([]string, error) {
+	lines := make([]string, 0, len(files))
+	for _, file := range files {
+		src, err := tmpl.New(ctx).Apply(file.Source)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := tmpl.New(ctx).Apply(file.Destination)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, fmt.Sprintf(
+			`install -Dm%o "./%s" "${pkgdir}/%s"`,
+			file.Mode.Perm(),
+			strings.TrimPrefix(src, "./"),
+			strings.TrimPrefix(dst, "/"),
+		))
+	}
+	return lines, nil
+}
+
 func buildPkgFile(ctx *context.Context, pkg config.AUR, client client.Client, artifacts []*artifact.Artifact, tpl string) (string, error) {
 	data, err := dataFor(ctx, pkg, client, artifacts)
 	if err != nil {
@@ -372,12 +411,27 @@ func doPublish(ctx *context.Context, pkgs []*artifact.Artifact) error {
 	if err != nil {
 		return err
 	}
+	if key == "" {
+		return pipe.Skip("aur.private_key is empty")
+	}
+
+	key, cleanupKey, err := client.KeyPath(key, "aur.private_key")
+	if err != nil {
+		return err
+	}
+	defer cleanupKey()
 
-	key, err = keyPath(key)
+	knownHosts, err := tmpl.New(ctx).Apply(cfg.KnownHosts)
 	if err != nil {
 		return err
 	}
 
+	knownHosts, cleanupKnownHosts, err := client.KnownHostsPath(knownHosts, "aur.known_hosts")
+	if err != nil {
+		return err
+	}
+	defer cleanupKnownHosts()
+
 	url, err := tmpl.New(ctx).Apply(cfg.GitURL)
 	if err != nil {
 		return err
@@ -387,9 +441,7 @@ func doPublish(ctx *context.Context, pkgs []*artifact.Artifact) error {
 		return pipe.Skip("aur.git_url is empty")
 	}
 
-	sshcmd, err := tmpl.New(ctx).WithExtraFields(tmpl.Fields{
-		"KeyPath": key,
-	}).Apply(cfg.GitSSHCommand)
+	sshcmd, err := client.GitSSHCommand(ctx, cfg.GitSSHCommand, key, knownHosts)
 	if err != nil {
 		return err
 	}
@@ -412,20 +464,29 @@ func doPublish(ctx *context.Context, pkgs []*artifact.Artifact) error {
 	}
 
 	env := []string{fmt.Sprintf("GIT_SSH_COMMAND=%s", sshcmd)}
+	if author.Signing.Enabled {
+		// the whole environment is needed so git can find the signing keys.
+		env = append(ctx.Env.Strings(), env...)
+	}
+
+	signing, cleanup, err := client.SigningCmds(ctx, author.Signing)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
 
-	if err := runGitCmds(ctx, parent, env, [][]string{
+	if err := client.RunGitCmds(ctx, parent, env, [][]string{
 		{"clone", url, cfg.Name},
 	}); err != nil {
 		return fmt.Errorf("failed to setup local AUR repo: %w", err)
 	}
 
-	if err := runGitCmds(ctx, cwd, env, [][]string{
+	if err := client.RunGitCmds(ctx, cwd, env, append([][]string{
 		// setup auth et al
 		{"config", "--local", "user.name", author.Name},
 		{"config", "--local", "user.email", author.Email},
-		{"config", "--local", "commit.gpgSign", "false"},
 		{"config", "--local", "init.defaultBranch", "master"},
-	}); err != nil {
+	}, signing...)); err != nil {
 		return fmt.Errorf("failed to setup local AUR repo: %w", err)
 	}
 
@@ -441,7 +502,7 @@ func doPublish(ctx *context.Context, pkgs []*artifact.Artifact) error {
 	}
 
 	log.WithField("repo", url).WithField("name", cfg.Name).Info("pushing")
-	if err := runGitCmds(ctx, cwd, env, [][]string{
+	if err := client.RunGitCmds(ctx, cwd, env, [][]string{
 		{"add", "-A", "."},
 		{"commit", "-m", msg},
 		{"push", "origin", "HEAD"},
@@ -451,55 +512,3 @@ func doPublish(ctx *context.Context, pkgs []*artifact.Artifact) error {
 
 	return nil
 }
-
-func keyPath(key string) (string, error) {
-	if key == "" {
-		return "", pipe.Skip("aur.private_key is empty")
-	}
-
-	path := key
-	if _, err := ssh.ParsePrivateKey([]byte(key)); err == nil {
-		// if it can be parsed as a valid private key, we write it to a
-		// temp file and use that path on GIT_SSH_COMMAND.
-		f, err := os.CreateTemp("", "id_*")
-		if err != nil {
-			return "", fmt.Errorf("failed to store private key: %w", err)
-		}
-		defer f.Close()
-
-		// the key needs to EOF at an empty line, seems like github actions
-		// is somehow removing them.
-		if !strings.HasSuffix(key, "\n") {
-			key += "\n"
-		}
-
-		if _, err := io.WriteString(f, key); err != nil {
-			return "", fmt.Errorf("failed to store private key: %w", err)
-		}
-		if err := f.Close(); err != nil {
-			return "", fmt.Errorf("failed to store private key: %w", err)
-		}
-		path = f.Name()
-	}
-
-	if _, err := os.Stat(path); err != nil {
-		return "", fmt.Errorf("could not stat aur.private_key: %w", err)
-	}
-
-	// in any case, ensure the key has the correct permissions.
-	if err := os.Chmod(path, 0o600); err != nil {
-		return "", fmt.Errorf("failed to ensure aur.private_key permissions: %w", err)
-	}
-
-	return path, nil
-}
-
-func runGitCmds(ctx *context.Context, cwd string, env []string, cmds [][]string) error {
-	for _, cmd := range cmds {
-		args := append([]string{"-C", cwd}, cmd...)
-		if _, err := git.Clean(git.RunWithEnv(ctx, env, args...)); err != nil {
-			return fmt.Errorf("%q failed: %w", strings.Join(cmd, " "), err)
-		}
-	}
-	return nil
-}
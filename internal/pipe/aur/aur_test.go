@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"testing"
 
 	"github.com/charmbracelet/keygen"
@@ -211,6 +210,12 @@ func TestFullPipe(t *testing.T) {
 			},
 			expectedPublishError: `could not stat aur.private_key: stat testdata/nope: no such file or directory`,
 		},
+		"known-hosts-not-found": {
+			prepare: func(ctx *context.Context) {
+				ctx.Config.AURs[0].KnownHosts = "testdata/nope"
+			},
+			expectedPublishError: `could not stat aur.known_hosts: stat testdata/nope: no such file or directory`,
+		},
 		"invalid-git-url-template": {
 			prepare: func(ctx *context.Context) {
 				ctx.Config.AURs[0].GitURL = "{{ .Asdsa }"
@@ -468,6 +473,88 @@ func TestRunPipe(t *testing.T) {
 	requireEqualRepoFiles(t, folder, "foo", url)
 }
 
+func TestRunPipeWithFiles(t *testing.T) {
+	folder := t.TempDir()
+	ctx := context.New(config.Project{
+		Dist:        folder,
+		ProjectName: "foo",
+		AURs: []config.AUR{
+			{
+				License:     "MIT",
+				Description: "A run pipe test aur",
+				Homepage:    "https://github.com/goreleaser",
+				Files: []config.AURFile{
+					{Source: "./completions/{{ .ProjectName }}.bash", Destination: "/usr/share/bash-completion/completions/{{ .ProjectName }}"},
+					{Source: "completions/foo.zsh", Destination: "/usr/share/zsh/site-functions/_foo"},
+					{Source: "completions/foo.fish", Destination: "/usr/share/fish/vendor_completions.d/foo.fish"},
+					{Source: "manpages/foo.1.gz", Destination: "/usr/share/man/man1/foo.1.gz"},
+				},
+			},
+		},
+	})
+	ctx.TokenType = context.TokenTypeGitHub
+	ctx.Git = context.GitInfo{
+		CurrentTag: "v1.0.1",
+	}
+	ctx.Semver = context.Semver{
+		Major: 1,
+		Minor: 0,
+		Patch: 1,
+	}
+	ctx.Version = "1.0.1"
+
+	path := filepath.Join(folder, "bin.tar.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    "bin.tar.gz",
+		Path:    path,
+		Goos:    "linux",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Type:    artifact.UploadableArchive,
+		Extra: map[string]interface{}{
+			artifact.ExtraID:       "foo",
+			artifact.ExtraFormat:   "tar.gz",
+			artifact.ExtraBinaries: []string{"foo"},
+		},
+	})
+
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, runAll(ctx, client.NewMock()))
+
+	bts, err := os.ReadFile(filepath.Join(folder, "aur", "foo-bin.pkgbuild"))
+	require.NoError(t, err)
+	golden.RequireEqualExt(t, bts, ".pkgbuild")
+}
+
+func TestRunPipeFilesTemplateError(t *testing.T) {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		AURs: []config.AUR{
+			{
+				Files: []config.AURFile{
+					{Source: "{{ .Nope }}", Destination: "/usr/share/man/man1/foo.1.gz"},
+				},
+			},
+		},
+	})
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    "bin.tar.gz",
+		Goos:    "linux",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Type:    artifact.UploadableArchive,
+		Extra: map[string]interface{}{
+			artifact.ExtraFormat:   "tar.gz",
+			artifact.ExtraBinaries: []string{"foo"},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	testlib.RequireTemplateError(t, runAll(ctx, client.NewMock()))
+}
+
 func TestRunPipeNoBuilds(t *testing.T) {
 	ctx := context.New(
 		config.Project{
@@ -642,7 +729,7 @@ func TestDefault(t *testing.T) {
 			Provides:              []string{"myproject"},
 			Rel:                   "1",
 			CommitMessageTemplate: defaultCommitMsg,
-			GitSSHCommand:         defaultSSHCommand,
+			GitSSHCommand:         client.DefaultGitSSHCommand,
 			Goamd64:               "v1",
 			CommitAuthor: config.CommitAuthor{
 				Name:  "goreleaserbot",
@@ -670,7 +757,7 @@ func TestDefault(t *testing.T) {
 			Provides:              []string{"myproject"},
 			Rel:                   "1",
 			CommitMessageTemplate: defaultCommitMsg,
-			GitSSHCommand:         defaultSSHCommand,
+			GitSSHCommand:         client.DefaultGitSSHCommand,
 			Goamd64:               "v1",
 			CommitAuthor: config.CommitAuthor{
 				Name:  "goreleaserbot",
@@ -699,7 +786,7 @@ func TestDefault(t *testing.T) {
 			Provides:              []string{"myproject"},
 			Rel:                   "1",
 			CommitMessageTemplate: defaultCommitMsg,
-			GitSSHCommand:         defaultSSHCommand,
+			GitSSHCommand:         client.DefaultGitSSHCommand,
 			Goamd64:               "v3",
 			CommitAuthor: config.CommitAuthor{
 				Name:  "goreleaserbot",
@@ -707,6 +794,50 @@ func TestDefault(t *testing.T) {
 			},
 		}, ctx.Config.AURs[0])
 	})
+
+	t.Run("files", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			ProjectName: "myproject",
+			AURs: []config.AUR{
+				{
+					Files: []config.AURFile{
+						{Source: "completions/foo.bash", Destination: "/usr/share/bash-completion/completions/foo"},
+						{Source: "foo.sh", Destination: "/usr/bin/foo.sh", Mode: 0o755},
+					},
+				},
+			},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.Equal(t, []config.AURFile{
+			{Source: "completions/foo.bash", Destination: "/usr/share/bash-completion/completions/foo", Mode: 0o644},
+			{Source: "foo.sh", Destination: "/usr/bin/foo.sh", Mode: 0o755},
+		}, ctx.Config.AURs[0].Files)
+	})
+
+	t.Run("files without dst", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			ProjectName: "myproject",
+			AURs: []config.AUR{
+				{
+					Files: []config.AURFile{{Source: "foo.1.gz"}},
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), "aur: files must have both src and dst set")
+	})
+}
+
+func TestDefaultSigningWithoutGit(t *testing.T) {
+	ctx := context.New(config.Project{
+		AURs: []config.AUR{
+			{
+				CommitAuthor: config.CommitAuthor{
+					Signing: config.CommitSigning{Enabled: true},
+				},
+			},
+		},
+	})
+	require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrSigningRequiresGit)
 }
 
 func TestSkip(t *testing.T) {
@@ -724,53 +855,6 @@ func TestSkip(t *testing.T) {
 	})
 }
 
-func TestKeyPath(t *testing.T) {
-	t.Run("with valid path", func(t *testing.T) {
-		path := makeKey(t, keygen.Ed25519)
-		result, err := keyPath(path)
-		require.NoError(t, err)
-		require.Equal(t, path, result)
-	})
-	t.Run("with invalid path", func(t *testing.T) {
-		result, err := keyPath("testdata/nope")
-		require.EqualError(t, err, `could not stat aur.private_key: stat testdata/nope: no such file or directory`)
-		require.Equal(t, "", result)
-	})
-	t.Run("with key", func(t *testing.T) {
-		for _, algo := range []keygen.KeyType{keygen.Ed25519, keygen.RSA} {
-			t.Run(string(algo), func(t *testing.T) {
-				path := makeKey(t, algo)
-				bts, err := os.ReadFile(path)
-				require.NoError(t, err)
-
-				result, err := keyPath(string(bts))
-				require.NoError(t, err)
-
-				resultbts, err := os.ReadFile(result)
-				require.NoError(t, err)
-				require.Equal(t, string(bts), string(resultbts))
-			})
-		}
-	})
-	t.Run("empty", func(t *testing.T) {
-		result, err := keyPath("")
-		require.EqualError(t, err, `aur.private_key is empty`)
-		require.Equal(t, "", result)
-	})
-	t.Run("with invalid EOF", func(t *testing.T) {
-		path := makeKey(t, keygen.Ed25519)
-		bts, err := os.ReadFile(path)
-		require.NoError(t, err)
-
-		result, err := keyPath(strings.TrimSpace(string(bts)))
-		require.NoError(t, err)
-
-		resultbts, err := os.ReadFile(result)
-		require.NoError(t, err)
-		require.Equal(t, string(bts), string(resultbts))
-	})
-}
-
 func makeBareRepo(tb testing.TB) string {
 	tb.Helper()
 	dir := tb.TempDir()
@@ -26,6 +26,20 @@ func (Pipe) Default(ctx *context.Context) error {
 		if blob.Folder == "" {
 			blob.Folder = "{{ .ProjectName }}/{{ .Tag }}"
 		}
+		if blob.Parallelism == 0 {
+			blob.Parallelism = ctx.Parallelism
+		}
+		switch blob.SSE {
+		case "", sseAES256, sseKMS:
+		default:
+			return fmt.Errorf("invalid sse %q, must be one of: %s, %s", blob.SSE, sseAES256, sseKMS)
+		}
+		if blob.SSEKMSKeyID != "" && blob.SSE != sseKMS {
+			return fmt.Errorf("sse_kms_key_id can only be set when sse is %s", sseKMS)
+		}
+		if blob.PartSize < 0 {
+			return fmt.Errorf("invalid part_size %d, must be positive", blob.PartSize)
+		}
 	}
 	return nil
 }
@@ -36,7 +50,7 @@ func (Pipe) Publish(ctx *context.Context) error {
 	for _, conf := range ctx.Config.Blobs {
 		conf := conf
 		g.Go(func() error {
-			return doUpload(ctx, conf)
+			return doUpload(ctx, conf, &productionUploader{})
 		})
 	}
 	return g.Wait()
@@ -0,0 +1,69 @@
+//go:build integration
+
+package blob
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
+)
+
+// TestB2Upload uploads to a real Backblaze B2 bucket.
+//
+// It requires B2_BUCKET and B2_REGION to be set, as well as
+// AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY set to a B2 application key ID
+// and application key, respectively.
+//
+//	go test -tags integration -run TestB2Upload ./internal/pipe/blob/...
+func TestB2Upload(t *testing.T) {
+	bucket := os.Getenv("B2_BUCKET")
+	region := os.Getenv("B2_REGION")
+	if bucket == "" || region == "" {
+		t.Skip("B2_BUCKET and B2_REGION must be set")
+	}
+
+	folder := t.TempDir()
+	tgzpath := filepath.Join(folder, "bin.tar.gz")
+	require.NoError(t, os.WriteFile(tgzpath, []byte("fake\ntargz"), 0o744))
+
+	ctx := context.New(config.Project{
+		Dist:        folder,
+		ProjectName: "testupload",
+		Blobs: []config.Blob{
+			{
+				Provider: "b2",
+				Bucket:   bucket,
+				Region:   region,
+			},
+		},
+	})
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Type: artifact.UploadableArchive,
+		Name: "bin.tar.gz",
+		Path: tgzpath,
+	})
+
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Publish(ctx))
+
+	bucketURL, err := urlFor(ctx, ctx.Config.Blobs[0])
+	require.NoError(t, err)
+	conn, err := blob.OpenBucket(ctx, bucketURL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	key := "testupload/v1.0.0/bin.tar.gz"
+	t.Cleanup(func() {
+		require.NoError(t, conn.Delete(ctx, key))
+	})
+	bts, err := conn.ReadAll(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, "fake\ntargz", string(bts))
+}
@@ -2,12 +2,21 @@ package blob
 
 import (
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
 )
 
 func TestDescription(t *testing.T) {
@@ -82,15 +91,17 @@ func TestDefaults(t *testing.T) {
 	require.NoError(t, Pipe{}.Default(ctx))
 	require.Equal(t, []config.Blob{
 		{
-			Bucket:   "foo",
-			Provider: "azblob",
-			Folder:   "{{ .ProjectName }}/{{ .Tag }}",
-			IDs:      []string{"foo", "bar"},
+			Bucket:      "foo",
+			Provider:    "azblob",
+			Folder:      "{{ .ProjectName }}/{{ .Tag }}",
+			IDs:         []string{"foo", "bar"},
+			Parallelism: 4,
 		},
 		{
-			Bucket:   "foobar",
-			Provider: "gcs",
-			Folder:   "{{ .ProjectName }}/{{ .Tag }}",
+			Bucket:      "foobar",
+			Provider:    "gcs",
+			Folder:      "{{ .ProjectName }}/{{ .Tag }}",
+			Parallelism: 4,
 		},
 	}, ctx.Config.Blobs)
 }
@@ -140,6 +151,57 @@ func TestURL(t *testing.T) {
 		require.Equal(t, "s3://foo?disableSSL=true&region=us-west-1", url)
 	})
 
+	t.Run("s3 with endpoint and path style disabled", func(t *testing.T) {
+		url, err := urlFor(context.New(config.Project{}), config.Blob{
+			Bucket:           "foo",
+			Provider:         "s3",
+			Region:           "us-west-1",
+			Endpoint:         "s3.foobar.com",
+			S3ForcePathStyle: boolPtr(false),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "s3://foo?endpoint=s3.foobar.com&region=us-west-1", url)
+	})
+
+	t.Run("s3 with path style", func(t *testing.T) {
+		url, err := urlFor(context.New(config.Project{}), config.Blob{
+			Bucket:           "foo",
+			Provider:         "s3",
+			S3ForcePathStyle: boolPtr(true),
+		})
+		require.NoError(t, err)
+		require.Equal(t, "s3://foo?s3ForcePathStyle=true", url)
+	})
+
+	t.Run("b2", func(t *testing.T) {
+		url, err := urlFor(context.New(config.Project{}), config.Blob{
+			Bucket:   "foo",
+			Provider: "b2",
+			Region:   "us-west-004",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "s3://foo?endpoint=https%3A%2F%2Fs3.us-west-004.backblazeb2.com&region=us-west-004&s3ForcePathStyle=true", url)
+	})
+
+	t.Run("b2 with endpoint", func(t *testing.T) {
+		url, err := urlFor(context.New(config.Project{}), config.Blob{
+			Bucket:   "foo",
+			Provider: "b2",
+			Region:   "eu-central-003",
+			Endpoint: "https://b2.foobar.com",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "s3://foo?endpoint=https%3A%2F%2Fb2.foobar.com&region=eu-central-003&s3ForcePathStyle=true", url)
+	})
+
+	t.Run("b2 without region", func(t *testing.T) {
+		_, err := urlFor(context.New(config.Project{}), config.Blob{
+			Bucket:   "foo",
+			Provider: "b2",
+		})
+		require.EqualError(t, err, "region is required when using the b2 provider")
+	})
+
 	t.Run("gs with opts", func(t *testing.T) {
 		url, err := urlFor(context.New(config.Project{}), config.Blob{
 			Bucket:     "foo",
@@ -216,3 +278,387 @@ func TestSkip(t *testing.T) {
 		require.False(t, Pipe{}.Skip(ctx))
 	})
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestDetectContentType(t *testing.T) {
+	for name, expected := range map[string]string{
+		"checksums.txt":       "text/plain; charset=utf-8",
+		"foo.tar.gz.sha256":   "text/plain; charset=utf-8",
+		"foo.tar.gz":          "application/gzip",
+		"foo.json":            "application/json",
+		"FOO.JSON":            "application/json",
+		"foo.unknown-ext-123": "application/octet-stream",
+		"foo":                 "application/octet-stream",
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, expected, detectContentType(name))
+		})
+	}
+}
+
+func TestWriterOptions(t *testing.T) {
+	art := &artifact.Artifact{
+		Name: "foo.tar.gz",
+		Path: "dist/foo.tar.gz",
+		Type: artifact.UploadableArchive,
+	}
+
+	t.Run("detected", func(t *testing.T) {
+		opts, err := writerOptions(context.New(config.Project{}), config.Blob{}, art)
+		require.NoError(t, err)
+		require.Equal(t, "application/gzip", opts.ContentType)
+		require.Equal(t, "attachment; filename=foo.tar.gz", opts.ContentDisposition)
+	})
+
+	t.Run("override", func(t *testing.T) {
+		opts, err := writerOptions(context.New(config.Project{}), config.Blob{
+			ContentType: `{{ if eq .ArtifactExt ".tar.gz" }}application/x-gtar{{ end }}`,
+		}, &artifact.Artifact{
+			Name: "foo.tar.gz",
+			Extra: map[string]interface{}{
+				artifact.ExtraExt: ".tar.gz",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "application/x-gtar", opts.ContentType)
+	})
+
+	t.Run("override evaluates to empty", func(t *testing.T) {
+		opts, err := writerOptions(context.New(config.Project{}), config.Blob{
+			ContentType: `{{ if eq .ArtifactName "nope" }}text/plain{{ end }}`,
+		}, art)
+		require.NoError(t, err)
+		require.Equal(t, "application/gzip", opts.ContentType)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := writerOptions(context.New(config.Project{}), config.Blob{
+			ContentType: "{{ .Nope }}",
+		}, art)
+		testlib.RequireTemplateError(t, err)
+	})
+
+	t.Run("no cache control nor metadata", func(t *testing.T) {
+		opts, err := writerOptions(context.New(config.Project{}), config.Blob{}, art)
+		require.NoError(t, err)
+		require.Empty(t, opts.CacheControl)
+		require.Nil(t, opts.Metadata)
+	})
+
+	t.Run("cache control and metadata", func(t *testing.T) {
+		ctx := context.New(config.Project{ProjectName: "proj"})
+		ctx.Git.CurrentTag = "v1.2.3"
+		opts, err := writerOptions(ctx, config.Blob{
+			CacheControl: "public, max-age=31536000, immutable",
+			Metadata: map[string]string{
+				"project": "{{ .ProjectName }}",
+				"version": "{{ .Tag }}",
+				"name":    "{{ .ArtifactName }}",
+			},
+		}, art)
+		require.NoError(t, err)
+		require.Equal(t, "public, max-age=31536000, immutable", opts.CacheControl)
+		require.Equal(t, map[string]string{
+			"project": "proj",
+			"version": "v1.2.3",
+			"name":    "foo.tar.gz",
+		}, opts.Metadata)
+	})
+
+	t.Run("part size", func(t *testing.T) {
+		opts, err := writerOptions(context.New(config.Project{}), config.Blob{
+			PartSize: 10 * 1024 * 1024,
+		}, art)
+		require.NoError(t, err)
+		require.Equal(t, 10*1024*1024, opts.BufferSize)
+	})
+
+	t.Run("invalid cache control template", func(t *testing.T) {
+		_, err := writerOptions(context.New(config.Project{}), config.Blob{
+			CacheControl: "{{ .Nope }}",
+		}, art)
+		testlib.RequireTemplateError(t, err)
+	})
+
+	t.Run("invalid metadata template", func(t *testing.T) {
+		_, err := writerOptions(context.New(config.Project{}), config.Blob{
+			Metadata: map[string]string{
+				"foo": "{{ .Nope }}",
+			},
+		}, art)
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+type fakeUploader struct {
+	lock    sync.Mutex
+	opts    map[string]*blob.WriterOptions
+	fail    string
+	current int32
+	max     int32
+	// started and release, when set, block every upload until release is
+	// closed, so tests can force uploads to overlap.
+	started chan struct{}
+	release chan struct{}
+}
+
+func (u *fakeUploader) Close() error                                { return nil }
+func (u *fakeUploader) Open(ctx *context.Context, url string) error { return nil }
+func (u *fakeUploader) Upload(ctx *context.Context, path string, data []byte, opts *blob.WriterOptions) error {
+	current := atomic.AddInt32(&u.current, 1)
+	defer atomic.AddInt32(&u.current, -1)
+	for {
+		max := atomic.LoadInt32(&u.max)
+		if current <= max || atomic.CompareAndSwapInt32(&u.max, max, current) {
+			break
+		}
+	}
+	if u.started != nil {
+		u.started <- struct{}{}
+		<-u.release
+	}
+
+	if path == u.fail {
+		return fmt.Errorf("failed to upload %s", path)
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.opts[path] = opts
+	return nil
+}
+
+func newUploadContext(tb testing.TB, n, parallelism int) *context.Context {
+	tb.Helper()
+	folder := tb.TempDir()
+	ctx := context.New(config.Project{
+		ProjectName: "proj",
+		Blobs: []config.Blob{
+			{
+				Bucket:      "foo",
+				Provider:    "s3",
+				Parallelism: parallelism,
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("bin%d.tar.gz", i)
+		file := filepath.Join(folder, name)
+		require.NoError(tb, os.WriteFile(file, []byte("foo"), 0o644))
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: name,
+			Path: file,
+			Type: artifact.UploadableArchive,
+		})
+	}
+	return ctx
+}
+
+func TestUploadParallelism(t *testing.T) {
+	ctx := newUploadContext(t, 12, 3)
+	require.NoError(t, Pipe{}.Default(ctx))
+	up := &fakeUploader{
+		opts:    map[string]*blob.WriterOptions{},
+		started: make(chan struct{}, 12),
+		release: make(chan struct{}),
+	}
+	errs := make(chan error, 1)
+	go func() {
+		errs <- doUpload(ctx, ctx.Config.Blobs[0], up)
+	}()
+	// only let the uploads finish once 3 of them are running at once.
+	for i := 0; i < 3; i++ {
+		<-up.started
+	}
+	close(up.release)
+	require.NoError(t, <-errs)
+	require.Len(t, up.opts, 12)
+	require.Equal(t, int32(3), up.max)
+}
+
+func TestUploadCollectsErrors(t *testing.T) {
+	ctx := newUploadContext(t, 5, 2)
+	require.NoError(t, Pipe{}.Default(ctx))
+	up := &fakeUploader{
+		opts: map[string]*blob.WriterOptions{},
+		fail: "proj/v1.0.0/bin1.tar.gz",
+	}
+	err := doUpload(ctx, ctx.Config.Blobs[0], up)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to write to bucket: failed to upload proj/v1.0.0/bin1.tar.gz")
+	require.Len(t, up.opts, 4)
+}
+
+func TestDefaultsParallelism(t *testing.T) {
+	ctx := context.New(config.Project{
+		Blobs: []config.Blob{
+			{
+				Bucket:   "foo",
+				Provider: "s3",
+			},
+			{
+				Bucket:      "foo",
+				Provider:    "s3",
+				Parallelism: 20,
+			},
+		},
+	})
+	ctx.Parallelism = 7
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, 7, ctx.Config.Blobs[0].Parallelism)
+	require.Equal(t, 20, ctx.Config.Blobs[1].Parallelism)
+}
+
+func TestUploadDataContentType(t *testing.T) {
+	folder := t.TempDir()
+	ctx := context.New(config.Project{})
+	up := &fakeUploader{opts: map[string]*blob.WriterOptions{}}
+	for name, expected := range map[string]string{
+		"checksums.txt": "text/plain; charset=utf-8",
+		"foo.tar.gz":    "application/gzip",
+		"foo.zip":       "application/zip",
+	} {
+		file := filepath.Join(folder, name)
+		require.NoError(t, os.WriteFile(file, []byte("foo"), 0o644))
+		require.NoError(t, uploadData(ctx, config.Blob{}, up, &artifact.Artifact{
+			Name: name,
+			Path: file,
+		}, "folder/"+name, "s3://foo"))
+		require.Equal(t, expected, up.opts["folder/"+name].ContentType, name)
+	}
+}
+
+func TestDefaultsSSE(t *testing.T) {
+	for name, tt := range map[string]struct {
+		sse      string
+		kmsKeyID string
+		err      string
+	}{
+		"none":            {},
+		"aes256":          {sse: "AES256"},
+		"kms":             {sse: "aws:kms"},
+		"kms with key":    {sse: "aws:kms", kmsKeyID: "arn:aws:kms:us-east-1:123:key/abc"},
+		"invalid":         {sse: "aes128", err: `invalid sse "aes128", must be one of: AES256, aws:kms`},
+		"key without kms": {sse: "AES256", kmsKeyID: "abc", err: "sse_kms_key_id can only be set when sse is aws:kms"},
+		"key without sse": {kmsKeyID: "abc", err: "sse_kms_key_id can only be set when sse is aws:kms"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Blobs: []config.Blob{
+					{
+						Bucket:      "foo",
+						Provider:    "s3",
+						SSE:         tt.sse,
+						SSEKMSKeyID: tt.kmsKeyID,
+					},
+				},
+			})
+			err := Pipe{}.Default(ctx)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestUploadSSE(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "fake")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "fake")
+
+	var lock sync.Mutex
+	headers := map[string]http.Header{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			lock.Lock()
+			headers[r.URL.Path] = r.Header.Clone()
+			lock.Unlock()
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("ETag", `"fake"`)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx := newUploadContext(t, 1, 1)
+	ctx.Env["KMS_KEY"] = "arn:aws:kms:us-east-1:123:key/abc"
+	ctx.Config.Blobs[0].Region = "us-east-1"
+	ctx.Config.Blobs[0].Endpoint = srv.URL
+	ctx.Config.Blobs[0].DisableSSL = true
+	ctx.Config.Blobs[0].SSE = "aws:kms"
+	ctx.Config.Blobs[0].SSEKMSKeyID = "{{ .Env.KMS_KEY }}"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Publish(ctx))
+
+	h, ok := headers["/foo/proj/v1.0.0/bin0.tar.gz"]
+	require.True(t, ok, "object was not uploaded: %v", headers)
+	require.Equal(t, "aws:kms", h.Get("X-Amz-Server-Side-Encryption"))
+	require.Equal(t, "arn:aws:kms:us-east-1:123:key/abc", h.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"))
+}
+
+func TestDefaultsPartSize(t *testing.T) {
+	for name, tt := range map[string]struct {
+		provider string
+		partSize int64
+		err      string
+	}{
+		"none":     {provider: "s3"},
+		"s3":       {provider: "s3", partSize: 5 * 1024 * 1024},
+		"gs":       {provider: "gs", partSize: 1024},
+		"negative": {provider: "gs", partSize: -1, err: "invalid part_size -1, must be positive"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Blobs: []config.Blob{
+					{
+						Bucket:   "foo",
+						Provider: tt.provider,
+						PartSize: tt.partSize,
+					},
+				},
+			})
+			err := Pipe{}.Default(ctx)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestUploadPartSize(t *testing.T) {
+	for name, tt := range map[string]struct {
+		provider string
+		region   string
+		partSize int64
+		err      string
+	}{
+		"s3":           {provider: "s3", partSize: 5 * 1024 * 1024},
+		"s3 too small": {provider: "s3", partSize: 1024, err: "invalid part_size 1024, s3 requires parts of at least 5242880 bytes"},
+		"templated s3": {provider: "{{ .Env.PROVIDER }}", partSize: 1024, err: "invalid part_size 1024, s3 requires parts of at least 5242880 bytes"},
+		"b2 too small": {provider: "b2", region: "us-west-002", partSize: 1024, err: "invalid part_size 1024, s3 requires parts of at least 5242880 bytes"},
+		"gs":           {provider: "gs", partSize: 1024},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := newUploadContext(t, 1, 1)
+			ctx.Env["PROVIDER"] = "s3"
+			ctx.Config.Blobs[0].Provider = tt.provider
+			ctx.Config.Blobs[0].Region = tt.region
+			ctx.Config.Blobs[0].PartSize = tt.partSize
+			require.NoError(t, Pipe{}.Default(ctx))
+			up := &fakeUploader{opts: map[string]*blob.WriterOptions{}}
+			err := doUpload(ctx, ctx.Config.Blobs[0], up)
+			if tt.err == "" {
+				require.NoError(t, err)
+				require.Len(t, up.opts, 1)
+				return
+			}
+			require.EqualError(t, err, tt.err)
+			require.Empty(t, up.opts)
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package blob
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/url"
 	"os"
 	"path"
 	"strings"
+	"sync"
 
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/extrafiles"
@@ -15,6 +20,7 @@ import (
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/hashicorp/go-multierror"
 	"gocloud.dev/blob"
 	"gocloud.dev/secrets"
 
@@ -29,6 +35,14 @@ import (
 	_ "gocloud.dev/secrets/gcpkms"
 )
 
+const (
+	sseAES256 = "AES256"
+	sseKMS    = "aws:kms"
+
+	// minS3PartSize is the minimum size of each part of a s3 multipart upload.
+	minS3PartSize = 5 * 1024 * 1024
+)
+
 func urlFor(ctx *context.Context, conf config.Blob) (string, error) {
 	bucket, err := tmpl.New(ctx).Apply(conf.Bucket)
 	if err != nil {
@@ -41,7 +55,7 @@ func urlFor(ctx *context.Context, conf config.Blob) (string, error) {
 	}
 
 	bucketURL := fmt.Sprintf("%s://%s", provider, bucket)
-	if provider != "s3" {
+	if provider != "s3" && provider != "b2" {
 		return bucketURL, nil
 	}
 
@@ -51,15 +65,36 @@ func urlFor(ctx *context.Context, conf config.Blob) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if endpoint != "" {
-		query.Add("endpoint", endpoint)
-		query.Add("s3ForcePathStyle", "true")
-	}
 
 	region, err := tmpl.New(ctx).Apply(conf.Region)
 	if err != nil {
 		return "", err
 	}
+
+	if provider == "b2" {
+		// Backblaze B2 is accessed through its S3-compatible API.
+		bucketURL = "s3://" + bucket
+		if endpoint == "" {
+			if region == "" {
+				return "", errors.New("region is required when using the b2 provider")
+			}
+			endpoint = fmt.Sprintf("https://s3.%s.backblazeb2.com", region)
+		}
+	}
+
+	if endpoint != "" {
+		query.Add("endpoint", endpoint)
+	}
+	// path-style addressing is the default for custom endpoints, as most
+	// s3-compatible backends don't support virtual-hosted-style requests.
+	forcePathStyle := endpoint != ""
+	if conf.S3ForcePathStyle != nil {
+		forcePathStyle = *conf.S3ForcePathStyle
+	}
+	if forcePathStyle {
+		query.Add("s3ForcePathStyle", "true")
+	}
+
 	if region != "" {
 		query.Add("region", region)
 	}
@@ -75,10 +110,21 @@ func urlFor(ctx *context.Context, conf config.Blob) (string, error) {
 	return bucketURL, nil
 }
 
+// checkPartSize checks the part size against the minimum required by the
+// driver the given bucket URL is opened with.
+// The provider might be a template, and b2 also uses the s3 driver, so this
+// can only be checked once the URL is known.
+func checkPartSize(bucketURL string, partSize int64) error {
+	if partSize > 0 && strings.HasPrefix(bucketURL, "s3://") && partSize < minS3PartSize {
+		return fmt.Errorf("invalid part_size %d, s3 requires parts of at least %d bytes", partSize, minS3PartSize)
+	}
+	return nil
+}
+
 // Takes goreleaser context(which includes artificats) and bucketURL for
 // upload to destination (eg: gs://gorelease-bucket) using the given uploader
 // implementation.
-func doUpload(ctx *context.Context, conf config.Blob) error {
+func doUpload(ctx *context.Context, conf config.Blob, up uploader) error {
 	folder, err := tmpl.New(ctx).Apply(conf.Folder)
 	if err != nil {
 		return err
@@ -89,6 +135,9 @@ func doUpload(ctx *context.Context, conf config.Blob) error {
 	if err != nil {
 		return err
 	}
+	if err := checkPartSize(bucketURL, conf.PartSize); err != nil {
+		return err
+	}
 
 	filter := artifact.Or(
 		artifact.ByType(artifact.UploadableArchive),
@@ -99,57 +148,170 @@ func doUpload(ctx *context.Context, conf config.Blob) error {
 		artifact.ByType(artifact.Certificate),
 		artifact.ByType(artifact.LinuxPackage),
 		artifact.ByType(artifact.SBOM),
+		artifact.And(
+			artifact.ByType(artifact.Attestation),
+			artifact.OnlyUploadable,
+		),
 	)
 	if len(conf.IDs) > 0 {
 		filter = artifact.And(filter, artifact.ByIDs(conf.IDs...))
 	}
 
-	up := &productionUploader{}
 	if err := up.Open(ctx, bucketURL); err != nil {
 		return handleError(err, bucketURL)
 	}
 	defer up.Close()
 
-	g := semerrgroup.New(ctx.Parallelism)
-	for _, artifact := range ctx.Artifacts.Filter(filter).List() {
-		artifact := artifact
-		g.Go(func() error {
-			// TODO: replace this with ?prefix=folder on the bucket url
-			dataFile := artifact.Path
-			uploadFile := path.Join(folder, artifact.Name)
-
-			return uploadData(ctx, conf, up, dataFile, uploadFile, bucketURL)
-		})
-	}
+	artifacts := ctx.Artifacts.Filter(filter).List()
 
 	files, err := extrafiles.Find(ctx, conf.ExtraFiles)
 	if err != nil {
 		return err
 	}
 	for name, fullpath := range files {
-		name := name
-		fullpath := fullpath
+		artifacts = append(artifacts, &artifact.Artifact{
+			Name: name,
+			Path: fullpath,
+			Type: artifact.UploadableFile,
+		})
+	}
+
+	// errors are collected instead of returned to the group, so a failed
+	// upload doesn't prevent the others from being attempted.
+	var lock sync.Mutex
+	var result error
+	g := semerrgroup.New(conf.Parallelism)
+	for _, art := range artifacts {
+		art := art
 		g.Go(func() error {
-			uploadFile := path.Join(folder, name)
-			return uploadData(ctx, conf, up, fullpath, uploadFile, bucketURL)
+			// TODO: replace this with ?prefix=folder on the bucket url
+			uploadFile := path.Join(folder, art.Name)
+			if err := uploadData(ctx, conf, up, art, uploadFile, bucketURL); err != nil {
+				lock.Lock()
+				result = multierror.Append(result, err)
+				lock.Unlock()
+			}
+			return nil
 		})
 	}
 
-	return g.Wait()
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	return result
 }
 
-func uploadData(ctx *context.Context, conf config.Blob, up uploader, dataFile, uploadFile, bucketURL string) error {
-	data, err := getData(ctx, conf, dataFile)
+func uploadData(ctx *context.Context, conf config.Blob, up uploader, art *artifact.Artifact, uploadFile, bucketURL string) error {
+	data, err := getData(ctx, conf, art.Path)
 	if err != nil {
 		return err
 	}
 
-	if err := up.Upload(ctx, uploadFile, data); err != nil {
+	opts, err := writerOptions(ctx, conf, art)
+	if err != nil {
+		return err
+	}
+
+	if err := up.Upload(ctx, uploadFile, data, opts); err != nil {
 		return handleError(err, bucketURL)
 	}
 	return nil
 }
 
+func writerOptions(ctx *context.Context, conf config.Blob, art *artifact.Artifact) (*blob.WriterOptions, error) {
+	contentType, err := tmpl.New(ctx).WithArtifact(art).Apply(conf.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	if contentType == "" {
+		contentType = detectContentType(art.Name)
+	}
+
+	cacheControl, err := tmpl.New(ctx).WithArtifact(art).Apply(conf.CacheControl)
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata map[string]string
+	for k, v := range conf.Metadata {
+		value, err := tmpl.New(ctx).WithArtifact(art).Apply(v)
+		if err != nil {
+			return nil, fmt.Errorf("metadata %s: %w", k, err)
+		}
+		if metadata == nil {
+			metadata = map[string]string{}
+		}
+		metadata[k] = value
+	}
+
+	opts := &blob.WriterOptions{
+		ContentDisposition: "attachment; filename=" + path.Base(art.Name),
+		ContentType:        contentType,
+		CacheControl:       cacheControl,
+		Metadata:           metadata,
+		BufferSize:         int(conf.PartSize),
+	}
+
+	if conf.SSE != "" {
+		kmsKeyID, err := tmpl.New(ctx).WithArtifact(art).Apply(conf.SSEKMSKeyID)
+		if err != nil {
+			return nil, err
+		}
+		opts.BeforeWrite = serverSideEncryption(conf.SSE, kmsKeyID)
+	}
+
+	return opts, nil
+}
+
+// serverSideEncryption sets the server-side encryption options on s3 uploads.
+func serverSideEncryption(sse, kmsKeyID string) func(asFunc func(interface{}) bool) error {
+	return func(asFunc func(interface{}) bool) error {
+		var input *s3manager.UploadInput
+		if !asFunc(&input) {
+			return fmt.Errorf("sse is only supported by the s3 and b2 providers")
+		}
+		input.ServerSideEncryption = aws.String(sse)
+		if kmsKeyID != "" {
+			input.SSEKMSKeyId = aws.String(kmsKeyID)
+		}
+		return nil
+	}
+}
+
+// knownContentTypes takes precedence over the system mime types database,
+// which might not know about some of these or vary between systems.
+var knownContentTypes = map[string]string{
+	".apk":    "application/vnd.android.package-archive",
+	".deb":    "application/vnd.debian.binary-package",
+	".gz":     "application/gzip",
+	".md5":    "text/plain; charset=utf-8",
+	".pem":    "application/x-pem-file",
+	".rpm":    "application/x-rpm",
+	".sbom":   "application/json",
+	".sha1":   "text/plain; charset=utf-8",
+	".sha224": "text/plain; charset=utf-8",
+	".sha256": "text/plain; charset=utf-8",
+	".sha384": "text/plain; charset=utf-8",
+	".sha512": "text/plain; charset=utf-8",
+	".sig":    "application/octet-stream",
+	".tgz":    "application/gzip",
+	".txt":    "text/plain; charset=utf-8",
+	".xz":     "application/x-xz",
+	".zip":    "application/zip",
+	".zst":    "application/zstd",
+}
+
+func detectContentType(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if ct, ok := knownContentTypes[ext]; ok {
+		return ct
+	}
+	if ct := mime.TypeByExtension(ext); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
 // errorContains check if error contains specific string.
 func errorContains(err error, subs ...string) bool {
 	for _, sub := range subs {
@@ -205,7 +367,7 @@ func getData(ctx *context.Context, conf config.Blob, path string) ([]byte, error
 type uploader interface {
 	io.Closer
 	Open(ctx *context.Context, url string) error
-	Upload(ctx *context.Context, path string, data []byte) error
+	Upload(ctx *context.Context, path string, data []byte, opts *blob.WriterOptions) error
 }
 
 // productionUploader actually do upload to.
@@ -233,12 +395,12 @@ func (u *productionUploader) Open(ctx *context.Context, bucket string) error {
 	return nil
 }
 
-func (u *productionUploader) Upload(ctx *context.Context, filepath string, data []byte) error {
-	log.WithField("path", filepath).Info("uploading")
+func (u *productionUploader) Upload(ctx *context.Context, filepath string, data []byte, opts *blob.WriterOptions) error {
+	log.WithFields(log.Fields{
+		"path":         filepath,
+		"content-type": opts.ContentType,
+	}).Info("uploading")
 
-	opts := &blob.WriterOptions{
-		ContentDisposition: "attachment; filename=" + path.Base(filepath),
-	}
 	w, err := u.bucket.NewWriter(ctx, filepath, opts)
 	if err != nil {
 		return err
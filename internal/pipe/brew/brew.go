@@ -12,6 +12,7 @@ import (
 	"strings"
 	"text/template"
 
+	"github.com/caarlos0/go-shellwords"
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/client"
@@ -44,6 +45,9 @@ func (Pipe) Default(ctx *context.Context) error {
 		brew := &ctx.Config.Brews[i]
 
 		brew.CommitAuthor = commitauthor.Default(brew.CommitAuthor)
+		if err := client.CheckSigning(brew.CommitAuthor, brew.Tap); err != nil {
+			return fmt.Errorf("brews: %w", err)
+		}
 
 		if brew.CommitMessageTemplate == "" {
 			brew.CommitMessageTemplate = "Brew formula update for {{ .ProjectName }} version {{ .Tag }}"
@@ -57,11 +61,31 @@ func (Pipe) Default(ctx *context.Context) error {
 		if brew.Goamd64 == "" {
 			brew.Goamd64 = "v1"
 		}
+		if brew.Service.Raw == "" && brew.Service.Run == "" &&
+			(brew.Service.KeepAlive || brew.Service.LogPath != "" || brew.Service.ErrorLogPath != "" || len(brew.Service.Environment) > 0) {
+			return fmt.Errorf("brews: service.run is required")
+		}
+		for _, dep := range brew.Dependencies {
+			if err := checkDependencyType(dep.Type); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
+// checkDependencyType checks that the given dependency type is one homebrew
+// understands.
+func checkDependencyType(t string) error {
+	switch t {
+	case "", "build", "test", "optional", "recommended":
+		return nil
+	default:
+		return fmt.Errorf("invalid brew dependency type: %s, valid options are: build, test, optional, recommended", t)
+	}
+}
+
 func (Pipe) Run(ctx *context.Context) error {
 	cli, err := client.New(ctx)
 	if err != nil {
@@ -112,10 +136,6 @@ func doPublish(ctx *context.Context, formula *artifact.Artifact, cl client.Clien
 	if err != nil {
 		return err
 	}
-	cl, err = client.NewIfToken(ctx, cl, brew.Tap.Token)
-	if err != nil {
-		return err
-	}
 
 	if strings.TrimSpace(brew.SkipUpload) == "true" {
 		return pipe.Skip("brew.skip_upload is set")
@@ -125,29 +145,39 @@ func doPublish(ctx *context.Context, formula *artifact.Artifact, cl client.Clien
 		return pipe.Skip("prerelease detected with 'auto' upload, skipping homebrew publish")
 	}
 
-	repo := client.RepoFromRef(brew.Tap)
+	return pushToTap(ctx, cl, formula, brew.Tap, brew.Folder, brew.CommitAuthor, brew.CommitMessageTemplate)
+}
+
+// pushToTap pushes the given formula or cask file to the given tap.
+func pushToTap(ctx *context.Context, cl client.Client, file *artifact.Artifact, tap config.RepoRef, folder string, commitAuthor config.CommitAuthor, commitMessageTemplate string) error {
+	cl, err := client.NewIfToken(ctx, cl, tap.Token)
+	if err != nil {
+		return err
+	}
 
-	gpath := buildFormulaPath(brew.Folder, formula.Name)
-	log.WithField("formula", gpath).
+	repo := client.RepoFromRef(tap)
+
+	gpath := buildFormulaPath(folder, file.Name)
+	log.WithField("file", gpath).
 		WithField("repo", repo.String()).
 		Info("pushing")
 
-	msg, err := tmpl.New(ctx).Apply(brew.CommitMessageTemplate)
+	msg, err := tmpl.New(ctx).Apply(commitMessageTemplate)
 	if err != nil {
 		return err
 	}
 
-	author, err := commitauthor.Get(ctx, brew.CommitAuthor)
+	author, err := commitauthor.Get(ctx, commitAuthor)
 	if err != nil {
 		return err
 	}
 
-	content, err := os.ReadFile(formula.Path)
+	content, err := os.ReadFile(file.Path)
 	if err != nil {
 		return err
 	}
 
-	return cl.CreateFile(ctx, author, repo, content, gpath, msg)
+	return client.NewIfGitURL(cl, tap).CreateFile(ctx, author, repo, content, gpath, msg)
 }
 
 func doRun(ctx *context.Context, brew config.Homebrew, cl client.Client) error {
@@ -155,6 +185,15 @@ func doRun(ctx *context.Context, brew config.Homebrew, cl client.Client) error {
 		return pipe.Skip("brew tap name is not set")
 	}
 
+	goarm, err := tmpl.New(ctx).Apply(brew.Goarm)
+	if err != nil {
+		return err
+	}
+	goamd64, err := tmpl.New(ctx).Apply(brew.Goamd64)
+	if err != nil {
+		return err
+	}
+
 	filters := []artifact.Filter{
 		artifact.Or(
 			artifact.ByGoos("darwin"),
@@ -163,13 +202,13 @@ func doRun(ctx *context.Context, brew config.Homebrew, cl client.Client) error {
 		artifact.Or(
 			artifact.And(
 				artifact.ByGoarch("amd64"),
-				artifact.ByGoamd64(brew.Goamd64),
+				artifact.ByGoamd64(goamd64),
 			),
 			artifact.ByGoarch("arm64"),
 			artifact.ByGoarch("all"),
 			artifact.And(
 				artifact.ByGoarch("arm"),
-				artifact.ByGoarm(brew.Goarm),
+				artifact.ByGoarm(goarm),
 			),
 		),
 		artifact.Or(
@@ -245,9 +284,15 @@ func buildFormula(ctx *context.Context, brew config.Homebrew, client client.Clie
 }
 
 func doBuildFormula(ctx *context.Context, data templateData) (string, error) {
+	return render(ctx, data.Name, formulaTemplate, data)
+}
+
+// render executes the given template with the given data, applies the
+// GoReleaser templates to the result, and gets rid of trailing whitespace.
+func render(ctx *context.Context, name, tpl string, data interface{}) (string, error) {
 	t, err := template.
-		New(data.Name).
-		Parse(formulaTemplate)
+		New(name).
+		Parse(tpl)
 	if err != nil {
 		return "", err
 	}
@@ -325,13 +370,18 @@ func dataFor(ctx *context.Context, cfg config.Homebrew, cl client.Client, artifa
 		Dependencies:  cfg.Dependencies,
 		Conflicts:     cfg.Conflicts,
 		Plist:         cfg.Plist,
-		Service:       split(cfg.Service),
 		PostInstall:   split(cfg.PostInstall),
 		Tests:         split(cfg.Test),
 		CustomRequire: cfg.CustomRequire,
 		CustomBlock:   split(cfg.CustomBlock),
 	}
 
+	service, err := serviceFor(ctx, cfg.Service)
+	if err != nil {
+		return result, err
+	}
+	result.Service = service
+
 	counts := map[string]int{}
 	for _, art := range artifacts {
 		sum, err := art.Checksum("sha256")
@@ -395,6 +445,79 @@ func lessFnFor(list []releasePackage) func(i, j int) bool {
 	return func(i, j int) bool { return list[i].OS > list[j].OS && list[i].Arch > list[j].Arch }
 }
 
+// serviceFor renders the lines of the formula service block.
+func serviceFor(ctx *context.Context, svc config.HomebrewService) ([]string, error) {
+	if svc.Raw != "" {
+		return split(svc.Raw), nil
+	}
+	if svc.Run == "" {
+		return []string{}, nil
+	}
+
+	t := tmpl.New(ctx)
+	run, err := t.Apply(svc.Run)
+	if err != nil {
+		return nil, err
+	}
+	args, err := shellwords.Parse(run)
+	if err != nil {
+		return nil, fmt.Errorf("invalid brew service run %q: %w", run, err)
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("brew service run is empty after templating: %q", svc.Run)
+	}
+	// relative names are binaries installed by the formula.
+	cmd := []string{fmt.Sprintf("%q", args[0])}
+	if !strings.HasPrefix(args[0], "/") {
+		cmd[0] = "opt_bin/" + cmd[0]
+	}
+	for _, arg := range args[1:] {
+		cmd = append(cmd, fmt.Sprintf("%q", arg))
+	}
+	result := []string{"run [" + strings.Join(cmd, ", ") + "]"}
+
+	if svc.KeepAlive {
+		result = append(result, "keep_alive true")
+	}
+
+	for _, entry := range []struct{ name, path string }{
+		{"log_path", svc.LogPath},
+		{"error_log_path", svc.ErrorLogPath},
+	} {
+		logPath, err := t.Apply(entry.path)
+		if err != nil {
+			return nil, err
+		}
+		if logPath == "" {
+			continue
+		}
+		// relative paths are relative to homebrew's var folder.
+		if strings.HasPrefix(logPath, "/") {
+			result = append(result, fmt.Sprintf("%s %q", entry.name, logPath))
+		} else {
+			result = append(result, fmt.Sprintf("%s var/%q", entry.name, logPath))
+		}
+	}
+
+	if len(svc.Environment) > 0 {
+		names := make([]string, 0, len(svc.Environment))
+		for name := range svc.Environment {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		env := make([]string, 0, len(names))
+		for _, name := range names {
+			value, err := t.Apply(svc.Environment[name])
+			if err != nil {
+				return nil, err
+			}
+			env = append(env, fmt.Sprintf("%s: %q", name, value))
+		}
+		result = append(result, "environment_variables "+strings.Join(env, ", "))
+	}
+	return result, nil
+}
+
 func split(s string) []string {
 	strings := strings.Split(strings.TrimSpace(s), "\n")
 	if len(strings) == 1 && strings[0] == "" {
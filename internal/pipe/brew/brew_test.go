@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -144,6 +145,125 @@ func TestFormulaeSimple(t *testing.T) {
 	require.NotContains(t, formulae, "def plist;")
 }
 
+func TestFormulaeDependencies(t *testing.T) {
+	for name, tt := range map[string]struct {
+		dep      config.HomebrewDependency
+		expected string
+	}{
+		"plain":       {config.HomebrewDependency{Name: "git"}, `depends_on "git"` + "\n"},
+		"version":     {config.HomebrewDependency{Name: "openssl", Version: "3"}, `depends_on "openssl@3"` + "\n"},
+		"build":       {config.HomebrewDependency{Name: "pkg-config", Type: "build"}, `depends_on "pkg-config" => :build`},
+		"test":        {config.HomebrewDependency{Name: "bats", Type: "test"}, `depends_on "bats" => :test`},
+		"optional":    {config.HomebrewDependency{Name: "zsh", Type: "optional"}, `depends_on "zsh" => :optional`},
+		"recommended": {config.HomebrewDependency{Name: "bash", Type: "recommended"}, `depends_on "bash" => :recommended`},
+		"version and type": {
+			config.HomebrewDependency{Name: "python", Type: "build", Version: "3.11"},
+			`depends_on "python@3.11" => :build`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := defaultTemplateData
+			data.Dependencies = []config.HomebrewDependency{tt.dep}
+			formulae, err := doBuildFormula(context.New(config.Project{}), data)
+			require.NoError(t, err)
+			require.Contains(t, formulae, tt.expected)
+		})
+	}
+}
+
+func TestFormulaeService(t *testing.T) {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Env:         []string{"PORT=8080"},
+	})
+	service, err := serviceFor(ctx, config.HomebrewService{
+		Run:          "{{ .ProjectName }} serve --port {{ .Env.PORT }}",
+		KeepAlive:    true,
+		LogPath:      "log/{{ .ProjectName }}.log",
+		ErrorLogPath: "/tmp/{{ .ProjectName }}.err",
+		Environment: map[string]string{
+			"FOO_PORT":  "{{ .Env.PORT }}",
+			"FOO_DEBUG": "true",
+		},
+	})
+	require.NoError(t, err)
+
+	data := defaultTemplateData
+	data.Service = service
+	formulae, err := doBuildFormula(ctx, data)
+	require.NoError(t, err)
+	require.Contains(t, formulae, `  service do
+    run [opt_bin/"foo", "serve", "--port", "8080"]
+    keep_alive true
+    log_path var/"log/foo.log"
+    error_log_path "/tmp/foo.err"
+    environment_variables FOO_DEBUG: "true", FOO_PORT: "8080"
+  end
+`)
+}
+
+func TestServiceFor(t *testing.T) {
+	ctx := context.New(config.Project{})
+
+	t.Run("raw", func(t *testing.T) {
+		service, err := serviceFor(ctx, config.HomebrewService{Raw: "run foo/bar\nkeep_alive true"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"run foo/bar", "keep_alive true"}, service)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		service, err := serviceFor(ctx, config.HomebrewService{})
+		require.NoError(t, err)
+		require.Empty(t, service)
+	})
+
+	t.Run("run only", func(t *testing.T) {
+		service, err := serviceFor(ctx, config.HomebrewService{Run: "foo"})
+		require.NoError(t, err)
+		require.Equal(t, []string{`run [opt_bin/"foo"]`}, service)
+	})
+
+	t.Run("quoted args", func(t *testing.T) {
+		service, err := serviceFor(ctx, config.HomebrewService{Run: `foo serve --name "my service" --greeting 'hello world'`})
+		require.NoError(t, err)
+		require.Equal(t, []string{`run [opt_bin/"foo", "serve", "--name", "my service", "--greeting", "hello world"]`}, service)
+	})
+
+	t.Run("absolute path", func(t *testing.T) {
+		service, err := serviceFor(ctx, config.HomebrewService{Run: "/usr/bin/env foo"})
+		require.NoError(t, err)
+		require.Equal(t, []string{`run ["/usr/bin/env", "foo"]`}, service)
+	})
+
+	t.Run("unterminated quote", func(t *testing.T) {
+		_, err := serviceFor(ctx, config.HomebrewService{Run: `foo "serve`})
+		require.ErrorContains(t, err, "invalid brew service run")
+	})
+
+	t.Run("invalid run template", func(t *testing.T) {
+		_, err := serviceFor(ctx, config.HomebrewService{Run: "{{ .Env.NOPE }}"})
+		testlib.RequireTemplateError(t, err)
+	})
+
+	t.Run("empty run after template", func(t *testing.T) {
+		_, err := serviceFor(ctx, config.HomebrewService{Run: "{{ if false }}foo{{ end }}"})
+		require.ErrorContains(t, err, "brew service run is empty")
+	})
+
+	t.Run("invalid log path template", func(t *testing.T) {
+		_, err := serviceFor(ctx, config.HomebrewService{Run: "foo", LogPath: "{{ .Env.NOPE }}"})
+		testlib.RequireTemplateError(t, err)
+	})
+
+	t.Run("invalid env template", func(t *testing.T) {
+		_, err := serviceFor(ctx, config.HomebrewService{
+			Run:         "foo",
+			Environment: map[string]string{"FOO": "{{ .Env.NOPE }}"},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
 func TestSplit(t *testing.T) {
 	parts := split("system \"true\"\nsystem \"#{bin}/foo\", \"-h\"")
 	require.Equal(t, []string{"system \"true\"", "system \"#{bin}/foo\", \"-h\""}, parts)
@@ -286,7 +406,7 @@ func TestFullPipe(t *testing.T) {
 								{Name: "fish", Type: "optional", Version: "v1.2.3"},
 							},
 							Conflicts:   []string{"gtk+", "qt"},
-							Service:     "run foo/bar\nkeep_alive true",
+							Service:     config.HomebrewService{Raw: "run foo/bar\nkeep_alive true"},
 							PostInstall: "system \"echo\"\ntouch \"/tmp/hi\"",
 							Install:     `bin.install "{{ .ProjectName }}_{{.Os}}_{{.Arch}} => {{.ProjectName}}"`,
 							Goamd64:     "v1",
@@ -781,6 +901,71 @@ func TestRunPipeForMultipleArmVersions(t *testing.T) {
 	}
 }
 
+func TestRunPipeTemplatedGoarm(t *testing.T) {
+	setup := func(tb testing.TB, goarm string) *context.Context {
+		tb.Helper()
+		folder := tb.TempDir()
+		ctx := context.New(config.Project{
+			Dist:        folder,
+			ProjectName: "foo",
+			Env:         []string{"BREW_GOARM=7"},
+			Brews: []config.Homebrew{
+				{
+					Name:  "foo",
+					Goarm: goarm,
+					Tap: config.RepoRef{
+						Owner: "test",
+						Name:  "test",
+					},
+				},
+			},
+			GitHubURLs: config.GitHubURLs{
+				Download: "https://github.com",
+			},
+			Release: config.Release{
+				GitHub: config.Repo{
+					Owner: "test",
+					Name:  "test",
+				},
+			},
+		})
+		ctx.TokenType = context.TokenTypeGitHub
+		ctx.Git.CurrentTag = "v1.0.1"
+		ctx.Version = "1.0.1"
+		for _, v := range []string{"6", "7"} {
+			name := "foo_linux_armv" + v + ".tar.gz"
+			path := filepath.Join(folder, name)
+			require.NoError(tb, os.WriteFile(path, []byte("fake"), 0o644))
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Name:   name,
+				Path:   path,
+				Goos:   "linux",
+				Goarch: "arm",
+				Goarm:  v,
+				Type:   artifact.UploadableArchive,
+				Extra: map[string]interface{}{
+					artifact.ExtraID:     "foo",
+					artifact.ExtraFormat: "tar.gz",
+				},
+			})
+		}
+		return ctx
+	}
+
+	t.Run("armv7 only", func(t *testing.T) {
+		ctx := setup(t, "{{ .Env.BREW_GOARM }}")
+		client := client.NewMock()
+		require.NoError(t, runAll(ctx, client))
+		require.NoError(t, publishAll(ctx, client))
+		require.Contains(t, client.Content, "foo_linux_armv7.tar.gz")
+		require.NotContains(t, client.Content, "foo_linux_armv6.tar.gz")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		ctx := setup(t, "{{ .Nope }}")
+		testlib.RequireTemplateError(t, runAll(ctx, client.NewMock()))
+	})
+}
 func TestRunPipeNoBuilds(t *testing.T) {
 	ctx := context.New(
 		config.Project{
@@ -998,6 +1183,60 @@ func TestRunPipeBinaryRelease(t *testing.T) {
 	golden.RequireEqualRb(t, []byte(client.Content))
 }
 
+func TestRunPipeMultipleBinaries(t *testing.T) {
+	folder := t.TempDir()
+	ctx := &context.Context{
+		Git: context.GitInfo{
+			CurrentTag: "v1.0.1",
+		},
+		Version:   "1.0.1",
+		Artifacts: artifact.New(),
+		Config: config.Project{
+			Dist:        folder,
+			ProjectName: "foo",
+			Brews: []config.Homebrew{
+				{
+					Name:        "foo",
+					Homepage:    "https://goreleaser.com",
+					Description: "Fake desc",
+					Tap: config.RepoRef{
+						Owner: "foo",
+						Name:  "bar",
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, Pipe{}.Default(ctx))
+
+	for _, arch := range []string{"darwin_all", "linux_amd64"} {
+		goos, goarch, _ := strings.Cut(arch, "_")
+		name := "foo_" + arch + ".tar.gz"
+		path := filepath.Join(folder, name)
+		require.NoError(t, os.WriteFile(path, []byte{}, 0o644))
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name:    name,
+			Path:    path,
+			Goos:    goos,
+			Goarch:  goarch,
+			Goamd64: "v1",
+			Type:    artifact.UploadableArchive,
+			Extra: map[string]interface{}{
+				artifact.ExtraID:       "foo",
+				artifact.ExtraFormat:   "tar.gz",
+				artifact.ExtraBinaries: []string{"fooctl", "foo"},
+				artifact.ExtraReplaces: true,
+			},
+		})
+	}
+
+	client := client.NewMock()
+	require.NoError(t, runAll(ctx, client))
+	require.NoError(t, publishAll(ctx, client))
+	require.True(t, client.CreatedFile)
+	golden.RequireEqualRb(t, []byte(client.Content))
+}
+
 func TestRunPipeNoUpload(t *testing.T) {
 	folder := t.TempDir()
 	ctx := context.New(config.Project{
@@ -1116,6 +1355,44 @@ func TestDefault(t *testing.T) {
 	require.NotEmpty(t, ctx.Config.Brews[0].CommitMessageTemplate)
 }
 
+func TestDefaultSigningWithoutGit(t *testing.T) {
+	ctx := context.New(config.Project{
+		Brews: []config.Homebrew{
+			{
+				CommitAuthor: config.CommitAuthor{
+					Signing: config.CommitSigning{Enabled: true},
+				},
+			},
+		},
+	})
+	require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrSigningRequiresGit)
+}
+
+func TestDefaultInvalidDependencyType(t *testing.T) {
+	ctx := context.New(config.Project{
+		Brews: []config.Homebrew{
+			{
+				Dependencies: []config.HomebrewDependency{
+					{Name: "pkg-config", Type: "build"},
+					{Name: "foo", Type: "runtime"},
+				},
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Default(ctx), "invalid brew dependency type: runtime, valid options are: build, test, optional, recommended")
+}
+
+func TestDefaultServiceWithoutRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Brews: []config.Homebrew{
+			{
+				Service: config.HomebrewService{KeepAlive: true},
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Default(ctx), "brews: service.run is required")
+}
+
 func TestGHFolder(t *testing.T) {
 	require.Equal(t, "bar.rb", buildFormulaPath("", "bar.rb"))
 	require.Equal(t, "fooo/bar.rb", buildFormulaPath("fooo", "bar.rb"))
@@ -1357,3 +1634,32 @@ func TestRunPipeUniversalBinaryNotReplacing(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, client.Content, string(distBts))
 }
+
+func TestPushToTapCustomCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.rb")
+	require.NoError(t, os.WriteFile(path, []byte("fake"), 0o644))
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Env:         []string{"BOT_NAME=release-bot", "BOT_EMAIL=bot@example.com"},
+	})
+	ctx.Git.CurrentTag = "v1.2.3"
+	ctx.Version = "1.2.3"
+	cli := client.NewMock()
+	require.NoError(t, pushToTap(
+		ctx,
+		cli,
+		&artifact.Artifact{Name: "foo.rb", Path: path},
+		config.RepoRef{Owner: "foo", Name: "homebrew-tap"},
+		"",
+		config.CommitAuthor{
+			Name:  "{{ .Env.BOT_NAME }}",
+			Email: "{{ .Env.BOT_EMAIL }}",
+		},
+		"chore(brew): update {{ .ProjectName }} to {{ .Version }}\n\nSigned-off-by: {{ .Env.BOT_NAME }} <{{ .Env.BOT_EMAIL }}>",
+	))
+	require.Equal(t, config.CommitAuthor{
+		Name:  "release-bot",
+		Email: "bot@example.com",
+	}, cli.CommitAuthor)
+	require.Equal(t, "chore(brew): update foo to 1.2.3\n\nSigned-off-by: release-bot <bot@example.com>", cli.CommitMessage)
+}
@@ -0,0 +1,279 @@
+package brew
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/commitauthor"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const caskConfigExtra = "BrewCaskConfig"
+
+// ErrNoCaskArchivesFound happens when no macOS zip archives are found.
+var ErrNoCaskArchivesFound = errors.New("no macos zip archives found")
+
+// CaskPipe for brew cask deployment.
+type CaskPipe struct{}
+
+func (CaskPipe) String() string                 { return "homebrew tap cask" }
+func (CaskPipe) Skip(ctx *context.Context) bool { return len(ctx.Config.HomebrewCasks) == 0 }
+
+// Default sets the pipe defaults.
+func (CaskPipe) Default(ctx *context.Context) error {
+	for i := range ctx.Config.HomebrewCasks {
+		cask := &ctx.Config.HomebrewCasks[i]
+
+		cask.CommitAuthor = commitauthor.Default(cask.CommitAuthor)
+		if err := client.CheckSigning(cask.CommitAuthor, cask.Tap); err != nil {
+			return fmt.Errorf("homebrew_casks: %w", err)
+		}
+
+		if cask.CommitMessageTemplate == "" {
+			cask.CommitMessageTemplate = "Brew cask update for {{ .ProjectName }} version {{ .Tag }}"
+		}
+		if cask.Name == "" {
+			cask.Name = ctx.Config.ProjectName
+		}
+		if cask.Folder == "" {
+			cask.Folder = "Casks"
+		}
+		if cask.App == "" {
+			cask.App = "{{ .ProjectName }}.app"
+		}
+	}
+	return nil
+}
+
+// Run the pipe.
+func (CaskPipe) Run(ctx *context.Context) error {
+	cli, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	return runAllCasks(ctx, cli)
+}
+
+// Publish brew casks.
+func (CaskPipe) Publish(ctx *context.Context) error {
+	cli, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	return publishAllCasks(ctx, cli)
+}
+
+func runAllCasks(ctx *context.Context, cli client.Client) error {
+	for _, cask := range ctx.Config.HomebrewCasks {
+		if err := doRunCask(ctx, cask, cli); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func publishAllCasks(ctx *context.Context, cli client.Client) error {
+	skips := pipe.SkipMemento{}
+	for _, cask := range ctx.Artifacts.Filter(artifact.ByType(artifact.BrewCask)).List() {
+		err := doPublishCask(ctx, cask, cli)
+		if err != nil && pipe.IsSkip(err) {
+			skips.Remember(err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return skips.Evaluate()
+}
+
+func doPublishCask(ctx *context.Context, file *artifact.Artifact, cl client.Client) error {
+	cask, err := artifact.Extra[config.HomebrewCask](*file, caskConfigExtra)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(cask.SkipUpload) == "true" {
+		return pipe.Skip("homebrew_casks.skip_upload is set")
+	}
+
+	if strings.TrimSpace(cask.SkipUpload) == "auto" && ctx.Semver.Prerelease != "" {
+		return pipe.Skip("prerelease detected with 'auto' upload, skipping homebrew cask publish")
+	}
+
+	return pushToTap(ctx, cl, file, cask.Tap, cask.Folder, cask.CommitAuthor, cask.CommitMessageTemplate)
+}
+
+func doRunCask(ctx *context.Context, cask config.HomebrewCask, cl client.Client) error {
+	if cask.Tap.Name == "" {
+		return pipe.Skip("homebrew cask tap name is not set")
+	}
+
+	filters := []artifact.Filter{
+		artifact.ByGoos("darwin"),
+		artifact.Or(
+			artifact.ByGoarch("amd64"),
+			artifact.ByGoarch("arm64"),
+			artifact.ByGoarch("all"),
+		),
+		artifact.ByFormats("zip"),
+		artifact.ByType(artifact.UploadableArchive),
+		artifact.OnlyReplacingUnibins,
+	}
+	if len(cask.IDs) > 0 {
+		filters = append(filters, artifact.ByIDs(cask.IDs...))
+	}
+
+	archives := ctx.Artifacts.Filter(artifact.And(filters...)).List()
+	if len(archives) == 0 {
+		return ErrNoCaskArchivesFound
+	}
+
+	t := tmpl.New(ctx)
+	var err error
+	for _, s := range []*string{&cask.Name, &cask.App, &cask.SkipUpload} {
+		*s, err = t.Apply(*s)
+		if err != nil {
+			return err
+		}
+	}
+
+	ref, err := client.TemplateRef(t.Apply, cask.Tap)
+	if err != nil {
+		return err
+	}
+	cask.Tap = ref
+
+	data, err := caskDataFor(ctx, cask, cl, archives)
+	if err != nil {
+		return err
+	}
+	content, err := render(ctx, data.Token, caskTemplate, data)
+	if err != nil {
+		return err
+	}
+
+	filename := cask.Name + ".rb"
+	path := filepath.Join(ctx.Config.Dist, "casks", filename)
+	log.WithField("cask", path).Info("writing")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to write brew cask: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil { //nolint: gosec
+		return fmt.Errorf("failed to write brew cask: %w", err)
+	}
+
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: filename,
+		Path: path,
+		Type: artifact.BrewCask,
+		Extra: map[string]interface{}{
+			caskConfigExtra: cask,
+		},
+	})
+
+	return nil
+}
+
+func caskDataFor(ctx *context.Context, cask config.HomebrewCask, cl client.Client, artifacts []*artifact.Artifact) (caskTemplateData, error) {
+	result := caskTemplateData{
+		Token:     cask.Name,
+		Name:      strings.TrimSuffix(cask.App, ".app"),
+		Desc:      cask.Description,
+		Homepage:  cask.Homepage,
+		Version:   ctx.Version,
+		App:       cask.App,
+		Caveats:   split(cask.Caveats),
+		Uninstall: caskStanza("uninstall", cask.Uninstall),
+		Zap:       caskStanza("zap", cask.Zap),
+	}
+
+	counts := map[string]int{}
+	for _, art := range artifacts {
+		sum, err := art.Checksum("sha256")
+		if err != nil {
+			return result, err
+		}
+
+		if cask.URLTemplate == "" {
+			url, err := cl.ReleaseURLTemplate(ctx)
+			if err != nil {
+				return result, err
+			}
+			cask.URLTemplate = url
+		}
+
+		url, err := tmpl.New(ctx).WithArtifact(art).Apply(cask.URLTemplate)
+		if err != nil {
+			return result, err
+		}
+
+		counts[art.Goarch]++
+		result.Packages = append(result.Packages, releasePackage{
+			DownloadURL: url,
+			SHA256:      sum,
+			OS:          art.Goos,
+			Arch:        art.Goarch,
+		})
+	}
+
+	for _, v := range counts {
+		if v > 1 {
+			return result, ErrMultipleArchivesSameOS
+		}
+	}
+	if counts["all"] > 0 && len(result.Packages) > 1 {
+		return result, ErrMultipleArchivesSameOS
+	}
+
+	sort.Slice(result.Packages, func(i, j int) bool {
+		return result.Packages[i].Arch < result.Packages[j].Arch
+	})
+	return result, nil
+}
+
+// caskStanza renders an uninstall or zap stanza, e.g.:
+//
+//	uninstall quit: "com.example.foo",
+//	          delete: ["/usr/local/bin/foo", "/tmp/foo"]
+func caskStanza(name string, u config.HomebrewCaskUninstall) string {
+	var lines []string
+	for _, kv := range []struct {
+		key    string
+		values []string
+	}{
+		{"launchctl", u.Launchctl},
+		{"quit", u.Quit},
+		{"login_item", u.LoginItem},
+		{"delete", u.Delete},
+		{"trash", u.Trash},
+	} {
+		if len(kv.values) == 0 {
+			continue
+		}
+		quoted := make([]string, 0, len(kv.values))
+		for _, v := range kv.values {
+			quoted = append(quoted, fmt.Sprintf("%q", v))
+		}
+		value := quoted[0]
+		if len(quoted) > 1 {
+			value = "[" + strings.Join(quoted, ", ") + "]"
+		}
+		lines = append(lines, kv.key+": "+value)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	indent := strings.Repeat(" ", len(name)+3)
+	return name + " " + strings.Join(lines, ",\n"+indent)
+}
@@ -0,0 +1,203 @@
+package brew
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/golden"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaskDescription(t *testing.T) {
+	require.NotEmpty(t, CaskPipe{}.String())
+}
+
+func TestCaskDefault(t *testing.T) {
+	testlib.Mktmp(t)
+
+	ctx := &context.Context{
+		TokenType: context.TokenTypeGitHub,
+		Config: config.Project{
+			ProjectName: "myproject",
+			HomebrewCasks: []config.HomebrewCask{
+				{},
+			},
+		},
+	}
+	require.NoError(t, CaskPipe{}.Default(ctx))
+	cask := ctx.Config.HomebrewCasks[0]
+	require.Equal(t, ctx.Config.ProjectName, cask.Name)
+	require.Equal(t, "Casks", cask.Folder)
+	require.Equal(t, "{{ .ProjectName }}.app", cask.App)
+	require.NotEmpty(t, cask.CommitAuthor.Name)
+	require.NotEmpty(t, cask.CommitAuthor.Email)
+	require.NotEmpty(t, cask.CommitMessageTemplate)
+}
+
+func TestCaskSkip(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		require.True(t, CaskPipe{}.Skip(context.New(config.Project{})))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			HomebrewCasks: []config.HomebrewCask{
+				{},
+			},
+		})
+		require.False(t, CaskPipe{}.Skip(ctx))
+	})
+}
+
+func newCaskCtx(tb testing.TB, folder string, cask config.HomebrewCask) *context.Context {
+	tb.Helper()
+	ctx := &context.Context{
+		Git: context.GitInfo{
+			CurrentTag: "v1.0.1",
+		},
+		Version:   "1.0.1",
+		Artifacts: artifact.New(),
+		Config: config.Project{
+			Dist:          folder,
+			ProjectName:   "foo",
+			HomebrewCasks: []config.HomebrewCask{cask},
+		},
+	}
+	require.NoError(tb, CaskPipe{}.Default(ctx))
+	return ctx
+}
+
+func addCaskArchive(tb testing.TB, ctx *context.Context, goarch, format string) {
+	tb.Helper()
+	name := "foo_darwin_" + goarch + "." + format
+	path := filepath.Join(ctx.Config.Dist, name)
+	require.NoError(tb, os.WriteFile(path, []byte{}, 0o644))
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   name,
+		Path:   path,
+		Goos:   "darwin",
+		Goarch: goarch,
+		Type:   artifact.UploadableArchive,
+		Extra: map[string]interface{}{
+			artifact.ExtraID:       "foo",
+			artifact.ExtraFormat:   format,
+			artifact.ExtraReplaces: true,
+		},
+	})
+}
+
+func TestRunCaskPipe(t *testing.T) {
+	cask := config.HomebrewCask{
+		Description: "Foo is a nice app",
+		Homepage:    "https://goreleaser.com",
+		App:         "Foo.app",
+		Tap: config.RepoRef{
+			Owner: "foo",
+			Name:  "homebrew-tap",
+		},
+		Uninstall: config.HomebrewCaskUninstall{
+			Quit:   []string{"com.goreleaser.foo"},
+			Delete: []string{"/usr/local/bin/foo", "/tmp/foo"},
+		},
+		Zap: config.HomebrewCaskUninstall{
+			Trash: []string{"~/Library/Preferences/com.goreleaser.foo.plist"},
+		},
+		Caveats: "Foo needs access to your files.",
+	}
+
+	for name, goarchs := range map[string][]string{
+		"multiple_archs": {"arm64", "amd64"},
+		"universal":      {"all"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			folder := t.TempDir()
+			ctx := newCaskCtx(t, folder, cask)
+			for _, goarch := range goarchs {
+				addCaskArchive(t, ctx, goarch, "zip")
+			}
+			// tar.gz archives are not used by casks
+			addCaskArchive(t, ctx, "386", "zip")
+			addCaskArchive(t, ctx, "arm64", "tar.gz")
+
+			client := client.NewMock()
+			require.NoError(t, runAllCasks(ctx, client))
+			require.NoError(t, publishAllCasks(ctx, client))
+			require.True(t, client.CreatedFile)
+			require.Equal(t, "Casks/foo.rb", client.Path)
+			golden.RequireEqualRb(t, []byte(client.Content))
+
+			distBts, err := os.ReadFile(filepath.Join(folder, "casks", "foo.rb"))
+			require.NoError(t, err)
+			require.Equal(t, client.Content, string(distBts))
+		})
+	}
+}
+
+func TestRunCaskPipeNoArchives(t *testing.T) {
+	folder := t.TempDir()
+	ctx := newCaskCtx(t, folder, config.HomebrewCask{
+		Tap: config.RepoRef{Owner: "foo", Name: "homebrew-tap"},
+	})
+	addCaskArchive(t, ctx, "arm64", "tar.gz")
+	require.Equal(t, ErrNoCaskArchivesFound, runAllCasks(ctx, client.NewMock()))
+}
+
+func TestRunCaskPipeMultipleArchivesSameArch(t *testing.T) {
+	folder := t.TempDir()
+	ctx := newCaskCtx(t, folder, config.HomebrewCask{
+		Tap: config.RepoRef{Owner: "foo", Name: "homebrew-tap"},
+	})
+	addCaskArchive(t, ctx, "all", "zip")
+	addCaskArchive(t, ctx, "arm64", "zip")
+	require.Equal(t, ErrMultipleArchivesSameOS, runAllCasks(ctx, client.NewMock()))
+}
+
+func TestRunCaskPipeNoTap(t *testing.T) {
+	ctx := newCaskCtx(t, t.TempDir(), config.HomebrewCask{})
+	testlib.AssertSkipped(t, runAllCasks(ctx, client.NewMock()))
+}
+
+func TestRunCaskPipeSkipUpload(t *testing.T) {
+	folder := t.TempDir()
+	ctx := newCaskCtx(t, folder, config.HomebrewCask{
+		Tap:        config.RepoRef{Owner: "foo", Name: "homebrew-tap"},
+		SkipUpload: "true",
+	})
+	addCaskArchive(t, ctx, "all", "zip")
+
+	client := client.NewMock()
+	require.NoError(t, runAllCasks(ctx, client))
+	testlib.AssertSkipped(t, publishAllCasks(ctx, client))
+	require.False(t, client.CreatedFile)
+	require.FileExists(t, filepath.Join(folder, "casks", "foo.rb"))
+}
+
+func TestRunCaskPipeInvalidAppTemplate(t *testing.T) {
+	folder := t.TempDir()
+	ctx := newCaskCtx(t, folder, config.HomebrewCask{
+		Tap: config.RepoRef{Owner: "foo", Name: "homebrew-tap"},
+		App: "{{ .Nope }",
+	})
+	addCaskArchive(t, ctx, "all", "zip")
+	testlib.RequireTemplateError(t, runAllCasks(ctx, client.NewMock()))
+}
+
+func TestCaskStanza(t *testing.T) {
+	require.Empty(t, caskStanza("zap", config.HomebrewCaskUninstall{}))
+	require.Equal(t, `zap trash: "~/Library/foo"`, caskStanza("zap", config.HomebrewCaskUninstall{
+		Trash: []string{"~/Library/foo"},
+	}))
+	require.Equal(t, "uninstall launchctl: \"com.foo\",\n"+
+		"            quit: [\"com.foo\", \"com.foo.helper\"],\n"+
+		"            login_item: \"Foo\"", caskStanza("uninstall", config.HomebrewCaskUninstall{
+		Launchctl: []string{"com.foo"},
+		Quit:      []string{"com.foo", "com.foo.helper"},
+		LoginItem: []string{"Foo"},
+	}))
+}
@@ -47,8 +47,8 @@ class {{ .Name }} < Formula
   {{- end }}
   {{- with .Dependencies }}
   {{ range $index, $element := . }}
-  depends_on "{{ .Name }}"
-  {{- if .Type }} => :{{ .Type }}{{- else if .Version }} => "{{ .Version }}"{{- end }}
+  depends_on "{{ .Name }}{{ with .Version }}@{{ . }}{{ end }}"
+  {{- if .Type }} => :{{ .Type }}{{- end }}
   {{- end }}
   {{- end -}}
 
@@ -205,3 +205,70 @@ class {{ .Name }} < Formula
   {{- end }}
 end
 `
+
+type caskTemplateData struct {
+	Token     string
+	Name      string
+	Desc      string
+	Homepage  string
+	Version   string
+	App       string
+	Caveats   []string
+	Uninstall string
+	Zap       string
+	Packages  []releasePackage
+}
+
+const caskTemplate = `# typed: false
+# frozen_string_literal: true
+
+# This file was generated by GoReleaser. DO NOT EDIT.
+cask "{{ .Token }}" do
+  version "{{ .Version }}"
+
+  {{- if eq (len .Packages) 1 }}
+  {{- with index .Packages 0 }}
+
+  url "{{ .DownloadURL }}"
+  sha256 "{{ .SHA256 }}"
+  {{- end }}
+  {{- else }}
+  {{- range .Packages }}
+
+  {{ if eq .Arch "arm64" }}on_arm{{ else }}on_intel{{ end }} do
+    url "{{ .DownloadURL }}"
+    sha256 "{{ .SHA256 }}"
+  end
+  {{- end }}
+  {{- end }}
+
+  name "{{ .Name }}"
+  {{- if .Desc }}
+  desc "{{ .Desc }}"
+  {{- end }}
+  {{- if .Homepage }}
+  homepage "{{ .Homepage }}"
+  {{- end }}
+
+  app "{{ .App }}"
+
+  {{- with .Uninstall }}
+
+  {{ . }}
+  {{- end }}
+
+  {{- with .Zap }}
+
+  {{ . }}
+  {{- end }}
+
+  {{- with .Caveats }}
+
+  caveats <<~EOS
+  {{- range . }}
+    {{ . }}
+  {{- end }}
+  EOS
+  {{- end }}
+end
+`
@@ -20,6 +20,7 @@ import (
 
 	// langs to init.
 	_ "github.com/goreleaser/goreleaser/internal/builders/golang"
+	_ "github.com/goreleaser/goreleaser/internal/builders/tinygo"
 )
 
 // Pipe for build.
@@ -228,7 +229,7 @@ func extFor(target string, build config.BuildDetails) string {
 		return ".a"
 	}
 
-	if target == "js_wasm" {
+	if target == "js_wasm" || target == "wasi_wasm" {
 		return ".wasm"
 	}
 
@@ -2,8 +2,10 @@ package build
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -461,10 +463,14 @@ func TestExtWindows(t *testing.T) {
 	require.Equal(t, ".dll", extFor("windows_386", config.BuildDetails{Buildmode: "c-shared"}))
 	require.Equal(t, ".lib", extFor("windows_amd64", config.BuildDetails{Buildmode: "c-archive"}))
 	require.Equal(t, ".lib", extFor("windows_386", config.BuildDetails{Buildmode: "c-archive"}))
+	require.Equal(t, ".exe", extFor("windows_arm64", config.BuildDetails{}))
+	require.Equal(t, ".dll", extFor("windows_arm64", config.BuildDetails{Buildmode: "c-shared"}))
+	require.Equal(t, ".lib", extFor("windows_arm64", config.BuildDetails{Buildmode: "c-archive"}))
 }
 
 func TestExtWasm(t *testing.T) {
 	require.Equal(t, ".wasm", extFor("js_wasm", config.BuildDetails{}))
+	require.Equal(t, ".wasm", extFor("wasi_wasm", config.BuildDetails{}))
 }
 
 func TestExtOthers(t *testing.T) {
@@ -563,6 +569,60 @@ func TestPipeOnBuild_hooksRunPerTarget(t *testing.T) {
 	require.FileExists(t, filepath.Join(tmpDir, "post-hook-windows_amd64"))
 }
 
+func TestPipeOnBuild_hooksTargetEnv(t *testing.T) {
+	tmpDir := testlib.Mktmp(t)
+	logFile := filepath.Join(tmpDir, "hooks.log")
+	require.NoError(t, os.WriteFile(
+		filepath.Join(tmpDir, "hook.sh"),
+		[]byte(fmt.Sprintf("echo \"$1 $CC $SYSROOT ${@:2}\" >> %s\n", logFile)),
+		0o755,
+	))
+
+	build := config.Build{
+		Builder: "fake",
+		Binary:  "testing",
+		Targets: []string{
+			"linux_amd64_v1",
+			"linux_arm64",
+			"linux_arm_7",
+		},
+		BuildDetails: config.BuildDetails{
+			Env: []string{"CC={{ .Arch }}-linux-gnu-gcc"},
+		},
+		Hooks: config.BuildHookConfig{
+			Pre: []config.Hook{
+				{
+					Cmd: "bash hook.sh pre {{ .Target }} {{ .Os }} {{ .Arch }} {{ .Arm }}",
+					Dir: tmpDir,
+					Env: []string{"SYSROOT=/sysroots/{{ .Os }}/{{ .Arch }}{{ .Arm }}"},
+				},
+			},
+			Post: config.Hooks{
+				{Cmd: "bash hook.sh post {{ .Target }} {{ .Name }}", Dir: tmpDir},
+			},
+		},
+	}
+	ctx := context.New(config.Project{
+		Builds: []config.Build{
+			build,
+		},
+	})
+	g := semerrgroup.New(ctx.Parallelism)
+	runPipeOnBuild(ctx, g, build)
+	require.NoError(t, g.Wait())
+
+	bts, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"pre amd64-linux-gnu-gcc /sysroots/linux/amd64 linux_amd64_v1 linux amd64",
+		"pre arm64-linux-gnu-gcc /sysroots/linux/arm64 linux_arm64 linux arm64",
+		"pre arm-linux-gnu-gcc /sysroots/linux/arm7 linux_arm_7 linux arm 7",
+		"post amd64-linux-gnu-gcc  linux_amd64_v1 testing",
+		"post arm64-linux-gnu-gcc  linux_arm64 testing",
+		"post arm-linux-gnu-gcc  linux_arm_7 testing",
+	}, strings.Split(strings.TrimSpace(string(bts)), "\n"))
+}
+
 func TestPipeOnBuild_invalidBinaryTpl(t *testing.T) {
 	build := config.Build{
 		Builder: "fake",
@@ -681,6 +741,43 @@ func TestBuildOptionsForTarget(t *testing.T) {
 				Gomips: "softfloat",
 			},
 		},
+		{
+			name: "windows amd64",
+			build: config.Build{
+				ID:     "testid",
+				Binary: "testbinary",
+				Targets: []string{
+					"windows_amd64",
+				},
+			},
+			expectedOpts: &api.Options{
+				Name:    "testbinary.exe",
+				Path:    filepath.Join(tmpDir, "testid_windows_amd64_v1", "testbinary.exe"),
+				Ext:     ".exe",
+				Target:  "windows_amd64_v1",
+				Goos:    "windows",
+				Goarch:  "amd64",
+				Goamd64: "v1",
+			},
+		},
+		{
+			name: "windows arm64",
+			build: config.Build{
+				ID:     "testid",
+				Binary: "testbinary",
+				Targets: []string{
+					"windows_arm64",
+				},
+			},
+			expectedOpts: &api.Options{
+				Name:   "testbinary.exe",
+				Path:   filepath.Join(tmpDir, "testid_windows_arm64", "testbinary.exe"),
+				Ext:    ".exe",
+				Target: "windows_arm64",
+				Goos:   "windows",
+				Goarch: "arm64",
+			},
+		},
 		{
 			name: "with goamd64",
 			build: config.Build{
@@ -20,6 +20,10 @@ import (
 // ErrInvalidSortDirection happens when the sort order is invalid.
 var ErrInvalidSortDirection = errors.New("invalid sort direction")
 
+// ErrShallowClone happens when the changelog can't be built because the
+// repository is a shallow clone without the needed history.
+var ErrShallowClone = errors.New("could not get the changelog from a shallow clone: fetch the whole history (e.g. `fetch-depth: 0` on GitHub Actions) or set `git.unshallow: true`\nLearn more at https://goreleaser.com/errors/no-history")
+
 const li = "* "
 
 type useChangelog string
@@ -35,6 +39,12 @@ const (
 	useGitHubNative = "github-native"
 )
 
+const (
+	matchSubject = "subject"
+	matchBody    = "body"
+	matchAuthor  = "author"
+)
+
 // Pipe for checksums.
 type Pipe struct{}
 
@@ -67,17 +77,27 @@ func (Pipe) Run(ctx *context.Context) error {
 		return err
 	}
 
-	entries, err := buildChangelog(ctx)
+	entries, use, err := buildChangelog(ctx)
 	if err != nil {
 		return err
 	}
 
-	changes, err := formatChangelog(ctx, entries)
+	changes, err := formatChangelog(ctx, use, entries)
 	if err != nil {
 		return err
 	}
 	changelogElements := []string{changes}
 
+	if ctx.Config.Changelog.IncludeContributors {
+		contributors, err := contributorsSection(ctx, use)
+		if err != nil {
+			return err
+		}
+		if contributors != "" {
+			changelogElements = append(changelogElements, contributors)
+		}
+	}
+
 	if header != "" {
 		changelogElements = append([]string{header}, changelogElements...)
 	}
@@ -128,10 +148,12 @@ func abbrevEntry(s string, abbr int) string {
 		return rest
 	default:
 		commit, rest, _ := strings.Cut(s, " ")
+		// the github and gitlab changelogs use "<commit>: <message>"
+		commit, sep, _ := strings.Cut(commit, ":")
 		if abbr > len(commit) {
 			return s
 		}
-		return fmt.Sprintf("%s %s", commit[:abbr], rest)
+		return fmt.Sprintf("%s%s %s", commit[:abbr], sep, rest)
 	}
 }
 
@@ -143,8 +165,8 @@ func abbrev(entries []string, abbr int) []string {
 	return result
 }
 
-func formatChangelog(ctx *context.Context, entries []string) (string, error) {
-	if !useChangelog(ctx.Config.Changelog.Use).formatable() {
+func formatChangelog(ctx *context.Context, use string, entries []string) (string, error) {
+	if !useChangelog(use).formatable() {
 		return strings.Join(entries, newLineFor(ctx)), nil
 	}
 
@@ -153,6 +175,9 @@ func formatChangelog(ctx *context.Context, entries []string) (string, error) {
 	result := []string{title("Changelog", 2)}
 	if len(ctx.Config.Changelog.Groups) == 0 {
 		log.Debug("not grouping entries")
+		if ctx.Config.Changelog.UseConventionalScopes {
+			return strings.Join(append(result, groupByScope(entries, 3)...), newLineFor(ctx)), nil
+		}
 		return strings.Join(append(result, filterAndPrefixItems(entries)...), newLineFor(ctx)), nil
 	}
 
@@ -164,8 +189,8 @@ func formatChangelog(ctx *context.Context, entries []string) (string, error) {
 			order: group.Order,
 		}
 		if group.Regexp == "" {
-			// If no regexp is provided, we purge all strikethrough entries and add remaining entries to the list
-			item.entries = filterAndPrefixItems(entries)
+			// If no regexp is provided, we add all remaining entries to the list
+			item.entries = entries
 			// clear array
 			entries = nil
 		} else {
@@ -180,7 +205,7 @@ func formatChangelog(ctx *context.Context, entries []string) (string, error) {
 				match := re.MatchString(entry)
 				log.Debugf("entry: %s match: %b\n", entry, match)
 				if match {
-					item.entries = append(item.entries, li+entry)
+					item.entries = append(item.entries, entry)
 				} else {
 					// Keep unmatched entry.
 					entries[i] = entry
@@ -200,12 +225,62 @@ func formatChangelog(ctx *context.Context, entries []string) (string, error) {
 	for _, group := range groups {
 		if len(group.entries) > 0 {
 			result = append(result, group.title)
-			result = append(result, group.entries...)
+			if ctx.Config.Changelog.UseConventionalScopes {
+				result = append(result, groupByScope(group.entries, 4)...)
+			} else {
+				result = append(result, filterAndPrefixItems(group.entries)...)
+			}
 		}
 	}
 	return strings.Join(result, newLineFor(ctx)), nil
 }
 
+// matches "<commit>[:] <type>(<scope>)[!]: ", the commit being optional as
+// it might have been removed by abbrev.
+var conventionalScopeRe = regexp.MustCompile(`^(?:\S+:? )?\w+\(([^)]+)\)!?:`)
+
+const otherScope = "Other"
+
+func conventionalScope(entry string) string {
+	match := conventionalScopeRe.FindStringSubmatch(entry)
+	if match == nil {
+		return ""
+	}
+	return strings.TrimSpace(match[1])
+}
+
+// groupByScope nests the given entries under titles of their conventional
+// commit scopes, sorted by name, followed by the entries without a scope.
+func groupByScope(entries []string, level int) []string {
+	scoped := map[string][]string{}
+	var scopes []string
+	for _, entry := range entries {
+		if entry == "" {
+			continue
+		}
+		scope := conventionalScope(entry)
+		if _, ok := scoped[scope]; !ok && scope != "" {
+			scopes = append(scopes, scope)
+		}
+		scoped[scope] = append(scoped[scope], li+entry)
+	}
+	sort.Strings(scopes)
+	if len(scoped[""]) > 0 {
+		scopes = append(scopes, "")
+	}
+
+	var result []string
+	for _, scope := range scopes {
+		name := scope
+		if name == "" {
+			name = otherScope
+		}
+		result = append(result, title(name, level))
+		result = append(result, scoped[scope]...)
+	}
+	return result
+}
+
 func groupSort(groups []changelogGroup) func(i, j int) bool {
 	return func(i, j int) bool {
 		return groups[i].order < groups[j].order
@@ -240,40 +315,108 @@ func checkSortDirection(mode string) error {
 	}
 }
 
-func buildChangelog(ctx *context.Context) ([]string, error) {
+// buildChangelog returns the changelog entries, and which changelog was
+// actually used to get them, as github-native falls back to git when it is
+// not available.
+func buildChangelog(ctx *context.Context) ([]string, string, error) {
 	l, err := getChangeloger(ctx)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+	use := ctx.Config.Changelog.Use
+	if _, ok := l.(gitChangeloger); ok {
+		use = useGit
+	}
+	out, err := l.Log(ctx)
+	if use == useGitHubNative && errors.Is(err, client.ErrNotImplemented) {
+		use = useGit
+		out, err = fallbackToGit(err).Log(ctx)
 	}
-	log, err := l.Log(ctx)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	entries := strings.Split(log, "\n")
+	entries := strings.Split(out, "\n")
 	if lastLine := entries[len(entries)-1]; strings.TrimSpace(lastLine) == "" {
 		entries = entries[0 : len(entries)-1]
 	}
-	if !useChangelog(ctx.Config.Changelog.Use).formatable() {
-		return entries, nil
+	if !useChangelog(use).formatable() {
+		return entries, use, nil
 	}
-	entries, err = filterEntries(ctx, entries)
+	entries, err = filterEntries(ctx, use, entries)
 	if err != nil {
-		return entries, err
+		return entries, use, err
 	}
-	return sortEntries(ctx, entries), nil
+	return sortEntries(ctx, entries), use, nil
 }
 
-func filterEntries(ctx *context.Context, entries []string) ([]string, error) {
+func filterEntries(ctx *context.Context, use string, entries []string) ([]string, error) {
+	var commits map[string]commitDetail
 	for _, filter := range ctx.Config.Changelog.Filters.Exclude {
-		r, err := regexp.Compile(filter)
+		r, err := regexp.Compile(filter.Regexp)
 		if err != nil {
 			return entries, err
 		}
-		entries = remove(r, entries)
+		switch filter.Match {
+		case "", matchSubject:
+			entries = remove(r, entries, extractCommitInfo)
+			continue
+		case matchBody, matchAuthor:
+		default:
+			return entries, fmt.Errorf("invalid changelog.filters.exclude.match: %q", filter.Match)
+		}
+		if use != "" && use != useGit {
+			return entries, fmt.Errorf("changelog.filters.exclude.match: %q is only supported when using git", filter.Match)
+		}
+		if commits == nil {
+			commits, err = commitDetails(ctx)
+			if err != nil {
+				return entries, err
+			}
+		}
+		entries = remove(r, entries, filterTarget(filter.Match, commits))
 	}
 	return entries, nil
 }
 
+// filterTarget returns the function extracting the part of each entry an
+// exclude filter with the given match is matched against.
+func filterTarget(match string, commits map[string]commitDetail) func(entry string) string {
+	return func(entry string) string {
+		hash, _, _ := strings.Cut(entry, " ")
+		if match == matchBody {
+			return commits[hash].body
+		}
+		return commits[hash].author
+	}
+}
+
+type commitDetail struct {
+	author string
+	body   string
+}
+
+// commitDetails returns the author email and body of the commits since the
+// previous tag, indexed by their abbreviated hashes.
+func commitDetails(ctx *context.Context) (map[string]commitDetail, error) {
+	args := []string{"log", "--pretty=format:%h%x1f%ae%x1f%b%x1e", "--abbrev-commit", "--no-color"}
+	out, err := git.Run(ctx, append(args, gitLogRange(ctx)...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit details: %w", err)
+	}
+	result := map[string]commitDetail{}
+	for _, record := range strings.Split(out, "\x1e") {
+		parts := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		result[parts[0]] = commitDetail{
+			author: parts[1],
+			body:   strings.TrimSpace(parts[2]),
+		}
+	}
+	return result, nil
+}
+
 func sortEntries(ctx *context.Context, entries []string) []string {
 	direction := ctx.Config.Changelog.Sort
 	if direction == "" {
@@ -292,9 +435,9 @@ func sortEntries(ctx *context.Context, entries []string) []string {
 	return result
 }
 
-func remove(filter *regexp.Regexp, entries []string) (result []string) {
+func remove(filter *regexp.Regexp, entries []string, info func(entry string) string) (result []string) {
 	for _, entry := range entries {
-		if !filter.MatchString(extractCommitInfo(entry)) {
+		if !filter.MatchString(info(entry)) {
 			result = append(result, entry)
 		}
 	}
@@ -316,30 +459,49 @@ func getChangeloger(ctx *context.Context) (changeloger, error) {
 	case useGitLab:
 		return newSCMChangeloger(ctx)
 	case useGitHubNative:
+		if ctx.TokenType == context.TokenTypeGitLab || ctx.TokenType == context.TokenTypeGitea {
+			return fallbackToGit(fmt.Errorf("not supported on %s", ctx.TokenType)), nil
+		}
 		return newGithubChangeloger(ctx)
 	default:
 		return nil, fmt.Errorf("invalid changelog.use: %q", ctx.Config.Changelog.Use)
 	}
 }
 
+// fallbackToGit returns the git changeloger to use instead of GitHub's
+// release notes generation, e.g. when releasing to other SCMs or when the API
+// is not available.
+func fallbackToGit(err error) changeloger {
+	log.WithError(err).Warnf("could not use %s changelog, falling back to %s", useGitHubNative, useGit)
+	return gitChangeloger{}
+}
+
 func newGithubChangeloger(ctx *context.Context) (changeloger, error) {
-	cli, err := client.NewGitHub(ctx, ctx.Token)
+	cli, repo, err := newGithubClient(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return &githubNativeChangeloger{
+		client: cli,
+		repo:   repo,
+	}, nil
+}
+
+func newGithubClient(ctx *context.Context) (client.GitHubClient, client.Repo, error) {
+	cli, err := client.NewGitHub(ctx, ctx.Token)
+	if err != nil {
+		return nil, client.Repo{}, err
+	}
 	repo, err := git.ExtractRepoFromConfig(ctx)
 	if err != nil {
-		return nil, err
+		return nil, client.Repo{}, err
 	}
 	if err := repo.CheckSCM(); err != nil {
-		return nil, err
+		return nil, client.Repo{}, err
 	}
-	return &githubNativeChangeloger{
-		client: cli,
-		repo: client.Repo{
-			Owner: repo.Owner,
-			Name:  repo.Name,
-		},
+	return cli, client.Repo{
+		Owner: repo.Owner,
+		Name:  repo.Name,
 	}, nil
 }
 
@@ -400,13 +562,27 @@ var validSHA1 = regexp.MustCompile(`^[a-fA-F0-9]{40}$`)
 
 func (g gitChangeloger) Log(ctx *context.Context) (string, error) {
 	args := []string{"log", "--pretty=oneline", "--abbrev-commit", "--no-decorate", "--no-color"}
+	out, err := git.Run(ctx, append(args, gitLogRange(ctx)...)...)
+	if err != nil {
+		if git.IsShallow(ctx) {
+			return "", fmt.Errorf("%w: %s", ErrShallowClone, strings.TrimSpace(err.Error()))
+		}
+		return "", err
+	}
+	if strings.TrimSpace(out) == "" && git.IsShallow(ctx) {
+		log.Warn("changelog is empty and this is a shallow clone, you might want to fetch the full history")
+	}
+	return out, nil
+}
+
+// gitLogRange returns the git log arguments selecting the commits since the
+// previous tag, or since the first commit if there is no previous tag.
+func gitLogRange(ctx *context.Context) []string {
 	prev, current := comparePair(ctx)
 	if validSHA1.MatchString(prev) {
-		args = append(args, prev, current)
-	} else {
-		args = append(args, fmt.Sprintf("tags/%s..tags/%s", ctx.Git.PreviousTag, ctx.Git.CurrentTag))
+		return []string{prev, current}
 	}
-	return git.Run(ctx, args...)
+	return []string{fmt.Sprintf("tags/%s..tags/%s", ctx.Git.PreviousTag, ctx.Git.CurrentTag)}
 }
 
 type scmChangeloger struct {
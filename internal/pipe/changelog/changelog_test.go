@@ -1,6 +1,9 @@
 package changelog
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
@@ -103,11 +106,11 @@ func TestChangelog(t *testing.T) {
 		Changelog: config.Changelog{
 			Use: "git",
 			Filters: config.Filters{
-				Exclude: []string{
-					"docs:",
-					"ignored:",
-					"(?i)cars",
-					"^Merge pull request",
+				Exclude: []config.ChangelogFilter{
+					{Regexp: "docs:"},
+					{Regexp: "ignored:"},
+					{Regexp: "(?i)cars"},
+					{Regexp: "^Merge pull request"},
 				},
 			},
 		},
@@ -154,11 +157,11 @@ func TestChangelogForGitlab(t *testing.T) {
 		Dist: folder,
 		Changelog: config.Changelog{
 			Filters: config.Filters{
-				Exclude: []string{
-					"docs:",
-					"ignored:",
-					"(?i)cars",
-					"^Merge pull request",
+				Exclude: []config.ChangelogFilter{
+					{Regexp: "docs:"},
+					{Regexp: "ignored:"},
+					{Regexp: "(?i)cars"},
+					{Regexp: "^Merge pull request"},
 				},
 			},
 		},
@@ -227,7 +230,7 @@ func TestChangelogSort(t *testing.T) {
 	} {
 		t.Run("changelog sort='"+cfg.Sort+"'", func(t *testing.T) {
 			ctx.Config.Changelog.Sort = cfg.Sort
-			entries, err := buildChangelog(ctx)
+			entries, _, err := buildChangelog(ctx)
 			require.NoError(t, err)
 			require.Len(t, entries, len(cfg.Entries))
 			var changes []string
@@ -281,8 +284,8 @@ func TestChangelogFilterInvalidRegex(t *testing.T) {
 	ctx := context.New(config.Project{
 		Changelog: config.Changelog{
 			Filters: config.Filters{
-				Exclude: []string{
-					"(?iasdr4qasd)not a valid regex i guess",
+				Exclude: []config.ChangelogFilter{
+					{Regexp: "(?iasdr4qasd)not a valid regex i guess"},
 				},
 			},
 		},
@@ -292,6 +295,131 @@ func TestChangelogFilterInvalidRegex(t *testing.T) {
 	require.EqualError(t, Pipe{}.Run(ctx), "error parsing regexp: invalid or unsupported Perl syntax: `(?ia`")
 }
 
+func TestChangelogFilterByAuthor(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommitWithAuthor(t, "feat: added feature 1", "Carlos", "carlos@example.com")
+	testlib.GitCommitWithAuthor(t, "build(deps): bump foo from 1.0 to 1.1", "dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com")
+	testlib.GitCommitWithAuthor(t, "fix: fixed bug 2", "Alice", "alice@example.com")
+	testlib.GitTag(t, "v0.0.2")
+	ctx := context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Filters: config.Filters{
+				Exclude: []config.ChangelogFilter{
+					{Regexp: `dependabot\[bot\]@`, Match: "author"},
+				},
+			},
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Contains(t, ctx.ReleaseNotes, "added feature 1")
+	require.Contains(t, ctx.ReleaseNotes, "fixed bug 2")
+	require.NotContains(t, ctx.ReleaseNotes, "bump foo")
+}
+
+func TestChangelogFilterByBody(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "feat: added feature 1\n\nthis one matters")
+	testlib.GitCommit(t, "Merge pull request #999 from goreleaser/some-branch\n\nchore: squashed noise")
+	testlib.GitCommit(t, "fix: fixed bug 2")
+	testlib.GitTag(t, "v0.0.2")
+	ctx := context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Filters: config.Filters{
+				Exclude: []config.ChangelogFilter{
+					{Regexp: "^chore:", Match: "body"},
+				},
+			},
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Contains(t, ctx.ReleaseNotes, "added feature 1")
+	require.Contains(t, ctx.ReleaseNotes, "fixed bug 2")
+	require.NotContains(t, ctx.ReleaseNotes, "Merge pull request")
+}
+
+func TestChangelogFilterMixedMatches(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommitWithAuthor(t, "feat: added feature 1", "Carlos", "carlos@example.com")
+	testlib.GitCommitWithAuthor(t, "build(deps): bump foo from 1.0 to 1.1", "dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com")
+	testlib.GitCommitWithAuthor(t, "docs: fixed typo", "Alice", "alice@example.com")
+	testlib.GitCommitWithAuthor(t, "fix: fixed bug 2", "Alice", "alice@example.com")
+	testlib.GitTag(t, "v0.0.2")
+	ctx := context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Filters: config.Filters{
+				Exclude: []config.ChangelogFilter{
+					{Regexp: "^docs:"},
+					{Regexp: `dependabot\[bot\]@`, Match: "author"},
+				},
+			},
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Contains(t, ctx.ReleaseNotes, "added feature 1")
+	require.Contains(t, ctx.ReleaseNotes, "fixed bug 2")
+	require.NotContains(t, ctx.ReleaseNotes, "bump foo")
+	require.NotContains(t, ctx.ReleaseNotes, "fixed typo")
+}
+
+func TestChangelogFilterMatchErrors(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "second")
+	testlib.GitTag(t, "v0.0.2")
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Changelog: config.Changelog{
+				Filters: config.Filters{
+					Exclude: []config.ChangelogFilter{
+						{Regexp: "foo", Match: "committer"},
+					},
+				},
+			},
+		})
+		ctx.Git.PreviousTag = "v0.0.1"
+		ctx.Git.CurrentTag = "v0.0.2"
+		require.EqualError(t, Pipe{}.Run(ctx), `invalid changelog.filters.exclude.match: "committer"`)
+	})
+
+	t.Run("not git", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Changelog: config.Changelog{
+				Use: useGitHub,
+				Filters: config.Filters{
+					Exclude: []config.ChangelogFilter{
+						{Regexp: "foo", Match: "author"},
+					},
+				},
+			},
+		})
+		ctx.Git.PreviousTag = "v0.0.1"
+		ctx.Git.CurrentTag = "v0.0.2"
+		_, err := filterEntries(ctx, useGitHub, []string{"abcdef: foo"})
+		require.EqualError(t, err, `changelog.filters.exclude.match: "author" is only supported when using git`)
+	})
+}
+
 func TestChangelogNoTags(t *testing.T) {
 	testlib.Mktmp(t)
 	testlib.GitInit(t)
@@ -515,6 +643,67 @@ func TestGetChangelogGitHubNative(t *testing.T) {
 	require.Equal(t, []string{"v0.180.1", "v0.180.2"}, mock.ReleaseNotesParams)
 }
 
+func TestChangelogGitHubNativeAPI(t *testing.T) {
+	setup := func(tb testing.TB, handler http.HandlerFunc) *context.Context {
+		tb.Helper()
+		srv := httptest.NewServer(handler)
+		tb.Cleanup(srv.Close)
+
+		folder := testlib.Mktmp(tb)
+		testlib.GitInit(tb)
+		testlib.GitRemoteAdd(tb, "git@github.com:goreleaser/goreleaser.git")
+		testlib.GitCommit(tb, "first")
+		testlib.GitTag(tb, "v0.0.1")
+		testlib.GitCommit(tb, "feat: added feature 1")
+		testlib.GitTag(tb, "v0.0.2")
+
+		ctx := context.New(config.Project{
+			Dist: folder,
+			GitHubURLs: config.GitHubURLs{
+				API: srv.URL + "/",
+			},
+			Changelog: config.Changelog{
+				Use: useGitHubNative,
+			},
+		})
+		ctx.TokenType = context.TokenTypeGitHub
+		ctx.Git.PreviousTag = "v0.0.1"
+		ctx.Git.CurrentTag = "v0.0.2"
+		return ctx
+	}
+
+	t.Run("generated", func(t *testing.T) {
+		ctx := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/repos/goreleaser/goreleaser/releases/generate-notes" {
+				fmt.Fprint(w, `{"name":"v0.0.2","body":"## What's Changed\n* feat: added feature 1 by @caarlos0"}`)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, "## What's Changed\n* feat: added feature 1 by @caarlos0\n", ctx.ReleaseNotes)
+		require.Equal(t, useGitHubNative, ctx.Config.Changelog.Use)
+	})
+
+	t.Run("api not available", func(t *testing.T) {
+		ctx := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Contains(t, ctx.ReleaseNotes, "## Changelog")
+		require.Contains(t, ctx.ReleaseNotes, "feat: added feature 1")
+		require.Equal(t, useGitHubNative, ctx.Config.Changelog.Use)
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		ctx := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+		require.ErrorContains(t, Pipe{}.Run(ctx), "401")
+		require.Empty(t, ctx.ReleaseNotes)
+	})
+}
+
 func TestGetChangelogGitHubNativeFirstRelease(t *testing.T) {
 	ctx := context.New(config.Project{
 		Changelog: config.Changelog{
@@ -587,6 +776,22 @@ func TestGetChangeloger(t *testing.T) {
 		require.IsType(t, c, &githubNativeChangeloger{})
 	})
 
+	for _, tokenType := range []context.TokenType{context.TokenTypeGitLab, context.TokenTypeGitea} {
+		tokenType := tokenType
+		t.Run(useGitHubNative+"-"+string(tokenType), func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Changelog: config.Changelog{
+					Use: useGitHubNative,
+				},
+			})
+			ctx.TokenType = tokenType
+			c, err := getChangeloger(ctx)
+			require.NoError(t, err)
+			require.IsType(t, c, gitChangeloger{})
+			require.Equal(t, useGitHubNative, ctx.Config.Changelog.Use)
+		})
+	}
+
 	t.Run(useGitHubNative+"-invalid-repo", func(t *testing.T) {
 		testlib.Mktmp(t)
 		testlib.GitInit(t)
@@ -742,6 +947,83 @@ func TestGroupBadRegex(t *testing.T) {
 	require.EqualError(t, Pipe{}.Run(ctx), "failed to group into \"Something\": error parsing regexp: missing closing ]: `[a-z`")
 }
 
+func TestChangelogConventionalScopes(t *testing.T) {
+	entries := []string{
+		"aea123 feat(api): add endpoint",
+		"aef653 fix(ui): fix button",
+		"aef654 feat: something without scope",
+		"aef655 feat(api)!: breaking change",
+		"aef656 fix: another one",
+		"aef657 update readme",
+	}
+
+	t.Run("without groups", func(t *testing.T) {
+		out, err := formatChangelog(context.New(config.Project{
+			Changelog: config.Changelog{
+				UseConventionalScopes: true,
+			},
+		}), "", entries)
+		require.NoError(t, err)
+		require.Equal(t, `## Changelog
+### api
+* aea123 feat(api): add endpoint
+* aef655 feat(api)!: breaking change
+### ui
+* aef653 fix(ui): fix button
+### Other
+* aef654 feat: something without scope
+* aef656 fix: another one
+* aef657 update readme`, out)
+	})
+
+	t.Run("with groups", func(t *testing.T) {
+		out, err := formatChangelog(context.New(config.Project{
+			Changelog: config.Changelog{
+				UseConventionalScopes: true,
+				Groups: []config.ChangelogGroup{
+					{Title: "Features", Regexp: `^.*?feat(\([[:word:]]+\))??!?:.+$`, Order: 0},
+					{Title: "Bug fixes", Regexp: `^.*?fix(\([[:word:]]+\))??!?:.+$`, Order: 1},
+					{Title: "Others", Order: 999},
+				},
+			},
+		}), "", append([]string{}, entries...))
+		require.NoError(t, err)
+		require.Equal(t, `## Changelog
+### Features
+#### api
+* aea123 feat(api): add endpoint
+* aef655 feat(api)!: breaking change
+#### Other
+* aef654 feat: something without scope
+### Bug fixes
+#### ui
+* aef653 fix(ui): fix button
+#### Other
+* aef656 fix: another one
+### Others
+#### Other
+* aef657 update readme`, out)
+	})
+
+	t.Run("without commit hashes", func(t *testing.T) {
+		out, err := formatChangelog(context.New(config.Project{
+			Changelog: config.Changelog{
+				UseConventionalScopes: true,
+				Abbrev:                -1,
+			},
+		}), "", []string{
+			"aea123 feat(api): add endpoint",
+			"aef654 feat: something without scope",
+		})
+		require.NoError(t, err)
+		require.Equal(t, `## Changelog
+### api
+* feat(api): add endpoint
+### Other
+* feat: something without scope`, out)
+	})
+}
+
 func TestChangelogFormat(t *testing.T) {
 	t.Run("without groups", func(t *testing.T) {
 		makeConf := func(u string) config.Project {
@@ -752,6 +1034,7 @@ func TestChangelogFormat(t *testing.T) {
 			t.Run(use, func(t *testing.T) {
 				out, err := formatChangelog(
 					context.New(makeConf(use)),
+					use,
 					[]string{
 						"aea123 foo",
 						"aef653 bar",
@@ -767,6 +1050,7 @@ func TestChangelogFormat(t *testing.T) {
 		t.Run(useGitHubNative, func(t *testing.T) {
 			out, err := formatChangelog(
 				context.New(makeConf(useGitHubNative)),
+				useGitHubNative,
 				[]string{
 					"# What's changed",
 					"* aea123 foo",
@@ -795,6 +1079,7 @@ func TestChangelogFormat(t *testing.T) {
 		t.Run(useGitHubNative, func(t *testing.T) {
 			out, err := formatChangelog(
 				context.New(makeConf(useGitHubNative)),
+				useGitHubNative,
 				[]string{
 					"# What's changed",
 					"* aea123 foo",
@@ -810,6 +1095,7 @@ func TestChangelogFormat(t *testing.T) {
 			t.Run(use, func(t *testing.T) {
 				out, err := formatChangelog(
 					context.New(makeConf(use)),
+					use,
 					[]string{
 						"aea123 foo",
 						"aef653 bar",
@@ -906,6 +1192,34 @@ func TestAbbrev(t *testing.T) {
 	})
 }
 
+func TestAbbrevEntry(t *testing.T) {
+	const (
+		gitEntry    = "3f0bf9a feat: added that thing"
+		githubEntry = "c90f1085f255d0af0b055160bfff5ee40f47af79: fix: do not skip any defaults (#2521) (@caarlos0)"
+		gitlabEntry = "c90f1085f255d0af0b055160bfff5ee40f47af79: fix: do not skip any defaults (#2521) (Carlos <carlos@example.com>)"
+	)
+	for _, tt := range []struct {
+		entry    string
+		abbrev   int
+		expected string
+	}{
+		{gitEntry, 0, gitEntry},
+		{gitEntry, -1, "feat: added that thing"},
+		{gitEntry, 3, "3f0 feat: added that thing"},
+		{gitEntry, 40, gitEntry},
+		{githubEntry, 0, githubEntry},
+		{githubEntry, -1, "fix: do not skip any defaults (#2521) (@caarlos0)"},
+		{githubEntry, 7, "c90f108: fix: do not skip any defaults (#2521) (@caarlos0)"},
+		{githubEntry, 40, githubEntry},
+		{gitlabEntry, 7, "c90f108: fix: do not skip any defaults (#2521) (Carlos <carlos@example.com>)"},
+		{gitlabEntry, -1, "fix: do not skip any defaults (#2521) (Carlos <carlos@example.com>)"},
+	} {
+		t.Run(fmt.Sprintf("%d %s", tt.abbrev, tt.entry), func(t *testing.T) {
+			require.Equal(t, tt.expected, abbrevEntry(tt.entry, tt.abbrev))
+		})
+	}
+}
+
 func ensureCommitHashLen(tb testing.TB, log string, l int) {
 	tb.Helper()
 	for _, line := range strings.Split(log, "\n") {
@@ -918,6 +1232,51 @@ func ensureCommitHashLen(tb testing.TB, log string, l int) {
 	}
 }
 
+func TestChangelogShallowClone(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "added feature 1")
+	testlib.GitTag(t, "v0.0.2")
+
+	t.Run("shallow", func(t *testing.T) {
+		testlib.GitShallowClone(t, folder)
+		ctx := context.New(config.Project{Dist: t.TempDir()})
+		ctx.Git.CurrentTag = "v0.0.2"
+		ctx.Git.FirstCommit = firstCommit(t)
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Contains(t, ctx.ReleaseNotes, "added feature 1")
+	})
+
+	t.Run("empty log", func(t *testing.T) {
+		testlib.GitShallowClone(t, folder)
+		ctx := context.New(config.Project{Dist: t.TempDir()})
+		ctx.Git.PreviousTag = "v0.0.2"
+		ctx.Git.CurrentTag = "v0.0.2"
+		require.NoError(t, Pipe{}.Run(ctx))
+	})
+
+	t.Run("missing previous tag", func(t *testing.T) {
+		testlib.GitShallowClone(t, folder)
+		ctx := context.New(config.Project{Dist: t.TempDir()})
+		ctx.Git.PreviousTag = "v0.0.1"
+		ctx.Git.CurrentTag = "v0.0.2"
+		require.ErrorIs(t, Pipe{}.Run(ctx), ErrShallowClone)
+	})
+
+	t.Run("unshallowed", func(t *testing.T) {
+		testlib.GitShallowClone(t, folder)
+		_, err := git.Run(context.New(config.Project{}), "fetch", "--unshallow", "--tags")
+		require.NoError(t, err)
+		ctx := context.New(config.Project{Dist: t.TempDir()})
+		ctx.Git.PreviousTag = "v0.0.1"
+		ctx.Git.CurrentTag = "v0.0.2"
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Contains(t, ctx.ReleaseNotes, "added feature 1")
+	})
+}
+
 func firstCommit(tb testing.TB) string {
 	tb.Helper()
 	s, err := git.Clean(git.Run(context.New(config.Project{}), "rev-list", "--max-parents=0", "HEAD"))
@@ -0,0 +1,115 @@
+package changelog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+type contributor struct {
+	name     string
+	email    string
+	username string
+}
+
+func (c contributor) String() string {
+	if c.username == "" {
+		return c.name
+	}
+	return fmt.Sprintf("%s (@%s)", c.name, c.username)
+}
+
+// contributorsSection renders the list of unique commit authors since the
+// previous tag, looking up their usernames when the given changelog uses
+// GitHub.
+func contributorsSection(ctx *context.Context, use string) (string, error) {
+	out, err := git.Run(ctx, append([]string{"log", "--pretty=format:%an%x09%ae", "--no-color"}, gitLogRange(ctx)...)...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get contributors: %w", err)
+	}
+
+	var usernames map[string]string
+	switch use {
+	case useGitHub, useGitHubNative:
+		cli, repo, err := newGithubClient(ctx)
+		if err != nil {
+			return "", err
+		}
+		usernames, err = lookupUsernames(ctx, cli, repo)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return formatContributors(ctx, parseContributors(out, usernames)), nil
+}
+
+// lookupUsernames finds the GitHub usernames of the commit authors.
+func lookupUsernames(ctx *context.Context, cli client.GitHubClient, repo client.Repo) (map[string]string, error) {
+	prev, current := comparePair(ctx)
+	usernames, err := cli.CommitAuthors(ctx, repo, prev, current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contributors usernames: %w", err)
+	}
+	return usernames, nil
+}
+
+// parseContributors parses the "name<TAB>email" lines of the given git log,
+// de-duplicating them by email.
+func parseContributors(out string, usernames map[string]string) []contributor {
+	seen := map[string]bool{}
+	var result []contributor
+	for _, line := range strings.Split(out, "\n") {
+		name, email, ok := strings.Cut(strings.TrimSpace(line), "\t")
+		if !ok {
+			continue
+		}
+		email = strings.ToLower(email)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		result = append(result, contributor{
+			name:     name,
+			email:    email,
+			username: usernames[email],
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return strings.ToLower(result[i].name) < strings.ToLower(result[j].name)
+	})
+	return result
+}
+
+func formatContributors(ctx *context.Context, contributors []contributor) string {
+	var items []string
+	for _, c := range contributors {
+		if isExcludedContributor(ctx.Config.Changelog.ExcludeContributors, c) {
+			log.WithField("contributor", c.email).Debug("excluding contributor")
+			continue
+		}
+		items = append(items, li+c.String())
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	return strings.Join(append([]string{title("Contributors", 2)}, items...), newLineFor(ctx))
+}
+
+// isExcludedContributor reports whether the contributor's name, email or
+// username is in the exclusion list.
+func isExcludedContributor(excluded []string, c contributor) bool {
+	for _, e := range excluded {
+		for _, s := range []string{c.name, c.email, c.username} {
+			if s != "" && strings.EqualFold(e, s) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangelogContributors(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommitWithAuthor(t, "first", "Old Timer", "old@example.com")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommitWithAuthor(t, "feat: added feature 1", "Carlos", "carlos@example.com")
+	testlib.GitCommitWithAuthor(t, "fix: fixed bug 2", "Alice", "alice@example.com")
+	testlib.GitCommitWithAuthor(t, "chore: bump deps", "dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com")
+	testlib.GitCommitWithAuthor(t, "fix: fixed bug 3", "Carlos Becker", "Carlos@Example.com")
+	testlib.GitTag(t, "v0.0.2")
+
+	ctx := context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Use:                 useGit,
+			IncludeContributors: true,
+			ExcludeContributors: []string{"dependabot[bot]"},
+		},
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Contains(t, ctx.ReleaseNotes, "## Changelog")
+	require.Contains(t, ctx.ReleaseNotes, "## Contributors\n* Alice\n* Carlos Becker\n")
+	require.NotContains(t, ctx.ReleaseNotes, "Old Timer")
+	require.NotContains(t, ctx.ReleaseNotes, "* dependabot")
+}
+
+func TestChangelogContributorsDisabled(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommitWithAuthor(t, "first", "Old Timer", "old@example.com")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommitWithAuthor(t, "feat: added feature 1", "Carlos", "carlos@example.com")
+	testlib.GitTag(t, "v0.0.2")
+
+	ctx := context.New(config.Project{
+		Dist: folder,
+	})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.NotContains(t, ctx.ReleaseNotes, "Contributors")
+}
+
+func TestParseContributors(t *testing.T) {
+	out := "Carlos Becker\tcarlos@example.com\n" +
+		"Alice\talice@example.com\n" +
+		"Carlos\tCARLOS@example.com\n" +
+		"invalid line\n" +
+		"\n" +
+		"bob\tbob@example.com\n"
+	require.Equal(t, []contributor{
+		{name: "Alice", email: "alice@example.com", username: "alice"},
+		{name: "bob", email: "bob@example.com"},
+		{name: "Carlos Becker", email: "carlos@example.com", username: "caarlos0"},
+	}, parseContributors(out, map[string]string{
+		"carlos@example.com": "caarlos0",
+		"alice@example.com":  "alice",
+	}))
+}
+
+func TestFormatContributors(t *testing.T) {
+	contributors := []contributor{
+		{name: "Alice", email: "alice@example.com", username: "alice"},
+		{name: "Bob", email: "bob@example.com"},
+		{name: "renovate", email: "bot@renovateapp.com", username: "renovate[bot]"},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		require.Equal(
+			t,
+			"## Contributors\n* Alice (@alice)\n* Bob\n* renovate (@renovate[bot])",
+			formatContributors(ctx, contributors),
+		)
+	})
+
+	t.Run("exclude by username and email", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Changelog: config.Changelog{
+				ExcludeContributors: []string{"Renovate[bot]", "bob@example.com"},
+			},
+		})
+		require.Equal(t, "## Contributors\n* Alice (@alice)", formatContributors(ctx, contributors))
+	})
+
+	t.Run("all excluded", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Changelog: config.Changelog{
+				ExcludeContributors: []string{"alice", "Bob", "renovate"},
+			},
+		})
+		require.Empty(t, formatContributors(ctx, contributors))
+	})
+
+	t.Run("gitlab", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		ctx.TokenType = context.TokenTypeGitLab
+		require.Equal(
+			t,
+			"## Contributors   \n* Alice (@alice)   \n* Bob   \n* renovate (@renovate[bot])",
+			formatContributors(ctx, contributors),
+		)
+	})
+}
+
+func TestLookupUsernames(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Git.PreviousTag = "v0.0.1"
+	ctx.Git.CurrentTag = "v0.0.2"
+	repo := client.Repo{Owner: "goreleaser", Name: "goreleaser"}
+
+	t.Run("ok", func(t *testing.T) {
+		mock := client.NewMock()
+		mock.Authors = map[string]string{"carlos@example.com": "caarlos0"}
+		usernames, err := lookupUsernames(ctx, mock, repo)
+		require.NoError(t, err)
+		require.Equal(t, mock.Authors, usernames)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := lookupUsernames(ctx, client.NewMock(), repo)
+		require.ErrorIs(t, err, client.ErrNotImplemented)
+	})
+}
@@ -20,7 +20,7 @@ import (
 )
 
 const (
-	artifactChecksumExtra = "Checksum"
+	artifactChecksumExtra = artifact.ExtraChecksum
 )
 
 var (
@@ -37,16 +37,24 @@ func (Pipe) Skip(ctx *context.Context) bool { return ctx.Config.Checksum.Disable
 // Default sets the pipe defaults.
 func (Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Checksum.NameTemplate == "" {
-		ctx.Config.Checksum.NameTemplate = "{{ .ProjectName }}_{{ .Version }}_checksums.txt"
+		if ctx.Config.Checksum.Split {
+			ctx.Config.Checksum.NameTemplate = "{{ .ArtifactName }}.{{ .Algorithm }}"
+		} else {
+			ctx.Config.Checksum.NameTemplate = "{{ .ProjectName }}_{{ .Version }}_checksums.txt"
+		}
 	}
 	if ctx.Config.Checksum.Algorithm == "" {
 		ctx.Config.Checksum.Algorithm = "sha256"
 	}
-	return nil
+	return artifact.ValidateChecksumAlgorithm(ctx.Config.Checksum.Algorithm)
 }
 
 // Run the pipe.
 func (Pipe) Run(ctx *context.Context) error {
+	if ctx.Config.Checksum.Split {
+		return splitChecksum(ctx)
+	}
+
 	filename, err := tmpl.New(ctx).Apply(ctx.Config.Checksum.NameTemplate)
 	if err != nil {
 		return err
@@ -72,9 +80,61 @@ func (Pipe) Run(ctx *context.Context) error {
 	return nil
 }
 
-func refresh(ctx *context.Context, filepath string) error {
+// splitChecksum writes one checksum file for each artifact.
+func splitChecksum(ctx *context.Context) error {
+	artifactList, err := buildArtifactList(ctx)
+	if err != nil {
+		if errors.Is(err, errNoArtifacts) {
+			return nil
+		}
+		return err
+	}
+
+	g := semerrgroup.New(ctx.Parallelism)
+	for _, art := range artifactList {
+		art := art
+		g.Go(func() error {
+			filename, err := tmpl.New(ctx).
+				WithArtifact(art).
+				WithExtraFields(tmpl.Fields{
+					"Algorithm": ctx.Config.Checksum.Algorithm,
+				}).
+				Apply(ctx.Config.Checksum.NameTemplate)
+			if err != nil {
+				return err
+			}
+			filepath := filepath.Join(ctx.Config.Dist, filename)
+			if err := refreshOne(ctx, art, filepath); err != nil {
+				return err
+			}
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Type: artifact.Checksum,
+				Path: filepath,
+				Name: filename,
+				Extra: map[string]interface{}{
+					artifact.ExtraRefresh: func() error {
+						log.WithField("file", filename).Info("refreshing checksums")
+						return refreshOne(ctx, art, filepath)
+					},
+				},
+			})
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func refreshOne(ctx *context.Context, art *artifact.Artifact, filepath string) error {
 	lock.Lock()
 	defer lock.Unlock()
+	sumLine, err := checksums(ctx.Config.Checksum.Algorithm, art)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath, []byte(sumLine), 0o644)
+}
+
+func buildArtifactList(ctx *context.Context) ([]*artifact.Artifact, error) {
 	filter := artifact.Or(
 		artifact.ByType(artifact.UploadableArchive),
 		artifact.ByType(artifact.UploadableBinary),
@@ -90,7 +150,7 @@ func refresh(ctx *context.Context, filepath string) error {
 
 	extraFiles, err := extrafiles.Find(ctx, ctx.Config.Checksum.ExtraFiles)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for name, path := range extraFiles {
@@ -102,7 +162,18 @@ func refresh(ctx *context.Context, filepath string) error {
 	}
 
 	if len(artifactList) == 0 {
-		return errNoArtifacts
+		return nil, errNoArtifacts
+	}
+	return artifactList, nil
+}
+
+func refresh(ctx *context.Context, filepath string) error {
+	lock.Lock()
+	defer lock.Unlock()
+
+	artifactList, err := buildArtifactList(ctx)
+	if err != nil {
+		return err
 	}
 
 	g := semerrgroup.New(ctx.Parallelism)
@@ -245,6 +245,121 @@ func TestDefault(t *testing.T) {
 	require.Equal(t, "sha256", ctx.Config.Checksum.Algorithm)
 }
 
+func TestDefaultSplit(t *testing.T) {
+	ctx := &context.Context{
+		Config: config.Project{
+			Checksum: config.Checksum{
+				Split: true,
+			},
+		},
+	}
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, "{{ .ArtifactName }}.{{ .Algorithm }}", ctx.Config.Checksum.NameTemplate)
+	require.Equal(t, "sha256", ctx.Config.Checksum.Algorithm)
+}
+
+func TestPipeSplit(t *testing.T) {
+	const sum = "61d034473102d7dac305902770471fd50f4c5b26f6831a56dd90b5184b3c30fc  "
+
+	folder := t.TempDir()
+	file := filepath.Join(folder, "binary")
+	require.NoError(t, os.WriteFile(file, []byte("some string"), 0o644))
+	ctx := context.New(
+		config.Project{
+			Dist:        folder,
+			ProjectName: "binary",
+			Checksum: config.Checksum{
+				Split: true,
+			},
+		},
+	)
+	ctx.Git.CurrentTag = "1.2.3"
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "binary.tar.gz",
+		Path: file,
+		Type: artifact.UploadableArchive,
+	})
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "binary.rpm",
+		Path: file,
+		Type: artifact.LinuxPackage,
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	checksums := ctx.Artifacts.Filter(artifact.ByType(artifact.Checksum)).List()
+	require.Len(t, checksums, 2)
+	var names []string
+	for _, a := range checksums {
+		names = append(names, a.Name)
+		require.Equal(t, filepath.Join(folder, a.Name), a.Path)
+		require.NoError(t, a.Refresh(), "refresh should not fail and yield same results as nothing changed")
+		bts, err := os.ReadFile(a.Path)
+		require.NoError(t, err)
+		require.Equal(t, sum+strings.TrimSuffix(a.Name, ".sha256")+"\n", string(bts))
+	}
+	require.ElementsMatch(t, []string{"binary.tar.gz.sha256", "binary.rpm.sha256"}, names)
+	require.NoFileExists(t, filepath.Join(folder, "binary_1.2.3_checksums.txt"))
+}
+
+func TestPipeSplitInvalidNameTemplate(t *testing.T) {
+	folder := t.TempDir()
+	file := filepath.Join(folder, "binary")
+	require.NoError(t, os.WriteFile(file, []byte("some string"), 0o644))
+	ctx := context.New(
+		config.Project{
+			Dist: folder,
+			Checksum: config.Checksum{
+				Split:        true,
+				NameTemplate: "{{ .Pro }_checksums.txt",
+				Algorithm:    "sha256",
+			},
+		},
+	)
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "whatever",
+		Path: file,
+		Type: artifact.UploadableBinary,
+	})
+	require.EqualError(t, Pipe{}.Run(ctx), `template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestDefaultInvalidAlgorithm(t *testing.T) {
+	ctx := &context.Context{
+		Config: config.Project{
+			Checksum: config.Checksum{
+				Algorithm: "sha3",
+			},
+		},
+	}
+	require.EqualError(t, Pipe{}.Default(ctx), "invalid algorithm: sha3")
+}
+
+func TestPipeBlake3(t *testing.T) {
+	folder := t.TempDir()
+	file := filepath.Join(folder, "binary")
+	require.NoError(t, os.WriteFile(file, []byte("some string"), 0o644))
+	ctx := context.New(
+		config.Project{
+			Dist: folder,
+			Checksum: config.Checksum{
+				NameTemplate: "checksums.txt",
+				Algorithm:    "blake3",
+			},
+		},
+	)
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "binary",
+		Path: file,
+		Type: artifact.UploadableBinary,
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+	bts, err := os.ReadFile(filepath.Join(folder, "checksums.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "0adc40edc746c9b6440704a5e8f67946aaf5943ede69a59949175ce0157b711d  binary\n", string(bts))
+}
+
 func TestDefaultSet(t *testing.T) {
 	ctx := &context.Context{
 		Config: config.Project{
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"text/template"
 
 	"github.com/caarlos0/log"
@@ -27,6 +28,13 @@ const chocoConfigExtra = "ChocolateyConfig"
 // cmd represents a command executor.
 var cmd cmder = stdCmd{}
 
+// dependencyVersionRegex matches nuget versions, either a single version
+// (e.g. 1.2.3) or a version range (e.g. [1.0,2.0)).
+// more info: https://learn.microsoft.com/en-us/nuget/concepts/package-versioning#version-ranges
+var dependencyVersionRegex = regexp.MustCompile(`^(` + nugetVersion + `|[\[(]\s*(` + nugetVersion + `)?\s*(,\s*(` + nugetVersion + `)?\s*)?[\])])$`)
+
+const nugetVersion = `\d+(\.\d+){0,3}(-[0-9A-Za-z.-]+)?`
+
 // Pipe for chocolatey packaging.
 type Pipe struct{}
 
@@ -53,6 +61,20 @@ func (Pipe) Default(ctx *context.Context) error {
 		if choco.SourceRepo == "" {
 			choco.SourceRepo = "https://push.chocolatey.org/"
 		}
+
+		switch choco.ChecksumType {
+		case "":
+			choco.ChecksumType = "sha256"
+		case "md5", "sha1", "sha256", "sha512":
+		default:
+			return fmt.Errorf("invalid chocolatey checksum type: %s, valid options are: md5, sha1, sha256, sha512", choco.ChecksumType)
+		}
+
+		for _, dep := range choco.Dependencies {
+			if dep.Version != "" && !dependencyVersionRegex.MatchString(dep.Version) {
+				return fmt.Errorf("invalid chocolatey dependency version for %s: %s", dep.ID, dep.Version)
+			}
+		}
 	}
 
 	return nil
@@ -285,7 +307,9 @@ func buildTemplate(name string, text string, data templateData) ([]byte, error)
 }
 
 func dataFor(ctx *context.Context, cl client.Client, choco config.Chocolatey, artifacts []*artifact.Artifact) (templateData, error) {
-	result := templateData{}
+	result := templateData{
+		ChecksumType: choco.ChecksumType,
+	}
 
 	if choco.URLTemplate == "" {
 		url, err := cl.ReleaseURLTemplate(ctx)
@@ -297,7 +321,7 @@ func dataFor(ctx *context.Context, cl client.Client, choco config.Chocolatey, ar
 	}
 
 	for _, artifact := range artifacts {
-		sum, err := artifact.Checksum("sha256")
+		sum, err := artifact.Checksum(choco.ChecksumType)
 		if err != nil {
 			return result, err
 		}
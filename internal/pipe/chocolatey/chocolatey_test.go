@@ -41,6 +41,58 @@ func TestDefault(t *testing.T) {
 	require.Equal(t, ctx.Config.ProjectName, ctx.Config.Chocolateys[0].Name)
 	require.Equal(t, ctx.Config.ProjectName, ctx.Config.Chocolateys[0].Title)
 	require.Equal(t, "v1", ctx.Config.Chocolateys[0].Goamd64)
+	require.Equal(t, "sha256", ctx.Config.Chocolateys[0].ChecksumType)
+}
+
+func TestDefaultErrors(t *testing.T) {
+	for name, tt := range map[string]struct {
+		choco config.Chocolatey
+		err   string
+	}{
+		"invalid checksum type": {
+			choco: config.Chocolatey{ChecksumType: "crc32"},
+			err:   "invalid chocolatey checksum type: crc32, valid options are: md5, sha1, sha256, sha512",
+		},
+		"invalid dependency version": {
+			choco: config.Chocolatey{
+				Dependencies: []config.ChocolateyDependency{
+					{ID: "vcredist140", Version: "14.0 or newer"},
+				},
+			},
+			err: "invalid chocolatey dependency version for vcredist140: 14.0 or newer",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				ProjectName: "myproject",
+				Chocolateys: []config.Chocolatey{tt.choco},
+			})
+			require.EqualError(t, Pipe{}.Default(ctx), tt.err)
+		})
+	}
+}
+
+func TestDependencyVersionRegex(t *testing.T) {
+	for _, v := range []string{
+		"1.0",
+		"14.0.24215.20170201",
+		"1.0.0-beta1",
+		"[1.0]",
+		"[14.0,15.0)",
+		"(,1.0]",
+		"(1.0,)",
+		"[1.0, 2.0]",
+	} {
+		require.True(t, dependencyVersionRegex.MatchString(v), v)
+	}
+	for _, v := range []string{
+		"latest",
+		"1.0 or newer",
+		"[1.0",
+		"v1.0",
+	} {
+		require.False(t, dependencyVersionRegex.MatchString(v), v)
+	}
 }
 
 func Test_doRun(t *testing.T) {
@@ -67,8 +119,9 @@ func Test_doRun(t *testing.T) {
 		{
 			name: "choco command not found",
 			choco: config.Chocolatey{
-				Name:    "app",
-				Goamd64: "v1",
+				Name:         "app",
+				Goamd64:      "v1",
+				ChecksumType: "sha256",
 			},
 			exec: func() ([]byte, error) {
 				return nil, errors.New(`exec: "choco.exe": executable file not found in $PATH`)
@@ -78,9 +131,10 @@ func Test_doRun(t *testing.T) {
 		{
 			name: "skip publish",
 			choco: config.Chocolatey{
-				Name:        "app",
-				Goamd64:     "v1",
-				SkipPublish: true,
+				Name:         "app",
+				Goamd64:      "v1",
+				SkipPublish:  true,
+				ChecksumType: "sha256",
 			},
 			exec: func() ([]byte, error) {
 				return []byte("success"), nil
@@ -89,8 +143,9 @@ func Test_doRun(t *testing.T) {
 		{
 			name: "success",
 			choco: config.Chocolatey{
-				Name:    "app",
-				Goamd64: "v1",
+				Name:         "app",
+				Goamd64:      "v1",
+				ChecksumType: "sha256",
 			},
 			exec: func() ([]byte, error) {
 				return []byte("success"), nil
@@ -172,6 +227,25 @@ func Test_buildNuspec(t *testing.T) {
 	golden.RequireEqualExt(t, out, ".nuspec")
 }
 
+func Test_buildNuspecDependencyVersions(t *testing.T) {
+	ctx := &context.Context{
+		Version: "1.12.3",
+	}
+	choco := config.Chocolatey{
+		Name:    "goreleaser",
+		Authors: "caarlos0",
+		Dependencies: []config.ChocolateyDependency{
+			{ID: "nfpm", Version: "2.20.0"},
+			{ID: "vcredist140", Version: "[14.0,15.0)"},
+		},
+	}
+
+	out, err := buildNuspec(ctx, choco)
+	require.NoError(t, err)
+	require.Contains(t, string(out), `<dependency id="nfpm" version="2.20.0" />`)
+	require.Contains(t, string(out), `<dependency id="vcredist140" version="[14.0,15.0)" />`)
+}
+
 func Test_buildTemplate(t *testing.T) {
 	folder := t.TempDir()
 	file := filepath.Join(folder, "archive")
@@ -202,7 +276,8 @@ func Test_buildTemplate(t *testing.T) {
 	}
 
 	choco := config.Chocolatey{
-		Name: "app",
+		Name:         "app",
+		ChecksumType: "sha256",
 	}
 
 	client := client.NewMock()
@@ -218,6 +293,42 @@ func Test_buildTemplate(t *testing.T) {
 	golden.RequireEqualExt(t, out, ".script.ps1")
 }
 
+func Test_buildTemplateChecksumType(t *testing.T) {
+	folder := t.TempDir()
+	file := filepath.Join(folder, "archive")
+	require.NoError(t, os.WriteFile(file, []byte("lorem ipsum"), 0o644))
+
+	ctx := &context.Context{
+		Version: "1.0.0",
+		Git: context.GitInfo{
+			CurrentTag: "v1.0.0",
+		},
+	}
+
+	artifacts := []*artifact.Artifact{
+		{
+			Name:    "app_1.0.0_windows_amd64.zip",
+			Goos:    "windows",
+			Goarch:  "amd64",
+			Goamd64: "v1",
+			Path:    file,
+		},
+	}
+
+	choco := config.Chocolatey{
+		Name:         "app",
+		ChecksumType: "sha512",
+	}
+
+	data, err := dataFor(ctx, client.NewMock(), choco, artifacts)
+	require.NoError(t, err)
+
+	out, err := buildTemplate(choco.Name, scriptTemplate, data)
+	require.NoError(t, err)
+	require.Contains(t, string(out), "checksumType64 = 'sha512'")
+	require.Contains(t, string(out), "checksum64     = 'f80eebd9aabb1a15fb869ed568d858a5c0dca3d5da07a410e1bd988763918d973e344814625f7c844695b2de36ffd27af290d0e34362c51dee5947d58d40527a'")
+}
+
 func TestPublish(t *testing.T) {
 	folder := t.TempDir()
 	file := filepath.Join(folder, "archive")
@@ -1,7 +1,8 @@
 package chocolatey
 
 type templateData struct {
-	Packages []releasePackage
+	Packages     []releasePackage
+	ChecksumType string
 }
 
 type releasePackage struct {
@@ -25,11 +26,11 @@ $packageArgs = @{
     {{- if eq $release.Arch "amd64" }}
     url64bit       = '{{ $release.DownloadURL }}'
     checksum64     = '{{ $release.Checksum }}'
-    checksumType64 = 'sha256'
+    checksumType64 = '{{ $.ChecksumType }}'
     {{- else }}
     url            = '{{ $release.DownloadURL }}'
     checksum       = '{{ $release.Checksum }}'
-    checksumType   = 'sha256'
+    checksumType   = '{{ $.ChecksumType }}'
     {{- end }}
     {{- end }}
 }
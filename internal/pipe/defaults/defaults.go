@@ -20,6 +20,9 @@ func (Pipe) String() string { return "setting defaults" }
 
 // Run the pipe.
 func (Pipe) Run(ctx *context.Context) error {
+	if err := tmpl.ValidateFunctions(ctx.Config.TemplateFunctions); err != nil {
+		return err
+	}
 	if ctx.Config.Dist == "" {
 		ctx.Config.Dist = "dist"
 	}
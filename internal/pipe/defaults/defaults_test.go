@@ -13,6 +13,13 @@ func TestDescription(t *testing.T) {
 	require.NotEmpty(t, Pipe{}.String())
 }
 
+func TestInvalidTemplateFunctions(t *testing.T) {
+	ctx := context.New(config.Project{
+		TemplateFunctions: map[string]string{"foo": "exec"},
+	})
+	require.EqualError(t, Pipe{}.Run(ctx), `template function "foo": unknown built-in function "exec"`)
+}
+
 func TestFillBasicData(t *testing.T) {
 	testlib.Mktmp(t)
 	testlib.GitInit(t)
@@ -3,12 +3,15 @@ package discord
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/caarlos0/log"
 	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/disgo/webhook"
 	"github.com/disgoorg/snowflake/v2"
+	"github.com/goreleaser/goreleaser/internal/httpclient"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
@@ -18,6 +21,7 @@ const (
 	defaultColor           = "3888754"
 	defaultIcon            = "https://goreleaser.com/static/avatar.png"
 	defaultMessageTemplate = `{{ .ProjectName }} {{ .Tag }} is out! Check it out at {{ .ReleaseURL }}`
+	defaultRetryBackoff    = time.Second
 )
 
 type Pipe struct{}
@@ -43,6 +47,9 @@ func (p Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Announce.Discord.Color == "" {
 		ctx.Config.Announce.Discord.Color = defaultColor
 	}
+	if ctx.Config.Announce.Discord.RetryBackoff == 0 {
+		ctx.Config.Announce.Discord.RetryBackoff = defaultRetryBackoff
+	}
 	return nil
 }
 
@@ -52,6 +59,11 @@ func (p Pipe) Announce(ctx *context.Context) error {
 		return fmt.Errorf("discord: %w", err)
 	}
 
+	if ctx.AnnounceDryRun {
+		log.WithField("message", msg).Info("dry-run, not posting")
+		return nil
+	}
+
 	var cfg Config
 	if err = env.Parse(&cfg); err != nil {
 		return fmt.Errorf("discord: %w", err)
@@ -68,7 +80,17 @@ func (p Pipe) Announce(ctx *context.Context) error {
 	if err != nil {
 		return fmt.Errorf("discord: %w", err)
 	}
-	if _, err = webhook.New(webhookID, cfg.WebhookToken).CreateMessage(discord.WebhookMessageCreate{
+	var opts []webhook.ConfigOpt
+	if ctx.Config.Announce.Discord.Retries > 0 {
+		client, err := httpclient.New(false, "", "")
+		if err != nil {
+			return fmt.Errorf("discord: %w", err)
+		}
+		client = httpclient.WithRetries(client, ctx.Config.Announce.Discord.Retries, ctx.Config.Announce.Discord.RetryBackoff)
+		opts = append(opts, webhook.WithRestClientConfigOpts(rest.WithHTTPClient(client)))
+	}
+
+	if _, err = webhook.New(webhookID, cfg.WebhookToken, opts...).CreateMessage(discord.WebhookMessageCreate{
 		Embeds: []discord.Embed{
 			{
 				Author: &discord.EmbedAuthor{
@@ -39,6 +39,21 @@ func TestAnnounceMissingEnv(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `discord: env: environment variable "DISCORD_WEBHOOK_ID" should not be empty; environment variable "DISCORD_WEBHOOK_TOKEN" should not be empty`)
 }
 
+func TestAnnounceDryRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Discord: config.Discord{
+				Enabled:         true,
+				MessageTemplate: "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
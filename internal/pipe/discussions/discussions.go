@@ -0,0 +1,151 @@
+// Package discussions announces releases as GitHub Discussions.
+package discussions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const (
+	defaultCategory        = "Announcements"
+	defaultMessageTemplate = `{{ .ProjectName }} {{ .Tag }} is out! Check it out at {{ .ReleaseURL }}`
+	defaultMessageTitle    = `{{ .ProjectName }} {{ .Tag }} is out!`
+)
+
+type Pipe struct{}
+
+func (Pipe) String() string                 { return "github discussions" }
+func (Pipe) Skip(ctx *context.Context) bool { return !ctx.Config.Announce.Discussions.Enabled }
+
+func (Pipe) Default(ctx *context.Context) error {
+	if ctx.Config.Announce.Discussions.MessageTemplate == "" {
+		ctx.Config.Announce.Discussions.MessageTemplate = defaultMessageTemplate
+	}
+	if ctx.Config.Announce.Discussions.TitleTemplate == "" {
+		ctx.Config.Announce.Discussions.TitleTemplate = defaultMessageTitle
+	}
+	if ctx.Config.Announce.Discussions.Category == "" {
+		ctx.Config.Announce.Discussions.Category = defaultCategory
+	}
+	if ctx.Config.Announce.Discussions.Repo.Name == "" {
+		ctx.Config.Announce.Discussions.Repo = ctx.Config.Release.GitHub
+	}
+	return nil
+}
+
+func (Pipe) Announce(ctx *context.Context) error {
+	title, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Discussions.TitleTemplate)
+	if err != nil {
+		return fmt.Errorf("discussions: %w", err)
+	}
+
+	msg, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Discussions.MessageTemplate)
+	if err != nil {
+		return fmt.Errorf("discussions: %w", err)
+	}
+
+	if ctx.AnnounceDryRun {
+		log.WithField("message", msg).Info("dry-run, not posting")
+		return nil
+	}
+
+	if ctx.TokenType != context.TokenTypeGitHub || ctx.Token == "" {
+		return errors.New("discussions: a GitHub token is required")
+	}
+
+	repo := ctx.Config.Announce.Discussions.Repo
+	if repo.Owner == "" || repo.Name == "" {
+		return errors.New("discussions: repository owner and name are required")
+	}
+
+	cli, err := client.New(ctx)
+	if err != nil {
+		return fmt.Errorf("discussions: %w", err)
+	}
+	gql, ok := cli.(client.GraphQLClient)
+	if !ok {
+		return errors.New("discussions: client does not support GraphQL")
+	}
+
+	category := ctx.Config.Announce.Discussions.Category
+	repoID, categoryID, err := findCategory(ctx, gql, repo.Owner, repo.Name, category)
+	if err != nil {
+		return fmt.Errorf("discussions: %w", err)
+	}
+
+	log.Infof("posting: '%s'", msg)
+	url, err := createDiscussion(ctx, gql, repoID, categoryID, title, msg)
+	if err != nil {
+		return fmt.Errorf("discussions: %w", err)
+	}
+	log.Infof("the discussion is available at: %s", url)
+	return nil
+}
+
+func findCategory(ctx *context.Context, cli client.GraphQLClient, owner, name, category string) (string, string, error) {
+	var result struct {
+		Repository *struct {
+			ID                   string `json:"id"`
+			DiscussionCategories struct {
+				Nodes []struct {
+					ID   string `json:"id"`
+					Name string `json:"name"`
+				} `json:"nodes"`
+			} `json:"discussionCategories"`
+		} `json:"repository"`
+	}
+	if err := cli.GraphQL(ctx, `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    id
+    discussionCategories(first: 100) {
+      nodes { id name }
+    }
+  }
+}`, map[string]interface{}{
+		"owner": owner,
+		"name":  name,
+	}, &result); err != nil {
+		return "", "", err
+	}
+	if result.Repository == nil {
+		return "", "", fmt.Errorf("repository %s/%s not found", owner, name)
+	}
+
+	var names []string
+	for _, node := range result.Repository.DiscussionCategories.Nodes {
+		if strings.EqualFold(node.Name, category) {
+			return result.Repository.ID, node.ID, nil
+		}
+		names = append(names, node.Name)
+	}
+	return "", "", fmt.Errorf("discussion category %q not found in %s/%s, available categories: %s", category, owner, name, strings.Join(names, ", "))
+}
+
+func createDiscussion(ctx *context.Context, cli client.GraphQLClient, repoID, categoryID, title, body string) (string, error) {
+	var result struct {
+		CreateDiscussion struct {
+			Discussion struct {
+				URL string `json:"url"`
+			} `json:"discussion"`
+		} `json:"createDiscussion"`
+	}
+	if err := cli.GraphQL(ctx, `mutation($repositoryId: ID!, $categoryId: ID!, $title: String!, $body: String!) {
+  createDiscussion(input: {repositoryId: $repositoryId, categoryId: $categoryId, title: $title, body: $body}) {
+    discussion { url }
+  }
+}`, map[string]interface{}{
+		"repositoryId": repoID,
+		"categoryId":   categoryID,
+		"title":        title,
+		"body":         body,
+	}, &result); err != nil {
+		return "", err
+	}
+	return result.CreateDiscussion.Discussion.URL, nil
+}
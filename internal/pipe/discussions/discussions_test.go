@@ -0,0 +1,153 @@
+package discussions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringer(t *testing.T) {
+	require.Equal(t, Pipe{}.String(), "github discussions")
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "goreleaser", Name: "goreleaser"},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	cfg := ctx.Config.Announce.Discussions
+	require.Equal(t, defaultMessageTemplate, cfg.MessageTemplate)
+	require.Equal(t, defaultMessageTitle, cfg.TitleTemplate)
+	require.Equal(t, defaultCategory, cfg.Category)
+	require.Equal(t, config.Repo{Owner: "goreleaser", Name: "goreleaser"}, cfg.Repo)
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Discussions: config.Discussions{
+					Enabled: true,
+				},
+			},
+		})
+		require.False(t, Pipe{}.Skip(ctx))
+	})
+}
+
+func newServer(t *testing.T, posted *string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/graphql", r.URL.Path)
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		bts, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var req struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.Unmarshal(bts, &req))
+
+		if strings.HasPrefix(req.Query, "query") {
+			fmt.Fprint(w, `{"data":{"repository":{"id":"R_1","discussionCategories":{"nodes":[{"id":"C_1","name":"General"},{"id":"C_2","name":"Announcements"}]}}}}`)
+			return
+		}
+		require.Equal(t, "R_1", req.Variables["repositoryId"])
+		require.Equal(t, "C_2", req.Variables["categoryId"])
+		*posted = req.Variables["title"].(string) + ": " + req.Variables["body"].(string)
+		fmt.Fprint(w, `{"data":{"createDiscussion":{"discussion":{"url":"https://github.com/goreleaser/goreleaser/discussions/1"}}}}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func newContext(srv *httptest.Server, category string) *context.Context {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		GitHubURLs:  config.GitHubURLs{API: srv.URL + "/api/v3/"},
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "goreleaser", Name: "goreleaser"},
+		},
+		Announce: config.Announce{
+			Discussions: config.Discussions{
+				Enabled:  true,
+				Category: category,
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Token = "token"
+	ctx.TokenType = context.TokenTypeGitHub
+	return ctx
+}
+
+func TestAnnounce(t *testing.T) {
+	var posted string
+	ctx := newContext(newServer(t, &posted), "announcements")
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, "foo v1.0.0 is out!: foo v1.0.0 is out! Check it out at ", posted)
+}
+
+func TestAnnounceCategoryNotFound(t *testing.T) {
+	var posted string
+	ctx := newContext(newServer(t, &posted), "Releases")
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `discussions: discussion category "Releases" not found in goreleaser/goreleaser, available categories: General, Announcements`)
+	require.Empty(t, posted)
+}
+
+func TestAnnounceGraphQLErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"errors":[{"message":"Could not resolve to a Repository"}]}`)
+	}))
+	t.Cleanup(srv.Close)
+	ctx := newContext(srv, "")
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), "discussions: Could not resolve to a Repository")
+}
+
+func TestAnnounceInvalidTemplate(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Discussions: config.Discussions{
+				TitleTemplate: "{{ .Foo }",
+			},
+		},
+	})
+	require.Error(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceMissingToken(t *testing.T) {
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "goreleaser", Name: "goreleaser"},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), "discussions: a GitHub token is required")
+}
+
+func TestAnnounceDryRun(t *testing.T) {
+	var posted string
+	ctx := newContext(newServer(t, &posted), "")
+	ctx.Config.Announce.Discussions.MessageTemplate = "{{ .Tag }} is out"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Empty(t, posted)
+}
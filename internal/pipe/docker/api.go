@@ -2,6 +2,7 @@ package docker
 
 import (
 	"bytes"
+	stdctx "context"
 	"fmt"
 	"io"
 	"os/exec"
@@ -35,6 +36,7 @@ func registerImager(use string, impl imager) {
 type imager interface {
 	Build(ctx *context.Context, root string, images, flags []string) error
 	Push(ctx *context.Context, image string, flags []string) (digest string, err error)
+	Test(ctx stdctx.Context, env []string, name, image string, args []string) error
 }
 
 // manifester is something that can create and push docker manifests.
@@ -45,6 +47,12 @@ type manifester interface {
 
 // nolint: unparam
 func runCommand(ctx *context.Context, dir, binary string, args ...string) error {
+	return runCommandContext(ctx, ctx.Env.Strings(), dir, binary, args...)
+}
+
+// runCommandContext runs the given command with the given environment,
+// killing it when the given context is done.
+func runCommandContext(ctx stdctx.Context, env []string, dir, binary string, args ...string) error {
 	fields := log.Fields{
 		"cmd": append([]string{binary}, args[0]),
 		"cwd": dir,
@@ -53,7 +61,7 @@ func runCommand(ctx *context.Context, dir, binary string, args ...string) error
 	/* #nosec */
 	cmd := exec.CommandContext(ctx, binary, args...)
 	cmd.Dir = dir
-	cmd.Env = ctx.Env.Strings()
+	cmd.Env = env
 
 	var b bytes.Buffer
 	w := gio.Safe(&b)
@@ -67,6 +75,22 @@ func runCommand(ctx *context.Context, dir, binary string, args ...string) error
 	return nil
 }
 
+// runTest runs the given image in a container with the given name.
+// Killing the client when the context is done doesn't stop the container,
+// so it is force removed if the test fails for any reason.
+func runTest(ctx stdctx.Context, env []string, binary, name, image string, args []string) error {
+	err := runCommandContext(ctx, env, ".", binary, testCommand(name, image, args)...)
+	if err != nil {
+		/* #nosec */
+		cmd := exec.Command(binary, "rm", "-f", name)
+		cmd.Env = env
+		if out, rmErr := cmd.CombinedOutput(); rmErr != nil {
+			log.WithError(rmErr).WithField("container", name).Debug(string(out))
+		}
+	}
+	return err
+}
+
 func runCommandWithOutput(ctx *context.Context, dir, binary string, args ...string) ([]byte, error) {
 	fields := log.Fields{
 		"cmd": append([]string{binary}, args[0]),
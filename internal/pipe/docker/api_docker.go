@@ -1,6 +1,7 @@
 package docker
 
 import (
+	stdctx "context"
 	"fmt"
 	"regexp"
 
@@ -9,6 +10,7 @@ import (
 
 func init() {
 	registerManifester(useDocker, dockerManifester{})
+	registerManifester(useBuildx, buildxManifester{})
 
 	registerImager(useDocker, dockerImager{})
 	registerImager(useBuildx, dockerImager{
@@ -45,6 +47,36 @@ func (m dockerManifester) Push(ctx *context.Context, manifest string, flags []st
 	return digest, nil
 }
 
+// buildxManifester creates manifests with `docker buildx imagetools`, which
+// supports annotations.
+// Note that `imagetools create` pushes the manifest right away.
+type buildxManifester struct{}
+
+func (m buildxManifester) Create(ctx *context.Context, manifest string, images, flags []string) error {
+	args := []string{"buildx", "imagetools", "create", "--tag", manifest}
+	args = append(args, flags...)
+	args = append(args, images...)
+
+	if err := runCommand(ctx, ".", "docker", args...); err != nil {
+		return fmt.Errorf("failed to create %s: %w", manifest, err)
+	}
+	return nil
+}
+
+func (m buildxManifester) Push(ctx *context.Context, manifest string, flags []string) (string, error) {
+	args := []string{"buildx", "imagetools", "inspect", manifest}
+	args = append(args, flags...)
+	bts, err := runCommandWithOutput(ctx, ".", "docker", args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect %s: %w", manifest, err)
+	}
+	digest := dockerDigestPattern.FindString(string(bts))
+	if digest == "" {
+		return "", fmt.Errorf("failed to find docker digest in docker buildx imagetools inspect output: %s", string(bts))
+	}
+	return digest, nil
+}
+
 type dockerImager struct {
 	buildx bool
 }
@@ -70,6 +102,10 @@ func (i dockerImager) Build(ctx *context.Context, root string, images, flags []s
 	return nil
 }
 
+func (i dockerImager) Test(ctx stdctx.Context, env []string, name, image string, args []string) error {
+	return runTest(ctx, env, "docker", name, image, args)
+}
+
 func (i dockerImager) buildCommand(images, flags []string) []string {
 	base := []string{"build", "."}
 	if i.buildx {
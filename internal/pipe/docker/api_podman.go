@@ -0,0 +1,106 @@
+package docker
+
+import (
+	stdctx "context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+func init() {
+	registerManifester(usePodman, podmanManifester{})
+	registerImager(usePodman, podmanImager{})
+}
+
+// checkPodman returns an error if podman is not available.
+func checkPodman() error {
+	if _, err := exec.LookPath("podman"); err != nil {
+		return fmt.Errorf("podman not found in $PATH, make sure it is installed: https://podman.io/getting-started/installation: %w", err)
+	}
+	return nil
+}
+
+type podmanManifester struct{}
+
+func (m podmanManifester) Create(ctx *context.Context, manifest string, images, flags []string) error {
+	_ = runCommand(ctx, ".", "podman", "manifest", "rm", manifest)
+
+	args := []string{"manifest", "create", manifest}
+	args = append(args, images...)
+	args = append(args, flags...)
+
+	if err := runCommand(ctx, ".", "podman", args...); err != nil {
+		return fmt.Errorf("failed to create %s: %w", manifest, err)
+	}
+	return nil
+}
+
+func (m podmanManifester) Push(ctx *context.Context, manifest string, flags []string) (string, error) {
+	return pushWithDigestFile(ctx, manifest, func(digestFile string) []string {
+		args := []string{"manifest", "push", "--all", "--digestfile", digestFile}
+		args = append(args, flags...)
+		return append(args, manifest)
+	})
+}
+
+type podmanImager struct{}
+
+func (i podmanImager) Push(ctx *context.Context, image string, flags []string) (string, error) {
+	return pushWithDigestFile(ctx, image, func(digestFile string) []string {
+		return i.pushCommand(image, digestFile, flags)
+	})
+}
+
+func (i podmanImager) Build(ctx *context.Context, root string, images, flags []string) error {
+	if err := runCommand(ctx, root, "podman", i.buildCommand(images, flags)...); err != nil {
+		return fmt.Errorf("failed to build %s: %w", images[0], err)
+	}
+	return nil
+}
+
+func (i podmanImager) Test(ctx stdctx.Context, env []string, name, image string, args []string) error {
+	return runTest(ctx, env, "podman", name, image, args)
+}
+
+func (i podmanImager) buildCommand(images, flags []string) []string {
+	base := []string{"build", "."}
+	for _, image := range images {
+		base = append(base, "-t", image)
+	}
+	base = append(base, flags...)
+	return base
+}
+
+func (i podmanImager) pushCommand(image, digestFile string, flags []string) []string {
+	base := []string{"push", "--digestfile", digestFile}
+	base = append(base, flags...)
+	return append(base, image)
+}
+
+// pushWithDigestFile runs a podman push command, reading the resulting digest
+// from the digest file, as podman doesn't output it.
+func pushWithDigestFile(ctx *context.Context, name string, args func(digestFile string) []string) (string, error) {
+	f, err := os.CreateTemp("", "goreleaser-podman-digest")
+	if err != nil {
+		return "", fmt.Errorf("failed to push %s: %w", name, err)
+	}
+	_ = f.Close()
+	defer os.Remove(f.Name())
+
+	if err := runCommand(ctx, ".", "podman", args(f.Name())...); err != nil {
+		return "", fmt.Errorf("failed to push %s: %w", name, err)
+	}
+
+	bts, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to push %s: %w", name, err)
+	}
+	digest := dockerDigestPattern.FindString(strings.TrimSpace(string(bts)))
+	if digest == "" {
+		return "", fmt.Errorf("failed to find digest in podman digest file: %s", string(bts))
+	}
+	return digest, nil
+}
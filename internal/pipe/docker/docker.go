@@ -1,11 +1,14 @@
 package docker
 
 import (
+	stdctx "context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -23,8 +26,11 @@ const (
 
 	useBuildx = "buildx"
 	useDocker = "docker"
+	usePodman = "podman"
 )
 
+const defaultTestTimeout = time.Minute
+
 // Pipe for docker.
 type Pipe struct{}
 
@@ -66,10 +72,28 @@ func (Pipe) Default(ctx *context.Context) error {
 				return fmt.Errorf("invalid docker.files: can't be . or inside dist folder: %s", f)
 			}
 		}
+		if err := validateSecrets(docker.Secrets); err != nil {
+			return err
+		}
+		if len(docker.TestCommand) > 0 && docker.TestTimeout == 0 {
+			docker.TestTimeout = defaultTestTimeout
+		}
 	}
 	return ids.Validate()
 }
 
+func validateSecrets(secrets []config.DockerSecret) error {
+	for _, secret := range secrets {
+		if secret.ID == "" {
+			return fmt.Errorf("invalid docker.secrets: id is required")
+		}
+		if (secret.Src == "") == (secret.Env == "") {
+			return fmt.Errorf("invalid docker.secrets: %s: exactly one of src or env must be set", secret.ID)
+		}
+	}
+	return nil
+}
+
 func validateImager(use string) error {
 	valid := make([]string, 0, len(imagers))
 	for k := range imagers {
@@ -102,6 +126,15 @@ func (Pipe) Publish(ctx *context.Context) error {
 
 // Run the pipe.
 func (Pipe) Run(ctx *context.Context) error {
+	for _, docker := range ctx.Config.Dockers {
+		if docker.Use == usePodman {
+			if err := checkPodman(); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
 	g := semerrgroup.NewSkipAware(semerrgroup.New(ctx.Parallelism))
 	for i, docker := range ctx.Config.Dockers {
 		i := i
@@ -162,13 +195,10 @@ func process(ctx *context.Context, docker config.Docker, artifacts []*artifact.A
 	log := log.WithField("image", images[0])
 	log.Debug("tempdir: " + tmp)
 
-	dockerfile, err := tmpl.New(ctx).Apply(docker.Dockerfile)
+	root, contextFlags, err := buildContext(ctx, docker, tmp)
 	if err != nil {
 		return err
 	}
-	if err := gio.Copy(dockerfile, filepath.Join(tmp, "Dockerfile")); err != nil {
-		return fmt.Errorf("failed to copy dockerfile: %w", err)
-	}
 
 	for _, file := range docker.Files {
 		if err := os.MkdirAll(filepath.Join(tmp, filepath.Dir(file)), 0o755); err != nil {
@@ -184,18 +214,39 @@ func process(ctx *context.Context, docker config.Docker, artifacts []*artifact.A
 		}
 	}
 
-	buildFlags, err := processBuildFlagTemplates(ctx, docker)
+	labelFlags, err := processLabels(ctx, docker)
+	if err != nil {
+		return err
+	}
+
+	templatedFlags, err := processBuildFlagTemplates(ctx, docker)
 	if err != nil {
 		return err
 	}
 
+	// labels go before the user build flags, so any --label set in them
+	// overrides the automatic ones.
+	buildFlags := append(contextFlags, labelFlags...)
+	buildFlags = append(buildFlags, templatedFlags...)
+
+	secretFlags, err := processSecrets(ctx, docker)
+	if err != nil {
+		return err
+	}
+	buildFlags = append(buildFlags, secretFlags...)
+
 	log.Info("building docker image")
-	if err := imagers[docker.Use].Build(ctx, tmp, images, buildFlags); err != nil {
+	if err := imagers[docker.Use].Build(ctx, root, images, buildFlags); err != nil {
 		return err
 	}
 
+	if err := testImage(ctx, docker, images[0]); err != nil {
+		return err
+	}
+
+	arts := make([]*artifact.Artifact, 0, len(images))
 	for _, img := range images {
-		ctx.Artifacts.Add(&artifact.Artifact{
+		art := &artifact.Artifact{
 			Type:   artifact.PublishableDockerImage,
 			Name:   img,
 			Path:   img,
@@ -205,15 +256,141 @@ func process(ctx *context.Context, docker config.Docker, artifacts []*artifact.A
 			Extra: map[string]interface{}{
 				dockerConfigExtra: docker,
 			},
-		})
+		}
+		ctx.Artifacts.Add(art)
+		arts = append(arts, art)
+	}
+
+	if !snapshotPush(ctx, docker) {
+		return nil
+	}
+	for _, art := range arts {
+		if err := dockerPush(ctx, art); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// artifactsBuildArg is the build arg holding the path, relative to the build
+// context, of the folder with the binaries and extra files, when using a
+// custom build context.
+const artifactsBuildArg = "GORELEASER_ARTIFACTS"
+
+// buildContext returns the folder the image should be built from, alongside
+// the extra build flags it needs.
+//
+// By default, the Dockerfile is copied into the tmp folder holding the
+// artifacts, which is then used as the build context.
+// When a context is set, the image is built from it, using the Dockerfile in
+// place, and the tmp folder path is passed as a build arg so the Dockerfile
+// can still COPY the artifacts.
+func buildContext(ctx *context.Context, docker config.Docker, tmp string) (string, []string, error) {
+	dockerfile, err := tmpl.New(ctx).Apply(docker.Dockerfile)
+	if err != nil {
+		return "", nil, err
+	}
+	root, err := tmpl.New(ctx).Apply(docker.Context)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if root == "" {
+		if err := gio.Copy(dockerfile, filepath.Join(tmp, "Dockerfile")); err != nil {
+			return "", nil, fmt.Errorf("failed to copy dockerfile: %w", err)
+		}
+		return tmp, nil, nil
+	}
+
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve docker context: %w", err)
+	}
+	if _, err := os.Stat(dockerfile); err != nil {
+		return "", nil, fmt.Errorf("failed to find dockerfile: %w", err)
+	}
+	dockerfile, err = filepath.Abs(dockerfile)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve dockerfile: %w", err)
+	}
+	absTmp, err := filepath.Abs(tmp)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to resolve artifacts folder: %w", err)
+	}
+	artifacts, err := filepath.Rel(root, absTmp)
+	if err != nil || artifacts == ".." || strings.HasPrefix(artifacts, ".."+string(filepath.Separator)) {
+		return "", nil, fmt.Errorf("invalid docker context %s: it must contain the dist folder %s", root, ctx.Config.Dist)
+	}
+	return root, []string{
+		"--file=" + dockerfile,
+		fmt.Sprintf("--build-arg=%s=%s", artifactsBuildArg, filepath.ToSlash(artifacts)),
+	}, nil
+}
+
+// snapshotPush returns whether the images of the given docker config should
+// be pushed right after being built.
+// That's only the case on snapshots with snapshot.push set, and only for
+// configs with snapshot_image_templates, so snapshots never get pushed to the
+// release image names.
+func snapshotPush(ctx *context.Context, docker config.Docker) bool {
+	return ctx.Snapshot && ctx.Config.Snapshot.Push && len(docker.SnapshotImageTemplates) > 0
+}
+
+// testImage runs the given image with the configured test command, failing
+// if it doesn't exit successfully within the configured timeout.
+func testImage(ctx *context.Context, docker config.Docker, image string) error {
+	if len(docker.TestCommand) == 0 {
+		return nil
+	}
+
+	// nolint:prealloc
+	var args []string
+	for _, argTemplate := range docker.TestCommand {
+		arg, err := tmpl.New(ctx).Apply(argTemplate)
+		if err != nil {
+			return fmt.Errorf("failed to process test command '%s': %w", argTemplate, err)
+		}
+		args = append(args, arg)
+	}
+
+	timeout := docker.TestTimeout
+	if timeout == 0 {
+		timeout = defaultTestTimeout
+	}
+	cctx, cancel := stdctx.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	name := fmt.Sprintf("goreleaser-test-%d", time.Now().UnixNano())
+	log.WithField("image", image).Info("testing docker image")
+	if err := imagers[docker.Use].Test(cctx, ctx.Env.Strings(), name, image, args); err != nil {
+		if errors.Is(cctx.Err(), stdctx.DeadlineExceeded) {
+			return fmt.Errorf("failed to test %s: timed out after %s", image, timeout)
+		}
+		return fmt.Errorf("failed to test %s: %w", image, err)
+	}
+	return nil
+}
+
+// testCommand returns the arguments to run the given image in a container
+// with the given name, removing the container afterwards.
+func testCommand(name, image string, args []string) []string {
+	return append([]string{"run", "--rm", "--name", name, image}, args...)
+}
+
 func processImageTemplates(ctx *context.Context, docker config.Docker) ([]string, error) {
+	registries, err := processRegistries(ctx, docker.Registries)
+	if err != nil {
+		return nil, err
+	}
+
+	templates := docker.ImageTemplates
+	if snapshotPush(ctx, docker) {
+		templates = docker.SnapshotImageTemplates
+	}
+
 	// nolint:prealloc
 	var images []string
-	for _, imageTemplate := range docker.ImageTemplates {
+	for _, imageTemplate := range templates {
 		image, err := tmpl.New(ctx).Apply(imageTemplate)
 		if err != nil {
 			return nil, fmt.Errorf("failed to execute image template '%s': %w", imageTemplate, err)
@@ -225,7 +402,43 @@ func processImageTemplates(ctx *context.Context, docker config.Docker) ([]string
 		images = append(images, image)
 	}
 
-	return images, nil
+	if len(registries) == 0 {
+		return images, nil
+	}
+
+	result := make([]string, 0, len(registries)*len(images))
+	for _, registry := range registries {
+		for _, image := range images {
+			result = append(result, withRegistry(registry, image))
+		}
+	}
+	return result, nil
+}
+
+// processRegistries evaluates the given registries templates, ignoring the
+// empty ones.
+func processRegistries(ctx *context.Context, registries []string) ([]string, error) {
+	// nolint:prealloc
+	var result []string
+	for _, registryTemplate := range registries {
+		registry, err := tmpl.New(ctx).Apply(registryTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute registry template '%s': %w", registryTemplate, err)
+		}
+		registry = strings.TrimSuffix(strings.TrimSpace(registry), "/")
+		if registry == "" {
+			continue
+		}
+		result = append(result, registry)
+	}
+	return result, nil
+}
+
+func withRegistry(registry, image string) string {
+	if registry == "" {
+		return image
+	}
+	return registry + "/" + image
 }
 
 func processBuildFlagTemplates(ctx *context.Context, docker config.Docker) ([]string, error) {
@@ -241,6 +454,80 @@ func processBuildFlagTemplates(ctx *context.Context, docker config.Docker) ([]st
 	return buildFlags, nil
 }
 
+// processLabels returns the label flags for the given docker config.
+// Labels set by the user take precedence over the automatic ones, and
+// labels set in the build flag templates take precedence over both.
+func processLabels(ctx *context.Context, docker config.Docker) ([]string, error) {
+	labels := map[string]string{}
+	if docker.AutoLabels {
+		labels["org.opencontainers.image.created"] = "{{ .Date }}"
+		labels["org.opencontainers.image.revision"] = "{{ .FullCommit }}"
+		labels["org.opencontainers.image.version"] = "{{ .Version }}"
+		labels["org.opencontainers.image.title"] = "{{ .ProjectName }}"
+		if source := sourceURL(ctx.Git.URL); source != "" {
+			labels["org.opencontainers.image.source"] = source
+		}
+	}
+	for k, v := range docker.Labels {
+		labels[k] = v
+	}
+
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	flags := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v, err := tmpl.New(ctx).Apply(labels[k])
+		if err != nil {
+			return nil, fmt.Errorf("failed to process label '%s': %w", k, err)
+		}
+		flags = append(flags, fmt.Sprintf("--label=%s=%s", k, v))
+	}
+	return flags, nil
+}
+
+// processSecrets translates the secrets and ssh options into build flags.
+func processSecrets(ctx *context.Context, docker config.Docker) ([]string, error) {
+	// nolint:prealloc
+	var flags []string
+	for _, secret := range docker.Secrets {
+		src, err := tmpl.New(ctx).Apply(secret.Src)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process secret '%s': %w", secret.ID, err)
+		}
+		env, err := tmpl.New(ctx).Apply(secret.Env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process secret '%s': %w", secret.ID, err)
+		}
+		flag := "--secret=id=" + secret.ID
+		if src != "" {
+			// the build runs from a temporary directory, so relative paths
+			// need to be resolved against the current one.
+			if !filepath.IsAbs(src) {
+				src, err = filepath.Abs(src)
+				if err != nil {
+					return nil, fmt.Errorf("failed to process secret '%s': %w", secret.ID, err)
+				}
+			}
+			flag += ",src=" + src
+		} else {
+			flag += ",env=" + env
+		}
+		flags = append(flags, flag)
+	}
+	for _, sshTemplate := range docker.SSH {
+		ssh, err := tmpl.New(ctx).Apply(sshTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process ssh '%s': %w", sshTemplate, err)
+		}
+		flags = append(flags, "--ssh="+ssh)
+	}
+	return flags, nil
+}
+
 func dockerPush(ctx *context.Context, image *artifact.Artifact) error {
 	log.WithField("image", image.Name).Info("pushing")
 
@@ -1,12 +1,14 @@
 package docker
 
 import (
+	stdctx "context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/pipe"
@@ -629,6 +631,64 @@ func TestRunPipe(t *testing.T) {
 			pubAssertError:      testlib.AssertSkipped,
 			manifestAssertError: shouldNotErr,
 		},
+		"test_command": {
+			dockers: []config.Docker{
+				{
+					ImageTemplates: []string{registry + "goreleaser/test_command:v1"},
+					Goos:           "linux",
+					Goarch:         "amd64",
+					Dockerfile:     "testdata/Dockerfile.true",
+					TestCommand:    []string{"/bin/sh", "-c", "echo {{ .ProjectName }}"},
+				},
+			},
+			expect: []string{
+				registry + "goreleaser/test_command:v1",
+			},
+			assertImageLabels:   noLabels,
+			assertError:         shouldNotErr,
+			pubAssertError:      shouldNotErr,
+			manifestAssertError: shouldNotErr,
+		},
+		"test_command_fail": {
+			dockers: []config.Docker{
+				{
+					ImageTemplates: []string{registry + "goreleaser/test_command_fail:v1"},
+					Goos:           "linux",
+					Goarch:         "amd64",
+					Dockerfile:     "testdata/Dockerfile.true",
+					TestCommand:    []string{"/bin/sh", "-c", "echo broken entrypoint && exit 1"},
+				},
+			},
+			assertImageLabels: noLabels,
+			assertError:       shouldErr("failed to test localhost:5050/goreleaser/test_command_fail:v1: exit status 1: broken entrypoint"),
+		},
+		"test_command_timeout": {
+			dockers: []config.Docker{
+				{
+					ImageTemplates: []string{registry + "goreleaser/test_command_timeout:v1"},
+					Goos:           "linux",
+					Goarch:         "amd64",
+					Dockerfile:     "testdata/Dockerfile.true",
+					TestCommand:    []string{"sleep", "30"},
+					TestTimeout:    time.Second,
+				},
+			},
+			assertImageLabels: noLabels,
+			assertError:       shouldErr("failed to test localhost:5050/goreleaser/test_command_timeout:v1: timed out after 1s"),
+		},
+		"test_command_template_error": {
+			dockers: []config.Docker{
+				{
+					ImageTemplates: []string{registry + "goreleaser/test_command_template_error:v1"},
+					Goos:           "linux",
+					Goarch:         "amd64",
+					Dockerfile:     "testdata/Dockerfile.true",
+					TestCommand:    []string{"{{ .Nope }"},
+				},
+			},
+			assertImageLabels: noLabels,
+			assertError:       shouldTemplateErr,
+		},
 		"one_img_error_with_skip_push": {
 			dockers: []config.Docker{
 				{
@@ -945,6 +1005,10 @@ func TestRunPipe(t *testing.T) {
 
 	for name, docker := range table {
 		for imager := range imagers {
+			if imager == usePodman {
+				// podman needs its own daemon-less setup, not covered here
+				continue
+			}
 			t.Run(name+" on "+imager, func(t *testing.T) {
 				folder := t.TempDir()
 				dist := filepath.Join(folder, "dist")
@@ -1111,6 +1175,300 @@ func TestBuildCommand(t *testing.T) {
 	}
 }
 
+func TestBuildContext(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dockerfile := filepath.Join(wd, "testdata", "Dockerfile")
+
+	setup := func(t *testing.T) (*context.Context, string, string) {
+		t.Helper()
+		root := t.TempDir()
+		dist := filepath.Join(root, "dist")
+		tmp := filepath.Join(dist, "goreleaserdocker123")
+		require.NoError(t, os.MkdirAll(tmp, 0o755))
+		ctx := context.New(config.Project{
+			Dist: dist,
+			Env:  []string{"CONTEXT=" + root},
+		})
+		return ctx, root, tmp
+	}
+
+	t.Run("default", func(t *testing.T) {
+		ctx, _, tmp := setup(t)
+		root, flags, err := buildContext(ctx, config.Docker{
+			Dockerfile: "testdata/Dockerfile",
+		}, tmp)
+		require.NoError(t, err)
+		require.Equal(t, tmp, root)
+		require.Empty(t, flags)
+		require.FileExists(t, filepath.Join(tmp, "Dockerfile"))
+	})
+
+	t.Run("custom context", func(t *testing.T) {
+		ctx, contextDir, tmp := setup(t)
+		root, flags, err := buildContext(ctx, config.Docker{
+			Dockerfile: "testdata/{{ .ProjectName }}Dockerfile",
+			Context:    "{{ .Env.CONTEXT }}",
+		}, tmp)
+		require.NoError(t, err)
+		require.Equal(t, contextDir, root)
+		require.Equal(t, []string{
+			"--file=" + dockerfile,
+			"--build-arg=GORELEASER_ARTIFACTS=dist/goreleaserdocker123",
+		}, flags)
+		require.NoFileExists(t, filepath.Join(tmp, "Dockerfile"))
+
+		imager := dockerImager{}
+		require.Equal(t, []string{
+			"build", ".", "-t", "img",
+			"--file=" + dockerfile,
+			"--build-arg=GORELEASER_ARTIFACTS=dist/goreleaserdocker123",
+		}, imager.buildCommand([]string{"img"}, flags))
+	})
+
+	t.Run("context without dist", func(t *testing.T) {
+		ctx, _, tmp := setup(t)
+		_, _, err := buildContext(ctx, config.Docker{
+			Dockerfile: "testdata/Dockerfile",
+			Context:    t.TempDir(),
+		}, tmp)
+		require.ErrorContains(t, err, "it must contain the dist folder")
+	})
+
+	t.Run("missing dockerfile", func(t *testing.T) {
+		ctx, root, tmp := setup(t)
+		_, _, err := buildContext(ctx, config.Docker{
+			Dockerfile: "testdata/Dockerfile.nope",
+			Context:    root,
+		}, tmp)
+		require.ErrorContains(t, err, "failed to find dockerfile")
+	})
+
+	t.Run("invalid dockerfile template", func(t *testing.T) {
+		ctx, _, tmp := setup(t)
+		_, _, err := buildContext(ctx, config.Docker{
+			Dockerfile: "{{ .Env.NOPE }}",
+		}, tmp)
+		testlib.RequireTemplateError(t, err)
+	})
+
+	t.Run("invalid context template", func(t *testing.T) {
+		ctx, _, tmp := setup(t)
+		_, _, err := buildContext(ctx, config.Docker{
+			Dockerfile: "testdata/Dockerfile",
+			Context:    "{{ .Env.NOPE }}",
+		}, tmp)
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestProcessSecrets(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Env = map[string]string{
+		"HOME": "/home/foo",
+	}
+	flags, err := processSecrets(ctx, config.Docker{
+		Secrets: []config.DockerSecret{
+			{ID: "netrc", Src: "{{ .Env.HOME }}/.netrc"},
+			{ID: "token", Env: "GITHUB_TOKEN"},
+		},
+		SSH: []string{"default", "github={{ .Env.HOME }}/.ssh/id_ed25519"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"--secret=id=netrc,src=/home/foo/.netrc",
+		"--secret=id=token,env=GITHUB_TOKEN",
+		"--ssh=default",
+		"--ssh=github=/home/foo/.ssh/id_ed25519",
+	}, flags)
+
+	t.Run("relative src", func(t *testing.T) {
+		flags, err := processSecrets(ctx, config.Docker{
+			Secrets: []config.DockerSecret{
+				{ID: "netrc", Src: "testdata/.netrc"},
+			},
+		})
+		require.NoError(t, err)
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"--secret=id=netrc,src=" + filepath.Join(wd, "testdata/.netrc"),
+		}, flags)
+	})
+
+	t.Run("invalid secret template", func(t *testing.T) {
+		_, err := processSecrets(ctx, config.Docker{
+			Secrets: []config.DockerSecret{
+				{ID: "netrc", Src: "{{ .Env.NOPE }}"},
+			},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+
+	t.Run("invalid ssh template", func(t *testing.T) {
+		_, err := processSecrets(ctx, config.Docker{
+			SSH: []string{"{{ .Env.NOPE }}"},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestProcessLabels(t *testing.T) {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+	})
+	ctx.Version = "1.2.3"
+	ctx.Date = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx.Git = context.GitInfo{
+		FullCommit: "da39a3ee5e6b4b0d3255bfef95601890afd80709",
+		URL:        "git@github.com:goreleaser/goreleaser.git",
+	}
+
+	t.Run("none", func(t *testing.T) {
+		flags, err := processLabels(ctx, config.Docker{})
+		require.NoError(t, err)
+		require.Empty(t, flags)
+	})
+
+	t.Run("auto", func(t *testing.T) {
+		flags, err := processLabels(ctx, config.Docker{
+			AutoLabels: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"--label=org.opencontainers.image.created=2023-01-02T03:04:05Z",
+			"--label=org.opencontainers.image.revision=da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			"--label=org.opencontainers.image.source=https://github.com/goreleaser/goreleaser",
+			"--label=org.opencontainers.image.title=foo",
+			"--label=org.opencontainers.image.version=1.2.3",
+		}, flags)
+	})
+
+	t.Run("user labels win", func(t *testing.T) {
+		flags, err := processLabels(ctx, config.Docker{
+			AutoLabels: true,
+			Labels: map[string]string{
+				"org.opencontainers.image.title":  "{{ .ProjectName }}-server",
+				"org.opencontainers.image.source": "https://example.com/foo",
+				"com.example.team":                "infra",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"--label=com.example.team=infra",
+			"--label=org.opencontainers.image.created=2023-01-02T03:04:05Z",
+			"--label=org.opencontainers.image.revision=da39a3ee5e6b4b0d3255bfef95601890afd80709",
+			"--label=org.opencontainers.image.source=https://example.com/foo",
+			"--label=org.opencontainers.image.title=foo-server",
+			"--label=org.opencontainers.image.version=1.2.3",
+		}, flags)
+	})
+
+	t.Run("no git url", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		flags, err := processLabels(ctx, config.Docker{
+			AutoLabels: true,
+		})
+		require.NoError(t, err)
+		for _, flag := range flags {
+			require.NotContains(t, flag, "org.opencontainers.image.source")
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := processLabels(ctx, config.Docker{
+			Labels: map[string]string{"foo": "{{ .Nope }}"},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestDefaultInvalidSecrets(t *testing.T) {
+	for secret, expected := range map[config.DockerSecret]string{
+		{Src: "foo"}:                        "invalid docker.secrets: id is required",
+		{ID: "foo"}:                         "invalid docker.secrets: foo: exactly one of src or env must be set",
+		{ID: "foo", Src: "bar", Env: "BAR"}: "invalid docker.secrets: foo: exactly one of src or env must be set",
+	} {
+		t.Run(expected, func(t *testing.T) {
+			ctx := &context.Context{
+				Config: config.Project{
+					Dockers: []config.Docker{
+						{
+							Secrets: []config.DockerSecret{secret},
+						},
+					},
+				},
+			}
+			require.EqualError(t, Pipe{}.Default(ctx), expected)
+		})
+	}
+}
+
+func TestPodmanCommands(t *testing.T) {
+	images := []string{"goreleaser/test_build_flag", "goreleaser/test_multiple_tags"}
+	imager := podmanImager{}
+	require.Equal(t, []string{
+		"build", ".", "-t", images[0], "-t", images[1], "--label=foo", "--secret=id=foo,src=bar",
+	}, imager.buildCommand(images, []string{"--label=foo", "--secret=id=foo,src=bar"}))
+	require.Equal(t, []string{
+		"push", "--digestfile", "/tmp/digest", "--tls-verify=false", images[0],
+	}, imager.pushCommand(images[0], "/tmp/digest", []string{"--tls-verify=false"}))
+
+	// ensure the command line is the same as the one built for docker
+	require.Equal(t, dockerImager{}.buildCommand(images, []string{"--pull"}), imager.buildCommand(images, []string{"--pull"}))
+}
+
+func TestTestCommand(t *testing.T) {
+	require.Equal(t, []string{
+		"run", "--rm", "--name", "test", "goreleaser/test", "--version",
+	}, testCommand("test", "goreleaser/test", []string{"--version"}))
+	require.Equal(t, []string{
+		"run", "--rm", "--name", "test", "goreleaser/test",
+	}, testCommand("test", "goreleaser/test", nil))
+}
+
+func TestDefaultTestTimeout(t *testing.T) {
+	ctx := context.New(config.Project{
+		Dockers: []config.Docker{
+			{ImageTemplates: []string{"foo/bar"}},
+			{ImageTemplates: []string{"foo/bar"}, TestCommand: []string{"--version"}},
+			{ImageTemplates: []string{"foo/bar"}, TestCommand: []string{"--version"}, TestTimeout: time.Second},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Zero(t, ctx.Config.Dockers[0].TestTimeout)
+	require.Equal(t, time.Minute, ctx.Config.Dockers[1].TestTimeout)
+	require.Equal(t, time.Second, ctx.Config.Dockers[2].TestTimeout)
+}
+
+func TestPodmanNotFound(t *testing.T) {
+	t.Setenv("PATH", "")
+	ctx := context.New(config.Project{
+		Dockers: []config.Docker{
+			{
+				Use:            usePodman,
+				ImageTemplates: []string{"foo/bar"},
+			},
+		},
+		DockerManifests: []config.DockerManifest{
+			{
+				Use:          usePodman,
+				NameTemplate: "foo/bar",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, ManifestPipe{}.Default(ctx))
+
+	err := Pipe{}.Run(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "podman not found in $PATH")
+
+	err = ManifestPipe{}.Publish(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "podman not found in $PATH")
+}
+
 func TestDescription(t *testing.T) {
 	require.NotEmpty(t, Pipe{}.String())
 }
@@ -1347,6 +1705,165 @@ func Test_processImageTemplates(t *testing.T) {
 	}, images)
 }
 
+func Test_processImageTemplatesWithRegistries(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Env = map[string]string{
+		"HARBOR": "harbor.internal/",
+	}
+	ctx.Git = context.GitInfo{
+		CurrentTag: "v1.0.0",
+	}
+
+	images, err := processImageTemplates(ctx, config.Docker{
+		ImageTemplates: []string{
+			"user/image:{{.Tag}}",
+			"user/image:latest",
+		},
+		Registries: []string{
+			"docker.io",
+			"ghcr.io/",
+			"{{ .Env.HARBOR }}",
+			"",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"docker.io/user/image:v1.0.0",
+		"docker.io/user/image:latest",
+		"ghcr.io/user/image:v1.0.0",
+		"ghcr.io/user/image:latest",
+		"harbor.internal/user/image:v1.0.0",
+		"harbor.internal/user/image:latest",
+	}, images)
+
+	t.Run("invalid registry template", func(t *testing.T) {
+		_, err := processImageTemplates(ctx, config.Docker{
+			ImageTemplates: []string{"user/image:{{.Tag}}"},
+			Registries:     []string{"{{ .Env.NOPE }}"},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestManifestWithRegistry(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Git = context.GitInfo{
+		CurrentTag: "v1.0.0",
+	}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "ghcr.io/user/image:v1.0.0-amd64",
+		Type: artifact.DockerImage,
+		Extra: artifact.Extras{
+			artifact.ExtraDigest: "sha256:d1",
+		},
+	})
+	manifest := config.DockerManifest{
+		NameTemplate: "user/image:{{ .Tag }}",
+		ImageTemplates: []string{
+			"user/image:{{ .Tag }}-amd64",
+		},
+		Registries: []string{"ghcr.io"},
+	}
+
+	name, err := manifestName(ctx, manifest, "ghcr.io")
+	require.NoError(t, err)
+	require.Equal(t, "ghcr.io/user/image:v1.0.0", name)
+
+	images, err := manifestImages(ctx, manifest, "ghcr.io")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ghcr.io/user/image:v1.0.0-amd64@sha256:d1"}, images)
+
+	name, err = manifestName(ctx, manifest, "")
+	require.NoError(t, err)
+	require.Equal(t, "user/image:v1.0.0", name)
+}
+
+func TestManifestAnnotations(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Version = "1.0.0"
+	ctx.Date = time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx.Git = context.GitInfo{
+		CurrentTag: "v1.0.0",
+		FullCommit: "a1b2c3d4",
+		URL:        "git@github.com:goreleaser/goreleaser.git",
+	}
+
+	t.Run("none", func(t *testing.T) {
+		flags, err := manifestAnnotations(ctx, config.DockerManifest{})
+		require.NoError(t, err)
+		require.Empty(t, flags)
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		flags, err := manifestAnnotations(ctx, config.DockerManifest{
+			Annotations: map[string]string{
+				"org.opencontainers.image.title":   "foo",
+				"org.opencontainers.image.version": "{{ .Tag }}",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"--annotation=index:org.opencontainers.image.title=foo",
+			"--annotation=index:org.opencontainers.image.version=v1.0.0",
+		}, flags)
+	})
+
+	t.Run("auto", func(t *testing.T) {
+		flags, err := manifestAnnotations(ctx, config.DockerManifest{
+			AutoAnnotations: true,
+			Annotations: map[string]string{
+				"org.opencontainers.image.version": "{{ .Tag }}",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"--annotation=index:org.opencontainers.image.created=2023-01-02T03:04:05Z",
+			"--annotation=index:org.opencontainers.image.revision=a1b2c3d4",
+			"--annotation=index:org.opencontainers.image.source=https://github.com/goreleaser/goreleaser",
+			"--annotation=index:org.opencontainers.image.version=v1.0.0",
+		}, flags)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := manifestAnnotations(ctx, config.DockerManifest{
+			Annotations: map[string]string{
+				"foo": "{{ .Nope }}",
+			},
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestSourceURL(t *testing.T) {
+	for remote, expected := range map[string]string{
+		"": "",
+		"git@github.com:goreleaser/goreleaser.git":       "https://github.com/goreleaser/goreleaser",
+		"https://github.com/goreleaser/goreleaser.git":   "https://github.com/goreleaser/goreleaser",
+		"ssh://git@gitlab.com:22/goreleaser/foo.git":     "https://gitlab.com/goreleaser/foo",
+		"https://token@github.com/goreleaser/goreleaser": "https://github.com/goreleaser/goreleaser",
+	} {
+		t.Run(remote, func(t *testing.T) {
+			require.Equal(t, expected, sourceURL(remote))
+		})
+	}
+}
+
+func TestDefaultManifestAnnotationsRequireBuildx(t *testing.T) {
+	ctx := &context.Context{
+		Config: config.Project{
+			DockerManifests: []config.DockerManifest{
+				{
+					AutoAnnotations: true,
+				},
+			},
+		},
+	}
+	require.EqualError(t, ManifestPipe{}.Default(ctx), "docker manifest: annotations require use: buildx")
+
+	ctx.Config.DockerManifests[0].Use = useBuildx
+	require.NoError(t, ManifestPipe{}.Default(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("image", func(t *testing.T) {
 		t.Run("skip", func(t *testing.T) {
@@ -1429,3 +1946,99 @@ func TestWithDigest(t *testing.T) {
 		})
 	}
 }
+
+type fakeImager struct {
+	built  []string
+	pushed []string
+}
+
+func (f *fakeImager) Build(_ *context.Context, _ string, images, _ []string) error {
+	f.built = append(f.built, images...)
+	return nil
+}
+
+func (f *fakeImager) Push(_ *context.Context, image string, _ []string) (string, error) {
+	f.pushed = append(f.pushed, image)
+	return "sha256:fake", nil
+}
+
+func (f *fakeImager) Test(_ stdctx.Context, _ []string, _, _ string, _ []string) error {
+	return nil
+}
+
+func TestSnapshotPush(t *testing.T) {
+	const use = "fake"
+	setup := func(tb testing.TB, push, snapshot bool, docker config.Docker) (*context.Context, *fakeImager) {
+		tb.Helper()
+		imager := &fakeImager{}
+		registerImager(use, imager)
+		tb.Cleanup(func() {
+			lock.Lock()
+			defer lock.Unlock()
+			delete(imagers, use)
+		})
+
+		dockerfile := filepath.Join(tb.TempDir(), "Dockerfile")
+		require.NoError(tb, os.WriteFile(dockerfile, []byte("FROM scratch\n"), 0o644))
+		docker.Use = use
+		docker.Dockerfile = dockerfile
+
+		ctx := context.New(config.Project{
+			Dist:     tb.TempDir(),
+			Snapshot: config.Snapshot{Push: push},
+			Dockers:  []config.Docker{docker},
+		})
+		ctx.Snapshot = snapshot
+		ctx.Version = "1.0.0-SNAPSHOT-a1b2c3d"
+		return ctx, imager
+	}
+
+	docker := config.Docker{
+		ImageTemplates:         []string{"owner/img:{{ .Version }}"},
+		SnapshotImageTemplates: []string{"owner/img-snapshot:{{ .Version }}"},
+	}
+
+	t.Run("push", func(t *testing.T) {
+		ctx, imager := setup(t, true, true, docker)
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, []string{"owner/img-snapshot:1.0.0-SNAPSHOT-a1b2c3d"}, imager.built)
+		require.Equal(t, []string{"owner/img-snapshot:1.0.0-SNAPSHOT-a1b2c3d"}, imager.pushed)
+
+		images := ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List()
+		require.Len(t, images, 1)
+		require.Equal(t, "sha256:fake", artifact.ExtraOr(*images[0], artifact.ExtraDigest, ""))
+	})
+
+	t.Run("push not set", func(t *testing.T) {
+		ctx, imager := setup(t, false, true, docker)
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, []string{"owner/img:1.0.0-SNAPSHOT-a1b2c3d"}, imager.built)
+		require.Empty(t, imager.pushed)
+		require.Empty(t, ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List())
+	})
+
+	t.Run("not a snapshot", func(t *testing.T) {
+		ctx, imager := setup(t, true, false, docker)
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, []string{"owner/img:1.0.0-SNAPSHOT-a1b2c3d"}, imager.built)
+		require.Empty(t, imager.pushed)
+	})
+
+	t.Run("no snapshot image templates", func(t *testing.T) {
+		ctx, imager := setup(t, true, true, config.Docker{
+			ImageTemplates: []string{"owner/img:{{ .Version }}"},
+		})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, []string{"owner/img:1.0.0-SNAPSHOT-a1b2c3d"}, imager.built)
+		require.Empty(t, imager.pushed)
+	})
+
+	t.Run("skip push", func(t *testing.T) {
+		d := docker
+		d.SkipPush = "true"
+		ctx, imager := setup(t, true, true, d)
+		testlib.AssertSkipped(t, Pipe{}.Run(ctx))
+		require.Equal(t, []string{"owner/img-snapshot:1.0.0-SNAPSHOT-a1b2c3d"}, imager.built)
+		require.Empty(t, imager.pushed)
+	})
+}
@@ -2,6 +2,7 @@ package docker
 
 import (
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 
@@ -38,12 +39,24 @@ func (ManifestPipe) Default(ctx *context.Context) error {
 		if err := validateManifester(manifest.Use); err != nil {
 			return err
 		}
+		if (len(manifest.Annotations) > 0 || manifest.AutoAnnotations) && manifest.Use != useBuildx {
+			return fmt.Errorf("docker manifest: annotations require use: %s", useBuildx)
+		}
 	}
 	return ids.Validate()
 }
 
 // Publish the docker manifests.
 func (ManifestPipe) Publish(ctx *context.Context) error {
+	for _, manifest := range ctx.Config.DockerManifests {
+		if manifest.Use == usePodman {
+			if err := checkPodman(); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
 	g := semerrgroup.NewSkipAware(semerrgroup.New(1))
 	for _, manifest := range ctx.Config.DockerManifests {
 		manifest := manifest
@@ -56,45 +69,68 @@ func (ManifestPipe) Publish(ctx *context.Context) error {
 				return pipe.Skip("prerelease detected with 'auto' push, skipping docker manifest")
 			}
 
-			name, err := manifestName(ctx, manifest)
+			registries, err := processRegistries(ctx, manifest.Registries)
 			if err != nil {
 				return err
 			}
-
-			images, err := manifestImages(ctx, manifest)
-			if err != nil {
-				return err
-			}
-
-			manifester := manifesters[manifest.Use]
-
-			log.WithField("manifest", name).WithField("images", images).Info("creating")
-			if err := manifester.Create(ctx, name, images, manifest.CreateFlags); err != nil {
-				return err
-			}
-			art := &artifact.Artifact{
-				Type:  artifact.DockerManifest,
-				Name:  name,
-				Path:  name,
-				Extra: map[string]interface{}{},
-			}
-			if manifest.ID != "" {
-				art.Extra[artifact.ExtraID] = manifest.ID
+			if len(registries) == 0 {
+				registries = []string{""}
 			}
 
-			log.WithField("manifest", name).Info("pushing")
-			digest, err := manifester.Push(ctx, name, manifest.PushFlags)
-			if err != nil {
-				return err
+			for _, registry := range registries {
+				if err := publishManifest(ctx, manifest, registry); err != nil {
+					return err
+				}
 			}
-			art.Extra[artifact.ExtraDigest] = digest
-			ctx.Artifacts.Add(art)
 			return nil
 		})
 	}
 	return g.Wait()
 }
 
+func publishManifest(ctx *context.Context, manifest config.DockerManifest, registry string) error {
+	name, err := manifestName(ctx, manifest, registry)
+	if err != nil {
+		return err
+	}
+
+	images, err := manifestImages(ctx, manifest, registry)
+	if err != nil {
+		return err
+	}
+
+	annotations, err := manifestAnnotations(ctx, manifest)
+	if err != nil {
+		return err
+	}
+	flags := append(annotations, manifest.CreateFlags...)
+
+	manifester := manifesters[manifest.Use]
+
+	log.WithField("manifest", name).WithField("images", images).Info("creating")
+	if err := manifester.Create(ctx, name, images, flags); err != nil {
+		return err
+	}
+	art := &artifact.Artifact{
+		Type:  artifact.DockerManifest,
+		Name:  name,
+		Path:  name,
+		Extra: map[string]interface{}{},
+	}
+	if manifest.ID != "" {
+		art.Extra[artifact.ExtraID] = manifest.ID
+	}
+
+	log.WithField("manifest", name).Info("pushing")
+	digest, err := manifester.Push(ctx, name, manifest.PushFlags)
+	if err != nil {
+		return err
+	}
+	art.Extra[artifact.ExtraDigest] = digest
+	ctx.Artifacts.Add(art)
+	return nil
+}
+
 func validateManifester(use string) error {
 	valid := make([]string, 0, len(manifesters))
 	for k := range manifesters {
@@ -109,7 +145,7 @@ func validateManifester(use string) error {
 	return fmt.Errorf("docker manifest: invalid use: %s, valid options are %v", use, valid)
 }
 
-func manifestName(ctx *context.Context, manifest config.DockerManifest) (string, error) {
+func manifestName(ctx *context.Context, manifest config.DockerManifest, registry string) (string, error) {
 	name, err := tmpl.New(ctx).Apply(manifest.NameTemplate)
 	if err != nil {
 		return name, err
@@ -117,10 +153,10 @@ func manifestName(ctx *context.Context, manifest config.DockerManifest) (string,
 	if strings.TrimSpace(name) == "" {
 		return name, pipe.Skip("manifest name is empty")
 	}
-	return name, nil
+	return withRegistry(registry, name), nil
 }
 
-func manifestImages(ctx *context.Context, manifest config.DockerManifest) ([]string, error) {
+func manifestImages(ctx *context.Context, manifest config.DockerManifest, registry string) ([]string, error) {
 	artifacts := ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List()
 	imgs := make([]string, 0, len(manifest.ImageTemplates))
 	for _, img := range manifest.ImageTemplates {
@@ -128,7 +164,7 @@ func manifestImages(ctx *context.Context, manifest config.DockerManifest) ([]str
 		if err != nil {
 			return []string{}, err
 		}
-		imgs = append(imgs, withDigest(manifest.Use, str, artifacts))
+		imgs = append(imgs, withDigest(manifest.Use, withRegistry(registry, str), artifacts))
 	}
 	if strings.TrimSpace(strings.Join(manifest.ImageTemplates, "")) == "" {
 		return imgs, pipe.Skip("manifest has no images")
@@ -136,6 +172,58 @@ func manifestImages(ctx *context.Context, manifest config.DockerManifest) ([]str
 	return imgs, nil
 }
 
+// manifestAnnotations returns the annotation flags for the given manifest.
+func manifestAnnotations(ctx *context.Context, manifest config.DockerManifest) ([]string, error) {
+	annotations := map[string]string{}
+	if manifest.AutoAnnotations {
+		annotations["org.opencontainers.image.created"] = "{{ .Date }}"
+		annotations["org.opencontainers.image.revision"] = "{{ .FullCommit }}"
+		annotations["org.opencontainers.image.version"] = "{{ .Version }}"
+		if source := sourceURL(ctx.Git.URL); source != "" {
+			annotations["org.opencontainers.image.source"] = source
+		}
+	}
+	for k, v := range manifest.Annotations {
+		annotations[k] = v
+	}
+
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	flags := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v, err := tmpl.New(ctx).Apply(annotations[k])
+		if err != nil {
+			return nil, fmt.Errorf("failed to process annotation '%s': %w", k, err)
+		}
+		flags = append(flags, fmt.Sprintf("--annotation=index:%s=%s", k, v))
+	}
+	return flags, nil
+}
+
+// sourceURL converts git remote URLs, like git@github.com:foo/bar.git, into
+// browsable URLs, like https://github.com/foo/bar.
+func sourceURL(remote string) string {
+	remote = strings.TrimSuffix(strings.TrimSpace(remote), ".git")
+	if remote == "" {
+		return ""
+	}
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return ""
+		}
+		return "https://" + u.Hostname() + u.Path
+	}
+	if _, after, ok := strings.Cut(remote, "@"); ok {
+		remote = after
+	}
+	return "https://" + strings.Replace(remote, ":", "/", 1)
+}
+
 func withDigest(use, name string, images []*artifact.Artifact) string {
 	for _, art := range images {
 		if art.Name == name {
@@ -3,15 +3,20 @@
 package env
 
 import (
-	"bufio"
+	"bytes"
+	stdctx "context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"os/exec"
 	"strings"
 
+	"github.com/caarlos0/go-shellwords"
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	homedir "github.com/mitchellh/go-homedir"
 )
@@ -29,6 +34,18 @@ func (e ErrMultipleTokens) Error() string {
 	return fmt.Sprintf("multiple tokens found, but only one is allowed: %s\n\nLearn more at https://goreleaser.com/errors/multiple-tokens\n", strings.Join(e.tokens, ", "))
 }
 
+// tokenEnvs are the environment variables tokens are loaded from.
+var tokenEnvs = []string{"GITHUB_TOKEN", "GITLAB_TOKEN", "GITEA_TOKEN"}
+
+func isTokenEnv(key string) bool {
+	for _, env := range tokenEnvs {
+		if env == key {
+			return true
+		}
+	}
+	return false
+}
+
 // Pipe for env.
 type Pipe struct{}
 
@@ -37,7 +54,10 @@ func (Pipe) String() string {
 }
 
 func setDefaultTokenFiles(ctx *context.Context) {
-	env := &ctx.Config.EnvFiles
+	ctx.Config.EnvFiles = withDefaultTokenFiles(ctx.Config.EnvFiles)
+}
+
+func withDefaultTokenFiles(env config.EnvFiles) config.EnvFiles {
 	if env.GitHubToken == "" {
 		env.GitHubToken = "~/.config/goreleaser/github_token"
 	}
@@ -47,6 +67,7 @@ func setDefaultTokenFiles(ctx *context.Context) {
 	if env.GiteaToken == "" {
 		env.GiteaToken = "~/.config/goreleaser/gitea_token"
 	}
+	return env
 }
 
 // Run the pipe.
@@ -60,14 +81,66 @@ func (Pipe) Run(ctx *context.Context) error {
 		}
 		tEnv = append(tEnv, env)
 	}
-	for k, v := range context.ToEnv(tEnv) {
-		ctx.Env[k] = v
+	for _, env := range tEnv {
+		// KEY?=VALUE entries unset KEY if VALUE is empty.
+		if k, v, ok := strings.Cut(env, "?="); ok && !strings.Contains(k, "=") {
+			if v == "" {
+				log.Debugf("unsetting %s as its value is empty", k)
+				delete(ctx.Env, k)
+				continue
+			}
+			env = k + "=" + v
+		}
+		for k, v := range context.ToEnv([]string{env}) {
+			if isTokenEnv(k) {
+				log.Warnf("%s is set in the env section, it will shadow the token loaded from the environment or env_files", k)
+			}
+			ctx.Env[k] = v
+		}
 	}
 
 	setDefaultTokenFiles(ctx)
-	githubToken, githubTokenErr := loadEnv("GITHUB_TOKEN", ctx.Config.EnvFiles.GitHubToken)
-	gitlabToken, gitlabTokenErr := loadEnv("GITLAB_TOKEN", ctx.Config.EnvFiles.GitLabToken)
-	giteaToken, giteaTokenErr := loadEnv("GITEA_TOKEN", ctx.Config.EnvFiles.GiteaToken)
+	tokenType, token, err := resolveToken(ctx)
+	if err != nil {
+		return err
+	}
+	ctx.TokenType = tokenType
+	ctx.Token = token
+	return nil
+}
+
+// ResolveTokenType resolves which SCM provider should be used based on the
+// available tokens and the force_token setting.
+// It does not change the context, but it does run the configured token
+// commands, if any, to find out which tokens are available.
+func ResolveTokenType(ctx *context.Context) (context.TokenType, error) {
+	tokenType, _, err := resolveToken(ctx)
+	return tokenType, err
+}
+
+func resolveToken(ctx *context.Context) (context.TokenType, string, error) {
+	files := withDefaultTokenFiles(ctx.Config.EnvFiles)
+	githubToken, githubTokenErr := loadToken(ctx, "GITHUB_TOKEN", files.GitHubToken, files.GitHubTokenCmd)
+	gitlabToken, gitlabTokenErr := loadToken(ctx, "GITLAB_TOKEN", files.GitLabToken, files.GitLabTokenCmd)
+	giteaToken, giteaTokenErr := loadToken(ctx, "GITEA_TOKEN", files.GiteaToken, files.GiteaTokenCmd)
+
+	switch context.TokenType(ctx.Config.ForceToken) {
+	case "":
+	case context.TokenTypeGitHub:
+		log.Debug("forcing github token")
+		gitlabToken, gitlabTokenErr = "", nil
+		giteaToken, giteaTokenErr = "", nil
+	case context.TokenTypeGitLab:
+		log.Debug("forcing gitlab token")
+		githubToken, githubTokenErr = "", nil
+		giteaToken, giteaTokenErr = "", nil
+	case context.TokenTypeGitea:
+		log.Debug("forcing gitea token")
+		githubToken, githubTokenErr = "", nil
+		gitlabToken, gitlabTokenErr = "", nil
+	default:
+		return "", "", fmt.Errorf("invalid force_token %q, must be one of: github, gitlab, gitea", ctx.Config.ForceToken)
+	}
 
 	var tokens []string
 	if githubToken != "" {
@@ -80,38 +153,30 @@ func (Pipe) Run(ctx *context.Context) error {
 		tokens = append(tokens, "GITEA_TOKEN")
 	}
 	if len(tokens) > 1 {
-		return ErrMultipleTokens{tokens}
+		return "", "", ErrMultipleTokens{tokens}
 	}
 
 	noTokens := githubToken == "" && gitlabToken == "" && giteaToken == ""
 	noTokenErrs := githubTokenErr == nil && gitlabTokenErr == nil && giteaTokenErr == nil
 
 	if err := checkErrors(ctx, noTokens, noTokenErrs, gitlabTokenErr, githubTokenErr, giteaTokenErr); err != nil {
-		return err
+		return "", "", err
 	}
 
 	if gitlabToken != "" {
 		log.Debug("token type: gitlab")
-		ctx.TokenType = context.TokenTypeGitLab
-		ctx.Token = gitlabToken
+		return context.TokenTypeGitLab, gitlabToken, nil
 	}
 
 	if giteaToken != "" {
 		log.Debug("token type: gitea")
-		ctx.TokenType = context.TokenTypeGitea
-		ctx.Token = giteaToken
+		return context.TokenTypeGitea, giteaToken, nil
 	}
 
 	if githubToken != "" {
 		log.Debug("token type: github")
-		ctx.Token = githubToken
 	}
-
-	if ctx.TokenType == "" {
-		ctx.TokenType = context.TokenTypeGitHub
-	}
-
-	return nil
+	return context.TokenTypeGitHub, githubToken, nil
 }
 
 func checkErrors(ctx *context.Context, noTokens, noTokenErrs bool, gitlabTokenErr, githubTokenErr, giteaTokenErr error) error {
@@ -140,13 +205,58 @@ func checkErrors(ctx *context.Context, noTokens, noTokenErrs bool, gitlabTokenEr
 	return nil
 }
 
+// loadToken loads the token from the given environment variable, falling back
+// to the output of the given command, if any, or else to the given file.
+func loadToken(ctx *context.Context, env, path, command string) (string, error) {
+	if command == "" || strings.TrimSpace(os.Getenv(env)) != "" {
+		return loadEnv(env, path)
+	}
+	return loadEnvFromCmd(ctx, command)
+}
+
+func loadEnvFromCmd(ctx *context.Context, command string) (string, error) {
+	args, err := shellwords.Parse(command)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %q: %w", command, err)
+	}
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	// the command is bound by the release --timeout, unless a shorter timeout
+	// is set.
+	var cctx stdctx.Context = ctx
+	timeout := ctx.Config.EnvFiles.TokenCmdTimeout
+	if timeout > 0 {
+		var cancel stdctx.CancelFunc
+		cctx, cancel = stdctx.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(cctx, args[0], args[1:]...) // #nosec
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	log.Infof("using token from command %q", command)
+	if err := cmd.Run(); err != nil {
+		if timeout > 0 && errors.Is(cctx.Err(), stdctx.DeadlineExceeded) && ctx.Err() == nil {
+			return "", fmt.Errorf("failed to run %q: timed out after %s", command, timeout)
+		}
+		if cctx.Err() != nil {
+			return "", fmt.Errorf("failed to run %q: %w", command, cctx.Err())
+		}
+		return "", fmt.Errorf("failed to run %q: %w: %s", command, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
 func loadEnv(env, path string) (string, error) {
-	val := os.Getenv(env)
+	val := strings.TrimSpace(os.Getenv(env))
 	if val != "" {
 		log.Infof("using token from %q", "$"+env)
 		return val, nil
 	}
-	path, err := homedir.Expand(path)
+	path, err := homedir.Expand(os.ExpandEnv(path))
 	if err != nil {
 		return "", err
 	}
@@ -159,6 +269,6 @@ func loadEnv(env, path string) (string, error) {
 	}
 	defer f.Close()
 	log.Infof("using token from %q", path)
-	bts, _, err := bufio.NewReader(f).ReadLine()
-	return string(bts), err
+	bts, err := io.ReadAll(f)
+	return strings.TrimSpace(string(bts)), err
 }
@@ -1,10 +1,14 @@
 package env
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -51,6 +55,26 @@ func TestSetDefaultTokenFiles(t *testing.T) {
 		require.Equal(t, "", ctx.Env["EMPTY_VAL"])
 	})
 
+	t.Run("unset if empty", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Env: []string{
+				"MAYBE_SET?={{ .Env.MAYBE_EMPTY }}",
+				"MAYBE_NOT_SET?={{ .Env.MAYBE_FULL }}",
+				"EMPTY_VAL=",
+			},
+		})
+		ctx.Env["MAYBE_SET"] = "old value"
+		t.Setenv("MAYBE_EMPTY", "")
+		t.Setenv("MAYBE_FULL", "full")
+		t.Setenv("GITHUB_TOKEN", "fake")
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.NotContains(t, ctx.Env, "MAYBE_SET")
+		require.NotContains(t, ctx.Env, "MAYBE_SET?")
+		require.Equal(t, "full", ctx.Env["MAYBE_NOT_SET"])
+		require.Contains(t, ctx.Env, "EMPTY_VAL")
+		require.Equal(t, "", ctx.Env["EMPTY_VAL"])
+	})
+
 	t.Run("template error", func(t *testing.T) {
 		ctx := context.New(config.Project{
 			Env: []string{
@@ -67,6 +91,29 @@ func TestSetDefaultTokenFiles(t *testing.T) {
 	})
 }
 
+func TestEnvShadowsToken(t *testing.T) {
+	var w bytes.Buffer
+	log.Log = log.New(&w)
+	t.Cleanup(func() {
+		log.Log = log.New(os.Stderr)
+	})
+
+	t.Setenv("GITHUB_TOKEN", "asdf")
+	ctx := context.New(config.Project{
+		Env: []string{
+			"GITHUB_TOKEN=qwer",
+			"FOO=bar",
+		},
+		EnvFiles: config.EnvFiles{
+			GitLabToken: "~/nope",
+			GiteaToken:  "~/nope",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Contains(t, w.String(), "GITHUB_TOKEN is set in the env section, it will shadow the token loaded from the environment or env_files")
+	require.NotContains(t, w.String(), "FOO is set in the env section")
+}
+
 func TestValidGithubEnv(t *testing.T) {
 	require.NoError(t, os.Setenv("GITHUB_TOKEN", "asdf"))
 	ctx := &context.Context{
@@ -128,6 +175,34 @@ func TestMultipleEnvTokens(t *testing.T) {
 	require.NoError(t, os.Unsetenv("GITEA_TOKEN"))
 }
 
+func TestMultipleEnvTokensForced(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "asdf")
+	t.Setenv("GITLAB_TOKEN", "qwer")
+	t.Setenv("GITEA_TOKEN", "zxcv")
+
+	for tokenType, token := range map[context.TokenType]string{
+		context.TokenTypeGitHub: "asdf",
+		context.TokenTypeGitLab: "qwer",
+		context.TokenTypeGitea:  "zxcv",
+	} {
+		t.Run(string(tokenType), func(t *testing.T) {
+			ctx := context.New(config.Project{
+				ForceToken: string(tokenType),
+			})
+			require.NoError(t, Pipe{}.Run(ctx))
+			require.Equal(t, token, ctx.Token)
+			require.Equal(t, tokenType, ctx.TokenType)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			ForceToken: "bitbucket",
+		})
+		require.EqualError(t, Pipe{}.Run(ctx), `invalid force_token "bitbucket", must be one of: github, gitlab, gitea`)
+	})
+}
+
 func TestEmptyGithubFileEnv(t *testing.T) {
 	require.NoError(t, os.Unsetenv("GITHUB_TOKEN"))
 	ctx := &context.Context{
@@ -286,6 +361,60 @@ func TestLoadEnv(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "123", v)
 	})
+	t.Run("env with surrounding whitespace", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV"
+		t.Setenv(env, " 1\t\n")
+		v, err := loadEnv(env, "nope")
+		require.NoError(t, err)
+		require.Equal(t, "1", v)
+	})
+	t.Run("env file with crlf line ending", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		f, err := os.CreateTemp(t.TempDir(), "token")
+		require.NoError(t, err)
+		fmt.Fprintf(f, "123\r\n")
+		require.NoError(t, f.Close())
+		v, err := loadEnv(env, f.Name())
+		require.NoError(t, err)
+		require.Equal(t, "123", v)
+	})
+	t.Run("env file with leading whitespace", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		f, err := os.CreateTemp(t.TempDir(), "token")
+		require.NoError(t, err)
+		fmt.Fprintf(f, " \t123\r\n")
+		require.NoError(t, f.Close())
+		v, err := loadEnv(env, f.Name())
+		require.NoError(t, err)
+		require.Equal(t, "123", v)
+	})
+	t.Run("env file path with env vars", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("123"), 0o600))
+		t.Setenv("TOKEN_DIR", dir)
+		for _, path := range []string{
+			"$TOKEN_DIR/token",
+			"${TOKEN_DIR}/token",
+		} {
+			v, err := loadEnv(env, path)
+			require.NoError(t, err)
+			require.Equal(t, "123", v)
+		}
+	})
+	t.Run("env file path with unset env var", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		require.NoError(t, os.Unsetenv("TOKEN_DIR_NOPE"))
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "token"), []byte("123"), 0o600))
+		v, err := loadEnv(env, dir+"/${TOKEN_DIR_NOPE}token")
+		require.NoError(t, err)
+		require.Equal(t, "123", v)
+	})
 	t.Run("env file is not readable", func(t *testing.T) {
 		env := "SUPER_SECRET_ENV_NOPE"
 		require.NoError(t, os.Unsetenv(env))
@@ -300,3 +429,94 @@ func TestLoadEnv(t *testing.T) {
 		require.Equal(t, "", v)
 	})
 }
+
+func TestLoadToken(t *testing.T) {
+	t.Run("env takes precedence over command", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV"
+		t.Setenv(env, "1")
+		v, err := loadToken(context.New(config.Project{}), env, "nope", "echo 2")
+		require.NoError(t, err)
+		require.Equal(t, "1", v)
+	})
+	t.Run("command", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		v, err := loadToken(context.New(config.Project{}), env, "nope", `echo "  123  "`)
+		require.NoError(t, err)
+		require.Equal(t, "123", v)
+	})
+	t.Run("command fails", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		v, err := loadToken(context.New(config.Project{}), env, "nope", `sh -c "echo not logged in >&2; exit 1"`)
+		require.EqualError(t, err, `failed to run "sh -c \"echo not logged in >&2; exit 1\"": exit status 1: not logged in`)
+		require.Equal(t, "", v)
+	})
+	t.Run("no command", func(t *testing.T) {
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		v, err := loadToken(context.New(config.Project{}), env, "nope", "")
+		require.NoError(t, err)
+		require.Equal(t, "", v)
+	})
+	t.Run("command timeout", func(t *testing.T) {
+		testlib.CheckPath(t, "sleep")
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		ctx := context.New(config.Project{
+			EnvFiles: config.EnvFiles{
+				TokenCmdTimeout: 10 * time.Millisecond,
+			},
+		})
+		v, err := loadToken(ctx, env, "nope", "sleep 5")
+		require.EqualError(t, err, `failed to run "sleep 5": timed out after 10ms`)
+		require.Equal(t, "", v)
+	})
+	t.Run("release timeout", func(t *testing.T) {
+		testlib.CheckPath(t, "sleep")
+		env := "SUPER_SECRET_ENV_NOPE"
+		require.NoError(t, os.Unsetenv(env))
+		ctx, cancel := context.NewWithTimeout(config.Project{}, 10*time.Millisecond)
+		defer cancel()
+		v, err := loadToken(ctx, env, "nope", "sleep 5")
+		require.EqualError(t, err, `failed to run "sleep 5": context deadline exceeded`)
+		require.Equal(t, "", v)
+	})
+}
+
+func TestValidGithubEnvCmd(t *testing.T) {
+	require.NoError(t, os.Unsetenv("GITHUB_TOKEN"))
+	require.NoError(t, os.Unsetenv("GITLAB_TOKEN"))
+	require.NoError(t, os.Unsetenv("GITEA_TOKEN"))
+	ctx := context.New(config.Project{
+		EnvFiles: config.EnvFiles{
+			GitHubTokenCmd: "echo asdf",
+			GitLabToken:    "~/nope",
+			GiteaToken:     "~/nope",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Equal(t, "asdf", ctx.Token)
+	require.Equal(t, context.TokenTypeGitHub, ctx.TokenType)
+}
+
+func TestResolveTokenType(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITLAB_TOKEN", "qwer")
+	t.Setenv("GITEA_TOKEN", "")
+	ctx := context.New(config.Project{
+		EnvFiles: config.EnvFiles{
+			GitHubToken: "~/nope",
+			GiteaToken:  "~/nope",
+		},
+	})
+	tokenType, err := ResolveTokenType(ctx)
+	require.NoError(t, err)
+	require.Equal(t, context.TokenTypeGitLab, tokenType)
+	require.Empty(t, ctx.Token)
+	require.Empty(t, ctx.TokenType)
+	require.Equal(t, config.EnvFiles{
+		GitHubToken: "~/nope",
+		GiteaToken:  "~/nope",
+	}, ctx.Config.EnvFiles)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/git"
 	"github.com/goreleaser/goreleaser/internal/pipe"
@@ -36,6 +37,9 @@ func (Pipe) Run(ctx *context.Context) error {
 		return ErrNoGit
 	}
 	setDefaults(ctx)
+	if err := unshallow(ctx); err != nil {
+		return err
+	}
 	info, err := getInfo(ctx)
 	if err != nil {
 		return err
@@ -56,6 +60,19 @@ var fakeInfo = context.GitInfo{
 	Summary:     "none",
 }
 
+// unshallow fetches the whole history of shallow clones if git.unshallow is
+// set, so the previous tag and the changelog can be found.
+func unshallow(ctx *context.Context) error {
+	if !ctx.Config.Git.Unshallow || !git.IsRepo(ctx) || !git.IsShallow(ctx) {
+		return nil
+	}
+	log.Info("shallow clone detected, fetching the whole history")
+	if _, err := git.Clean(git.Run(ctx, "fetch", "--unshallow", "--tags")); err != nil {
+		return fmt.Errorf("failed to unshallow repository: %w", err)
+	}
+	return nil
+}
+
 func getInfo(ctx *context.Context) (context.GitInfo, error) {
 	if !git.IsRepo(ctx) && ctx.Snapshot {
 		log.Warn("accepting to run without a git repository because this is a snapshot")
@@ -174,8 +191,8 @@ func validate(ctx *context.Context) error {
 	if ctx.SkipValidate {
 		return pipe.ErrSkipValidateEnabled
 	}
-	if _, err := os.Stat(".git/shallow"); err == nil {
-		log.Warn("running against a shallow clone - check your CI documentation at https://goreleaser.com/ci")
+	if git.IsShallow(ctx) {
+		log.Warn("running against a shallow clone - set git.unshallow or check your CI documentation at https://goreleaser.com/ci")
 	}
 	if err := CheckDirty(ctx); err != nil {
 		return err
@@ -200,7 +217,18 @@ func CheckDirty(ctx *context.Context) error {
 }
 
 func getBranch(ctx *context.Context) (string, error) {
-	return git.Clean(git.Run(ctx, "rev-parse", "--abbrev-ref", "HEAD", "--quiet"))
+	branch, err := git.Clean(git.Run(ctx, "rev-parse", "--abbrev-ref", "HEAD", "--quiet"))
+	if err != nil || branch != "HEAD" {
+		return branch, err
+	}
+	// CI systems usually checkout a detached HEAD, but let us know the
+	// branch being built through the environment.
+	for _, env := range []string{"GITHUB_HEAD_REF", "GITHUB_REF_NAME", "CI_COMMIT_REF_NAME"} {
+		if name := os.Getenv(env); name != "" {
+			return name, nil
+		}
+	}
+	return branch, nil
 }
 
 func getCommitDate(ctx *context.Context) (time.Time, error) {
@@ -268,11 +296,21 @@ func getPreviousTag(ctx *context.Context, current string) (string, error) {
 	for _, fn := range []func() ([]string, error){
 		getFromEnv("GORELEASER_PREVIOUS_TAG"),
 		func() ([]string, error) {
-			sha, err := previousTagSha(ctx, current)
-			if err != nil {
-				return nil, err
+			ref := "tags/" + current
+			for {
+				sha, err := previousTagSha(ctx, ref)
+				if err != nil {
+					return nil, err
+				}
+				tags, err := gitTagsPointingAt(ctx, sha)
+				if err != nil || !ignorePrereleases(ctx, current) {
+					return tags, err
+				}
+				if tags = withoutPrereleases(tags); len(tags) > 0 {
+					return tags, nil
+				}
+				ref = sha
 			}
-			return gitTagsPointingAt(ctx, sha)
 		},
 	} {
 		tags, err := fn()
@@ -287,15 +325,41 @@ func getPreviousTag(ctx *context.Context, current string) (string, error) {
 	return "", nil
 }
 
+// ignorePrereleases returns whether prerelease tags should be skipped when
+// looking for the tag previous to the given one.
+func ignorePrereleases(ctx *context.Context, current string) bool {
+	return ctx.Config.Git.IgnorePrereleases && !isPrerelease(current)
+}
+
+func isPrerelease(tag string) bool {
+	sv, err := semver.NewVersion(tag)
+	return err == nil && sv.Prerelease() != ""
+}
+
+func withoutPrereleases(tags []string) []string {
+	var result []string
+	for _, tag := range tags {
+		if !isPrerelease(tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
 func gitTagsPointingAt(ctx *context.Context, ref string) ([]string, error) {
-	return git.CleanAllLines(git.Run(
-		ctx,
+	var args []string
+	if ctx.Config.Git.PrereleaseSuffix != "" {
+		args = append(args, "-c", "versionsort.suffix="+ctx.Config.Git.PrereleaseSuffix)
+	}
+	args = append(
+		args,
 		"tag",
 		"--points-at",
 		ref,
 		"--sort",
 		ctx.Config.Git.TagSort,
-	))
+	)
+	return git.CleanAllLines(git.Run(ctx, args...))
 }
 
 func gitDescribe(ctx *context.Context, ref string) (string, error) {
@@ -308,8 +372,8 @@ func gitDescribe(ctx *context.Context, ref string) (string, error) {
 	))
 }
 
-func previousTagSha(ctx *context.Context, current string) (string, error) {
-	tag, err := gitDescribe(ctx, fmt.Sprintf("tags/%s^", current))
+func previousTagSha(ctx *context.Context, ref string) (string, error) {
+	tag, err := gitDescribe(ctx, ref+"^")
 	if err != nil {
 		return "", err
 	}
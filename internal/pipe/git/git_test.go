@@ -65,6 +65,37 @@ func TestBranch(t *testing.T) {
 	require.Equal(t, "test-branch-tag", ctx.Git.Summary)
 }
 
+func TestBranchDetachedHead(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@github.com:foo/bar.git")
+	testlib.GitCommit(t, "test-branch-commit")
+	testlib.GitTag(t, "test-branch-tag")
+	_, err := exec.Command("git", "checkout", "--detach").CombinedOutput()
+	require.NoError(t, err)
+
+	t.Run("no env", func(t *testing.T) {
+		t.Setenv("GITHUB_HEAD_REF", "")
+		t.Setenv("GITHUB_REF_NAME", "")
+		t.Setenv("CI_COMMIT_REF_NAME", "")
+		ctx := context.New(config.Project{})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, "HEAD", ctx.Git.Branch)
+	})
+
+	for _, env := range []string{"GITHUB_HEAD_REF", "GITHUB_REF_NAME", "CI_COMMIT_REF_NAME"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv("GITHUB_HEAD_REF", "")
+			t.Setenv("GITHUB_REF_NAME", "")
+			t.Setenv("CI_COMMIT_REF_NAME", "")
+			t.Setenv(env, "feature/x")
+			ctx := context.New(config.Project{})
+			require.NoError(t, Pipe{}.Run(ctx))
+			require.Equal(t, "feature/x", ctx.Git.Branch)
+		})
+	}
+}
+
 func TestNoRemote(t *testing.T) {
 	testlib.Mktmp(t)
 	testlib.GitInit(t)
@@ -145,6 +176,45 @@ func TestRemoteURLContainsWithUsernameAndTokenWithInvalidURL(t *testing.T) {
 	require.Error(t, Pipe{}.Run(ctx))
 }
 
+func TestUnshallow(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "commit1")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "commit2")
+	testlib.GitTag(t, "v0.0.2")
+
+	t.Run("not set", func(t *testing.T) {
+		testlib.GitShallowClone(t, folder)
+		ctx := context.New(config.Project{})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, "v0.0.2", ctx.Git.CurrentTag)
+		require.Empty(t, ctx.Git.PreviousTag)
+		require.Equal(t, ctx.Git.FullCommit, ctx.Git.FirstCommit)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		testlib.GitShallowClone(t, folder)
+		ctx := context.New(config.Project{
+			Git: config.Git{Unshallow: true},
+		})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, "v0.0.2", ctx.Git.CurrentTag)
+		require.Equal(t, "v0.0.1", ctx.Git.PreviousTag)
+		require.NotEqual(t, ctx.Git.FullCommit, ctx.Git.FirstCommit)
+	})
+
+	t.Run("no remote", func(t *testing.T) {
+		testlib.GitShallowClone(t, folder)
+		_, err := exec.Command("git", "remote", "remove", "origin").CombinedOutput()
+		require.NoError(t, err)
+		ctx := context.New(config.Project{
+			Git: config.Git{Unshallow: true},
+		})
+		require.ErrorContains(t, Pipe{}.Run(ctx), "failed to unshallow repository")
+	})
+}
+
 func TestShallowClone(t *testing.T) {
 	folder := testlib.Mktmp(t)
 	require.NoError(
@@ -191,6 +261,85 @@ func TestTagSortOrder(t *testing.T) {
 	require.Equal(t, "v0.0.1", ctx.Git.CurrentTag)
 }
 
+func TestPrereleaseSuffix(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@github.com:foo/bar.git")
+	testlib.GitCommit(t, "commit1")
+	testlib.GitTag(t, "v1.1.0")
+	testlib.GitCommit(t, "commit2")
+	testlib.GitTag(t, "v1.2.0-rc1")
+	testlib.GitTag(t, "v1.2.0")
+
+	t.Run("not set", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, "v1.2.0-rc1", ctx.Git.CurrentTag)
+	})
+
+	t.Run("set", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Git: config.Git{
+				PrereleaseSuffix: "-",
+			},
+		})
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.Equal(t, "v1.2.0", ctx.Git.CurrentTag)
+		require.Equal(t, "v1.1.0", ctx.Git.PreviousTag)
+	})
+}
+
+func TestIgnorePrereleases(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitRemoteAdd(t, "git@github.com:foo/bar.git")
+	testlib.GitCommit(t, "commit1")
+	testlib.GitTag(t, "v1.1.0")
+	testlib.GitCommit(t, "commit2")
+	testlib.GitTag(t, "v1.2.0-rc1")
+	testlib.GitCommit(t, "commit3")
+	testlib.GitTag(t, "v1.2.0-rc2")
+	testlib.GitCommit(t, "commit4")
+
+	for name, tt := range map[string]struct {
+		current  string
+		ignore   bool
+		expected string
+	}{
+		"stable": {
+			current:  "v1.2.0",
+			expected: "v1.2.0-rc2",
+		},
+		"stable ignoring prereleases": {
+			current:  "v1.2.0",
+			ignore:   true,
+			expected: "v1.1.0",
+		},
+		"prerelease ignoring prereleases": {
+			current:  "v1.2.0-rc3",
+			ignore:   true,
+			expected: "v1.2.0-rc2",
+		},
+	} {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			testlib.GitTag(t, tt.current)
+			t.Cleanup(func() {
+				_, err := exec.Command("git", "tag", "-d", tt.current).CombinedOutput()
+				require.NoError(t, err)
+			})
+			ctx := context.New(config.Project{
+				Git: config.Git{
+					IgnorePrereleases: tt.ignore,
+				},
+			})
+			require.NoError(t, Pipe{}.Run(ctx))
+			require.Equal(t, tt.current, ctx.Git.CurrentTag)
+			require.Equal(t, tt.expected, ctx.Git.PreviousTag)
+		})
+	}
+}
+
 func TestTagIsNotLastCommit(t *testing.T) {
 	testlib.Mktmp(t)
 	testlib.GitInit(t)
@@ -20,6 +20,7 @@ import (
 	"github.com/google/ko/pkg/build"
 	"github.com/google/ko/pkg/commands/options"
 	"github.com/google/ko/pkg/publish"
+	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/ids"
 	"github.com/goreleaser/goreleaser/internal/semerrgroup"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
@@ -144,6 +145,7 @@ type buildOptions struct {
 	bare                bool
 	preserveImportPaths bool
 	baseImportPaths     bool
+	labels              map[string]string
 }
 
 func (o *buildOptions) makeBuilder(ctx *context.Context) (*build.Caching, error) {
@@ -157,6 +159,11 @@ func (o *buildOptions) makeBuilder(ctx *context.Context) (*build.Caching, error)
 			},
 		}),
 		build.WithPlatforms(o.platforms...),
+	}
+	for k, v := range o.labels {
+		buildOptions = append(buildOptions, build.WithLabel(k, v))
+	}
+	buildOptions = append(buildOptions,
 		build.WithBaseImages(func(ctx stdctx.Context, s string) (name.Reference, build.Result, error) {
 			ref, err := name.ParseReference(o.baseImage)
 			if err != nil {
@@ -186,7 +193,7 @@ func (o *buildOptions) makeBuilder(ctx *context.Context) (*build.Caching, error)
 			}
 			return nil, nil, fmt.Errorf("unexpected base image media type: %s", desc.MediaType)
 		}),
-	}
+	)
 	switch o.sbom {
 	case "spdx":
 		buildOptions = append(buildOptions, build.WithSPDX("devel"))
@@ -236,12 +243,27 @@ func doBuild(ctx *context.Context, ko config.Ko) func() error {
 			return fmt.Errorf("newDefault: %w", err)
 		}
 		defer func() { _ = p.Close() }()
-		if _, err = p.Publish(ctx, r, opts.importPath); err != nil {
+		ref, err := p.Publish(ctx, r, opts.importPath)
+		if err != nil {
 			return fmt.Errorf("publish: %w", err)
 		}
 		if err := p.Close(); err != nil {
 			return fmt.Errorf("close: %w", err)
 		}
+
+		// register one image per tag, so the manifest and sign pipes can
+		// pick them up.
+		for _, tag := range opts.tags {
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Type: artifact.DockerImage,
+				Name: ref.Context().Tag(tag).String(),
+				Path: ref.Context().Tag(tag).String(),
+				Extra: map[string]interface{}{
+					artifact.ExtraID:     ko.ID,
+					artifact.ExtraDigest: ref.Identifier(),
+				},
+			})
+		}
 		return nil
 	}
 }
@@ -299,6 +321,17 @@ func buildBuildOptions(ctx *context.Context, cfg config.Ko) (*buildOptions, erro
 	}
 	opts.tags = tags
 
+	if len(cfg.Labels) > 0 {
+		opts.labels = make(map[string]string, len(cfg.Labels))
+		for k, v := range cfg.Labels {
+			tv, err := tmpl.New(ctx).Apply(v)
+			if err != nil {
+				return nil, err
+			}
+			opts.labels[k] = tv
+		}
+	}
+
 	if len(cfg.Env) > 0 {
 		env, err := applyTemplate(ctx, cfg.Env)
 		if err != nil {
@@ -2,10 +2,12 @@ package ko
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	_ "github.com/distribution/distribution/v3/registry/auth/htpasswd"
 	_ "github.com/distribution/distribution/v3/registry/storage/driver/inmemory"
+	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -148,6 +150,10 @@ func TestPublishPipeSuccess(t *testing.T) {
 			Name:      "multiple-platforms",
 			Platforms: []string{"linux/amd64", "linux/arm64"},
 		},
+		{
+			Name:      "all-platforms",
+			Platforms: []string{"all"},
+		},
 	}
 
 	for _, table := range table {
@@ -171,14 +177,29 @@ func TestPublishPipeSuccess(t *testing.T) {
 						BaseImage:  table.BaseImage,
 						Repository: fmt.Sprintf("%s/goreleasertest", registry),
 						Platforms:  table.Platforms,
-						Tags:       []string{table.Name},
+						Tags:       []string{table.Name, "{{.Version}}"},
 						SBOM:       table.SBOM,
+						Labels: map[string]string{
+							"org.opencontainers.image.version": "{{.Version}}",
+						},
 					},
 				},
 			})
+			ctx.Version = "1.2.3"
 
 			require.NoError(t, Pipe{}.Default(ctx))
 			require.NoError(t, Pipe{}.Publish(ctx))
+
+			images := ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List()
+			require.Len(t, images, 2)
+			var tags []string
+			for _, img := range images {
+				require.Contains(t, img.Name, "goreleasertest:")
+				tags = append(tags, img.Name[strings.LastIndex(img.Name, ":")+1:])
+				require.Equal(t, "default", artifact.ExtraOr(*img, artifact.ExtraID, ""))
+				require.True(t, strings.HasPrefix(artifact.ExtraOr(*img, artifact.ExtraDigest, ""), "sha256:"))
+			}
+			require.ElementsMatch(t, []string{table.Name, "1.2.3"}, tags)
 		})
 	}
 }
@@ -234,6 +255,13 @@ func TestPublishPipeError(t *testing.T) {
 		testlib.RequireTemplateError(t, Pipe{}.Publish(ctx))
 	})
 
+	t.Run("invalid labels tmpl", func(t *testing.T) {
+		ctx := makeCtx()
+		ctx.Config.Kos[0].Labels = map[string]string{"foo": "{{.Nope}}"}
+		require.NoError(t, Pipe{}.Default(ctx))
+		testlib.RequireTemplateError(t, Pipe{}.Publish(ctx))
+	})
+
 	t.Run("invalid env tmpl", func(t *testing.T) {
 		ctx := makeCtx()
 		ctx.Config.Builds[0].Env = []string{"{{.Nope}}"}
@@ -277,3 +305,36 @@ func TestApplyTemplate(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestBuildBuildOptions(t *testing.T) {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Env:         []string{"FOO=bar"},
+	})
+	ctx.Git.CurrentTag = "v1.2.3"
+	ctx.Version = "1.2.3"
+	ctx.Semver = context.Semver{Major: 1, Minor: 2, Patch: 3}
+
+	opts, err := buildBuildOptions(ctx, config.Ko{
+		ID:         "default",
+		WorkingDir: "./testdata/app/",
+		Main:       ".",
+		BaseImage:  "alpine",
+		Repository: "ghcr.io/foo/bar",
+		Platforms:  []string{"linux/amd64", "linux/arm64", "linux/arm/v7"},
+		Tags:       []string{"latest", "{{.Tag}}", "v{{.Major}}.{{.Minor}}", "{{.Env.FOO}}"},
+		Labels: map[string]string{
+			"org.opencontainers.image.title":   "{{.ProjectName}}",
+			"org.opencontainers.image.version": "{{.Version}}",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"latest", "v1.2.3", "v1.2", "bar"}, opts.tags)
+	require.Equal(t, []string{"linux/amd64", "linux/arm64", "linux/arm/v7"}, opts.platforms)
+	require.Equal(t, map[string]string{
+		"org.opencontainers.image.title":   "foo",
+		"org.opencontainers.image.version": "1.2.3",
+	}, opts.labels)
+	require.Equal(t, "ghcr.io/foo/bar", opts.imageRepo)
+	require.Equal(t, "alpine", opts.baseImage)
+}
@@ -29,6 +29,9 @@ const (
 	manifestsFolder = "plugins"
 	kind            = "Plugin"
 	apiVersion      = "krew.googlecontainertools.github.com/v1alpha2"
+
+	// krew-index asks for short descriptions to have at most 50 characters.
+	maxShortDescriptionLength = 50
 )
 
 var ErrNoArchivesFound = errors.New("no archives found")
@@ -44,6 +47,9 @@ func (Pipe) Default(ctx *context.Context) error {
 		krew := &ctx.Config.Krews[i]
 
 		krew.CommitAuthor = commitauthor.Default(krew.CommitAuthor)
+		if err := client.CheckSigning(krew.CommitAuthor, krew.Index); err != nil {
+			return err
+		}
 		if krew.CommitMessageTemplate == "" {
 			krew.CommitMessageTemplate = "Krew manifest update for {{ .ProjectName }} version {{ .Tag }}"
 		}
@@ -53,6 +59,12 @@ func (Pipe) Default(ctx *context.Context) error {
 		if krew.Goamd64 == "" {
 			krew.Goamd64 = "v1"
 		}
+		if err := client.DefaultPullRequestBase(&krew.PullRequest, "kubernetes-sigs", "krew-index"); err != nil {
+			return fmt.Errorf("krew: %w", err)
+		}
+		if err := checkShortDescription(ctx, *krew); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -328,7 +340,24 @@ func doPublish(ctx *context.Context, manifest *artifact.Artifact, cl client.Clie
 		return err
 	}
 
-	return cl.CreateFile(ctx, author, repo, content, gpath, msg)
+	if err := client.NewIfGitURL(cl, cfg.Index).CreateFile(ctx, author, repo, content, gpath, msg); err != nil {
+		return err
+	}
+
+	return client.OpenPullRequest(ctx, cl, cfg.PullRequest, repo, msg)
+}
+
+// checkShortDescription ensures the templated short description isn't longer
+// than krew-index allows.
+func checkShortDescription(ctx *context.Context, krew config.Krew) error {
+	desc, err := tmpl.New(ctx).Apply(krew.ShortDescription)
+	if err != nil {
+		return err
+	}
+	if l := len([]rune(desc)); l > maxShortDescriptionLength {
+		return fmt.Errorf("krew: manifest short description must have at most %d characters, got %d", maxShortDescriptionLength, l)
+	}
+	return nil
 }
 
 func buildManifestPath(folder, filename string) string {
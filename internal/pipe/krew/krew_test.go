@@ -13,6 +13,7 @@ import (
 	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/golden"
 	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/internal/yaml"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
@@ -519,6 +520,140 @@ func TestRunPipeNameTemplate(t *testing.T) {
 	require.Equal(t, client.Content, string(distBts))
 }
 
+func TestRunPipeAllPlatforms(t *testing.T) {
+	folder := t.TempDir()
+	ctx := context.New(config.Project{
+		Dist:        folder,
+		ProjectName: "foo",
+		Krews: []config.Krew{
+			{
+				Description:      "Some desc",
+				ShortDescription: "Short desc",
+				Homepage:         "https://goreleaser.com",
+				Index: config.RepoRef{
+					Owner: "foo",
+					Name:  "bar",
+				},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.1"
+	ctx.Version = "1.0.1"
+	for _, platform := range []struct{ goos, goarch, bin string }{
+		{"linux", "amd64", "kubectl-foo"},
+		{"darwin", "arm64", "kubectl-foo"},
+		{"windows", "amd64", "kubectl-foo.exe"},
+	} {
+		name := fmt.Sprintf("foo_%s_%s.tar.gz", platform.goos, platform.goarch)
+		path := filepath.Join(folder, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0o644))
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name:    name,
+			Path:    path,
+			Goos:    platform.goos,
+			Goarch:  platform.goarch,
+			Goamd64: "v1",
+			Type:    artifact.UploadableArchive,
+			Extra: map[string]interface{}{
+				artifact.ExtraID:       "foo",
+				artifact.ExtraFormat:   "tar.gz",
+				artifact.ExtraBinaries: []string{platform.bin},
+			},
+		})
+	}
+
+	cli := client.NewMock()
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, runAll(ctx, cli))
+	require.NoError(t, publishAll(ctx, cli))
+	require.Equal(t, "plugins/foo.yaml", cli.Path)
+
+	var manifest Manifest
+	require.NoError(t, yaml.Unmarshal([]byte(cli.Content), &manifest))
+	require.Equal(t, "foo", manifest.Metadata.Name)
+	require.Equal(t, "v1.0.1", manifest.Spec.Version)
+	require.Equal(t, "Short desc", manifest.Spec.ShortDescription)
+	require.Equal(t, []Platform{
+		{
+			Bin:      "kubectl-foo.exe",
+			URI:      "https://dummyhost/download/v1.0.1/foo_windows_amd64.tar.gz",
+			Sha256:   "f420f4e97a5cff68fdd5cba7a1470aa8f48acb5c14347bff958474d43c087e07",
+			Selector: Selector{MatchLabels: MatchLabels{Os: "windows", Arch: "amd64"}},
+		},
+		{
+			Bin:      "kubectl-foo",
+			URI:      "https://dummyhost/download/v1.0.1/foo_linux_amd64.tar.gz",
+			Sha256:   "6b9f95ba20b1ddaf4412da36c627438118098c88de4681a23e0a93de0d345085",
+			Selector: Selector{MatchLabels: MatchLabels{Os: "linux", Arch: "amd64"}},
+		},
+		{
+			Bin:      "kubectl-foo",
+			URI:      "https://dummyhost/download/v1.0.1/foo_darwin_arm64.tar.gz",
+			Sha256:   "4b22bdf42714a2edcd7705a276d2416118c7a707ab404490ff6d0e3638d26a70",
+			Selector: Selector{MatchLabels: MatchLabels{Os: "darwin", Arch: "arm64"}},
+		},
+	}, manifest.Spec.Platforms)
+}
+
+func TestPublishPullRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("fake"), 0o644))
+	ctx := context.New(config.Project{ProjectName: "foo"})
+	ctx.Git.CurrentTag = "v1.2.3"
+	krew := config.Krew{
+		Index: config.RepoRef{
+			Owner: "goreleaser",
+			Name:  "krew-index",
+		},
+		CommitMessageTemplate: "Update {{ .ProjectName }} to {{ .Tag }}",
+		PullRequest: config.PullRequest{
+			Enabled: true,
+			Base: config.RepoRef{
+				Owner:  "kubernetes-sigs",
+				Name:   "krew-index",
+				Branch: "master",
+			},
+		},
+	}
+	newManifest := func(krew config.Krew) *artifact.Artifact {
+		return &artifact.Artifact{
+			Name: "foo.yaml",
+			Path: path,
+			Type: artifact.KrewPluginManifest,
+			Extra: map[string]interface{}{
+				krewConfigExtra: krew,
+			},
+		}
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		cli := client.NewMock()
+		require.NoError(t, doPublish(ctx, newManifest(krew), cli))
+		require.True(t, cli.CreatedFile)
+		require.True(t, cli.OpenedPullRequest)
+		require.Equal(t, client.Repo{Owner: "kubernetes-sigs", Name: "krew-index", Branch: "master"}, cli.PullRequestBase)
+		require.Equal(t, client.Repo{Owner: "goreleaser", Name: "krew-index"}, cli.PullRequestHead)
+		require.Equal(t, "Update foo to v1.2.3", cli.PullRequestTitle)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		krew := krew
+		krew.PullRequest.Enabled = false
+		cli := client.NewMock()
+		require.NoError(t, doPublish(ctx, newManifest(krew), cli))
+		require.True(t, cli.CreatedFile)
+		require.False(t, cli.OpenedPullRequest)
+	})
+
+	t.Run("invalid base template", func(t *testing.T) {
+		krew := krew
+		krew.PullRequest.Base.Owner = "{{ .Nope }"
+		cli := client.NewMock()
+		require.Error(t, doPublish(ctx, newManifest(krew), cli))
+		require.False(t, cli.OpenedPullRequest)
+	})
+}
+
 func TestRunPipeMultipleKrewWithSkip(t *testing.T) {
 	folder := t.TempDir()
 	ctx := &context.Context{
@@ -911,6 +1046,78 @@ func TestDefault(t *testing.T) {
 	require.NotEmpty(t, ctx.Config.Krews[0].CommitMessageTemplate)
 }
 
+func TestDefaultShortDescription(t *testing.T) {
+	t.Run("too long", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Krews: []config.Krew{
+				{ShortDescription: "A short description that is way too long for krew-index"},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `krew: manifest short description must have at most 50 characters, got 55`)
+	})
+
+	t.Run("too long once templated", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Env: []string{"DESC=A short description that is way too long for krew-index"},
+			Krews: []config.Krew{
+				{ShortDescription: "{{ .Env.DESC }}"},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `krew: manifest short description must have at most 50 characters, got 55`)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Krews: []config.Krew{
+				{ShortDescription: "{{ .Nope }"},
+			},
+		})
+		require.Error(t, Pipe{}.Default(ctx))
+	})
+}
+
+func TestDefaultPullRequestBase(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Krews: []config.Krew{
+				{PullRequest: config.PullRequest{Enabled: true}},
+			},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.Equal(t, config.RepoRef{
+			Owner: "kubernetes-sigs",
+			Name:  "krew-index",
+		}, ctx.Config.Krews[0].PullRequest.Base)
+	})
+
+	t.Run("partially set", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Krews: []config.Krew{
+				{
+					PullRequest: config.PullRequest{
+						Enabled: true,
+						Base:    config.RepoRef{Owner: "me"},
+					},
+				},
+			},
+		})
+		require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrNoPullRequestBase)
+	})
+}
+
+func TestDefaultSigningWithoutGit(t *testing.T) {
+	ctx := context.New(config.Project{
+		Krews: []config.Krew{
+			{
+				CommitAuthor: config.CommitAuthor{
+					Signing: config.CommitSigning{Enabled: true},
+				},
+			},
+		},
+	})
+	require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrSigningRequiresGit)
+}
+
 func TestGHFolder(t *testing.T) {
 	require.Equal(t, "bar.yaml", buildManifestPath("", "bar.yaml"))
 	require.Equal(t, "fooo/bar.yaml", buildManifestPath("fooo", "bar.yaml"))
@@ -958,3 +1165,36 @@ func requireValidManifest(t *testing.T) {
 		require.NoError(t, err, string(out))
 	})
 }
+
+func TestPublishCustomCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("fake"), 0o644))
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Env:         []string{"BOT_NAME=release-bot", "BOT_EMAIL=bot@example.com"},
+	})
+	ctx.Git.CurrentTag = "v1.2.3"
+	ctx.Version = "1.2.3"
+	art := &artifact.Artifact{
+		Name: "foo.yaml",
+		Path: path,
+		Type: artifact.KrewPluginManifest,
+		Extra: map[string]interface{}{
+			krewConfigExtra: config.Krew{
+				Index: config.RepoRef{Owner: "foo", Name: "bar"},
+				CommitAuthor: config.CommitAuthor{
+					Name:  "{{ .Env.BOT_NAME }}",
+					Email: "{{ .Env.BOT_EMAIL }}",
+				},
+				CommitMessageTemplate: "chore(krew): update {{ .ProjectName }} to {{ .Version }}\n\nSigned-off-by: {{ .Env.BOT_NAME }} <{{ .Env.BOT_EMAIL }}>",
+			},
+		},
+	}
+	cli := client.NewMock()
+	require.NoError(t, doPublish(ctx, art, cli))
+	require.Equal(t, config.CommitAuthor{
+		Name:  "release-bot",
+		Email: "bot@example.com",
+	}, cli.CommitAuthor)
+	require.Equal(t, "chore(krew): update foo to 1.2.3\n\nSigned-off-by: release-bot <bot@example.com>", cli.CommitMessage)
+}
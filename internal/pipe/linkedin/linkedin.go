@@ -34,6 +34,11 @@ func (Pipe) Announce(ctx *context.Context) error {
 		return fmt.Errorf("linkedin: %w", err)
 	}
 
+	if ctx.AnnounceDryRun {
+		log.WithField("message", message).Info("dry-run, not posting")
+		return nil
+	}
+
 	var cfg Config
 	if err := env.Parse(&cfg); err != nil {
 		return fmt.Errorf("linkedin: %w", err)
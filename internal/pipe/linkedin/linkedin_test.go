@@ -48,6 +48,21 @@ func TestAnnounceMissingEnv(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `linkedin: env: environment variable "LINKEDIN_ACCESS_TOKEN" should not be empty`)
 }
 
+func TestAnnounceDryRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			LinkedIn: config.LinkedIn{
+				Enabled:         true,
+				MessageTemplate: "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -2,6 +2,7 @@ package mastodon
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/caarlos0/log"
@@ -10,7 +11,12 @@ import (
 	"github.com/mattn/go-mastodon"
 )
 
-const defaultMessageTemplate = `{{ .ProjectName }} {{ .Tag }} is out! Check it out at {{ .ReleaseURL }}`
+const (
+	defaultMessageTemplate = `{{ .ProjectName }} {{ .Tag }} is out! Check it out at {{ .ReleaseURL }}`
+	defaultVisibility      = "public"
+)
+
+var validVisibilities = []string{"public", "unlisted", "private", "direct"}
 
 type Pipe struct{}
 
@@ -30,15 +36,44 @@ func (Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Announce.Mastodon.MessageTemplate == "" {
 		ctx.Config.Announce.Mastodon.MessageTemplate = defaultMessageTemplate
 	}
+	if ctx.Config.Announce.Mastodon.Visibility == "" {
+		ctx.Config.Announce.Mastodon.Visibility = defaultVisibility
+	}
+	if _, err := templateVisibility(ctx); err != nil {
+		return fmt.Errorf("mastodon: %w", err)
+	}
 	return nil
 }
 
+func templateVisibility(ctx *context.Context) (string, error) {
+	visibility, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Mastodon.Visibility)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range validVisibilities {
+		if v == visibility {
+			return visibility, nil
+		}
+	}
+	return "", fmt.Errorf("invalid visibility %q, must be one of: %s", visibility, strings.Join(validVisibilities, ", "))
+}
+
 func (Pipe) Announce(ctx *context.Context) error {
 	msg, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Mastodon.MessageTemplate)
 	if err != nil {
 		return fmt.Errorf("mastodon: %w", err)
 	}
 
+	visibility, err := templateVisibility(ctx)
+	if err != nil {
+		return fmt.Errorf("mastodon: %w", err)
+	}
+
+	if ctx.AnnounceDryRun {
+		log.WithField("message", msg).Info("dry-run, not posting")
+		return nil
+	}
+
 	var cfg Config
 	if err := env.Parse(&cfg); err != nil {
 		return fmt.Errorf("mastodon: %w", err)
@@ -53,7 +88,8 @@ func (Pipe) Announce(ctx *context.Context) error {
 
 	log.Infof("posting: '%s'", msg)
 	if _, err := client.PostStatus(ctx, &mastodon.Toot{
-		Status: msg,
+		Status:     msg,
+		Visibility: visibility,
 	}); err != nil {
 		return fmt.Errorf("mastodon: %w", err)
 	}
@@ -16,6 +16,58 @@ func TestDefault(t *testing.T) {
 	ctx := context.New(config.Project{})
 	require.NoError(t, Pipe{}.Default(ctx))
 	require.Equal(t, ctx.Config.Announce.Mastodon.MessageTemplate, defaultMessageTemplate)
+	require.Equal(t, ctx.Config.Announce.Mastodon.Visibility, defaultVisibility)
+}
+
+func TestDefaultVisibility(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Mastodon: config.Mastodon{
+					Visibility: "unlisted",
+				},
+			},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.Equal(t, "unlisted", ctx.Config.Announce.Mastodon.Visibility)
+	})
+
+	t.Run("templated", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Mastodon: config.Mastodon{
+					Visibility: "{{ .Env.VISIBILITY }}",
+				},
+			},
+		})
+		ctx.Env["VISIBILITY"] = "private"
+		require.NoError(t, Pipe{}.Default(ctx))
+		visibility, err := templateVisibility(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "private", visibility)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Mastodon: config.Mastodon{
+					Visibility: "unlistd",
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `mastodon: invalid visibility "unlistd", must be one of: public, unlisted, private, direct`)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Mastodon: config.Mastodon{
+					Visibility: "{{ .Foo }",
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `mastodon: template: tmpl:1: unexpected "}" in operand`)
+	})
 }
 
 func TestAnnounceInvalidTemplate(t *testing.T) {
@@ -39,6 +91,21 @@ func TestAnnounceMissingEnv(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `mastodon: env: environment variable "MASTODON_CLIENT_ID" should not be empty; environment variable "MASTODON_CLIENT_SECRET" should not be empty; environment variable "MASTODON_ACCESS_TOKEN" should not be empty`)
 }
 
+func TestAnnounceDryRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Mastodon: config.Mastodon{
+				Enabled:         true,
+				MessageTemplate: "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
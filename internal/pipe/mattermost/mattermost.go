@@ -59,6 +59,11 @@ func (Pipe) Announce(ctx *context.Context) error {
 		return fmt.Errorf("teams: %w", err)
 	}
 
+	if ctx.AnnounceDryRun {
+		log.WithField("message", msg).Info("dry-run, not posting")
+		return nil
+	}
+
 	var cfg Config
 	if err := env.Parse(&cfg); err != nil {
 		return fmt.Errorf("mattermost: %w", err)
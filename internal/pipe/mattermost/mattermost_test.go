@@ -44,6 +44,27 @@ func TestAnnounceMissingEnv(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `mattermost: env: environment variable "MATTERMOST_WEBHOOK" should not be empty`)
 }
 
+func TestAnnounceDryRun(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not post on dry-run")
+	}))
+	defer ts.Close()
+	t.Setenv("MATTERMOST_WEBHOOK", ts.URL)
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Mattermost: config.Mattermost{
+				Enabled:         true,
+				MessageTemplate: "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -3,6 +3,7 @@ package metadata
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"time"
@@ -27,20 +28,46 @@ func (Pipe) Run(ctx *context.Context) error {
 }
 
 func writeMetadata(ctx *context.Context) error {
-	return writeJSON(ctx, metadata{
+	return writeJSON(ctx, Metadata{
 		ProjectName: ctx.Config.ProjectName,
 		Tag:         ctx.Git.CurrentTag,
 		PreviousTag: ctx.Git.PreviousTag,
 		Version:     ctx.Version,
 		Commit:      ctx.Git.Commit,
 		Date:        ctx.Date,
+		ReleaseID:   ctx.ReleaseID,
 		Runtime: metaRuntime{
 			Goos:   ctx.Runtime.Goos,
 			Goarch: ctx.Runtime.Goarch,
 		},
+		Artifacts: artifactsMetadata(ctx.Artifacts.List()),
 	}, "metadata.json")
 }
 
+func artifactsMetadata(artifacts []*artifact.Artifact) []ArtifactMetadata {
+	result := make([]ArtifactMetadata, 0, len(artifacts))
+	for _, a := range artifacts {
+		digest := artifact.ExtraOr(*a, artifact.ExtraDigest, "")
+		if digest == "" {
+			digest = artifact.ExtraOr(*a, artifact.ExtraChecksum, "")
+		}
+		result = append(result, ArtifactMetadata{
+			Name:    a.Name,
+			Path:    filepath.ToSlash(filepath.Clean(a.Path)),
+			Type:    a.Type.String(),
+			ID:      a.ID(),
+			Goos:    a.Goos,
+			Goarch:  a.Goarch,
+			Goarm:   a.Goarm,
+			Gomips:  a.Gomips,
+			Goamd64: a.Goamd64,
+			Digest:  digest,
+			URL:     artifact.ExtraOr(*a, artifact.ExtraURL, ""),
+		})
+	}
+	return result
+}
+
 func writeArtifacts(ctx *context.Context) error {
 	_ = ctx.Artifacts.Visit(func(a *artifact.Artifact) error {
 		a.TypeS = a.Type.String()
@@ -60,14 +87,51 @@ func writeJSON(ctx *context.Context, j interface{}, name string) error {
 	return os.WriteFile(path, bts, 0o644)
 }
 
-type metadata struct {
+// Metadata is the content of the metadata.json file.
+type Metadata struct {
 	ProjectName string      `json:"project_name"`
 	Tag         string      `json:"tag"`
 	PreviousTag string      `json:"previous_tag"`
 	Version     string      `json:"version"`
 	Commit      string      `json:"commit"`
 	Date        time.Time   `json:"date"`
+	ReleaseID   string      `json:"release_id,omitempty"`
 	Runtime     metaRuntime `json:"runtime"`
+
+	Artifacts []ArtifactMetadata `json:"artifacts"`
+}
+
+// ArtifactMetadata describes a single artifact in the metadata.json file.
+type ArtifactMetadata struct {
+	Name    string `json:"name"`
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	ID      string `json:"id,omitempty"`
+	Goos    string `json:"goos,omitempty"`
+	Goarch  string `json:"goarch,omitempty"`
+	Goarm   string `json:"goarm,omitempty"`
+	Gomips  string `json:"gomips,omitempty"`
+	Goamd64 string `json:"goamd64,omitempty"`
+	// Digest is the docker image digest, or the checksum of the file, in the
+	// algorithm:hash format.
+	Digest string `json:"digest,omitempty"`
+	// URL is the download URL of the artifact, if it was uploaded to the
+	// release.
+	URL string `json:"url,omitempty"`
+}
+
+// Read reads the metadata.json file from the given dist folder.
+func Read(dist string) (Metadata, error) {
+	var m Metadata
+	path := filepath.Join(dist, "metadata.json")
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return m, fmt.Errorf("failed to read metadata: %w", err)
+	}
+	if err := json.Unmarshal(bts, &m); err != nil {
+		return m, fmt.Errorf("failed to read metadata: %s: %w", path, err)
+	}
+	return m, nil
 }
 
 type metaRuntime struct {
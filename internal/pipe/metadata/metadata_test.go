@@ -1,6 +1,8 @@
 package metadata
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -68,3 +70,165 @@ func requireEqualJSONFile(tb testing.TB, tmp, s string) {
 	require.NoError(tb, err)
 	require.Equal(tb, "-rw-r--r--", info.Mode().String())
 }
+
+func TestRead(t *testing.T) {
+	t.Run("with release id", func(t *testing.T) {
+		tmp := t.TempDir()
+		ctx := context.New(config.Project{
+			Dist:        tmp,
+			ProjectName: "name",
+		})
+		ctx.Version = "1.2.3"
+		ctx.Git = context.GitInfo{
+			CurrentTag: "v1.2.3",
+			Commit:     "aef34a",
+		}
+		ctx.ReleaseID = "123456"
+		require.NoError(t, Pipe{}.Run(ctx))
+
+		meta, err := Read(tmp)
+		require.NoError(t, err)
+		require.Equal(t, "name", meta.ProjectName)
+		require.Equal(t, "v1.2.3", meta.Tag)
+		require.Equal(t, "1.2.3", meta.Version)
+		require.Equal(t, "123456", meta.ReleaseID)
+	})
+
+	t.Run("without release id", func(t *testing.T) {
+		tmp := t.TempDir()
+		require.NoError(t, Pipe{}.Run(context.New(config.Project{
+			Dist: tmp,
+		})))
+		bts, err := os.ReadFile(filepath.Join(tmp, "metadata.json"))
+		require.NoError(t, err)
+		require.NotContains(t, string(bts), "release_id")
+
+		meta, err := Read(tmp)
+		require.NoError(t, err)
+		require.Empty(t, meta.ReleaseID)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := Read(t.TempDir())
+		require.ErrorContains(t, err, "failed to read metadata")
+	})
+}
+
+func TestMetadataSchema(t *testing.T) {
+	tmp := t.TempDir()
+	ctx := context.New(config.Project{
+		Dist:        tmp,
+		ProjectName: "foo",
+	})
+	ctx.Version = "1.2.3"
+	ctx.Git = context.GitInfo{
+		CurrentTag:  "v1.2.3",
+		PreviousTag: "v1.2.2",
+		Commit:      "aef34a",
+	}
+	ctx.Date = time.Date(2022, 0o1, 22, 10, 12, 13, 0, time.UTC)
+	ctx.ReleaseID = "123456"
+	ctx.Runtime = context.Runtime{
+		Goos:   "linux",
+		Goarch: "amd64",
+	}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    "foo",
+		Path:    "dist/foo_linux_amd64_v1/foo",
+		Type:    artifact.Binary,
+		Goos:    "linux",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Extra: map[string]interface{}{
+			artifact.ExtraID: "foo",
+		},
+	})
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    "foo_1.2.3_linux_amd64.tar.gz",
+		Path:    "dist/foo_1.2.3_linux_amd64.tar.gz",
+		Type:    artifact.UploadableArchive,
+		Goos:    "linux",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Extra: map[string]interface{}{
+			artifact.ExtraID:       "foo",
+			artifact.ExtraChecksum: "sha256:5d41402abc4b2a76b9719d911017c592",
+			artifact.ExtraURL:      "https://github.com/foo/foo/releases/download/v1.2.3/foo_1.2.3_linux_amd64.tar.gz",
+		},
+	})
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "ghcr.io/foo/foo:v1.2.3",
+		Path:   "ghcr.io/foo/foo:v1.2.3",
+		Type:   artifact.DockerImage,
+		Goos:   "linux",
+		Goarch: "amd64",
+		Extra: map[string]interface{}{
+			artifact.ExtraDigest: "sha256:7d865e959b2466918c9863afca942d0f",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	bts, err := os.ReadFile(filepath.Join(tmp, "metadata.json"))
+	require.NoError(t, err)
+
+	// fails if the file has any field not in the documented schema.
+	var meta Metadata
+	dec := json.NewDecoder(bytes.NewReader(bts))
+	dec.DisallowUnknownFields()
+	require.NoError(t, dec.Decode(&meta))
+
+	require.Equal(t, Metadata{
+		ProjectName: "foo",
+		Tag:         "v1.2.3",
+		PreviousTag: "v1.2.2",
+		Version:     "1.2.3",
+		Commit:      "aef34a",
+		Date:        ctx.Date,
+		ReleaseID:   "123456",
+		Runtime: metaRuntime{
+			Goos:   "linux",
+			Goarch: "amd64",
+		},
+		Artifacts: []ArtifactMetadata{
+			{
+				Name:    "foo",
+				Path:    "dist/foo_linux_amd64_v1/foo",
+				Type:    "Binary",
+				ID:      "foo",
+				Goos:    "linux",
+				Goarch:  "amd64",
+				Goamd64: "v1",
+			},
+			{
+				Name:    "foo_1.2.3_linux_amd64.tar.gz",
+				Path:    "dist/foo_1.2.3_linux_amd64.tar.gz",
+				Type:    "Archive",
+				ID:      "foo",
+				Goos:    "linux",
+				Goarch:  "amd64",
+				Goamd64: "v1",
+				Digest:  "sha256:5d41402abc4b2a76b9719d911017c592",
+				URL:     "https://github.com/foo/foo/releases/download/v1.2.3/foo_1.2.3_linux_amd64.tar.gz",
+			},
+			{
+				Name:   "ghcr.io/foo/foo:v1.2.3",
+				Path:   "ghcr.io/foo/foo:v1.2.3",
+				Type:   "Published Docker Image",
+				Goos:   "linux",
+				Goarch: "amd64",
+				Digest: "sha256:7d865e959b2466918c9863afca942d0f",
+			},
+		},
+	}, meta)
+
+	// every artifact must have the required fields set.
+	var raw struct {
+		Artifacts []map[string]interface{} `json:"artifacts"`
+	}
+	require.NoError(t, json.Unmarshal(bts, &raw))
+	for _, a := range raw.Artifacts {
+		for _, field := range []string{"name", "path", "type"} {
+			require.NotEmpty(t, a[field], "artifact is missing %q: %v", field, a)
+		}
+	}
+}
@@ -2,6 +2,9 @@
 package milestone
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/git"
@@ -74,8 +77,7 @@ func doPublish(ctx *context.Context, vcsClient client.Client) error {
 			WithField("repo", repo.String()).
 			Info("closing milestone")
 
-		err = vcsClient.CloseMilestone(ctx, repo, name)
-
+		err = closeMilestone(ctx, vcsClient, repo, name, milestone.CreateIfMissing)
 		if err != nil {
 			if milestone.FailOnError {
 				return err
@@ -89,3 +91,19 @@ func doPublish(ctx *context.Context, vcsClient client.Client) error {
 
 	return nil
 }
+
+func closeMilestone(ctx *context.Context, vcsClient client.Client, repo client.Repo, name string, createIfMissing bool) error {
+	err := vcsClient.CloseMilestone(ctx, repo, name)
+	var notFound client.ErrNoMilestoneFound
+	if !createIfMissing || !errors.As(err, &notFound) {
+		return err
+	}
+
+	log.WithField("milestone", name).
+		WithField("repo", repo.String()).
+		Info("milestone not found, creating it")
+	if err := vcsClient.CreateMilestone(ctx, repo, name); err != nil {
+		return fmt.Errorf("failed to create milestone: %w", err)
+	}
+	return vcsClient.CloseMilestone(ctx, repo, name)
+}
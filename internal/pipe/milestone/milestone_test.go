@@ -190,6 +190,79 @@ func TestPublishCloseFailOnError(t *testing.T) {
 	require.Equal(t, "", client.ClosedMilestone)
 }
 
+func TestPublishCreateIfMissing(t *testing.T) {
+	ctx := context.New(config.Project{
+		Milestones: []config.Milestone{
+			{
+				Close:           true,
+				FailOnError:     true,
+				CreateIfMissing: true,
+				NameTemplate:    defaultNameTemplate,
+				Repo: config.Repo{
+					Name:  "configrepo",
+					Owner: "configowner",
+				},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	client := &client.Mock{
+		MissingMilestone: true,
+	}
+	require.NoError(t, doPublish(ctx, client))
+	require.Equal(t, "v1.0.0", client.CreatedMilestone)
+	require.Equal(t, "v1.0.0", client.ClosedMilestone)
+}
+
+func TestPublishCreateIfMissingError(t *testing.T) {
+	ctx := context.New(config.Project{
+		Milestones: []config.Milestone{
+			{
+				Close:           true,
+				FailOnError:     true,
+				CreateIfMissing: true,
+				NameTemplate:    defaultNameTemplate,
+				Repo: config.Repo{
+					Name:  "configrepo",
+					Owner: "configowner",
+				},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	client := &client.Mock{
+		MissingMilestone:      true,
+		FailToCreateMilestone: true,
+	}
+	require.EqualError(t, doPublish(ctx, client), "failed to create milestone: failed to create milestone")
+	require.Equal(t, "", client.ClosedMilestone)
+}
+
+func TestPublishMissingMilestone(t *testing.T) {
+	ctx := context.New(config.Project{
+		Milestones: []config.Milestone{
+			{
+				Close:        true,
+				FailOnError:  true,
+				NameTemplate: defaultNameTemplate,
+				Repo: config.Repo{
+					Name:  "configrepo",
+					Owner: "configowner",
+				},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	cli := &client.Mock{
+		MissingMilestone: true,
+	}
+	var notFound client.ErrNoMilestoneFound
+	require.ErrorAs(t, doPublish(ctx, cli), &notFound)
+	require.Equal(t, "v1.0.0", notFound.Title)
+	require.Equal(t, "", cli.CreatedMilestone)
+	require.Equal(t, "", cli.ClosedMilestone)
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -0,0 +1,126 @@
+package nfpm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/yaml"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// changelogFormats are the formats in which nfpm embeds the changelog.
+// Other formats don't support it, so no changelog is generated for them.
+var changelogFormats = map[string]bool{
+	"deb":        true,
+	termuxFormat: true,
+	"rpm":        true,
+}
+
+// chglogEntry is a single release in the changelog format nfpm expects, see
+// https://github.com/goreleaser/chglog.
+type chglogEntry struct {
+	Semver   string         `yaml:"semver"`
+	Date     time.Time      `yaml:"date"`
+	Packager string         `yaml:"packager"`
+	Deb      *chglogDeb     `yaml:"deb,omitempty"`
+	Changes  []chglogChange `yaml:"changes,omitempty"`
+}
+
+type chglogDeb struct {
+	Urgency       string   `yaml:"urgency"`
+	Distributions []string `yaml:"distributions"`
+}
+
+type chglogChange struct {
+	Commit string `yaml:"commit,omitempty"`
+	Note   string `yaml:"note"`
+}
+
+var (
+	changelogItemRe = regexp.MustCompile(`^\s*[*-]\s+(.+)$`)
+	changelogSHARe  = regexp.MustCompile(`^([0-9a-f]{7,40}):?\s+(.+)$`)
+	markdownLinkRe  = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+)
+
+// releaseNotesChangelog writes the release notes generated by the changelog
+// pipe as a changelog nfpm can embed in the given format, returning its path.
+// It returns an empty path if there is nothing to write.
+func releaseNotesChangelog(ctx *context.Context, fpm config.NFPM, format, arch, maintainer string) (string, error) {
+	if !fpm.GenerateChangelog || !changelogFormats[format] {
+		return "", nil
+	}
+	if strings.TrimSpace(ctx.ReleaseNotes) == "" {
+		log.Warn("no release notes available, skipping package changelog")
+		return "", nil
+	}
+
+	entries := toChglog(ctx, format, maintainer)
+	bts, err := yaml.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to write changelog: %w", err)
+	}
+
+	path := filepath.Join(ctx.Config.Dist, format, fpm.PackageName+"_"+arch, "changelog.yml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("failed to write changelog: %w", err)
+	}
+	log.Debugf("creating %q", path)
+	if err := os.WriteFile(path, bts, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write changelog: %w", err)
+	}
+	return path, nil
+}
+
+// toChglog converts the markdown release notes into a single changelog
+// entry for the current version.
+func toChglog(ctx *context.Context, format, maintainer string) []chglogEntry {
+	date := ctx.Git.CommitDate
+	if date.IsZero() {
+		date = ctx.Date
+	}
+	entry := chglogEntry{
+		// both deb and rpm sort prereleases before the final version
+		// when they are separated with a tilde.
+		Semver:   strings.Replace(ctx.Version, "-", "~", 1),
+		Date:     date.UTC(),
+		Packager: maintainer,
+		Changes:  changelogChanges(ctx.ReleaseNotes),
+	}
+	if format != "rpm" {
+		// deb changelogs require both the urgency and the target
+		// distributions in every entry's header.
+		entry.Deb = &chglogDeb{
+			Urgency:       "low",
+			Distributions: []string{"stable"},
+		}
+	}
+	return []chglogEntry{entry}
+}
+
+// changelogChanges extracts the list items of the given markdown release
+// notes.
+func changelogChanges(notes string) []chglogChange {
+	var changes []chglogChange
+	for _, line := range strings.Split(notes, "\n") {
+		match := changelogItemRe.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		var change chglogChange
+		item := strings.TrimSpace(markdownLinkRe.ReplaceAllString(match[1], "$1"))
+		if sha := changelogSHARe.FindStringSubmatch(item); sha != nil {
+			change.Commit = sha[1]
+			item = sha[2]
+		}
+		// changelog notes must fit in a single line.
+		change.Note = strings.Join(strings.Fields(item), " ")
+		changes = append(changes, change)
+	}
+	return changes
+}
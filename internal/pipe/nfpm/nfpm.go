@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/caarlos0/log"
@@ -62,6 +63,15 @@ func (Pipe) Default(ctx *context.Context) error {
 		if len(fpm.Replacements) != 0 {
 			deprecate.Notice(ctx, "nfpms.replacements")
 		}
+		if fpm.GenerateChangelog && fpm.Changelog != "" {
+			return fmt.Errorf("invalid nfpms.%s: changelog and generate_changelog can't be used together", fpm.ID)
+		}
+		if err := defaultSystemd(fpm.Systemd); err != nil {
+			return err
+		}
+		if err := validateCompressions(fpm); err != nil {
+			return err
+		}
 		ids.Inc(fpm.ID)
 	}
 
@@ -69,6 +79,46 @@ func (Pipe) Default(ctx *context.Context) error {
 	return ids.Validate()
 }
 
+// supportedCompressions are the compression algorithms supported by each
+// format.
+var supportedCompressions = map[string][]string{
+	"deb": {"gzip", "xz", "zstd", "none"},
+	"rpm": {"gzip", "lzma", "xz", "zstd"},
+}
+
+func validateCompressions(fpm *config.NFPM) error {
+	all := []config.NFPMOverridables{fpm.NFPMOverridables}
+	for _, overrides := range fpm.Overrides {
+		all = append(all, overrides)
+	}
+	for _, o := range all {
+		if err := validateCompression("deb", o.Deb.Compression); err != nil {
+			return err
+		}
+		if err := validateCompression("rpm", o.RPM.Compression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateCompression(format, compression string) error {
+	if compression == "" {
+		return nil
+	}
+	algo := compression
+	if format == "rpm" {
+		// rpm also accepts a compression level, e.g. `zstd:19`.
+		algo, _, _ = strings.Cut(compression, ":")
+	}
+	for _, s := range supportedCompressions[format] {
+		if s == algo {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid nfpms.%s.compression: %s, valid options are %v", format, compression, supportedCompressions[format])
+}
+
 // Run the pipe.
 func (Pipe) Run(ctx *context.Context) error {
 	for _, nfpm := range ctx.Config.NFPMs {
@@ -222,6 +272,12 @@ func create(ctx *context.Context, fpm config.NFPM, format string, binaries []*ar
 		return err
 	}
 
+	if format == "apk" && apkKeyFile != "" && !ctx.SkipSign {
+		if err := checkSigningKey(format, apkKeyFile); err != nil {
+			return err
+		}
+	}
+
 	contents := files.Contents{}
 	for _, content := range overridden.Contents {
 		src, err := t.Apply(content.Source)
@@ -232,12 +288,16 @@ func create(ctx *context.Context, fpm config.NFPM, format string, binaries []*ar
 		if err != nil {
 			return err
 		}
+		info, err := contentFileInfo(t, content.FileInfo)
+		if err != nil {
+			return err
+		}
 		contents = append(contents, &files.Content{
 			Source:      src,
 			Destination: dst,
 			Type:        content.Type,
 			Packager:    content.Packager,
-			FileInfo:    content.FileInfo,
+			FileInfo:    info,
 		})
 	}
 
@@ -265,8 +325,27 @@ func create(ctx *context.Context, fpm config.NFPM, format string, binaries []*ar
 		})
 	}
 
+	systemdUnits, enable, err := systemdContents(ctx, t, fpm, format, arch, binDir, binaries)
+	if err != nil {
+		return err
+	}
+	contents = append(contents, systemdUnits...)
+
+	postInstall, err := systemdPostInstall(ctx, fpm, format, arch, enable, overridden.Scripts.PostInstall)
+	if err != nil {
+		return err
+	}
+
 	log := log.WithField("package", fpm.PackageName).WithField("format", format).WithField("arch", arch)
 
+	changelog := fpm.Changelog
+	if fpm.GenerateChangelog {
+		changelog, err = releaseNotesChangelog(ctx, fpm, format, arch, maintainer)
+		if err != nil {
+			return err
+		}
+	}
+
 	// FPM meta package should not contain binaries at all
 	if !fpm.Meta {
 		for _, binary := range binaries {
@@ -301,7 +380,7 @@ func create(ctx *context.Context, fpm config.NFPM, format string, binaries []*ar
 		Vendor:          fpm.Vendor,
 		Homepage:        homepage,
 		License:         fpm.License,
-		Changelog:       fpm.Changelog,
+		Changelog:       changelog,
 		Overridables: nfpm.Overridables{
 			Conflicts:  overridden.Conflicts,
 			Depends:    overridden.Dependencies,
@@ -312,12 +391,13 @@ func create(ctx *context.Context, fpm config.NFPM, format string, binaries []*ar
 			Contents:   contents,
 			Scripts: nfpm.Scripts{
 				PreInstall:  overridden.Scripts.PreInstall,
-				PostInstall: overridden.Scripts.PostInstall,
+				PostInstall: postInstall,
 				PreRemove:   overridden.Scripts.PreRemove,
 				PostRemove:  overridden.Scripts.PostRemove,
 			},
 			Deb: nfpm.Deb{
-				// TODO: Compression, Fields
+				// TODO: Fields
+				Compression: overridden.Deb.Compression,
 				Scripts: nfpm.DebScripts{
 					Rules:     overridden.Deb.Scripts.Rules,
 					Templates: overridden.Deb.Scripts.Templates,
@@ -443,6 +523,40 @@ func create(ctx *context.Context, fpm config.NFPM, format string, binaries []*ar
 	return nil
 }
 
+// contentFileInfo evaluates the templates of the given file info and parses
+// its mode.
+func contentFileInfo(t *tmpl.Template, info *config.NFPMContentFileInfo) (*files.ContentFileInfo, error) {
+	if info == nil {
+		return nil, nil
+	}
+	owner, err := t.Apply(info.Owner)
+	if err != nil {
+		return nil, err
+	}
+	group, err := t.Apply(info.Group)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := t.Apply(info.Mode)
+	if err != nil {
+		return nil, err
+	}
+	result := &files.ContentFileInfo{
+		Owner: owner,
+		Group: group,
+		MTime: info.MTime,
+	}
+	if mode = strings.TrimSpace(mode); mode != "" {
+		// base 0 handles both 0644 and 0o644 as octal, as yaml itself does.
+		m, err := strconv.ParseUint(mode, 0, 32)
+		if err != nil || os.FileMode(m)&^os.ModePerm != 0 {
+			return nil, fmt.Errorf("invalid file mode %q: must be an octal number such as 0644", mode)
+		}
+		result.Mode = os.FileMode(m)
+	}
+	return result, nil
+}
+
 func destinations(contents files.Contents) []string {
 	result := make([]string, 0, len(contents))
 	for _, f := range contents {
@@ -451,6 +565,16 @@ func destinations(contents files.Contents) []string {
 	return result
 }
 
+// checkSigningKey makes sure the given signing key can be read, so an invalid
+// key fails with a clear error instead of deep inside nfpm.
+func checkSigningKey(format, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s signing key: %w", format, err)
+	}
+	return f.Close()
+}
+
 func getPassphraseFromEnv(ctx *context.Context, packager string, nfpmID string) string {
 	var passphrase string
 
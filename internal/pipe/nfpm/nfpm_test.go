@@ -1,10 +1,17 @@
 package nfpm
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/testlib"
@@ -139,7 +146,7 @@ func TestRunPipe(t *testing.T) {
 					Provides:         []string{"ash"},
 					Release:          "10",
 					Epoch:            "20",
-					Contents: []*files.Content{
+					Contents: []*config.NFPMContent{
 						{
 							Destination: "/var/log/foobar",
 							Type:        "dir",
@@ -498,7 +505,7 @@ func TestInvalidTemplate(t *testing.T) {
 	t.Run("source", func(t *testing.T) {
 		ctx := makeCtx()
 		ctx.Config.NFPMs[0].NFPMOverridables = config.NFPMOverridables{
-			Contents: files.Contents{
+			Contents: []*config.NFPMContent{
 				{
 					Source:      "{{ .NOPE_SOURCE }}",
 					Destination: "/foo",
@@ -511,7 +518,7 @@ func TestInvalidTemplate(t *testing.T) {
 	t.Run("target", func(t *testing.T) {
 		ctx := makeCtx()
 		ctx.Config.NFPMs[0].NFPMOverridables = config.NFPMOverridables{
-			Contents: files.Contents{
+			Contents: []*config.NFPMContent{
 				{
 					Source:      "./testdata/testfile.txt",
 					Destination: "{{ .NOPE_TARGET }}",
@@ -579,7 +586,7 @@ func TestRunPipeInvalidContentsSourceTemplate(t *testing.T) {
 				{
 					NFPMOverridables: config.NFPMOverridables{
 						PackageName: "foo",
-						Contents: []*files.Content{
+						Contents: []*config.NFPMContent{
 							{
 								Source:      "{{.asdsd}",
 								Destination: "testfile",
@@ -643,7 +650,7 @@ func TestCreateFileDoesntExist(t *testing.T) {
 				Builds:  []string{"default"},
 				NFPMOverridables: config.NFPMOverridables{
 					PackageName: "foo",
-					Contents: []*files.Content{
+					Contents: []*config.NFPMContent{
 						{
 							Source:      "testdata/testfile.txt",
 							Destination: "/var/lib/test/testfile.txt",
@@ -785,7 +792,7 @@ func TestDebSpecificConfig(t *testing.T) {
 					Maintainer: "foo",
 					NFPMOverridables: config.NFPMOverridables{
 						PackageName: "foo",
-						Contents: []*files.Content{
+						Contents: []*config.NFPMContent{
 							{
 								Source:      "testdata/testfile.txt",
 								Destination: "/usr/share/testfile.txt",
@@ -881,7 +888,7 @@ func TestRPMSpecificConfig(t *testing.T) {
 				Formats: []string{"rpm"},
 				NFPMOverridables: config.NFPMOverridables{
 					PackageName: "foo",
-					Contents: []*files.Content{
+					Contents: []*config.NFPMContent{
 						{
 							Source:      "testdata/testfile.txt",
 							Destination: "/usr/share/testfile.txt",
@@ -1028,7 +1035,7 @@ func TestAPKSpecificConfig(t *testing.T) {
 				Formats:    []string{"apk"},
 				NFPMOverridables: config.NFPMOverridables{
 					PackageName: "foo",
-					Contents: []*files.Content{
+					Contents: []*config.NFPMContent{
 						{
 							Source:      "testdata/testfile.txt",
 							Destination: "/usr/share/testfile.txt",
@@ -1081,6 +1088,51 @@ func TestAPKSpecificConfig(t *testing.T) {
 		}
 		require.NoError(t, Pipe{}.Run(ctx))
 	})
+
+	t.Run("signature section", func(t *testing.T) {
+		ctx.Artifacts = artifact.New()
+		for _, goarch := range []string{"amd64", "386"} {
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Name:   "mybin",
+				Path:   binPath,
+				Goarch: goarch,
+				Goos:   "linux",
+				Type:   artifact.Binary,
+				Extra: map[string]interface{}{
+					artifact.ExtraID: "default",
+				},
+			})
+		}
+		ctx.Env = map[string]string{
+			"NFPM_SOMEID_APK_PASSPHRASE": "hunter2",
+		}
+		ctx.Config.NFPMs[0].APK.Signature.KeyName = "{{ .ProjectName }}.rsa.pub"
+		require.NoError(t, Pipe{}.Run(ctx))
+
+		packages := ctx.Artifacts.Filter(artifact.ByType(artifact.LinuxPackage)).List()
+		require.Len(t, packages, 2)
+		for _, pkg := range packages {
+			f, err := os.Open(pkg.Path)
+			require.NoError(t, err)
+			defer f.Close()
+
+			// the signature is the first gzip stream of the package
+			gz, err := gzip.NewReader(f)
+			require.NoError(t, err)
+			gz.Multistream(false)
+			hdr, err := tar.NewReader(gz).Next()
+			require.NoError(t, err)
+			require.Equal(t, ".SIGN.RSA.mybin.rsa.pub", hdr.Name)
+			require.NotZero(t, hdr.Size)
+		}
+	})
+
+	t.Run("unreadable key", func(t *testing.T) {
+		ctx.Config.NFPMs[0].APK.Signature.KeyFile = "./testdata/nope.priv"
+		err := Pipe{}.Run(ctx)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to read apk signing key")
+	})
 }
 
 func TestAPKSpecificScriptsConfig(t *testing.T) {
@@ -1107,7 +1159,7 @@ func TestAPKSpecificScriptsConfig(t *testing.T) {
 				Formats:    []string{"apk"},
 				NFPMOverridables: config.NFPMOverridables{
 					PackageName: "foo",
-					Contents: []*files.Content{
+					Contents: []*config.NFPMContent{
 						{
 							Source:      "testdata/testfile.txt",
 							Destination: "/usr/share/testfile.txt",
@@ -1219,7 +1271,7 @@ func TestMeta(t *testing.T) {
 					Conflicts:        []string{"git"},
 					Release:          "10",
 					Epoch:            "20",
-					Contents: []*files.Content{
+					Contents: []*config.NFPMContent{
 						{
 							Source:      "testdata/testfile.txt",
 							Destination: "/usr/share/testfile.txt",
@@ -1302,7 +1354,7 @@ func TestSkipSign(t *testing.T) {
 				NFPMOverridables: config.NFPMOverridables{
 					PackageName:      "foo",
 					FileNameTemplate: defaultNameTemplate,
-					Contents: []*files.Content{
+					Contents: []*config.NFPMContent{
 						{
 							Source:      "testdata/testfile.txt",
 							Destination: "/usr/share/testfile.txt",
@@ -1428,6 +1480,126 @@ func TestBinDirTemplating(t *testing.T) {
 	}
 }
 
+func TestContentFileInfoTemplating(t *testing.T) {
+	makeCtx := func(t *testing.T, info *config.NFPMContentFileInfo) *context.Context {
+		t.Helper()
+		folder := t.TempDir()
+		dist := filepath.Join(folder, "dist")
+		require.NoError(t, os.Mkdir(dist, 0o755))
+		require.NoError(t, os.Mkdir(filepath.Join(dist, "mybin"), 0o755))
+		binPath := filepath.Join(dist, "mybin", "mybin")
+		f, err := os.Create(binPath)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		ctx := context.New(config.Project{
+			ProjectName: "mybin",
+			Dist:        dist,
+			Env: []string{
+				"SVC_USER=myservice",
+				"RPM_USER=_myservice",
+				"CONF_MODE=0640",
+			},
+			NFPMs: []config.NFPM{
+				{
+					ID:         "someid",
+					Builds:     []string{"default"},
+					Formats:    []string{"deb", "rpm"},
+					Maintainer: "me@me",
+					NFPMOverridables: config.NFPMOverridables{
+						PackageName: "foo",
+						Contents: []*config.NFPMContent{
+							{
+								Source:      "./testdata/testfile.txt",
+								Destination: "/etc/foo/foo.conf",
+								Type:        "config",
+								FileInfo:    info,
+							},
+						},
+					},
+					Overrides: map[string]config.NFPMOverridables{
+						"rpm": {
+							Contents: []*config.NFPMContent{
+								{
+									Source:      "./testdata/testfile.txt",
+									Destination: "/etc/foo/foo.conf",
+									Type:        "config",
+									FileInfo: &config.NFPMContentFileInfo{
+										Owner: "{{ .Env.RPM_USER }}",
+										Group: "{{ .Env.RPM_USER }}",
+										Mode:  "{{ .Env.CONF_MODE }}",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+		ctx.Version = "1.0.0"
+		ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name:   "mybin",
+			Path:   binPath,
+			Goarch: "amd64",
+			Goos:   "linux",
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				artifact.ExtraID: "default",
+			},
+		})
+		return ctx
+	}
+
+	t.Run("rendered", func(t *testing.T) {
+		ctx := makeCtx(t, &config.NFPMContentFileInfo{
+			Owner: "{{ .Env.SVC_USER }}",
+			Group: "{{ .Env.SVC_USER }}",
+			Mode:  "{{ .Env.CONF_MODE }}",
+		})
+		require.NoError(t, Pipe{}.Run(ctx))
+		packages := ctx.Artifacts.Filter(artifact.ByType(artifact.LinuxPackage)).List()
+		require.Len(t, packages, 2)
+
+		expected := map[string]string{
+			"deb": "myservice",
+			"rpm": "_myservice",
+		}
+		for _, pkg := range packages {
+			var conf *files.Content
+			for _, content := range artifact.ExtraOr(*pkg, extraFiles, files.Contents{}) {
+				if content.Destination == "/etc/foo/foo.conf" {
+					conf = content
+				}
+			}
+			require.NotNil(t, conf, pkg.Format())
+			require.Equal(t, expected[pkg.Format()], conf.FileInfo.Owner)
+			require.Equal(t, expected[pkg.Format()], conf.FileInfo.Group)
+			require.Equal(t, os.FileMode(0o640), conf.FileInfo.Mode.Perm())
+		}
+	})
+
+	t.Run("invalid mode", func(t *testing.T) {
+		ctx := makeCtx(t, &config.NFPMContentFileInfo{
+			Mode: "rw-r-----",
+		})
+		require.EqualError(t, Pipe{}.Run(ctx), `invalid file mode "rw-r-----": must be an octal number such as 0644`)
+	})
+
+	t.Run("mode out of range", func(t *testing.T) {
+		ctx := makeCtx(t, &config.NFPMContentFileInfo{
+			Mode: "01000",
+		})
+		require.EqualError(t, Pipe{}.Run(ctx), `invalid file mode "01000": must be an octal number such as 0644`)
+	})
+
+	t.Run("invalid owner template", func(t *testing.T) {
+		ctx := makeCtx(t, &config.NFPMContentFileInfo{
+			Owner: "{{ .Env.NOPE }}",
+		})
+		testlib.RequireTemplateError(t, Pipe{}.Run(ctx))
+	})
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -1453,3 +1625,405 @@ func sources(contents files.Contents) []string {
 	}
 	return result
 }
+
+func TestSystemd(t *testing.T) {
+	folder := t.TempDir()
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "mybin"), 0o755))
+	binPath := filepath.Join(dist, "mybin", "mybin")
+	f, err := os.Create(binPath)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	ctx := context.New(config.Project{
+		ProjectName: "mybin",
+		Dist:        dist,
+		NFPMs: []config.NFPM{
+			{
+				ID:         "someid",
+				Builds:     []string{"default"},
+				Formats:    []string{"deb", "rpm", "apk", "archlinux"},
+				Maintainer: "me@me",
+				NFPMOverridables: config.NFPMOverridables{
+					PackageName: "foo",
+					Scripts: config.NFPMScripts{
+						PostInstall: "testdata/postinstall.sh",
+					},
+				},
+				Systemd: []config.NFPMSystemd{
+					{
+						Name:        "mybin.service",
+						Description: "{{ .ProjectName }} daemon",
+						User:        "nobody",
+						Environment: []string{"VERSION={{ .Version }}"},
+					},
+					{
+						Name:      "mybin-cleanup",
+						ExecStart: "/usr/bin/mybin cleanup",
+						Timer: &config.NFPMSystemdTimer{
+							OnCalendar: "daily",
+							Persistent: true,
+						},
+					},
+				},
+			},
+		},
+	})
+	ctx.Version = "1.0.0"
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "mybin",
+		Path:   binPath,
+		Goarch: "amd64",
+		Goos:   "linux",
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			artifact.ExtraID: "default",
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	unitDirs := map[string]string{
+		"deb":       "/lib/systemd/system",
+		"rpm":       "/usr/lib/systemd/system",
+		"archlinux": "/usr/lib/systemd/system",
+	}
+	packages := ctx.Artifacts.Filter(artifact.ByType(artifact.LinuxPackage)).List()
+	require.Len(t, packages, 4)
+	for _, pkg := range packages {
+		format := pkg.Format()
+		dests := destinations(artifact.ExtraOr(*pkg, extraFiles, files.Contents{}))
+		dir, ok := unitDirs[format]
+		if !ok {
+			require.Equal(t, []string{"/usr/bin/mybin"}, dests, format)
+			continue
+		}
+		require.ElementsMatch(t, []string{
+			"/usr/bin/mybin",
+			dir + "/mybin.service",
+			dir + "/mybin-cleanup.service",
+			dir + "/mybin-cleanup.timer",
+		}, dests, format)
+	}
+
+	systemd := filepath.Join(dist, "deb", "foo_amd64", "systemd")
+	bts, err := os.ReadFile(filepath.Join(systemd, "mybin.service"))
+	require.NoError(t, err)
+	require.Equal(t, `[Unit]
+Description=mybin daemon
+After=network.target
+
+[Service]
+Type=simple
+User=nobody
+Environment="VERSION=1.0.0"
+ExecStart=/usr/bin/mybin
+Restart=on-failure
+
+[Install]
+WantedBy=multi-user.target
+`, string(bts))
+
+	bts, err = os.ReadFile(filepath.Join(systemd, "mybin-cleanup.service"))
+	require.NoError(t, err)
+	require.Equal(t, `[Unit]
+Description=mybin-cleanup
+
+[Service]
+Type=oneshot
+ExecStart=/usr/bin/mybin cleanup
+`, string(bts))
+
+	bts, err = os.ReadFile(filepath.Join(systemd, "mybin-cleanup.timer"))
+	require.NoError(t, err)
+	require.Equal(t, `[Unit]
+Description=Timer for mybin-cleanup.service
+
+[Timer]
+OnCalendar=daily
+Persistent=true
+
+[Install]
+WantedBy=timers.target
+`, string(bts))
+
+	bts, err = os.ReadFile(filepath.Join(systemd, "postinstall.sh"))
+	require.NoError(t, err)
+	require.Contains(t, string(bts), "deb-systemd-helper enable 'mybin.service'")
+	require.Contains(t, string(bts), "deb-systemd-helper enable 'mybin-cleanup.timer'")
+	require.Contains(t, string(bts), "deb-systemd-invoke start mybin.service mybin-cleanup.timer")
+	require.Contains(t, string(bts), `echo "installed"`)
+
+	bts, err = os.ReadFile(filepath.Join(dist, "rpm", "foo_amd64", "systemd", "postinstall.sh"))
+	require.NoError(t, err)
+	require.Contains(t, string(bts), "systemctl --no-reload preset mybin.service mybin-cleanup.timer")
+	require.Contains(t, string(bts), `echo "installed"`)
+
+	require.NoFileExists(t, filepath.Join(dist, "archlinux", "foo_amd64", "systemd", "postinstall.sh"))
+	require.NoDirExists(t, filepath.Join(dist, "apk", "foo_amd64"))
+}
+
+func TestSystemdInvalid(t *testing.T) {
+	for name, unit := range map[string]config.NFPMSystemd{
+		"no name": {},
+		"timer without schedule": {
+			Name:  "foo",
+			Timer: &config.NFPMSystemdTimer{Persistent: true},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				NFPMs: []config.NFPM{
+					{Systemd: []config.NFPMSystemd{unit}},
+				},
+			})
+			require.ErrorContains(t, Pipe{}.Default(ctx), "invalid nfpms.systemd")
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	folder := t.TempDir()
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	require.NoError(t, os.Mkdir(filepath.Join(dist, "mybin"), 0o755))
+	binPath := filepath.Join(dist, "mybin", "mybin")
+	f, err := os.Create(binPath)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	ctx := context.New(config.Project{
+		ProjectName: "mybin",
+		Dist:        dist,
+		NFPMs: []config.NFPM{
+			{
+				ID:         "someid",
+				Builds:     []string{"default"},
+				Formats:    []string{"deb", "rpm"},
+				Maintainer: "me@me",
+				NFPMOverridables: config.NFPMOverridables{
+					PackageName: "foo",
+					Deb: config.NFPMDeb{
+						Compression: "zstd",
+					},
+					RPM: config.NFPMRPM{
+						Compression: "zstd",
+					},
+				},
+			},
+		},
+	})
+	ctx.Version = "1.0.0"
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "mybin",
+		Path:   binPath,
+		Goarch: "amd64",
+		Goos:   "linux",
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			artifact.ExtraID: "default",
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	packages := ctx.Artifacts.Filter(artifact.ByType(artifact.LinuxPackage)).List()
+	require.Len(t, packages, 2)
+	for _, pkg := range packages {
+		bts, err := os.ReadFile(pkg.Path)
+		require.NoError(t, err)
+		switch pkg.Format() {
+		case "deb":
+			require.Contains(t, arEntries(t, bts), "data.tar.zst")
+		case "rpm":
+			require.Equal(t, "zstd", rpmHeaderString(t, bts, rpmTagPayloadCompressor))
+		}
+	}
+}
+
+// arEntries lists the file names inside the given ar archive, e.g. a deb.
+func arEntries(tb testing.TB, bts []byte) []string {
+	tb.Helper()
+	const magic = "!<arch>\n"
+	require.True(tb, strings.HasPrefix(string(bts), magic))
+	var names []string
+	for off := len(magic); off+60 <= len(bts); {
+		header := bts[off : off+60]
+		names = append(names, strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/"))
+		size, err := strconv.Atoi(strings.TrimSpace(string(header[48:58])))
+		require.NoError(tb, err)
+		off += 60 + size + size%2
+	}
+	return names
+}
+
+// rpmTagPayloadCompressor is the rpm header tag holding the name of the
+// payload compressor.
+const rpmTagPayloadCompressor = 1125
+
+// rpmHeaderString reads the given string tag from the main header of the
+// given rpm package, see
+// https://rpm-software-management.github.io/rpm/manual/format.html.
+func rpmHeaderString(tb testing.TB, bts []byte, tag uint32) string {
+	tb.Helper()
+	// headers start after the 96 bytes lead.
+	off := 96
+	readHeader := func() (index, store []byte) {
+		tb.Helper()
+		require.GreaterOrEqual(tb, len(bts), off+16)
+		require.Equal(tb, []byte{0x8e, 0xad, 0xe8, 0x01}, bts[off:off+4])
+		count := int(binary.BigEndian.Uint32(bts[off+8:]))
+		size := int(binary.BigEndian.Uint32(bts[off+12:]))
+		start := off + 16
+		end := start + count*16 + size
+		require.GreaterOrEqual(tb, len(bts), end)
+		index, store = bts[start:start+count*16], bts[start+count*16:end]
+		off = end
+		return index, store
+	}
+
+	// the signature header comes first, padded to a multiple of 8 bytes.
+	readHeader()
+	off += (8 - off%8) % 8
+
+	index, store := readHeader()
+	for i := 0; i < len(index); i += 16 {
+		if binary.BigEndian.Uint32(index[i:]) != tag {
+			continue
+		}
+		value, _, _ := bytes.Cut(store[binary.BigEndian.Uint32(index[i+8:]):], []byte{0})
+		return string(value)
+	}
+	tb.Fatalf("rpm header tag %d not found", tag)
+	return ""
+}
+
+func TestInvalidCompression(t *testing.T) {
+	for name, fpm := range map[string]config.NFPM{
+		"deb": {
+			NFPMOverridables: config.NFPMOverridables{
+				Deb: config.NFPMDeb{Compression: "lzma"},
+			},
+		},
+		"rpm": {
+			NFPMOverridables: config.NFPMOverridables{
+				RPM: config.NFPMRPM{Compression: "none"},
+			},
+		},
+		"override": {
+			Overrides: map[string]config.NFPMOverridables{
+				"deb": {Deb: config.NFPMDeb{Compression: "bzip2"}},
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				NFPMs: []config.NFPM{fpm},
+			})
+			require.ErrorContains(t, Pipe{}.Default(ctx), "compression")
+		})
+	}
+
+	ctx := context.New(config.Project{
+		NFPMs: []config.NFPM{
+			{
+				NFPMOverridables: config.NFPMOverridables{
+					Deb: config.NFPMDeb{Compression: "none"},
+					RPM: config.NFPMRPM{Compression: "zstd:19"},
+				},
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+}
+
+func TestGenerateChangelog(t *testing.T) {
+	now := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	notes := `## Changelog
+### New Features
+* 1a2b3c4d feat: add the [thing](https://example.com/thing)
+* 5e6f7a8b fix: handle   long
+### Others
+- docs: update readme (@someone)
+`
+	ctx := context.New(config.Project{})
+	ctx.Version = "1.0.0-rc1"
+	ctx.Git.CommitDate = now
+	ctx.ReleaseNotes = notes
+
+	changes := []chglogChange{
+		{Commit: "1a2b3c4d", Note: "feat: add the thing"},
+		{Commit: "5e6f7a8b", Note: "fix: handle long"},
+		{Note: "docs: update readme (@someone)"},
+	}
+
+	t.Run("deb", func(t *testing.T) {
+		require.Equal(t, []chglogEntry{
+			{
+				Semver:   "1.0.0~rc1",
+				Date:     now,
+				Packager: "Foo <foo@bar>",
+				Deb: &chglogDeb{
+					Urgency:       "low",
+					Distributions: []string{"stable"},
+				},
+				Changes: changes,
+			},
+		}, toChglog(ctx, "deb", "Foo <foo@bar>"))
+	})
+
+	t.Run("rpm", func(t *testing.T) {
+		require.Equal(t, []chglogEntry{
+			{
+				Semver:   "1.0.0~rc1",
+				Date:     now,
+				Packager: "Foo <foo@bar>",
+				Changes:  changes,
+			},
+		}, toChglog(ctx, "rpm", "Foo <foo@bar>"))
+	})
+
+	t.Run("write", func(t *testing.T) {
+		ctx := context.New(config.Project{Dist: t.TempDir()})
+		ctx.Version = "1.0.0"
+		ctx.Git.CommitDate = now
+		ctx.ReleaseNotes = notes
+		fpm := config.NFPM{
+			GenerateChangelog: true,
+			NFPMOverridables:  config.NFPMOverridables{PackageName: "foo"},
+		}
+
+		path, err := releaseNotesChangelog(ctx, fpm, "deb", "amd64", "Foo <foo@bar>")
+		require.NoError(t, err)
+		require.Equal(t, filepath.Join(ctx.Config.Dist, "deb", "foo_amd64", "changelog.yml"), path)
+		bts, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Contains(t, string(bts), "semver: 1.0.0\n")
+		require.Contains(t, string(bts), "urgency: low\n")
+		require.Contains(t, string(bts), "commit: 1a2b3c4d\n")
+
+		path, err = releaseNotesChangelog(ctx, fpm, "apk", "amd64", "Foo <foo@bar>")
+		require.NoError(t, err)
+		require.Empty(t, path)
+	})
+
+	t.Run("no release notes", func(t *testing.T) {
+		ctx := context.New(config.Project{Dist: t.TempDir()})
+		path, err := releaseNotesChangelog(ctx, config.NFPM{GenerateChangelog: true}, "rpm", "amd64", "")
+		require.NoError(t, err)
+		require.Empty(t, path)
+	})
+
+	t.Run("with changelog file", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			NFPMs: []config.NFPM{
+				{
+					Changelog:         "./testdata/changelog.yaml",
+					GenerateChangelog: true,
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), "invalid nfpms.default: changelog and generate_changelog can't be used together")
+	})
+}
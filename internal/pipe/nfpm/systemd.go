@@ -0,0 +1,239 @@
+package nfpm
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/goreleaser/nfpm/v2/files"
+)
+
+// systemdUnitDirs are the directories in which each package format expects
+// the systemd units to be.
+// Formats not listed here don't get systemd units.
+var systemdUnitDirs = map[string]string{
+	"deb":       "/lib/systemd/system",
+	"rpm":       "/usr/lib/systemd/system",
+	"archlinux": "/usr/lib/systemd/system",
+}
+
+func defaultSystemd(units []config.NFPMSystemd) error {
+	for i := range units {
+		unit := &units[i]
+		unit.Name = strings.TrimSuffix(unit.Name, ".service")
+		if unit.Name == "" {
+			return fmt.Errorf("invalid nfpms.systemd: name is required")
+		}
+		if unit.Timer != nil {
+			if unit.Timer.OnCalendar == "" && unit.Timer.OnBootSec == "" && unit.Timer.OnUnitActiveSec == "" {
+				return fmt.Errorf("invalid nfpms.systemd: %s: timer needs at least one of on_calendar, on_boot_sec or on_unit_active_sec", unit.Name)
+			}
+			continue
+		}
+		if unit.Restart == "" {
+			unit.Restart = "on-failure"
+		}
+		if unit.WantedBy == "" {
+			unit.WantedBy = "multi-user.target"
+		}
+	}
+	return nil
+}
+
+// systemdContents writes the systemd units of the given package, returning
+// them as package contents, alongside the names of the units that should be
+// enabled on install.
+func systemdContents(ctx *context.Context, t *tmpl.Template, fpm config.NFPM, format, arch, binDir string, binaries []*artifact.Artifact) (files.Contents, []string, error) {
+	unitDir, ok := systemdUnitDirs[format]
+	if !ok || len(fpm.Systemd) == 0 {
+		return nil, nil, nil
+	}
+
+	dir := filepath.Join(ctx.Config.Dist, format, fpm.PackageName+"_"+arch, "systemd")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, nil, fmt.Errorf("failed to write systemd units: %w", err)
+	}
+
+	var contents files.Contents
+	var enable []string
+	for _, unit := range fpm.Systemd {
+		service, err := serviceUnit(t, unit, binDir, binaries)
+		if err != nil {
+			return nil, nil, err
+		}
+		units := [][2]string{{unit.Name + ".service", service}}
+		if unit.Timer != nil {
+			units = append(units, [2]string{unit.Name + ".timer", timerUnit(unit)})
+			enable = append(enable, unit.Name+".timer")
+		} else {
+			enable = append(enable, unit.Name+".service")
+		}
+
+		for _, u := range units {
+			src := filepath.Join(dir, u[0])
+			log.Debugf("creating %q", src)
+			if err := os.WriteFile(src, []byte(u[1]), 0o644); err != nil {
+				return nil, nil, fmt.Errorf("failed to write systemd unit: %w", err)
+			}
+			contents = append(contents, &files.Content{
+				Source:      src,
+				Destination: path.Join(unitDir, u[0]),
+				FileInfo: &files.ContentFileInfo{
+					Mode: 0o644,
+				},
+			})
+		}
+	}
+	return contents, enable, nil
+}
+
+func serviceUnit(t *tmpl.Template, unit config.NFPMSystemd, binDir string, binaries []*artifact.Artifact) (string, error) {
+	description, err := t.Apply(unit.Description)
+	if err != nil {
+		return "", err
+	}
+	if description == "" {
+		description = unit.Name
+	}
+
+	execStart, err := t.Apply(unit.ExecStart)
+	if err != nil {
+		return "", err
+	}
+	if execStart == "" {
+		execStart = path.Join(binDir, binaries[0].Name)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("[Unit]\n")
+	sb.WriteString("Description=" + description + "\n")
+	if unit.Timer == nil {
+		sb.WriteString("After=network.target\n")
+	}
+
+	sb.WriteString("\n[Service]\n")
+	if unit.Timer == nil {
+		sb.WriteString("Type=simple\n")
+	} else {
+		sb.WriteString("Type=oneshot\n")
+	}
+	if unit.User != "" {
+		sb.WriteString("User=" + unit.User + "\n")
+	}
+	for _, envTemplate := range unit.Environment {
+		env, err := t.Apply(envTemplate)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(fmt.Sprintf("Environment=%q\n", env))
+	}
+	sb.WriteString("ExecStart=" + execStart + "\n")
+	if unit.Timer != nil {
+		// the service is activated by the timer.
+		return sb.String(), nil
+	}
+	sb.WriteString("Restart=" + unit.Restart + "\n")
+
+	sb.WriteString("\n[Install]\n")
+	sb.WriteString("WantedBy=" + unit.WantedBy + "\n")
+	return sb.String(), nil
+}
+
+func timerUnit(unit config.NFPMSystemd) string {
+	var sb strings.Builder
+	sb.WriteString("[Unit]\n")
+	sb.WriteString("Description=Timer for " + unit.Name + ".service\n")
+
+	sb.WriteString("\n[Timer]\n")
+	if unit.Timer.OnCalendar != "" {
+		sb.WriteString("OnCalendar=" + unit.Timer.OnCalendar + "\n")
+	}
+	if unit.Timer.OnBootSec != "" {
+		sb.WriteString("OnBootSec=" + unit.Timer.OnBootSec + "\n")
+	}
+	if unit.Timer.OnUnitActiveSec != "" {
+		sb.WriteString("OnUnitActiveSec=" + unit.Timer.OnUnitActiveSec + "\n")
+	}
+	if unit.Timer.Persistent {
+		sb.WriteString("Persistent=true\n")
+	}
+
+	sb.WriteString("\n[Install]\n")
+	sb.WriteString("WantedBy=timers.target\n")
+	return sb.String()
+}
+
+// systemdPostInstall writes a post install script that enables the given
+// units following the conventions of the given format, followed by the
+// contents of the user provided post install script, if any.
+// It returns the path of the post install script to use.
+func systemdPostInstall(ctx *context.Context, fpm config.NFPM, format, arch string, units []string, postInstall string) (string, error) {
+	if len(units) == 0 {
+		return postInstall, nil
+	}
+
+	var enable string
+	switch format {
+	case "deb":
+		enable = debEnable(units)
+	case "rpm":
+		enable = rpmEnable(units)
+	}
+	if enable == "" {
+		return postInstall, nil
+	}
+
+	script := "#!/bin/sh\n" + enable
+	if postInstall != "" {
+		bts, err := os.ReadFile(postInstall)
+		if err != nil {
+			return "", fmt.Errorf("failed to read postinstall script: %w", err)
+		}
+		script += "\n" + string(bts)
+	}
+
+	scriptPath := filepath.Join(ctx.Config.Dist, format, fpm.PackageName+"_"+arch, "systemd", "postinstall.sh")
+	log.Debugf("creating %q", scriptPath)
+	if err := os.WriteFile(scriptPath, []byte(script), 0o755); err != nil {
+		return "", fmt.Errorf("failed to write postinstall script: %w", err)
+	}
+	return scriptPath, nil
+}
+
+// debEnable mimics dh_installsystemd: units are enabled by default, and
+// started if systemd is running.
+func debEnable(units []string) string {
+	var sb strings.Builder
+	sb.WriteString(`if [ "$1" = "configure" ] || [ "$1" = "abort-upgrade" ] || [ "$1" = "abort-deconfigure" ] || [ "$1" = "abort-remove" ]; then` + "\n")
+	for _, unit := range units {
+		sb.WriteString(fmt.Sprintf("\tdeb-systemd-helper unmask '%s' >/dev/null || true\n", unit))
+		sb.WriteString(fmt.Sprintf("\tif deb-systemd-helper --quiet was-enabled '%s'; then\n", unit))
+		sb.WriteString(fmt.Sprintf("\t\tdeb-systemd-helper enable '%s' >/dev/null || true\n", unit))
+		sb.WriteString("\telse\n")
+		sb.WriteString(fmt.Sprintf("\t\tdeb-systemd-helper update-state '%s' >/dev/null || true\n", unit))
+		sb.WriteString("\tfi\n")
+	}
+	sb.WriteString("\tif [ -d /run/systemd/system ]; then\n")
+	sb.WriteString("\t\tsystemctl --system daemon-reload >/dev/null || true\n")
+	sb.WriteString("\t\tdeb-systemd-invoke start " + strings.Join(units, " ") + " >/dev/null || true\n")
+	sb.WriteString("\tfi\n")
+	sb.WriteString("fi\n")
+	return sb.String()
+}
+
+// rpmEnable mimics the %systemd_post macro: on first install, units are
+// enabled or not according to the system presets.
+func rpmEnable(units []string) string {
+	var sb strings.Builder
+	sb.WriteString(`if [ "$1" -eq 1 ] && command -v systemctl >/dev/null 2>&1; then` + "\n")
+	sb.WriteString("\tsystemctl --no-reload preset " + strings.Join(units, " ") + " >/dev/null 2>&1 || :\n")
+	sb.WriteString("fi\n")
+	return sb.String()
+}
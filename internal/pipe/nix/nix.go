@@ -0,0 +1,370 @@
+// Package nix implements Piper and Publisher, providing nix derivations and
+// flakes creation and upload to a repository.
+package nix
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/commitauthor"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const (
+	nixConfigExtra = "NixConfig"
+
+	formatClassic = "classic"
+	formatFlake   = "flake"
+)
+
+// ErrNoArchivesFound happens when there are no linux or darwin archives
+// that nix can use.
+var ErrNoArchivesFound = errors.New("no linux or macos archives found")
+
+var errNoRepoName = errors.New("nix.repository.name is required")
+
+var errInvalidLicense = errors.New("nix.license must be a nixpkgs license identifier, e.g. mit")
+
+// validLicense matches the nix identifiers, as the license is used as an
+// attribute of pkgs.lib.licenses.
+// nolint: gochecknoglobals
+var validLicense = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_'-]*$`)
+
+// Pipe for nix derivations and flakes.
+type Pipe struct{}
+
+func (Pipe) String() string                 { return "nixpkgs" }
+func (Pipe) Skip(ctx *context.Context) bool { return len(ctx.Config.Nix) == 0 }
+
+func (Pipe) Default(ctx *context.Context) error {
+	for i := range ctx.Config.Nix {
+		nix := &ctx.Config.Nix[i]
+
+		nix.CommitAuthor = commitauthor.Default(nix.CommitAuthor)
+		if err := client.CheckSigning(nix.CommitAuthor, nix.Repository); err != nil {
+			return err
+		}
+		if nix.CommitMessageTemplate == "" {
+			nix.CommitMessageTemplate = "{{ .ProjectName }}: {{ .PreviousTag }} -> {{ .Tag }}"
+		}
+		if nix.Name == "" {
+			nix.Name = ctx.Config.ProjectName
+		}
+		if nix.Goamd64 == "" {
+			nix.Goamd64 = "v1"
+		}
+		switch nix.Format {
+		case "":
+			nix.Format = formatClassic
+		case formatClassic, formatFlake:
+		default:
+			return fmt.Errorf("invalid nix format: %s, valid options are: %s, %s", nix.Format, formatClassic, formatFlake)
+		}
+		if nix.Path == "" {
+			if nix.Format == formatFlake {
+				nix.Path = "flake.nix"
+			} else {
+				nix.Path = path.Join("pkgs", nix.Name, "default.nix")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (Pipe) Run(ctx *context.Context) error {
+	cli, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runAll(ctx, cli)
+}
+
+func runAll(ctx *context.Context, cli client.Client) error {
+	for _, nix := range ctx.Config.Nix {
+		if err := doRun(ctx, nix, cli); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func doRun(ctx *context.Context, nix config.Nix, cl client.Client) error {
+	if nix.Repository.Name == "" {
+		return errNoRepoName
+	}
+
+	filters := []artifact.Filter{
+		artifact.Or(
+			artifact.ByGoos("linux"),
+			artifact.ByGoos("darwin"),
+		),
+		artifact.Or(
+			artifact.And(
+				artifact.ByGoarch("amd64"),
+				artifact.ByGoamd64(nix.Goamd64),
+			),
+			artifact.ByGoarch("arm64"),
+			artifact.ByGoarch("386"),
+			artifact.ByGoarch("arm"),
+			artifact.ByGoarch("all"),
+		),
+		artifact.ByType(artifact.UploadableArchive),
+		artifact.ByFormats("tar.gz", "tgz", "tar.xz", "txz", "zip"),
+		artifact.OnlyReplacingUnibins,
+	}
+	if len(nix.IDs) > 0 {
+		filters = append(filters, artifact.ByIDs(nix.IDs...))
+	}
+
+	archives := ctx.Artifacts.Filter(artifact.And(filters...)).List()
+	if len(archives) == 0 {
+		return ErrNoArchivesFound
+	}
+
+	nix, err := templateFields(ctx, nix)
+	if err != nil {
+		return err
+	}
+
+	data, err := dataFor(ctx, nix, cl, archives)
+	if err != nil {
+		return err
+	}
+
+	tpl := classicTemplate
+	if nix.Format == formatFlake {
+		tpl = flakeTemplate
+	}
+	content, err := doBuildPkg(tpl, data)
+	if err != nil {
+		return err
+	}
+
+	filename := path.Base(nix.Path)
+	folder := filepath.Join(ctx.Config.Dist, "nix", nix.Name)
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		return fmt.Errorf("failed to create nix folder: %w", err)
+	}
+	nixPath := filepath.Join(folder, filename)
+	log.WithField("nixpkg", nixPath).Info("writing")
+	if err := os.WriteFile(nixPath, []byte(content), 0o644); err != nil { //nolint: gosec
+		return fmt.Errorf("failed to write nixpkg: %w", err)
+	}
+
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: filename,
+		Path: nixPath,
+		Type: artifact.Nixpkg,
+		Extra: map[string]interface{}{
+			nixConfigExtra: nix,
+		},
+	})
+
+	return nil
+}
+
+func templateFields(ctx *context.Context, nix config.Nix) (config.Nix, error) {
+	t := tmpl.New(ctx)
+	for _, s := range []*string{
+		&nix.Name,
+		&nix.Path,
+		&nix.Description,
+		&nix.Homepage,
+		&nix.License,
+	} {
+		var err error
+		*s, err = t.Apply(*s)
+		if err != nil {
+			return config.Nix{}, err
+		}
+	}
+	if nix.License != "" && !validLicense.MatchString(nix.License) {
+		return config.Nix{}, fmt.Errorf("%w: %s", errInvalidLicense, nix.License)
+	}
+	return nix, nil
+}
+
+func dataFor(ctx *context.Context, nix config.Nix, cl client.Client, archives []*artifact.Artifact) (templateData, error) {
+	data := templateData{
+		Name:        nix.Name,
+		Version:     ctx.Version,
+		Description: nix.Description,
+		Homepage:    nix.Homepage,
+		License:     nix.License,
+		Archives:    map[string]archive{},
+	}
+
+	if nix.URLTemplate == "" {
+		url, err := cl.ReleaseURLTemplate(ctx)
+		if err != nil {
+			return data, err
+		}
+		nix.URLTemplate = url
+	}
+
+	for _, art := range archives {
+		sum, err := art.Checksum("sha256")
+		if err != nil {
+			return data, err
+		}
+
+		url, err := tmpl.New(ctx).WithArtifact(art).Apply(nix.URLTemplate)
+		if err != nil {
+			return data, err
+		}
+
+		sourceRoot := artifact.ExtraOr(*art, artifact.ExtraWrappedIn, "")
+		if sourceRoot == "" {
+			sourceRoot = "."
+		}
+
+		for _, system := range systemsFor(art) {
+			if _, ok := data.Archives[system]; ok {
+				return data, fmt.Errorf("nix: multiple archives found for %s, use ids to filter them", system)
+			}
+			data.Archives[system] = archive{
+				URL:        url,
+				Sha256:     sum,
+				SourceRoot: sourceRoot,
+			}
+		}
+
+		if len(data.Binaries) == 0 {
+			data.Binaries = artifact.ExtraOr(*art, artifact.ExtraBinaries, []string{})
+		}
+		if art.Format() == "zip" {
+			data.Zip = true
+		}
+	}
+
+	return data, nil
+}
+
+// systemsFor returns the nix systems the given archive can be installed in.
+func systemsFor(art *artifact.Artifact) []string {
+	var arch string
+	switch art.Goarch {
+	case "all":
+		return []string{"aarch64-darwin", "x86_64-darwin"}
+	case "amd64":
+		arch = "x86_64"
+	case "arm64":
+		arch = "aarch64"
+	case "386":
+		arch = "i686"
+	case "arm":
+		arch = "armv" + art.Goarm + "l"
+	default:
+		return nil
+	}
+	return []string{arch + "-" + art.Goos}
+}
+
+func doBuildPkg(tpl string, data templateData) (string, error) {
+	t, err := template.New("nix").
+		Funcs(template.FuncMap{"escape": escapeString}).
+		Parse(tpl)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	if err := t.Execute(&out, data); err != nil {
+		return "", err
+	}
+
+	// Sanitize the template output and get rid of trailing whitespace.
+	var result strings.Builder
+	s := bufio.NewScanner(&out)
+	for s.Scan() {
+		result.WriteString(strings.TrimRight(s.Text(), " "))
+		result.WriteString("\n")
+	}
+	return result.String(), s.Err()
+}
+
+// Publish nix derivations and flakes.
+func (Pipe) Publish(ctx *context.Context) error {
+	cli, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	return publishAll(ctx, cli)
+}
+
+func publishAll(ctx *context.Context, cli client.Client) error {
+	skips := pipe.SkipMemento{}
+	for _, nixpkg := range ctx.Artifacts.Filter(artifact.ByType(artifact.Nixpkg)).List() {
+		err := doPublish(ctx, nixpkg, cli)
+		if err != nil && pipe.IsSkip(err) {
+			skips.Remember(err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return skips.Evaluate()
+}
+
+func doPublish(ctx *context.Context, nixpkg *artifact.Artifact, cl client.Client) error {
+	nix, err := artifact.Extra[config.Nix](*nixpkg, nixConfigExtra)
+	if err != nil {
+		return err
+	}
+
+	cl, err = client.NewIfToken(ctx, cl, nix.Repository.Token)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(nix.SkipUpload) == "true" {
+		return pipe.Skip("nix.skip_upload is set")
+	}
+
+	if strings.TrimSpace(nix.SkipUpload) == "auto" && ctx.Semver.Prerelease != "" {
+		return pipe.Skip("prerelease detected with 'auto' upload, skipping nix publish")
+	}
+
+	ref, err := client.TemplateRef(tmpl.New(ctx).Apply, nix.Repository)
+	if err != nil {
+		return err
+	}
+	nix.Repository = ref
+	repo := client.RepoFromRef(nix.Repository)
+
+	msg, err := tmpl.New(ctx).Apply(nix.CommitMessageTemplate)
+	if err != nil {
+		return err
+	}
+
+	author, err := commitauthor.Get(ctx, nix.CommitAuthor)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(nixpkg.Path)
+	if err != nil {
+		return err
+	}
+
+	log.WithField("nixpkg", nix.Path).
+		WithField("repo", repo.String()).
+		Info("pushing")
+	return client.NewIfGitURL(cl, nix.Repository).CreateFile(ctx, author, repo, content, nix.Path, msg)
+}
@@ -0,0 +1,295 @@
+package nix
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/golden"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescription(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Nix: []config.Nix{{}},
+		})
+		require.False(t, Pipe{}.Skip(ctx))
+	})
+}
+
+func TestDefault(t *testing.T) {
+	t.Run("classic", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			ProjectName: "foo",
+			Nix:         []config.Nix{{}},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		nix := ctx.Config.Nix[0]
+		require.Equal(t, "foo", nix.Name)
+		require.Equal(t, "classic", nix.Format)
+		require.Equal(t, "pkgs/foo/default.nix", nix.Path)
+		require.Equal(t, "v1", nix.Goamd64)
+		require.NotEmpty(t, nix.CommitMessageTemplate)
+		require.NotEmpty(t, nix.CommitAuthor.Name)
+	})
+
+	t.Run("flake", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			ProjectName: "foo",
+			Nix:         []config.Nix{{Format: "flake"}},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.Equal(t, "flake.nix", ctx.Config.Nix[0].Path)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			ProjectName: "foo",
+			Nix:         []config.Nix{{Format: "nope"}},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), "invalid nix format: nope, valid options are: classic, flake")
+	})
+}
+
+func TestDefaultSigningWithoutGit(t *testing.T) {
+	ctx := context.New(config.Project{
+		Nix: []config.Nix{
+			{
+				CommitAuthor: config.CommitAuthor{
+					Signing: config.CommitSigning{Enabled: true},
+				},
+			},
+		},
+	})
+	require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrSigningRequiresGit)
+}
+
+func newContext(tb testing.TB, nix config.Nix) *context.Context {
+	tb.Helper()
+	ctx := context.New(config.Project{
+		Dist:        tb.TempDir(),
+		ProjectName: "foo",
+		Nix:         []config.Nix{nix},
+	})
+	ctx.Git.CurrentTag = "v1.2.1"
+	ctx.Version = "1.2.1"
+	require.NoError(tb, Pipe{}.Default(ctx))
+	return ctx
+}
+
+func addArchive(tb testing.TB, ctx *context.Context, goos, goarch, content string) {
+	tb.Helper()
+	name := "foo_" + goos + "_" + goarch + ".tar.gz"
+	path := filepath.Join(tb.TempDir(), name)
+	require.NoError(tb, os.WriteFile(path, []byte(content), 0o644))
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    name,
+		Path:    path,
+		Goos:    goos,
+		Goarch:  goarch,
+		Goamd64: "v1",
+		Type:    artifact.UploadableArchive,
+		Extra: map[string]interface{}{
+			artifact.ExtraID:        "foo",
+			artifact.ExtraFormat:    "tar.gz",
+			artifact.ExtraWrappedIn: "",
+			artifact.ExtraBinaries:  []string{"foo"},
+		},
+	})
+}
+
+func TestRunPipe(t *testing.T) {
+	for _, format := range []string{"classic", "flake"} {
+		t.Run(format, func(t *testing.T) {
+			ctx := newContext(t, config.Nix{
+				Format:      format,
+				Description: "foo is a test package",
+				Homepage:    "https://goreleaser.com",
+				License:     "mit",
+				Repository: config.RepoRef{
+					Owner: "foo",
+					Name:  "nur",
+				},
+			})
+			addArchive(t, ctx, "linux", "amd64", "linux amd64")
+			addArchive(t, ctx, "linux", "arm64", "linux arm64")
+			addArchive(t, ctx, "darwin", "all", "darwin all")
+			addArchive(t, ctx, "windows", "amd64", "ignored")
+
+			cli := client.NewMock()
+			require.NoError(t, runAll(ctx, cli))
+
+			nixpkgs := ctx.Artifacts.Filter(artifact.ByType(artifact.Nixpkg)).List()
+			require.Len(t, nixpkgs, 1)
+
+			content := golden.RequireReadFile(t, nixpkgs[0].Path)
+			golden.RequireEqualExt(t, content, ".nix")
+
+			require.NoError(t, publishAll(ctx, cli))
+			require.True(t, cli.CreatedFile)
+			require.Equal(t, ctx.Config.Nix[0].Path, cli.Path)
+			require.Equal(t, string(content), cli.Content)
+		})
+	}
+}
+
+func TestRunPipeWrappedZip(t *testing.T) {
+	ctx := newContext(t, config.Nix{
+		Repository: config.RepoRef{
+			Owner: "foo",
+			Name:  "nur",
+		},
+	})
+	path := filepath.Join(t.TempDir(), "foo.zip")
+	require.NoError(t, os.WriteFile(path, []byte("zip"), 0o644))
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "foo.zip",
+		Path:   path,
+		Goos:   "linux",
+		Goarch: "arm",
+		Goarm:  "7",
+		Type:   artifact.UploadableArchive,
+		Extra: map[string]interface{}{
+			artifact.ExtraFormat:    "zip",
+			artifact.ExtraWrappedIn: "foo_linux_armv7",
+			artifact.ExtraBinaries:  []string{"foo", "bar"},
+		},
+	})
+
+	require.NoError(t, runAll(ctx, client.NewMock()))
+	nixpkgs := ctx.Artifacts.Filter(artifact.ByType(artifact.Nixpkg)).List()
+	require.Len(t, nixpkgs, 1)
+
+	content := string(golden.RequireReadFile(t, nixpkgs[0].Path))
+	require.Contains(t, content, "armv7l-linux = {")
+	require.Contains(t, content, `sourceRoot = "foo_linux_armv7";`)
+	require.Contains(t, content, "nativeBuildInputs = [ pkgs.unzip ];")
+	require.Contains(t, content, "cp -vr ./foo $out/bin/foo")
+	require.Contains(t, content, "cp -vr ./bar $out/bin/bar")
+	require.NotContains(t, content, "pkgs.lib.licenses")
+}
+
+func TestRunPipeErrors(t *testing.T) {
+	t.Run("no repository", func(t *testing.T) {
+		ctx := newContext(t, config.Nix{})
+		addArchive(t, ctx, "linux", "amd64", "a")
+		require.ErrorIs(t, runAll(ctx, client.NewMock()), errNoRepoName)
+	})
+
+	t.Run("no archives", func(t *testing.T) {
+		ctx := newContext(t, config.Nix{
+			Repository: config.RepoRef{Name: "nur"},
+		})
+		addArchive(t, ctx, "windows", "amd64", "a")
+		require.ErrorIs(t, runAll(ctx, client.NewMock()), ErrNoArchivesFound)
+	})
+
+	t.Run("multiple archives for the same system", func(t *testing.T) {
+		ctx := newContext(t, config.Nix{
+			Repository: config.RepoRef{Name: "nur"},
+		})
+		addArchive(t, ctx, "linux", "amd64", "a")
+		addArchive(t, ctx, "linux", "amd64", "b")
+		require.EqualError(t, runAll(ctx, client.NewMock()), "nix: multiple archives found for x86_64-linux, use ids to filter them")
+	})
+
+	t.Run("invalid license", func(t *testing.T) {
+		ctx := newContext(t, config.Nix{
+			License:    "mit; foo = bar",
+			Repository: config.RepoRef{Name: "nur"},
+		})
+		addArchive(t, ctx, "linux", "amd64", "a")
+		require.ErrorIs(t, runAll(ctx, client.NewMock()), errInvalidLicense)
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		ctx := newContext(t, config.Nix{
+			Description: "{{ .Nope }}",
+			Repository:  config.RepoRef{Name: "nur"},
+		})
+		addArchive(t, ctx, "linux", "amd64", "a")
+		testlib.RequireTemplateError(t, runAll(ctx, client.NewMock()))
+	})
+}
+
+func TestEscapeString(t *testing.T) {
+	require.Equal(t, `a \"quoted\" \\ \${path} $HOME`, escapeString(`a "quoted" \ ${path} $HOME`))
+}
+
+func TestRunPipeEscapesStrings(t *testing.T) {
+	ctx := newContext(t, config.Nix{
+		Description: `foo "bar" ${baz}`,
+		Homepage:    `https://example.com/\`,
+		Repository:  config.RepoRef{Name: "nur"},
+	})
+	addArchive(t, ctx, "linux", "amd64", "a")
+	require.NoError(t, runAll(ctx, client.NewMock()))
+
+	nixpkgs := ctx.Artifacts.Filter(artifact.ByType(artifact.Nixpkg)).List()
+	require.Len(t, nixpkgs, 1)
+	content, err := os.ReadFile(nixpkgs[0].Path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `description = "foo \"bar\" \${baz}";`)
+	require.Contains(t, string(content), `homepage = "https://example.com/\\";`)
+}
+
+func TestPublishSkipUpload(t *testing.T) {
+	ctx := newContext(t, config.Nix{
+		SkipUpload: "true",
+		Repository: config.RepoRef{Name: "nur"},
+	})
+	addArchive(t, ctx, "linux", "amd64", "a")
+
+	cli := client.NewMock()
+	require.NoError(t, runAll(ctx, cli))
+	testlib.AssertSkipped(t, publishAll(ctx, cli))
+	require.False(t, cli.CreatedFile)
+}
+
+func TestPublishCustomCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "default.nix")
+	require.NoError(t, os.WriteFile(path, []byte("fake"), 0o644))
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Env:         []string{"BOT_NAME=release-bot", "BOT_EMAIL=bot@example.com"},
+	})
+	ctx.Git.CurrentTag = "v1.2.3"
+	ctx.Version = "1.2.3"
+	art := &artifact.Artifact{
+		Name: "default.nix",
+		Path: path,
+		Type: artifact.Nixpkg,
+		Extra: map[string]interface{}{
+			nixConfigExtra: config.Nix{
+				Repository: config.RepoRef{Owner: "foo", Name: "bar"},
+				Path:       "pkgs/foo/default.nix",
+				CommitAuthor: config.CommitAuthor{
+					Name:  "{{ .Env.BOT_NAME }}",
+					Email: "{{ .Env.BOT_EMAIL }}",
+				},
+				CommitMessageTemplate: "chore(nix): update {{ .ProjectName }} to {{ .Version }}\n\nSigned-off-by: {{ .Env.BOT_NAME }} <{{ .Env.BOT_EMAIL }}>",
+			},
+		},
+	}
+	cli := client.NewMock()
+	require.NoError(t, doPublish(ctx, art, cli))
+	require.Equal(t, config.CommitAuthor{
+		Name:  "release-bot",
+		Email: "bot@example.com",
+	}, cli.CommitAuthor)
+	require.Equal(t, "chore(nix): update foo to 1.2.3\n\nSigned-off-by: release-bot <bot@example.com>", cli.CommitMessage)
+}
@@ -0,0 +1,142 @@
+package nix
+
+import "strings"
+
+// nixStringReplacer escapes the characters with a special meaning inside nix
+// double quoted strings.
+// nolint: gochecknoglobals
+var nixStringReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"${", `\${`,
+)
+
+// escapeString escapes the given value so it can be used inside a nix
+// double quoted string.
+func escapeString(s string) string {
+	return nixStringReplacer.Replace(s)
+}
+
+type archive struct {
+	URL        string
+	Sha256     string
+	SourceRoot string
+}
+
+type templateData struct {
+	Name        string
+	Version     string
+	Description string
+	Homepage    string
+	License     string
+	Archives    map[string]archive // keyed by nix system, e.g. x86_64-linux
+	Binaries    []string
+	Zip         bool
+}
+
+const classicTemplate = `# This file was generated by GoReleaser. DO NOT EDIT.
+{ system ? builtins.currentSystem
+, pkgs ? import <nixpkgs> { inherit system; }
+}:
+let
+  archives = {
+    {{- range $system, $archive := .Archives }}
+    {{ $system }} = {
+      url = "{{ $archive.URL }}";
+      sha256 = "{{ $archive.Sha256 }}";
+      sourceRoot = "{{ $archive.SourceRoot }}";
+    };
+    {{- end }}
+  };
+  archive = archives.${system} or (throw "unsupported system: ${system}");
+in
+pkgs.stdenvNoCC.mkDerivation {
+  pname = "{{ .Name }}";
+  version = "{{ .Version }}";
+  src = pkgs.fetchurl {
+    inherit (archive) url sha256;
+  };
+  sourceRoot = archive.sourceRoot;
+  {{- if .Zip }}
+  nativeBuildInputs = [ pkgs.unzip ];
+  {{- end }}
+
+  installPhase = ''
+    mkdir -p $out/bin
+    {{- range .Binaries }}
+    cp -vr ./{{ . }} $out/bin/{{ . }}
+    {{- end }}
+  '';
+
+  meta = {
+    {{- with .Description }}
+    description = "{{ escape . }}";
+    {{- end }}
+    {{- with .Homepage }}
+    homepage = "{{ escape . }}";
+    {{- end }}
+    {{- with .License }}
+    license = pkgs.lib.licenses.{{ . }};
+    {{- end }}
+    platforms = builtins.attrNames archives;
+  };
+}
+`
+
+const flakeTemplate = `# This file was generated by GoReleaser. DO NOT EDIT.
+{
+  description = "{{ with .Description }}{{ escape . }}{{ else }}{{ escape .Name }}{{ end }}";
+
+  inputs.nixpkgs.url = "github:NixOS/nixpkgs/nixos-unstable";
+
+  outputs = { self, nixpkgs }:
+    let
+      archives = {
+        {{- range $system, $archive := .Archives }}
+        {{ $system }} = {
+          url = "{{ $archive.URL }}";
+          sha256 = "{{ $archive.Sha256 }}";
+          sourceRoot = "{{ $archive.SourceRoot }}";
+        };
+        {{- end }}
+      };
+      forAllSystems = f: nixpkgs.lib.genAttrs (builtins.attrNames archives) (system:
+        f system nixpkgs.legacyPackages.${system} archives.${system});
+    in
+    {
+      packages = forAllSystems (system: pkgs: archive: {
+        default = pkgs.stdenvNoCC.mkDerivation {
+          pname = "{{ .Name }}";
+          version = "{{ .Version }}";
+          src = pkgs.fetchurl {
+            inherit (archive) url sha256;
+          };
+          sourceRoot = archive.sourceRoot;
+          {{- if .Zip }}
+          nativeBuildInputs = [ pkgs.unzip ];
+          {{- end }}
+
+          installPhase = ''
+            mkdir -p $out/bin
+            {{- range .Binaries }}
+            cp -vr ./{{ . }} $out/bin/{{ . }}
+            {{- end }}
+          '';
+
+          meta = {
+            {{- with .Description }}
+            description = "{{ escape . }}";
+            {{- end }}
+            {{- with .Homepage }}
+            homepage = "{{ escape . }}";
+            {{- end }}
+            {{- with .License }}
+            license = pkgs.lib.licenses.{{ . }};
+            {{- end }}
+            platforms = builtins.attrNames archives;
+          };
+        };
+      });
+    };
+}
+`
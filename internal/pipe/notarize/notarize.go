@@ -0,0 +1,298 @@
+// Package notarize implements a Pipe that notarizes macOS artifacts with
+// Apple's notary service, stapling the notarization ticket when possible.
+package notarize
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/ids"
+	"github.com/goreleaser/goreleaser/internal/semerrgroup"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const (
+	defaultTimeout = 10 * time.Minute
+	statusAccepted = "Accepted"
+	statusProgress = "In Progress"
+)
+
+// pollInterval is how often the notarization status is checked.
+// nolint: gochecknoglobals
+var pollInterval = 30 * time.Second
+
+// notarytool only accepts these formats, and the ticket can only be stapled
+// to disk images and installer packages.
+// nolint: gochecknoglobals
+var (
+	notarizableExts = []string{".zip", ".dmg", ".pkg"}
+	stapleableExts  = []string{".dmg", ".pkg"}
+)
+
+// Pipe that notarizes macOS artifacts.
+type Pipe struct{}
+
+func (Pipe) String() string { return "notarizing macOS artifacts" }
+func (Pipe) Skip(ctx *context.Context) bool {
+	return ctx.SkipSign || len(ctx.Config.Notarize) == 0
+}
+
+// Default sets the Pipes defaults.
+func (Pipe) Default(ctx *context.Context) error {
+	ids := ids.New("notarize")
+	for i := range ctx.Config.Notarize {
+		cfg := &ctx.Config.Notarize[i]
+		if cfg.Cmd == "" {
+			cfg.Cmd = "xcrun"
+		}
+		if cfg.Timeout == 0 {
+			cfg.Timeout = defaultTimeout
+		}
+		if cfg.ID == "" {
+			cfg.ID = "default"
+		}
+		ids.Inc(cfg.ID)
+	}
+	return ids.Validate()
+}
+
+// Run executes the Pipe.
+func (Pipe) Run(ctx *context.Context) error {
+	for _, cfg := range ctx.Config.Notarize {
+		auth, err := authArgs(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		artifacts := ctx.Artifacts.Filter(filterFor(cfg)).List()
+		if len(artifacts) == 0 {
+			log.WithField("id", cfg.ID).Warn("no macOS artifacts to notarize")
+			continue
+		}
+		g := semerrgroup.New(ctx.Parallelism)
+		for _, a := range artifacts {
+			a := a
+			cfg := cfg
+			g.Go(func() error {
+				return notarize(ctx, cfg, auth, a)
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func filterFor(cfg config.Notarize) artifact.Filter {
+	filters := []artifact.Filter{
+		artifact.ByGoos("darwin"),
+		func(a *artifact.Artifact) bool {
+			return hasExt(a.Path, notarizableExts)
+		},
+	}
+	if len(cfg.IDs) > 0 {
+		filters = append(filters, artifact.ByIDs(cfg.IDs...))
+	}
+	return artifact.And(filters...)
+}
+
+func hasExt(path string, exts []string) bool {
+	ext := filepath.Ext(path)
+	for _, e := range exts {
+		if ext == e {
+			return true
+		}
+	}
+	return false
+}
+
+// authArgs returns the notarytool flags to authenticate either with
+// credentials stored in the keychain, with an App Store Connect API key or
+// with an Apple ID and app-specific password.
+//
+// notarytool only takes the app-specific password as a flag, so it is
+// visible in the process list while it runs. The keychain profile should be
+// preferred on shared machines.
+func authArgs(ctx *context.Context, cfg config.Notarize) ([]string, error) {
+	t := tmpl.New(ctx)
+	fields := []*string{
+		&cfg.KeychainProfile,
+		&cfg.AppleID,
+		&cfg.Password,
+		&cfg.TeamID,
+		&cfg.Key,
+		&cfg.KeyID,
+		&cfg.Issuer,
+	}
+	for _, field := range fields {
+		s, err := t.Apply(*field)
+		if err != nil {
+			return nil, fmt.Errorf("notarize: %w", err)
+		}
+		*field = s
+	}
+
+	switch {
+	case cfg.KeychainProfile != "":
+		return []string{"--keychain-profile", cfg.KeychainProfile}, nil
+	case cfg.Key != "":
+		if cfg.KeyID == "" || cfg.Issuer == "" {
+			return nil, errors.New("notarize: key_id and issuer are required when using key")
+		}
+		return []string{"--key", cfg.Key, "--key-id", cfg.KeyID, "--issuer", cfg.Issuer}, nil
+	case cfg.AppleID != "":
+		if cfg.Password == "" || cfg.TeamID == "" {
+			return nil, errors.New("notarize: password and team_id are required when using apple_id")
+		}
+		return []string{"--apple-id", cfg.AppleID, "--password", cfg.Password, "--team-id", cfg.TeamID}, nil
+	default:
+		return nil, errors.New("notarize: either keychain_profile, key or apple_id must be set")
+	}
+}
+
+func submitArgs(path string, auth []string) []string {
+	args := []string{"notarytool", "submit", path}
+	args = append(args, auth...)
+	return append(args, "--output-format", "json", "--no-wait")
+}
+
+func infoArgs(id string, auth []string) []string {
+	args := []string{"notarytool", "info", id}
+	args = append(args, auth...)
+	return append(args, "--output-format", "json")
+}
+
+func logArgs(id, output string, auth []string) []string {
+	args := []string{"notarytool", "log", id, output}
+	return append(args, auth...)
+}
+
+func stapleArgs(path string) []string {
+	return []string{"stapler", "staple", path}
+}
+
+// submission is the relevant part of notarytool's json output.
+type submission struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+func notarize(ctx *context.Context, cfg config.Notarize, auth []string, a *artifact.Artifact) error {
+	log := log.WithField("artifact", a.Name)
+	log.Info("submitting for notarization")
+	var sub submission
+	if err := runJSON(ctx, cfg.Cmd, submitArgs(a.Path, auth), &sub); err != nil {
+		return fmt.Errorf("notarize: failed to submit %s: %w", a.Name, err)
+	}
+	if sub.ID == "" {
+		return fmt.Errorf("notarize: failed to submit %s: no submission id returned: %s", a.Name, sub.Message)
+	}
+
+	log = log.WithField("id", sub.ID)
+	status, err := wait(ctx, cfg, auth, a, sub.ID)
+	if err != nil {
+		return err
+	}
+	if status != statusAccepted {
+		return rejected(ctx, cfg, auth, a, sub.ID, status)
+	}
+	log.Info("notarized")
+
+	if !hasExt(a.Path, stapleableExts) {
+		log.Debug("ticket can't be stapled to this kind of file, skipping")
+		return nil
+	}
+	if _, err := run(ctx, cfg.Cmd, stapleArgs(a.Path)); err != nil {
+		return fmt.Errorf("notarize: failed to staple %s: %w", a.Name, err)
+	}
+	log.Info("stapled")
+	return nil
+}
+
+// wait polls the notary service until the submission is processed or the
+// configured timeout is reached, returning the final status.
+func wait(ctx *context.Context, cfg config.Notarize, auth []string, a *artifact.Artifact, id string) (string, error) {
+	start := time.Now()
+	for {
+		var info submission
+		if err := runJSON(ctx, cfg.Cmd, infoArgs(id, auth), &info); err != nil {
+			return "", fmt.Errorf("notarize: failed to check status of %s (submission id %s): %w", a.Name, id, err)
+		}
+		if info.Status != statusProgress {
+			return info.Status, nil
+		}
+
+		elapsed := time.Since(start)
+		if elapsed >= cfg.Timeout {
+			return "", fmt.Errorf("notarize: timed out after %s waiting for %s to be notarized (submission id %s)", cfg.Timeout, a.Name, id)
+		}
+		log.WithField("artifact", a.Name).
+			WithField("id", id).
+			WithField("elapsed", elapsed.Round(time.Second)).
+			Info("waiting for notarization")
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// rejected downloads the notarization log into the dist folder and returns
+// an error pointing to it.
+func rejected(ctx *context.Context, cfg config.Notarize, auth []string, a *artifact.Artifact, id, status string) error {
+	path := filepath.Join(ctx.Config.Dist, "notarize", id+".json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("notarize: %s failed with status %q (submission id %s)", a.Name, status, id)
+	}
+	if _, err := run(ctx, cfg.Cmd, logArgs(id, path, auth)); err != nil {
+		log.WithError(err).Warn("failed to download the notarization log")
+		return fmt.Errorf(
+			"notarize: %s failed with status %q (submission id %s), run `xcrun notarytool log %s` to see why",
+			a.Name, status, id, id,
+		)
+	}
+	return fmt.Errorf(
+		"notarize: %s failed with status %q (submission id %s), check the notarization log at %s",
+		a.Name, status, id, path,
+	)
+}
+
+func runJSON(ctx *context.Context, cmd string, args []string, v interface{}) error {
+	out, err := run(ctx, cmd, args)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(out, v); err != nil {
+		return fmt.Errorf("invalid output: %w: %s", err, string(out))
+	}
+	return nil
+}
+
+func run(ctx *context.Context, cmd string, args []string) ([]byte, error) {
+	// args contain credentials, so we only log the subcommand.
+	log.WithField("cmd", cmd+" "+strings.Join(args[:2], " ")).Debug("running")
+	/* #nosec */
+	c := exec.CommandContext(ctx, cmd, args...)
+	c.Env = ctx.Env.Strings()
+	var stderr bytes.Buffer
+	c.Stderr = &stderr
+	out, err := c.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()+string(out)))
+	}
+	return out, nil
+}
@@ -0,0 +1,290 @@
+package notarize
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescription(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
+	})
+
+	t.Run("skip sign", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Notarize: []config.Notarize{{}},
+		})
+		ctx.SkipSign = true
+		require.True(t, Pipe{}.Skip(ctx))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		require.False(t, Pipe{}.Skip(context.New(config.Project{
+			Notarize: []config.Notarize{{}},
+		})))
+	})
+}
+
+func TestDefault(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Notarize: []config.Notarize{{}},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.Equal(t, config.Notarize{
+			ID:      "default",
+			Cmd:     "xcrun",
+			Timeout: 10 * time.Minute,
+		}, ctx.Config.Notarize[0])
+	})
+
+	t.Run("duplicated ids", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Notarize: []config.Notarize{{}, {}},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), "found 2 notarize with the ID 'default', please fix your config")
+	})
+}
+
+func TestAuthArgs(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Env["APPLE_PASSWORD"] = "secret"
+
+	t.Run("apple id", func(t *testing.T) {
+		args, err := authArgs(ctx, config.Notarize{
+			AppleID:  "dev@example.com",
+			Password: "{{ .Env.APPLE_PASSWORD }}",
+			TeamID:   "ABC123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"--apple-id", "dev@example.com",
+			"--password", "secret",
+			"--team-id", "ABC123",
+		}, args)
+	})
+
+	t.Run("keychain profile", func(t *testing.T) {
+		ctx.Env["PROFILE"] = "goreleaser"
+		args, err := authArgs(ctx, config.Notarize{
+			KeychainProfile: "{{ .Env.PROFILE }}",
+			AppleID:         "dev@example.com",
+			Password:        "{{ .Env.APPLE_PASSWORD }}",
+			TeamID:          "ABC123",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"--keychain-profile", "goreleaser"}, args)
+	})
+
+	t.Run("key", func(t *testing.T) {
+		args, err := authArgs(ctx, config.Notarize{
+			Key:    "AuthKey.p8",
+			KeyID:  "KEY123",
+			Issuer: "issuer-uuid",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"--key", "AuthKey.p8",
+			"--key-id", "KEY123",
+			"--issuer", "issuer-uuid",
+		}, args)
+	})
+
+	for name, tc := range map[string]struct {
+		cfg config.Notarize
+		err string
+	}{
+		"none": {
+			cfg: config.Notarize{},
+			err: "notarize: either keychain_profile, key or apple_id must be set",
+		},
+		"apple id without password": {
+			cfg: config.Notarize{AppleID: "dev@example.com", TeamID: "ABC123"},
+			err: "notarize: password and team_id are required when using apple_id",
+		},
+		"key without issuer": {
+			cfg: config.Notarize{Key: "AuthKey.p8", KeyID: "KEY123"},
+			err: "notarize: key_id and issuer are required when using key",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := authArgs(ctx, tc.cfg)
+			require.EqualError(t, err, tc.err)
+		})
+	}
+
+	t.Run("bad template", func(t *testing.T) {
+		_, err := authArgs(ctx, config.Notarize{
+			AppleID: "{{ .Nope }}",
+		})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestCommands(t *testing.T) {
+	auth := []string{"--key", "k.p8", "--key-id", "id", "--issuer", "iss"}
+	require.Equal(t, []string{
+		"notarytool", "submit", "dist/foo.dmg",
+		"--key", "k.p8", "--key-id", "id", "--issuer", "iss",
+		"--output-format", "json", "--no-wait",
+	}, submitArgs("dist/foo.dmg", auth))
+	require.Equal(t, []string{
+		"notarytool", "info", "sub-id",
+		"--key", "k.p8", "--key-id", "id", "--issuer", "iss",
+		"--output-format", "json",
+	}, infoArgs("sub-id", auth))
+	require.Equal(t, []string{
+		"notarytool", "log", "sub-id", "dist/notarize/sub-id.json",
+		"--key", "k.p8", "--key-id", "id", "--issuer", "iss",
+	}, logArgs("sub-id", "dist/notarize/sub-id.json", auth))
+	require.Equal(t, []string{"stapler", "staple", "dist/foo.dmg"}, stapleArgs("dist/foo.dmg"))
+}
+
+func TestFilterFor(t *testing.T) {
+	ctx := context.New(config.Project{})
+	for _, a := range []*artifact.Artifact{
+		{Name: "foo_darwin_all.zip", Path: "dist/foo_darwin_all.zip", Goos: "darwin", Type: artifact.UploadableArchive, Extra: map[string]interface{}{artifact.ExtraID: "foo"}},
+		{Name: "foo_darwin_all.tar.gz", Path: "dist/foo_darwin_all.tar.gz", Goos: "darwin", Type: artifact.UploadableArchive, Extra: map[string]interface{}{artifact.ExtraID: "foo"}},
+		{Name: "foo.dmg", Path: "dist/foo.dmg", Goos: "darwin", Type: artifact.UploadableFile, Extra: map[string]interface{}{artifact.ExtraID: "bar"}},
+		{Name: "foo_linux_amd64.zip", Path: "dist/foo_linux_amd64.zip", Goos: "linux", Type: artifact.UploadableArchive, Extra: map[string]interface{}{artifact.ExtraID: "foo"}},
+		{Name: "foo", Path: "dist/foo_darwin_all/foo", Goos: "darwin", Type: artifact.UniversalBinary, Extra: map[string]interface{}{artifact.ExtraID: "foo"}},
+	} {
+		ctx.Artifacts.Add(a)
+	}
+
+	names := func(arts []*artifact.Artifact) []string {
+		var result []string
+		for _, a := range arts {
+			result = append(result, a.Name)
+		}
+		return result
+	}
+
+	require.ElementsMatch(t, []string{"foo_darwin_all.zip", "foo.dmg"}, names(ctx.Artifacts.Filter(filterFor(config.Notarize{})).List()))
+	require.ElementsMatch(t, []string{"foo.dmg"}, names(ctx.Artifacts.Filter(filterFor(config.Notarize{IDs: []string{"bar"}})).List()))
+}
+
+// fakeXcrun writes a script that behaves like the notarytool and stapler
+// subcommands of xcrun, returning the status set in FAKE_STATUS.
+func fakeXcrun(tb testing.TB, dir string) string {
+	tb.Helper()
+	if runtime.GOOS == "windows" {
+		tb.Skip("uses a shell script as xcrun")
+	}
+	path := filepath.Join(dir, "xcrun")
+	require.NoError(tb, os.WriteFile(path, []byte(`#!/bin/sh
+case "$1 $2" in
+"notarytool submit")
+	echo '{"id":"sub-id","message":"Successfully uploaded file","path":"'"$3"'"}'
+	;;
+"notarytool info")
+	echo '{"id":"sub-id","status":"'"$FAKE_STATUS"'"}'
+	;;
+"notarytool log")
+	echo '{"issues":[{"message":"The binary is not signed."}]}' > "$4"
+	;;
+"stapler staple")
+	touch "$3.stapled"
+	;;
+*)
+	echo "unexpected command: $*" >&2
+	exit 1
+	;;
+esac
+`), 0o755))
+	return path
+}
+
+func TestRun(t *testing.T) {
+	pollInterval = time.Millisecond
+	t.Cleanup(func() {
+		pollInterval = 30 * time.Second
+	})
+
+	setup := func(tb testing.TB, status string) (*context.Context, string) {
+		tb.Helper()
+		folder := tb.TempDir()
+		xcrun := fakeXcrun(tb, folder)
+		dist := filepath.Join(folder, "dist")
+		require.NoError(tb, os.MkdirAll(dist, 0o755))
+		for _, name := range []string{"foo.dmg", "foo_darwin_all.zip"} {
+			require.NoError(tb, os.WriteFile(filepath.Join(dist, name), []byte("fake"), 0o644))
+		}
+		ctx := context.New(config.Project{
+			Dist: dist,
+			Notarize: []config.Notarize{
+				{
+					Cmd:      xcrun,
+					AppleID:  "dev@example.com",
+					Password: "secret",
+					TeamID:   "ABC123",
+					Timeout:  time.Second,
+				},
+			},
+		})
+		ctx.Env["FAKE_STATUS"] = status
+		for _, name := range []string{"foo.dmg", "foo_darwin_all.zip"} {
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Name: name,
+				Path: filepath.Join(dist, name),
+				Goos: "darwin",
+				Type: artifact.UploadableArchive,
+			})
+		}
+		require.NoError(tb, Pipe{}.Default(ctx))
+		return ctx, dist
+	}
+
+	t.Run("accepted", func(t *testing.T) {
+		ctx, dist := setup(t, "Accepted")
+		require.NoError(t, Pipe{}.Run(ctx))
+		require.FileExists(t, filepath.Join(dist, "foo.dmg.stapled"))
+		require.NoFileExists(t, filepath.Join(dist, "foo_darwin_all.zip.stapled"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx, dist := setup(t, "Invalid")
+		err := Pipe{}.Run(ctx)
+		logPath := filepath.Join(dist, "notarize", "sub-id.json")
+		require.ErrorContains(t, err, `failed with status "Invalid" (submission id sub-id), check the notarization log at `+logPath)
+		require.FileExists(t, logPath)
+		require.NoFileExists(t, filepath.Join(dist, "foo.dmg.stapled"))
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		ctx, _ := setup(t, "In Progress")
+		ctx.Config.Notarize[0].Timeout = 10 * time.Millisecond
+		require.ErrorContains(t, Pipe{}.Run(ctx), "notarize: timed out after 10ms waiting for")
+	})
+
+	t.Run("failing command", func(t *testing.T) {
+		ctx, _ := setup(t, "Accepted")
+		ctx.Config.Notarize[0].Cmd = "false"
+		require.ErrorContains(t, Pipe{}.Run(ctx), "notarize: failed to submit")
+	})
+
+	t.Run("no artifacts", func(t *testing.T) {
+		ctx, _ := setup(t, "Accepted")
+		ctx.Config.Notarize[0].IDs = []string{"nope"}
+		require.NoError(t, Pipe{}.Run(ctx))
+	})
+
+	t.Run("invalid auth", func(t *testing.T) {
+		ctx, _ := setup(t, "Accepted")
+		ctx.Config.Notarize[0].AppleID = ""
+		require.EqualError(t, Pipe{}.Run(ctx), "notarize: either apple_id or key must be set")
+	})
+}
@@ -3,6 +3,8 @@ package pipe
 
 import (
 	"errors"
+	"path"
+	"reflect"
 	"strings"
 )
 
@@ -67,3 +69,9 @@ func (e *SkipMemento) Evaluate() error {
 	}
 	return Skip(strings.Join(e.skips, ", "))
 }
+
+// ID returns the id of the given pipe, as used by --only, which is the name
+// of its package, e.g. "docker" for both docker.Pipe and docker.ManifestPipe.
+func ID(p interface{}) string {
+	return path.Base(reflect.TypeOf(p).PkgPath())
+}
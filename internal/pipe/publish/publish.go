@@ -3,10 +3,13 @@ package publish
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/goreleaser/goreleaser/internal/middleware/errhandler"
 	"github.com/goreleaser/goreleaser/internal/middleware/logging"
 	"github.com/goreleaser/goreleaser/internal/middleware/skip"
+	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/pipe/artifactory"
 	"github.com/goreleaser/goreleaser/internal/pipe/aur"
 	"github.com/goreleaser/goreleaser/internal/pipe/blob"
@@ -17,11 +20,13 @@ import (
 	"github.com/goreleaser/goreleaser/internal/pipe/ko"
 	"github.com/goreleaser/goreleaser/internal/pipe/krew"
 	"github.com/goreleaser/goreleaser/internal/pipe/milestone"
+	"github.com/goreleaser/goreleaser/internal/pipe/nix"
 	"github.com/goreleaser/goreleaser/internal/pipe/release"
 	"github.com/goreleaser/goreleaser/internal/pipe/scoop"
 	"github.com/goreleaser/goreleaser/internal/pipe/sign"
 	"github.com/goreleaser/goreleaser/internal/pipe/snapcraft"
 	"github.com/goreleaser/goreleaser/internal/pipe/upload"
+	"github.com/goreleaser/goreleaser/internal/pipe/winget"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
@@ -48,9 +53,12 @@ var publishers = []Publisher{
 	release.Pipe{},
 	// brew et al use the release URL, so, they should be last
 	brew.Pipe{},
+	brew.CaskPipe{},
 	aur.Pipe{},
 	krew.Pipe{},
 	scoop.Pipe{},
+	winget.Pipe{},
+	nix.Pipe{},
 	chocolatey.Pipe{},
 	milestone.Pipe{},
 }
@@ -62,7 +70,7 @@ func (Pipe) String() string                 { return "publishing" }
 func (Pipe) Skip(ctx *context.Context) bool { return ctx.SkipPublish }
 
 func (Pipe) Run(ctx *context.Context) error {
-	for _, publisher := range publishers {
+	for _, publisher := range selected(ctx) {
 		if err := skip.Maybe(
 			publisher,
 			logging.PadLog(
@@ -75,3 +83,39 @@ func (Pipe) Run(ctx *context.Context) error {
 	}
 	return nil
 }
+
+// IDs returns the sorted list of publisher ids, which can be given to --only.
+func IDs() []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, publisher := range publishers {
+		id := pipe.ID(publisher)
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// selected returns the publishers to run: if any of the ids given to --only
+// matches a publisher, only the matching publishers run, otherwise all of
+// them do.
+func selected(ctx *context.Context) []Publisher {
+	only := map[string]bool{}
+	for _, id := range ctx.Only {
+		only[strings.TrimSpace(id)] = true
+	}
+	var result []Publisher
+	for _, publisher := range publishers {
+		if only[pipe.ID(publisher)] {
+			result = append(result, publisher)
+		}
+	}
+	if len(result) == 0 {
+		return publishers
+	}
+	return result
+}
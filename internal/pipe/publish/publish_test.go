@@ -3,6 +3,8 @@ package publish
 import (
 	"testing"
 
+	"github.com/goreleaser/goreleaser/internal/pipe/docker"
+	"github.com/goreleaser/goreleaser/internal/pipe/release"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
@@ -22,6 +24,38 @@ func TestPublish(t *testing.T) {
 	require.NoError(t, Pipe{}.Run(ctx))
 }
 
+func TestSelected(t *testing.T) {
+	t.Run("no ids", func(t *testing.T) {
+		require.Equal(t, publishers, selected(context.New(config.Project{})))
+	})
+
+	t.Run("no publisher ids", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		ctx.Only = []string{"build", "publish"}
+		require.Equal(t, publishers, selected(ctx))
+	})
+
+	t.Run("docker", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		ctx.Only = []string{"build", "docker", "publish"}
+		require.Equal(t, []Publisher{docker.Pipe{}, docker.ManifestPipe{}}, selected(ctx))
+	})
+
+	t.Run("docker and release", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		ctx.Only = []string{"docker", "release", "publish"}
+		require.Equal(t, []Publisher{docker.Pipe{}, docker.ManifestPipe{}, release.Pipe{}}, selected(ctx))
+	})
+}
+
+func TestIDs(t *testing.T) {
+	ids := IDs()
+	require.Contains(t, ids, "docker")
+	require.Contains(t, ids, "release")
+	require.Contains(t, ids, "brew")
+	require.IsIncreasing(t, ids)
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		ctx := context.New(config.Project{})
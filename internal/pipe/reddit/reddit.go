@@ -54,6 +54,11 @@ func (Pipe) Announce(ctx *context.Context) error {
 		URL:       url,
 	}
 
+	if ctx.AnnounceDryRun {
+		log.WithField("title", title).WithField("url", url).Info("dry-run, not posting")
+		return nil
+	}
+
 	var cfg Config
 	if err := env.Parse(&cfg); err != nil {
 		return fmt.Errorf("reddit: %w", err)
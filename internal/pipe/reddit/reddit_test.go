@@ -50,6 +50,22 @@ func TestAnnounceMissingEnv(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `reddit: env: environment variable "REDDIT_SECRET" should not be empty; environment variable "REDDIT_PASSWORD" should not be empty`)
 }
 
+func TestAnnounceDryRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Reddit: config.Reddit{
+				Enabled:       true,
+				TitleTemplate: "{{ .Tag }} is out",
+				URLTemplate:   "https://example.com/{{ .Tag }}",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
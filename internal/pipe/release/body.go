@@ -2,6 +2,9 @@ package release
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"strings"
 	"text/template"
 
 	"github.com/goreleaser/goreleaser/internal/tmpl"
@@ -26,6 +29,11 @@ func describeBody(ctx *context.Context) (bytes.Buffer, error) {
 		return out, err
 	}
 
+	notes, err := releaseNotes(ctx)
+	if err != nil {
+		return out, err
+	}
+
 	bodyTemplate := template.Must(template.New("release").Parse(bodyTemplateText))
 	err = bodyTemplate.Execute(&out, struct {
 		Header       string
@@ -34,7 +42,34 @@ func describeBody(ctx *context.Context) (bytes.Buffer, error) {
 	}{
 		Header:       header,
 		Footer:       footer,
-		ReleaseNotes: ctx.ReleaseNotes,
+		ReleaseNotes: notes,
 	})
 	return out, err
 }
+
+// releaseNotes combines the changelog with the rendered
+// release.release_notes_tmpl file, if any.
+func releaseNotes(ctx *context.Context) (string, error) {
+	path := ctx.Config.Release.ReleaseNotesTmpl
+	if path == "" {
+		return ctx.ReleaseNotes, nil
+	}
+
+	bts, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read release notes template: %w", err)
+	}
+	notes, err := tmpl.New(ctx).Apply(string(bts))
+	if err != nil {
+		return "", err
+	}
+
+	if strings.TrimSpace(ctx.ReleaseNotes) == "" {
+		return notes, nil
+	}
+	notes = strings.TrimSuffix(notes, "\n")
+	if ctx.Config.Release.ReleaseNotesPosition == "append" {
+		return strings.TrimSuffix(ctx.ReleaseNotes, "\n") + "\n\n" + notes + "\n", nil
+	}
+	return notes + "\n\n" + ctx.ReleaseNotes, nil
+}
@@ -1,9 +1,12 @@
 package release
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/golden"
+	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
@@ -64,3 +67,64 @@ func TestDescribeBodyWithInvalidFooterTemplate(t *testing.T) {
 	_, err := describeBody(ctx)
 	require.EqualError(t, err, `template: tmpl:1: unexpected "}" in operand`)
 }
+
+func TestDescribeBodyWithReleaseNotesTmpl(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "NOTES.md")
+	require.NoError(t, os.WriteFile(path, []byte("## {{ .ProjectName }} {{ .Version }}\n\nSome notes.\n"), 0o644))
+
+	for position, expected := range map[string]string{
+		"prepend": "## foo 1.0.0\n\nSome notes.\n\nfeature1: description\n",
+		"append":  "feature1: description\n\n## foo 1.0.0\n\nSome notes.\n",
+	} {
+		t.Run(position, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				ProjectName: "foo",
+				Release: config.Release{
+					ReleaseNotesTmpl:     path,
+					ReleaseNotesPosition: position,
+				},
+			})
+			ctx.Version = "1.0.0"
+			ctx.ReleaseNotes = "feature1: description\n"
+			out, err := describeBody(ctx)
+			require.NoError(t, err)
+			require.Equal(t, expected, out.String())
+		})
+	}
+
+	t.Run("empty changelog", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			ProjectName: "foo",
+			Release: config.Release{
+				ReleaseNotesTmpl: path,
+			},
+		})
+		ctx.Version = "1.0.0"
+		out, err := describeBody(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "## foo 1.0.0\n\nSome notes.\n", out.String())
+	})
+}
+
+func TestDescribeBodyWithMissingReleaseNotesTmpl(t *testing.T) {
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			ReleaseNotesTmpl: filepath.Join(t.TempDir(), "NOTES.md"),
+		},
+	})
+	_, err := describeBody(ctx)
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.ErrorContains(t, err, "failed to read release notes template")
+}
+
+func TestDescribeBodyWithInvalidReleaseNotesTmpl(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "NOTES.md")
+	require.NoError(t, os.WriteFile(path, []byte("{{ .Nope }}"), 0o644))
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			ReleaseNotesTmpl: path,
+		},
+	})
+	_, err := describeBody(ctx)
+	testlib.RequireTemplateError(t, err)
+}
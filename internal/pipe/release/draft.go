@@ -0,0 +1,59 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/pipe/metadata"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+var errPublishNotSupported = errors.New("publishing existing draft releases is only supported on GitHub and Gitea")
+
+// DraftPipe publishes a draft release created by a previous
+// `goreleaser release` run, using the release id stored in the metadata.json
+// file in the dist folder.
+type DraftPipe struct{}
+
+func (DraftPipe) String() string { return "publishing draft release" }
+
+// Run the pipe.
+func (DraftPipe) Run(ctx *context.Context) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	if err := publishDraft(ctx, c); err != nil {
+		return err
+	}
+	log.WithField("url", ctx.ReleaseURL).Info("published")
+	return nil
+}
+
+func publishDraft(ctx *context.Context, cli client.Client) error {
+	publisher, ok := cli.(client.ReleasePublisher)
+	if !ok {
+		return errPublishNotSupported
+	}
+
+	meta, err := metadata.Read(ctx.Config.Dist)
+	if err != nil {
+		return err
+	}
+	if meta.ReleaseID == "" {
+		return fmt.Errorf(
+			"no release id found in %s, make sure it was created by `goreleaser release` with the same dist folder",
+			filepath.Join(ctx.Config.Dist, "metadata.json"),
+		)
+	}
+
+	ctx.Git.CurrentTag = meta.Tag
+	ctx.ReleaseID = meta.ReleaseID
+	log.WithField("tag", meta.Tag).
+		WithField("release-id", meta.ReleaseID).
+		Info("publishing draft release")
+	return publisher.PublishRelease(ctx, meta.ReleaseID)
+}
@@ -0,0 +1,89 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/pipe/metadata"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDraftPipeDescription(t *testing.T) {
+	require.NotEmpty(t, DraftPipe{}.String())
+}
+
+func TestPublishDraft(t *testing.T) {
+	t.Run("from metadata", func(t *testing.T) {
+		folder := t.TempDir()
+
+		// the first run creates the draft release and stores its id.
+		ctx := context.New(config.Project{
+			Dist:        folder,
+			ProjectName: "foo",
+		})
+		ctx.Git.CurrentTag = "v1.0.0"
+		ctx.ReleaseID = "123456"
+		require.NoError(t, metadata.Pipe{}.Run(ctx))
+
+		// the second run publishes it.
+		ctx = context.New(config.Project{
+			Dist: folder,
+		})
+		cli := client.NewMock()
+		require.NoError(t, publishDraft(ctx, cli))
+		require.Equal(t, "123456", cli.PublishedReleaseID)
+		require.Equal(t, "123456", ctx.ReleaseID)
+		require.Equal(t, "v1.0.0", ctx.Git.CurrentTag)
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Dist: t.TempDir(),
+		})
+		require.ErrorContains(t, publishDraft(ctx, client.NewMock()), "failed to read metadata")
+	})
+
+	t.Run("invalid metadata", func(t *testing.T) {
+		folder := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(folder, "metadata.json"), []byte("nope"), 0o644))
+		ctx := context.New(config.Project{
+			Dist: folder,
+		})
+		require.ErrorContains(t, publishDraft(ctx, client.NewMock()), "failed to read metadata")
+	})
+
+	t.Run("no release id", func(t *testing.T) {
+		folder := t.TempDir()
+		ctx := context.New(config.Project{
+			Dist: folder,
+		})
+		require.NoError(t, metadata.Pipe{}.Run(ctx))
+		cli := client.NewMock()
+		require.ErrorContains(t, publishDraft(ctx, cli), "no release id found in "+filepath.Join(folder, "metadata.json"))
+		require.Empty(t, cli.PublishedReleaseID)
+	})
+
+	t.Run("publish fails", func(t *testing.T) {
+		folder := t.TempDir()
+		ctx := context.New(config.Project{
+			Dist: folder,
+		})
+		ctx.ReleaseID = "123456"
+		require.NoError(t, metadata.Pipe{}.Run(ctx))
+		require.EqualError(t, publishDraft(ctx, &client.Mock{
+			FailToPublishRelease: true,
+		}), "release failed")
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Dist: t.TempDir(),
+		})
+		cli := struct{ client.Client }{client.NewMock()}
+		require.ErrorIs(t, publishDraft(ctx, cli), errPublishNotSupported)
+	})
+}
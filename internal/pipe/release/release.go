@@ -52,6 +52,18 @@ func (Pipe) Default(ctx *context.Context) error {
 		ctx.Config.Release.NameTemplate = "{{.Tag}}"
 	}
 
+	switch ctx.Config.Release.ReleaseNotesPosition {
+	case "":
+		ctx.Config.Release.ReleaseNotesPosition = "prepend"
+	case "prepend", "append":
+	default:
+		return fmt.Errorf("invalid release.release_notes_position: %s, valid options are: prepend, append", ctx.Config.Release.ReleaseNotesPosition)
+	}
+
+	if err := client.ValidateGitLabLinkTypes(ctx.Config.Release.GitLabLinkTypes); err != nil {
+		return fmt.Errorf("invalid release.gitlab_link_types: %w", err)
+	}
+
 	switch ctx.TokenType {
 	case context.TokenTypeGitLab:
 		if err := setupGitLab(ctx); err != nil {
@@ -119,6 +131,7 @@ func doPublish(ctx *context.Context, client client.Client) error {
 	if err != nil {
 		return err
 	}
+	ctx.ReleaseID = releaseID
 
 	skipUpload, err := tmpl.New(ctx).Bool(ctx.Config.Release.SkipUpload)
 	if err != nil {
@@ -153,6 +166,10 @@ func doPublish(ctx *context.Context, client client.Client) error {
 		artifact.ByType(artifact.Certificate),
 		artifact.ByType(artifact.LinuxPackage),
 		artifact.ByType(artifact.SBOM),
+		artifact.And(
+			artifact.ByType(artifact.Attestation),
+			artifact.OnlyUploadable,
+		),
 	)
 
 	if len(ctx.Config.Release.IDs) > 0 {
@@ -161,16 +178,38 @@ func doPublish(ctx *context.Context, client client.Client) error {
 
 	filters = artifact.Or(filters, artifact.ByType(artifact.UploadableFile))
 
+	urlTemplate, err := client.ReleaseURLTemplate(ctx)
+	if err != nil {
+		return err
+	}
+
 	g := semerrgroup.New(ctx.Parallelism)
 	for _, artifact := range ctx.Artifacts.Filter(filters).List() {
 		artifact := artifact
 		g.Go(func() error {
-			return upload(ctx, client, releaseID, artifact)
+			if err := upload(ctx, client, releaseID, artifact); err != nil {
+				return err
+			}
+			return setURL(ctx, urlTemplate, artifact)
 		})
 	}
 	return g.Wait()
 }
 
+// setURL stores the download URL of the given uploaded artifact, so it ends
+// up in the metadata.json file.
+func setURL(ctx *context.Context, urlTemplate string, a *artifact.Artifact) error {
+	url, err := tmpl.New(ctx).WithArtifact(a).Apply(urlTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to template download url for %s: %w", a.Name, err)
+	}
+	if a.Extra == nil {
+		a.Extra = artifact.Extras{}
+	}
+	a.Extra[artifact.ExtraURL] = url
+	return nil
+}
+
 func upload(ctx *context.Context, cli client.Client, releaseID string, artifact *artifact.Artifact) error {
 	var try int
 	tryUpload := func() error {
@@ -125,6 +125,10 @@ func TestRunPipeWithoutIDsThenDoesNotFilter(t *testing.T) {
 	require.Contains(t, client.UploadedFileNames, "checksum")
 	require.Contains(t, client.UploadedFileNames, "checksum.pem")
 	require.Contains(t, client.UploadedFileNames, "checksum.sig")
+
+	for _, a := range ctx.Artifacts.List() {
+		require.Equal(t, "https://dummyhost/download/v1.0.0/"+a.Name, artifact.ExtraOr(*a, artifact.ExtraURL, ""))
+	}
 }
 
 func TestRunPipeWithIDsThenFilters(t *testing.T) {
@@ -352,6 +356,25 @@ func TestDefault(t *testing.T) {
 	require.Equal(t, "goreleaser", ctx.Config.Release.GitHub.Name)
 	require.Equal(t, "goreleaser", ctx.Config.Release.GitHub.Owner)
 	require.Equal(t, "https://github.com/goreleaser/goreleaser/releases/tag/v1.0.0", ctx.ReleaseURL)
+	require.Equal(t, "prepend", ctx.Config.Release.ReleaseNotesPosition)
+}
+
+func TestDefaultInvalidReleaseNotesPosition(t *testing.T) {
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			ReleaseNotesPosition: "middle",
+		},
+	})
+	require.EqualError(t, Pipe{}.Default(ctx), "invalid release.release_notes_position: middle, valid options are: prepend, append")
+}
+
+func TestDefaultInvalidGitLabLinkTypes(t *testing.T) {
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			GitLabLinkTypes: map[string]string{"*.deb": "binary"},
+		},
+	})
+	require.EqualError(t, Pipe{}.Default(ctx), `invalid release.gitlab_link_types: invalid gitlab link type "binary" for "*.deb", valid options are: other, package, image, runbook`)
 }
 
 func TestDefaultInvalidURL(t *testing.T) {
@@ -446,6 +446,62 @@ func TestSBOMCatalogArtifacts(t *testing.T) {
 	}
 }
 
+func TestSBOMCatalogMultipleFormats(t *testing.T) {
+	testlib.CheckPath(t, "sh")
+	tmpdir := t.TempDir()
+
+	ctx := context.New(config.Project{
+		Dist: tmpdir,
+		SBOMs: []config.SBOM{
+			{
+				ID:        "spdx",
+				Cmd:       "sh",
+				Args:      []string{"-c", "echo spdx ${artifact} > ${document}"},
+				Documents: []string{"{{ .ArtifactName }}.spdx.sbom.json"},
+			},
+			{
+				ID:        "cyclonedx",
+				Cmd:       "sh",
+				Args:      []string{"-c", "echo cyclonedx ${artifact} > ${document}"},
+				Documents: []string{"{{ .ArtifactName }}.cdx.sbom.json"},
+			},
+		},
+	})
+	ctx.Version = "1.2.2"
+
+	for _, name := range []string{"artifact1.tar.gz", "artifact2.tar.gz"} {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpdir, name), []byte("foo"), 0o644))
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: name,
+			Path: filepath.Join(tmpdir, name),
+			Type: artifact.UploadableArchive,
+		})
+	}
+
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	sboms := map[string]string{}
+	for _, sbom := range ctx.Artifacts.Filter(artifact.ByType(artifact.SBOM)).List() {
+		sboms[sbom.Name] = sbom.ID()
+	}
+	require.Equal(t, map[string]string{
+		"artifact1.tar.gz.spdx.sbom.json": "spdx",
+		"artifact2.tar.gz.spdx.sbom.json": "spdx",
+		"artifact1.tar.gz.cdx.sbom.json":  "cyclonedx",
+		"artifact2.tar.gz.cdx.sbom.json":  "cyclonedx",
+	}, sboms)
+
+	for name, content := range map[string]string{
+		"artifact1.tar.gz.spdx.sbom.json": "spdx artifact1.tar.gz\n",
+		"artifact2.tar.gz.cdx.sbom.json":  "cyclonedx artifact2.tar.gz\n",
+	} {
+		bts, err := os.ReadFile(filepath.Join(tmpdir, name))
+		require.NoError(t, err)
+		require.Equal(t, content, string(bts))
+	}
+}
+
 func testSBOMCataloging(tb testing.TB, ctx *context.Context, sbomPaths, sbomNames []string, expectedErrMsg string) {
 	tb.Helper()
 	testlib.CheckPath(tb, "syft")
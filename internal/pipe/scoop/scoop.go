@@ -58,6 +58,9 @@ func (Pipe) Default(ctx *context.Context) error {
 		ctx.Config.Scoop.Name = ctx.Config.ProjectName
 	}
 	ctx.Config.Scoop.CommitAuthor = commitauthor.Default(ctx.Config.Scoop.CommitAuthor)
+	if err := client.CheckSigning(ctx.Config.Scoop.CommitAuthor, ctx.Config.Scoop.Bucket); err != nil {
+		return fmt.Errorf("scoop: %w", err)
+	}
 	if ctx.Config.Scoop.CommitMessageTemplate == "" {
 		ctx.Config.Scoop.CommitMessageTemplate = "Scoop update for {{ .ProjectName }} version {{ .Tag }}"
 	}
@@ -80,6 +83,7 @@ func doRun(ctx *context.Context, cl client.Client) error {
 					artifact.ByGoamd64(scoop.Goamd64),
 				),
 				artifact.ByGoarch("386"),
+				artifact.ByGoarch("arm64"),
 			),
 		),
 	).List()
@@ -173,7 +177,7 @@ func doPublish(ctx *context.Context, cl client.Client) error {
 	scoop.Bucket = ref
 
 	repo := client.RepoFromRef(scoop.Bucket)
-	return cl.CreateFile(
+	return client.NewIfGitURL(cl, scoop.Bucket).CreateFile(
 		ctx,
 		author,
 		repo,
@@ -187,7 +191,10 @@ func doPublish(ctx *context.Context, cl client.Client) error {
 // more info: https://github.com/lukesampson/scoop/wiki/App-Manifests
 type Manifest struct {
 	Version      string              `json:"version"`                // The version of the app that this manifest installs.
-	Architecture map[string]Resource `json:"architecture"`           // `architecture`: If the app has 32- and 64-bit versions, architecture can be used to wrap the differences.
+	URL          string              `json:"url,omitempty"`          // URL to the archive, only set when there is a single architecture.
+	Bin          []string            `json:"bin,omitempty"`          // name of binary inside the archive, only set when there is a single architecture.
+	Hash         string              `json:"hash,omitempty"`         // the archive checksum, only set when there is a single architecture.
+	Architecture map[string]Resource `json:"architecture,omitempty"` // `architecture`: If the app has 32- and 64-bit versions, architecture can be used to wrap the differences.
 	Homepage     string              `json:"homepage,omitempty"`     // `homepage`: The home page for the program.
 	License      string              `json:"license,omitempty"`      // `license`: The software license for the program. For well-known licenses, this will be a string like "MIT" or "GPL2". For custom licenses, this should be the URL of the license.
 	Description  string              `json:"description,omitempty"`  // Description of the app
@@ -244,6 +251,8 @@ func dataFor(ctx *context.Context, cl client.Client, artifacts []*artifact.Artif
 			arch = "32bit"
 		case artifact.Goarch == "amd64":
 			arch = "64bit"
+		case artifact.Goarch == "arm64":
+			arch = "arm64"
 		default:
 			continue
 		}
@@ -277,6 +286,20 @@ func dataFor(ctx *context.Context, cl client.Client, artifacts []*artifact.Artif
 		}
 	}
 
+	// with a single x86 architecture, use the flat form instead.
+	// arm64-only manifests keep the architecture map, otherwise scoop would
+	// install them on x86 machines too.
+	if len(manifest.Architecture) == 1 {
+		if _, ok := manifest.Architecture["arm64"]; !ok {
+			for _, res := range manifest.Architecture {
+				manifest.URL = res.URL
+				manifest.Bin = res.Bin
+				manifest.Hash = res.Hash
+			}
+			manifest.Architecture = nil
+		}
+	}
+
 	return manifest, nil
 }
 
@@ -33,6 +33,17 @@ func TestDefault(t *testing.T) {
 	require.NotEmpty(t, ctx.Config.Scoop.CommitMessageTemplate)
 }
 
+func TestDefaultSigningWithoutGit(t *testing.T) {
+	ctx := context.New(config.Project{
+		Scoop: config.Scoop{
+			CommitAuthor: config.CommitAuthor{
+				Signing: config.CommitSigning{Enabled: true},
+			},
+		},
+	})
+	require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrSigningRequiresGit)
+}
+
 func Test_doRun(t *testing.T) {
 	folder := testlib.Mktmp(t)
 	file := filepath.Join(folder, "archive")
@@ -901,6 +912,84 @@ func TestWrapInDirectory(t *testing.T) {
 	golden.RequireEqualJSON(t, out.Bytes())
 }
 
+func TestMultipleArchitectures(t *testing.T) {
+	folder := t.TempDir()
+	file := filepath.Join(folder, "archive")
+	require.NoError(t, os.WriteFile(file, []byte("lorem ipsum"), 0o644))
+	ctx := context.New(config.Project{
+		ProjectName: "run-pipe",
+		Scoop: config.Scoop{
+			Bucket: config.RepoRef{
+				Owner: "test",
+				Name:  "test",
+			},
+			Description: "A run pipe test formula",
+			Homepage:    "https://github.com/goreleaser",
+		},
+	})
+	ctx.TokenType = context.TokenTypeGitHub
+	ctx.Git = context.GitInfo{
+		CurrentTag: "v1.0.1",
+	}
+	ctx.Version = "1.0.1"
+	require.NoError(t, Pipe{}.Default(ctx))
+
+	builds := map[string]interface{}{
+		artifact.ExtraBuilds: []*artifact.Artifact{
+			{
+				Name: "foo.exe",
+			},
+		},
+	}
+	amd64 := &artifact.Artifact{
+		Name:    "foo_1.0.1_windows_amd64.tar.gz",
+		Goos:    "windows",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Path:    file,
+		Extra:   builds,
+	}
+	arm64 := &artifact.Artifact{
+		Name:   "foo_1.0.1_windows_arm64.tar.gz",
+		Goos:   "windows",
+		Goarch: "arm64",
+		Path:   file,
+		Extra:  builds,
+	}
+
+	t.Run("multiple", func(t *testing.T) {
+		mf, err := dataFor(ctx, client.NewMock(), []*artifact.Artifact{amd64, arm64})
+		require.NoError(t, err)
+		require.Empty(t, mf.URL)
+		require.Len(t, mf.Architecture, 2)
+
+		out, err := doBuildManifest(mf)
+		require.NoError(t, err)
+		golden.RequireEqualJSON(t, out.Bytes())
+	})
+
+	t.Run("single", func(t *testing.T) {
+		mf, err := dataFor(ctx, client.NewMock(), []*artifact.Artifact{amd64})
+		require.NoError(t, err)
+		require.Empty(t, mf.Architecture)
+
+		out, err := doBuildManifest(mf)
+		require.NoError(t, err)
+		golden.RequireEqualJSON(t, out.Bytes())
+	})
+
+	t.Run("single arm64", func(t *testing.T) {
+		mf, err := dataFor(ctx, client.NewMock(), []*artifact.Artifact{arm64})
+		require.NoError(t, err)
+		require.Empty(t, mf.URL)
+		require.Len(t, mf.Architecture, 1)
+
+		out, err := doBuildManifest(mf)
+		require.NoError(t, err)
+		golden.RequireEqualJSON(t, out.Bytes())
+	})
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -917,3 +1006,37 @@ func TestSkip(t *testing.T) {
 		require.False(t, Pipe{}.Skip(ctx))
 	})
 }
+
+func TestPublishCustomCommit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.json")
+	require.NoError(t, os.WriteFile(path, []byte("fake"), 0o644))
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Env:         []string{"BOT_NAME=release-bot", "BOT_EMAIL=bot@example.com"},
+	})
+	ctx.Git.CurrentTag = "v1.2.3"
+	ctx.Version = "1.2.3"
+	art := &artifact.Artifact{
+		Name: "foo.json",
+		Path: path,
+		Type: artifact.ScoopManifest,
+		Extra: map[string]interface{}{
+			scoopConfigExtra: config.Scoop{
+				Bucket: config.RepoRef{Owner: "foo", Name: "bar"},
+				CommitAuthor: config.CommitAuthor{
+					Name:  "{{ .Env.BOT_NAME }}",
+					Email: "{{ .Env.BOT_EMAIL }}",
+				},
+				CommitMessageTemplate: "chore(scoop): update {{ .ProjectName }} to {{ .Version }}\n\nSigned-off-by: {{ .Env.BOT_NAME }} <{{ .Env.BOT_EMAIL }}>",
+			},
+		},
+	}
+	cli := client.NewMock()
+	ctx.Artifacts.Add(art)
+	require.NoError(t, doPublish(ctx, cli))
+	require.Equal(t, config.CommitAuthor{
+		Name:  "release-bot",
+		Email: "bot@example.com",
+	}, cli.CommitAuthor)
+	require.Equal(t, "chore(scoop): update foo to 1.2.3\n\nSigned-off-by: release-bot <bot@example.com>", cli.CommitMessage)
+}
@@ -1,22 +1,17 @@
 package sign
 
 import (
-	"bytes"
 	"fmt"
-	"io"
-	"os"
-	"os/exec"
+	"net/url"
 	"path/filepath"
 	"strings"
 
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
-	"github.com/goreleaser/goreleaser/internal/gio"
 	"github.com/goreleaser/goreleaser/internal/ids"
-	"github.com/goreleaser/goreleaser/internal/logext"
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/semerrgroup"
-	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/internal/signer"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
@@ -39,7 +34,7 @@ func (Pipe) Default(ctx *context.Context) error {
 			cfg.Signature = "${artifact}.sig"
 		}
 		if len(cfg.Args) == 0 {
-			cfg.Args = []string{"--output", "$signature", "--detach-sig", "$artifact"}
+			cfg.Args = defaultArgs(cfg.Cmd)
 		}
 		if cfg.Artifacts == "" {
 			cfg.Artifacts = "none"
@@ -52,6 +47,14 @@ func (Pipe) Default(ctx *context.Context) error {
 	return ids.Validate()
 }
 
+func defaultArgs(cmd string) []string {
+	if filepath.Base(cmd) == "ssh-keygen" {
+		// ssh-keygen always writes the signature to "$artifact.sig".
+		return []string{"-Y", "sign", "-n", "file", "-f", "$key", "$artifact"}
+	}
+	return []string{"--output", "$signature", "--detach-sig", "$artifact"}
+}
+
 // Run executes the Pipe.
 func (Pipe) Run(ctx *context.Context) error {
 	g := semerrgroup.New(ctx.Parallelism)
@@ -126,82 +129,41 @@ func sign(ctx *context.Context, cfg config.Sign, artifacts []*artifact.Artifact)
 	return nil
 }
 
-func relativeToDist(dist, f string) (string, error) {
-	af, err := filepath.Abs(f)
-	if err != nil {
-		return "", err
-	}
-	df, err := filepath.Abs(dist)
-	if err != nil {
-		return "", err
-	}
-	if strings.HasPrefix(af, df) {
-		return f, nil
-	}
-	return filepath.Join(dist, f), nil
-}
-
-func tmplPath(ctx *context.Context, env map[string]string, s string) (string, error) {
-	result, err := tmpl.New(ctx).WithEnv(env).Apply(expand(s, env))
-	if err != nil || result == "" {
-		return "", err
-	}
-	return relativeToDist(ctx.Config.Dist, result)
-}
-
 func signone(ctx *context.Context, cfg config.Sign, art *artifact.Artifact) ([]*artifact.Artifact, error) {
-	env := ctx.Env.Copy()
-	env["artifactName"] = art.Name // shouldn't be used
-	env["artifact"] = art.Path
-	env["artifactID"] = art.ID()
-	env["digest"] = artifact.ExtraOr(*art, artifact.ExtraDigest, "")
-
-	tmplEnv, err := templateEnvS(ctx, cfg.Env)
+	env, err := signer.Env(ctx, art, cfg.Env)
 	if err != nil {
 		return nil, fmt.Errorf("sign failed: %s: %w", art.Name, err)
 	}
 
-	for k, v := range context.ToEnv(tmplEnv) {
-		env[k] = v
-	}
-
-	name, err := tmplPath(ctx, env, cfg.Signature)
+	name, err := signer.Path(ctx, env, cfg.Signature)
 	if err != nil {
 		return nil, fmt.Errorf("sign failed: %s: %w", art.Name, err)
 	}
 	env["signature"] = name
 
-	cert, err := tmplPath(ctx, env, cfg.Certificate)
+	cert, err := signer.Path(ctx, env, cfg.Certificate)
 	if err != nil {
 		return nil, fmt.Errorf("sign failed: %s: %w", art.Name, err)
 	}
 	env["certificate"] = cert
 
-	// nolint:prealloc
-	var args []string
-	for _, a := range cfg.Args {
-		arg, err := tmpl.New(ctx).WithEnv(env).Apply(expand(a, env))
-		if err != nil {
-			return nil, fmt.Errorf("sign failed: %s: %w", art.Name, err)
-		}
-		args = append(args, arg)
+	key, err := signer.KeyPath(ctx, env, cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("sign failed: %s: %w", art.Name, err)
 	}
+	env["key"] = key
 
-	var stdin io.Reader
-	if cfg.Stdin != nil {
-		s, err := tmpl.New(ctx).WithEnv(env).Apply(expand(*cfg.Stdin, env))
-		if err != nil {
-			return nil, err
-		}
-		stdin = strings.NewReader(s)
-	} else if cfg.StdinFile != "" {
-		f, err := os.Open(cfg.StdinFile)
-		if err != nil {
-			return nil, fmt.Errorf("sign failed: cannot open file %s: %w", cfg.StdinFile, err)
-		}
-		defer f.Close()
+	flags, err := sigstoreFlags(ctx, env, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("sign failed: %s: %w", art.Name, err)
+	}
 
-		stdin = f
+	args, err := signer.Args(ctx, env, cfg.Args)
+	if err != nil {
+		return nil, fmt.Errorf("sign failed: %s: %w", art.Name, err)
+	}
+	if filepath.Base(cfg.Cmd) == "cosign" {
+		args = appendMissingFlags(args, flags)
 	}
 
 	fields := log.Fields{"cmd": cfg.Cmd, "artifact": art.Name}
@@ -212,30 +174,23 @@ func signone(ctx *context.Context, cfg config.Sign, art *artifact.Artifact) ([]*
 		fields["certificate"] = cert
 	}
 
-	// The GoASTScanner flags this as a security risk.
-	// However, this works as intended. The nosec annotation
-	// tells the scanner to ignore this.
-	// #nosec
-	cmd := exec.CommandContext(ctx, cfg.Cmd, args...)
-	var b bytes.Buffer
-	w := gio.Safe(&b)
-	cmd.Stderr = io.MultiWriter(logext.NewConditionalWriter(fields, logext.Error, cfg.Output), w)
-	cmd.Stdout = io.MultiWriter(logext.NewConditionalWriter(fields, logext.Info, cfg.Output), w)
-	if stdin != nil {
-		cmd.Stdin = stdin
-	}
-	cmd.Env = env.Strings()
 	log.WithFields(fields).Info("signing")
-	if err := cmd.Run(); err != nil {
-		return nil, fmt.Errorf("sign: %s failed: %w: %s", cfg.Cmd, err, b.String())
+	if err := signer.Run(ctx, env, signer.Command{
+		Name:      "sign",
+		Cmd:       cfg.Cmd,
+		Stdin:     cfg.Stdin,
+		StdinFile: cfg.StdinFile,
+		Output:    cfg.Output,
+	}, args, fields); err != nil {
+		return nil, err
 	}
 
 	var result []*artifact.Artifact
 
 	// re-execute template results, using artifact desc as artifact so they eval to the actual needed file desc.
 	env["artifact"] = art.Name
-	name, _ = tmpl.New(ctx).WithEnv(env).Apply(expand(cfg.Signature, env))   // could never error as it passed the previous check
-	cert, _ = tmpl.New(ctx).WithEnv(env).Apply(expand(cfg.Certificate, env)) // could never error as it passed the previous check
+	name, _ = signer.Apply(ctx, env, cfg.Signature)   // could never error as it passed the previous check
+	cert, _ = signer.Apply(ctx, env, cfg.Certificate) // could never error as it passed the previous check
 
 	if cfg.Signature != "" {
 		result = append(result, &artifact.Artifact{
@@ -262,20 +217,51 @@ func signone(ctx *context.Context, cfg config.Sign, art *artifact.Artifact) ([]*
 	return result, nil
 }
 
-func expand(s string, env map[string]string) string {
-	return os.Expand(s, func(key string) string {
-		return env[key]
-	})
-}
-
-func templateEnvS(ctx *context.Context, s []string) ([]string, error) {
-	var out []string
-	for _, s := range s {
-		ts, err := tmpl.New(ctx).WithEnvS(out).Apply(s)
+// sigstoreFlags templates and validates the custom sigstore instance options,
+// making them available in the env and returning the respective cosign flags.
+func sigstoreFlags(ctx *context.Context, env context.Env, cfg config.Sign) ([]string, error) {
+	var flags []string
+	for _, opt := range []struct {
+		name, env, flag, value string
+	}{
+		{"fulcio_url", "fulcioURL", "--fulcio-url", cfg.FulcioURL},
+		{"rekor_url", "rekorURL", "--rekor-url", cfg.RekorURL},
+		{"oidc_issuer", "oidcIssuer", "--oidc-issuer", cfg.OIDCIssuer},
+	} {
+		if opt.value == "" {
+			continue
+		}
+		value, err := signer.Apply(ctx, env, opt.value)
 		if err != nil {
 			return nil, err
 		}
-		out = append(out, ts)
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("invalid %s: %q", opt.name, value)
+		}
+		env[opt.env] = value
+		flags = append(flags, opt.flag+"="+value)
+	}
+	return flags, nil
+}
+
+// appendMissingFlags appends the given flags to args, unless they were
+// already set by the user.
+func appendMissingFlags(args, flags []string) []string {
+	for _, flag := range flags {
+		name, _, _ := strings.Cut(flag, "=")
+		if !hasFlag(args, name) {
+			args = append(args, flag)
+		}
+	}
+	return args
+}
+
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == name || strings.HasPrefix(arg, name+"=") {
+			return true
+		}
 	}
-	return out, nil
+	return false
 }
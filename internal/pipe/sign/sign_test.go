@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
@@ -45,6 +46,19 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
+func TestArtifactsPassValidation(t *testing.T) {
+	for _, artifacts := range []string{"none", "all", "checksum", "source", "archive", "binary", "sbom", "package"} {
+		require.NoError(t, config.Validate(config.Project{
+			Signs: []config.Sign{{Artifacts: artifacts}},
+		}), artifacts)
+	}
+	for _, artifacts := range []string{"none", "all", "images", "manifests"} {
+		require.NoError(t, config.Validate(config.Project{
+			DockerSigns: []config.Sign{{Artifacts: artifacts}},
+		}), artifacts)
+	}
+}
+
 func TestDescription(t *testing.T) {
 	require.NotEmpty(t, Pipe{}.String())
 }
@@ -783,3 +797,177 @@ func TestSkip(t *testing.T) {
 		require.False(t, Pipe{}.Skip(ctx))
 	})
 }
+
+func TestSignWithSSHKey(t *testing.T) {
+	testlib.CheckPath(t, "ssh-keygen")
+
+	tmpdir := t.TempDir()
+	key := filepath.Join(tmpdir, "id_ed25519")
+	out, err := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "goreleaser", "-f", key).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	dist := filepath.Join(tmpdir, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	checksums := filepath.Join(dist, "checksums.txt")
+	require.NoError(t, os.WriteFile(checksums, []byte("foo"), 0o644))
+
+	ctx := context.New(config.Project{
+		Dist: dist,
+		Signs: []config.Sign{
+			{
+				Cmd:       "ssh-keygen",
+				Key:       key,
+				Artifacts: "checksum",
+			},
+		},
+	})
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "checksums.txt",
+		Path: checksums,
+		Type: artifact.Checksum,
+	})
+
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, []string{"-Y", "sign", "-n", "file", "-f", "$key", "$artifact"}, ctx.Config.Signs[0].Args)
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	sigs := ctx.Artifacts.Filter(artifact.ByType(artifact.Signature)).List()
+	require.Len(t, sigs, 1)
+	require.Equal(t, "checksums.txt.sig", sigs[0].Name)
+	require.Equal(t, checksums+".sig", sigs[0].Path)
+	require.Equal(t, "default", sigs[0].ID())
+
+	f, err := os.Open(checksums)
+	require.NoError(t, err)
+	defer f.Close()
+	cmd := exec.Command("ssh-keygen", "-Y", "check-novalidate", "-n", "file", "-f", key+".pub", "-s", sigs[0].Path)
+	cmd.Stdin = f
+	out, err = cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestSignWithInvalidKey(t *testing.T) {
+	ctx := context.New(config.Project{
+		Signs: []config.Sign{
+			{
+				Cmd:       "ssh-keygen",
+				Key:       "{{ .Env.NOPE }}",
+				Artifacts: "checksum",
+			},
+		},
+	})
+	testSign(t, ctx, nil, nil, nil, user, `map has no entry for key "NOPE"`)
+
+	ctx = context.New(config.Project{
+		Signs: []config.Sign{
+			{
+				Cmd:       "ssh-keygen",
+				Key:       "testdata/does-not-exist",
+				Artifacts: "checksum",
+			},
+		},
+	})
+	testSign(t, ctx, nil, nil, nil, user, "sign failed: checksum: invalid key: stat testdata/does-not-exist: no such file or directory")
+}
+
+func TestSignKeylessCustomSigstore(t *testing.T) {
+	testlib.CheckPath(t, "sh")
+
+	// fake cosign, which writes its arguments into the signature file.
+	bin := filepath.Join(t.TempDir(), "cosign")
+	require.NoError(t, os.WriteFile(bin, []byte("#!/bin/sh\necho \"$@\" > \"$signature\"\n"), 0o755))
+
+	newCtx := func(tb testing.TB) (*context.Context, *artifact.Artifact) {
+		tb.Helper()
+		dist := tb.TempDir()
+		path := filepath.Join(dist, "checksums.txt")
+		require.NoError(tb, os.WriteFile(path, []byte("foo"), 0o644))
+		ctx := context.New(config.Project{Dist: dist})
+		ctx.Env["SIGSTORE_HOST"] = "sigstore.internal"
+		return ctx, &artifact.Artifact{
+			Name: "checksums.txt",
+			Path: path,
+			Type: artifact.Checksum,
+		}
+	}
+
+	t.Run("flags", func(t *testing.T) {
+		ctx, art := newCtx(t)
+		artifacts, err := signone(ctx, config.Sign{
+			Cmd:        bin,
+			Signature:  "${artifact}.sig",
+			Args:       []string{"sign-blob", "--yes", "$artifact"},
+			FulcioURL:  "https://fulcio.{{ .Env.SIGSTORE_HOST }}",
+			RekorURL:   "https://rekor.sigstore.internal",
+			OIDCIssuer: "https://oidc.sigstore.internal/auth",
+		}, art)
+		require.NoError(t, err)
+		require.Len(t, artifacts, 1)
+
+		bts, err := os.ReadFile(artifacts[0].Path)
+		require.NoError(t, err)
+		require.Equal(t, strings.Join([]string{
+			"sign-blob",
+			"--yes",
+			art.Path,
+			"--fulcio-url=https://fulcio.sigstore.internal",
+			"--rekor-url=https://rekor.sigstore.internal",
+			"--oidc-issuer=https://oidc.sigstore.internal/auth",
+		}, " ")+"\n", string(bts))
+	})
+
+	t.Run("flags already set", func(t *testing.T) {
+		ctx, art := newCtx(t)
+		artifacts, err := signone(ctx, config.Sign{
+			Cmd:       bin,
+			Signature: "${artifact}.sig",
+			Args:      []string{"sign-blob", "--rekor-url=$rekorURL/v2", "$artifact"},
+			RekorURL:  "https://rekor.sigstore.internal",
+		}, art)
+		require.NoError(t, err)
+
+		bts, err := os.ReadFile(artifacts[0].Path)
+		require.NoError(t, err)
+		require.Equal(t, "sign-blob --rekor-url=https://rekor.sigstore.internal/v2 "+art.Path+"\n", string(bts))
+	})
+
+	t.Run("not cosign", func(t *testing.T) {
+		ctx, art := newCtx(t)
+		artifacts, err := signone(ctx, config.Sign{
+			Cmd:        "sh",
+			Signature:  "${artifact}.sig",
+			Args:       []string{"-c", "echo $fulcioURL $oidcIssuer > $signature"},
+			FulcioURL:  "https://fulcio.sigstore.internal",
+			OIDCIssuer: "https://oidc.sigstore.internal",
+		}, art)
+		require.NoError(t, err)
+
+		bts, err := os.ReadFile(artifacts[0].Path)
+		require.NoError(t, err)
+		require.Equal(t, "https://fulcio.sigstore.internal https://oidc.sigstore.internal\n", string(bts))
+	})
+
+	for name, cfg := range map[string]config.Sign{
+		"invalid fulcio_url":  {FulcioURL: "fulcio.sigstore.internal"},
+		"invalid rekor_url":   {RekorURL: "ftp://rekor.sigstore.internal"},
+		"invalid oidc_issuer": {OIDCIssuer: "https://"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, art := newCtx(t)
+			cfg.Cmd = bin
+			cfg.Signature = "${artifact}.sig"
+			_, err := signone(ctx, cfg, art)
+			require.ErrorContains(t, err, name)
+		})
+	}
+
+	t.Run("template error", func(t *testing.T) {
+		ctx, art := newCtx(t)
+		_, err := signone(ctx, config.Sign{
+			Cmd:       bin,
+			Signature: "${artifact}.sig",
+			FulcioURL: "{{ .Nope }}",
+		}, art)
+		testlib.RequireTemplateError(t, err)
+	})
+}
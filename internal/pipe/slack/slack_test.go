@@ -43,6 +43,21 @@ func TestAnnounceMissingEnv(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `slack: env: environment variable "SLACK_WEBHOOK" should not be empty`)
 }
 
+func TestAnnounceDryRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Slack: config.Slack{
+				Enabled:         true,
+				MessageTemplate: "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -51,6 +51,11 @@ func (Pipe) Announce(ctx *context.Context) error {
 		return fmt.Errorf("SMTP: %w", err)
 	}
 
+	if ctx.AnnounceDryRun {
+		log.WithField("subject", subject).WithField("body", body).Info("dry-run, not sending mail")
+		return nil
+	}
+
 	m := gomail.NewMessage()
 
 	// Set E-Mail sender
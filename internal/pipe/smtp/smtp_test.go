@@ -12,6 +12,22 @@ func TestStringer(t *testing.T) {
 	require.NotEmpty(t, Pipe{}.String())
 }
 
+func TestAnnounceDryRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			SMTP: config.SMTP{
+				Enabled:         true,
+				SubjectTemplate: "{{ .Tag }}",
+				BodyTemplate:    "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
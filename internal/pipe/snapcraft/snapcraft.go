@@ -35,6 +35,14 @@ var ErrNoDescription = errors.New("no description provided for snapcraft")
 // ErrNoSummary is shown when no summary provided.
 var ErrNoSummary = errors.New("no summary provided for snapcraft")
 
+// ErrNoCredentials is shown when publishing without being logged in to the
+// snap store.
+var ErrNoCredentials = errors.New("no snap store credentials: set " + credentialsEnv + " or run `snapcraft login` first")
+
+// credentialsEnv is the environment variable snapcraft reads the exported
+// store credentials from.
+const credentialsEnv = "SNAPCRAFT_STORE_CREDENTIALS"
+
 // Metadata to generate the snap package.
 type Metadata struct {
 	Name          string
@@ -49,6 +57,7 @@ type Metadata struct {
 	Layout        map[string]LayoutMetadata `yaml:",omitempty"`
 	Apps          map[string]AppMetadata
 	Plugs         map[string]interface{} `yaml:",omitempty"`
+	Slots         map[string]interface{} `yaml:",omitempty"`
 }
 
 // AppMetadata for the binaries that will be in the snap package.
@@ -118,6 +127,19 @@ func (Pipe) Default(ctx *context.Context) error {
 		if snap.Grade == "" {
 			snap.Grade = "stable"
 		}
+		if err := validate("grade", snap.Grade, validGrades); err != nil {
+			return err
+		}
+		confinement, err := tmpl.New(ctx).Apply(snap.Confinement)
+		if err != nil {
+			return err
+		}
+		snap.Confinement = confinement
+		if snap.Confinement != "" {
+			if err := validate("confinement", snap.Confinement, validConfinements); err != nil {
+				return err
+			}
+		}
 		if len(snap.ChannelTemplates) == 0 {
 			switch snap.Grade {
 			case "devel":
@@ -139,6 +161,21 @@ func (Pipe) Default(ctx *context.Context) error {
 	return ids.Validate()
 }
 
+// https://snapcraft.io/docs/snapcraft-yaml-reference
+var (
+	validGrades       = []string{"stable", "devel"}
+	validConfinements = []string{"strict", "devmode", "classic"}
+)
+
+func validate(field, value string, valid []string) error {
+	for _, v := range valid {
+		if value == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid snapcraft %s: %q, valid options are: %s", field, value, strings.Join(valid, ", "))
+}
+
 // Run the pipe.
 func (Pipe) Run(ctx *context.Context) error {
 	for _, snap := range ctx.Config.Snapcrafts {
@@ -213,6 +250,12 @@ func (Pipe) Publish(ctx *context.Context) error {
 		return pipe.ErrSkipPublishEnabled
 	}
 	snaps := ctx.Artifacts.Filter(artifact.ByType(artifact.PublishableSnapcraft)).List()
+	if len(snaps) == 0 {
+		return nil
+	}
+	if err := checkCredentials(ctx); err != nil {
+		return err
+	}
 	for _, snap := range snaps {
 		if err := push(ctx, snap); err != nil {
 			return err
@@ -383,9 +426,12 @@ func create(ctx *context.Context, snap config.Snapcraft, arch string, binaries [
 		}
 
 		metadata.Apps[name] = appMetadata
-		metadata.Plugs = snap.Plugs
 	}
 
+	// interface definitions, e.g. content interfaces, are shared by all apps.
+	metadata.Plugs = snap.Plugs
+	metadata.Slots = snap.Slots
+
 	out, err := yaml.Marshal(metadata)
 	if err != nil {
 		return err
@@ -427,11 +473,37 @@ const (
 	needsReviewMsg = `(NEEDS REVIEW)`
 )
 
+// checkCredentials checks that snapcraft is able to authenticate against the
+// store, either through the environment or a previous `snapcraft login`.
+func checkCredentials(ctx *context.Context) error {
+	if ctx.Env[credentialsEnv] != "" {
+		return nil
+	}
+	/* #nosec */
+	cmd := exec.CommandContext(ctx, "snapcraft", "whoami")
+	cmd.Env = ctx.Env.Strings()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		log.WithError(err).Debug(string(out))
+		return ErrNoCredentials
+	}
+	return nil
+}
+
+// uploadArgs returns the snapcraft arguments to upload the given snap and
+// release it to its channels, if any.
+func uploadArgs(snap *artifact.Artifact) []string {
+	args := []string{"upload"}
+	if releases := artifact.ExtraOr(*snap, releasesExtra, []string{}); len(releases) > 0 {
+		args = append(args, "--release="+strings.Join(releases, ","))
+	}
+	return append(args, snap.Path)
+}
+
 func push(ctx *context.Context, snap *artifact.Artifact) error {
 	log := log.WithField("snap", snap.Name)
-	releases := artifact.ExtraOr(*snap, releasesExtra, []string{})
 	/* #nosec */
-	cmd := exec.CommandContext(ctx, "snapcraft", "upload", "--release="+strings.Join(releases, ","), snap.Path)
+	cmd := exec.CommandContext(ctx, "snapcraft", uploadArgs(snap)...)
+	cmd.Env = ctx.Env.Strings()
 	log.WithField("args", cmd.Args).Info("pushing snap")
 	if out, err := cmd.CombinedOutput(); err != nil {
 		if strings.Contains(string(out), reviewWaitMsg) || strings.Contains(string(out), humanReviewMsg) || strings.Contains(string(out), needsReviewMsg) {
@@ -471,7 +543,6 @@ var archToSnap = map[string]string{
 	"ppc64le": "ppc64el",
 }
 
-// TODO: write tests for this
 func linuxArch(key string) string {
 	// XXX: list of all linux arches: `go tool dist list | grep linux`
 	arch := strings.TrimPrefix(key, "linux")
@@ -325,6 +325,126 @@ func TestRunPipeMetadata(t *testing.T) {
 	require.Equal(t, "$SNAP_DATA/etc", metadata.Layout["/etc/testprojectname"].Bind)
 }
 
+func TestRunPipePlugsAndSlots(t *testing.T) {
+	testlib.CheckPath(t, "snapcraft")
+	for name, apps := range map[string]map[string]config.SnapcraftAppMetadata{
+		"with apps": {
+			"foo": {
+				Command: "foo",
+				Plugs:   []string{"network", "home", "themes"},
+				Slots:   []string{"foo-data"},
+			},
+		},
+		"without apps": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			folder := t.TempDir()
+			dist := filepath.Join(folder, "dist")
+			require.NoError(t, os.Mkdir(dist, 0o755))
+			ctx := context.New(config.Project{
+				ProjectName: "testprojectname",
+				Dist:        dist,
+				Snapcrafts: []config.Snapcraft{
+					{
+						NameTemplate: "foo_{{.Arch}}",
+						Summary:      "test summary",
+						Description:  "test description",
+						Builds:       []string{"foo"},
+						Apps:         apps,
+						Plugs: map[string]interface{}{
+							"themes": map[string]interface{}{
+								"interface":        "content",
+								"target":           "$SNAP/data-dir/themes",
+								"default-provider": "gtk-common-themes",
+							},
+						},
+						Slots: map[string]interface{}{
+							"foo-data": map[string]interface{}{
+								"interface": "content",
+								"content":   "foo-data",
+								"read":      []string{"$SNAP/data"},
+							},
+						},
+					},
+				},
+			})
+			ctx.Git.CurrentTag = "v1.2.3"
+			ctx.Version = "1.2.3"
+			addBinaries(t, ctx, "foo", dist)
+			require.NoError(t, Pipe{}.Run(ctx))
+			yamlFile, err := os.ReadFile(filepath.Join(dist, "foo_amd64", "prime", "meta", "snap.yaml"))
+			require.NoError(t, err)
+			var metadata Metadata
+			require.NoError(t, yaml.Unmarshal(yamlFile, &metadata))
+			if apps != nil {
+				require.Equal(t, []string{"network", "home", "themes"}, metadata.Apps["foo"].Plugs)
+				require.Equal(t, []string{"foo-data"}, metadata.Apps["foo"].Slots)
+			}
+			require.Equal(t, map[string]interface{}{
+				"interface":        "content",
+				"target":           "$SNAP/data-dir/themes",
+				"default-provider": "gtk-common-themes",
+			}, metadata.Plugs["themes"])
+			require.Equal(t, map[string]interface{}{
+				"interface": "content",
+				"content":   "foo-data",
+				"read":      []interface{}{"$SNAP/data"},
+			}, metadata.Slots["foo-data"])
+		})
+	}
+}
+
+func TestRunPipeArm64TemplatedGrade(t *testing.T) {
+	testlib.CheckPath(t, "snapcraft")
+	folder := t.TempDir()
+	dist := filepath.Join(folder, "dist")
+	require.NoError(t, os.Mkdir(dist, 0o755))
+	ctx := context.New(config.Project{
+		ProjectName: "testprojectname",
+		Dist:        dist,
+		Snapcrafts: []config.Snapcraft{
+			{
+				NameTemplate: "foo_{{.Arch}}",
+				Summary:      "test summary",
+				Description:  "test description",
+				Grade:        "{{ if .Prerelease }}devel{{ else }}stable{{ end }}",
+				Confinement:  "{{ if .Prerelease }}devmode{{ else }}strict{{ end }}",
+				Builds:       []string{"foo"},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.2.3-rc1"
+	ctx.Version = "1.2.3-rc1"
+	ctx.Semver.Prerelease = "rc1"
+	binPath := filepath.Join(dist, "foo")
+	f, err := os.Create(binPath)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "subdir/foo",
+		Path:   binPath,
+		Goarch: "arm64",
+		Goos:   "linux",
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			artifact.ExtraID: "foo",
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	prime := filepath.Join(dist, "foo_arm64", "prime")
+	require.FileExists(t, filepath.Join(prime, "foo"))
+	yamlFile, err := os.ReadFile(filepath.Join(prime, "meta", "snap.yaml"))
+	require.NoError(t, err)
+	var metadata Metadata
+	require.NoError(t, yaml.Unmarshal(yamlFile, &metadata))
+	require.Equal(t, []string{"arm64"}, metadata.Architectures)
+	require.Equal(t, "devel", metadata.Grade)
+	require.Equal(t, "devmode", metadata.Confinement)
+	require.FileExists(t, filepath.Join(dist, "foo_arm64.snap"))
+}
+
 func TestNoSnapcraftInPath(t *testing.T) {
 	path := os.Getenv("PATH")
 	defer func() {
@@ -526,8 +646,55 @@ func TestDefaultGradeTmplError(t *testing.T) {
 	testlib.RequireTemplateError(t, Pipe{}.Default(ctx))
 }
 
+func TestDefaultConfinementTmpl(t *testing.T) {
+	ctx := context.New(config.Project{
+		Builds: []config.Build{{ID: "foo"}},
+		Snapcrafts: []config.Snapcraft{{
+			Grade:       "{{ if .Prerelease }}devel{{ else }}stable{{ end }}",
+			Confinement: "{{ if .Prerelease }}devmode{{ else }}strict{{ end }}",
+		}},
+	})
+	ctx.Semver.Prerelease = "rc1"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, "devel", ctx.Config.Snapcrafts[0].Grade)
+	require.Equal(t, "devmode", ctx.Config.Snapcrafts[0].Confinement)
+}
+
+func TestDefaultConfinementTmplError(t *testing.T) {
+	ctx := context.New(config.Project{
+		Builds:     []config.Build{{ID: "foo"}},
+		Snapcrafts: []config.Snapcraft{{Confinement: "{{.Env.Confinement}}"}},
+	})
+	testlib.RequireTemplateError(t, Pipe{}.Default(ctx))
+}
+
+func TestDefaultInvalidGradeAndConfinement(t *testing.T) {
+	for name, tt := range map[string]struct {
+		snap config.Snapcraft
+		err  string
+	}{
+		"grade": {
+			snap: config.Snapcraft{Grade: "{{ .Env.GRADE }}"},
+			err:  `invalid snapcraft grade: "beta", valid options are: stable, devel`,
+		},
+		"confinement": {
+			snap: config.Snapcraft{Confinement: "jailed"},
+			err:  `invalid snapcraft confinement: "jailed", valid options are: strict, devmode, classic`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Env:        []string{"GRADE=beta"},
+				Snapcrafts: []config.Snapcraft{tt.snap},
+			})
+			require.EqualError(t, Pipe{}.Default(ctx), tt.err)
+		})
+	}
+}
+
 func TestPublish(t *testing.T) {
 	ctx := context.New(config.Project{})
+	ctx.Env[credentialsEnv] = "secret"
 	ctx.Artifacts.Add(&artifact.Artifact{
 		Name:   "mybin",
 		Path:   "nope.snap",
@@ -542,6 +709,68 @@ func TestPublish(t *testing.T) {
 	require.Contains(t, err.Error(), "failed to push nope.snap package")
 }
 
+func TestPublishNoCredentials(t *testing.T) {
+	t.Setenv("PATH", "")
+	ctx := context.New(config.Project{})
+	delete(ctx.Env, credentialsEnv)
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "mybin",
+		Path:   "nope.snap",
+		Goarch: "amd64",
+		Goos:   "linux",
+		Type:   artifact.PublishableSnapcraft,
+		Extra: map[string]interface{}{
+			releasesExtra: []string{"stable"},
+		},
+	})
+	require.ErrorIs(t, Pipe{}.Publish(ctx), ErrNoCredentials)
+}
+
+func TestPublishNothing(t *testing.T) {
+	ctx := context.New(config.Project{})
+	delete(ctx.Env, credentialsEnv)
+	require.NoError(t, Pipe{}.Publish(ctx))
+}
+
+func TestUploadArgs(t *testing.T) {
+	channels := []string{"{{ if .Prerelease }}edge{{ else }}stable{{ end }}"}
+	for name, tt := range map[string]struct {
+		prerelease string
+		channels   []string
+		want       []string
+	}{
+		"prerelease": {
+			prerelease: "rc1",
+			channels:   channels,
+			want:       []string{"upload", "--release=edge", "foo.snap"},
+		},
+		"stable": {
+			channels: channels,
+			want:     []string{"upload", "--release=stable", "foo.snap"},
+		},
+		"multiple": {
+			channels: []string{"edge", "beta", "{{ .Major }}.{{ .Minor }}/candidate"},
+			want:     []string{"upload", "--release=edge,beta,1.2/candidate", "foo.snap"},
+		},
+		"no channels": {
+			want: []string{"upload", "foo.snap"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{})
+			ctx.Semver = context.Semver{Major: 1, Minor: 2, Patch: 3, Prerelease: tt.prerelease}
+			releases, err := processChannelsTemplates(ctx, config.Snapcraft{ChannelTemplates: tt.channels})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, uploadArgs(&artifact.Artifact{
+				Path: "foo.snap",
+				Extra: map[string]interface{}{
+					releasesExtra: releases,
+				},
+			}))
+		})
+	}
+}
+
 func TestPublishSkip(t *testing.T) {
 	ctx := context.New(config.Project{})
 	ctx.SkipPublish = true
@@ -714,6 +943,25 @@ func Test_isValidArch(t *testing.T) {
 	}
 }
 
+func Test_linuxArch(t *testing.T) {
+	for key, want := range map[string]string{
+		"linuxamd64v1":         "amd64",
+		"linuxamd64v3":         "amd64",
+		"linux386":             "i386",
+		"linuxarm64":           "arm64",
+		"linuxarm6":            "armhf",
+		"linuxarm7":            "armhf",
+		"linuxppc64le":         "ppc64el",
+		"linuxs390x":           "s390x",
+		"linuxmipshardfloat":   "mips",
+		"linuxmips64softfloat": "mips64",
+	} {
+		t.Run(key, func(t *testing.T) {
+			require.Equal(t, want, linuxArch(key))
+		})
+	}
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -3,6 +3,7 @@ package snapshot
 
 import (
 	"fmt"
+	"regexp"
 
 	"github.com/caarlos0/log"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
@@ -24,7 +25,11 @@ func (Pipe) Default(ctx *context.Context) error {
 }
 
 func (Pipe) Run(ctx *context.Context) error {
-	name, err := tmpl.New(ctx).Apply(ctx.Config.Snapshot.NameTemplate)
+	name, err := tmpl.New(ctx).
+		WithExtraFields(tmpl.Fields{
+			"Branch": sanitize(ctx.Git.Branch),
+		}).
+		Apply(ctx.Config.Snapshot.NameTemplate)
 	if err != nil {
 		return fmt.Errorf("failed to generate snapshot name: %w", err)
 	}
@@ -35,3 +40,11 @@ func (Pipe) Run(ctx *context.Context) error {
 	log.WithField("version", ctx.Version).Infof("building snapshot...")
 	return nil
 }
+
+var unsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._+-]+`)
+
+// sanitize makes a branch name such as `feature/foo` safe to be used in the
+// snapshot name, which ends up in file names and tags.
+func sanitize(name string) string {
+	return unsafeChars.ReplaceAllString(name, "-")
+}
@@ -65,6 +65,38 @@ func TestSnapshot(t *testing.T) {
 	require.Equal(t, "v1.2.4", ctx.Version)
 }
 
+func TestSnapshotWithBranch(t *testing.T) {
+	for branch, expected := range map[string]string{
+		"main":                  "0.0.0-main",
+		"feature/x":             "0.0.0-feature-x",
+		"feat/foo/bar":          "0.0.0-feat-foo-bar",
+		"fix/weird chars:here~": "0.0.0-fix-weird-chars-here-",
+	} {
+		t.Run(branch, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Snapshot: config.Snapshot{
+					NameTemplate: "0.0.0-{{ .Branch }}",
+				},
+			})
+			ctx.Git.Branch = branch
+			require.NoError(t, Pipe{}.Run(ctx))
+			require.Equal(t, expected, ctx.Version)
+		})
+	}
+}
+
+func TestSnapshotOnlySanitizesBranch(t *testing.T) {
+	ctx := context.New(config.Project{
+		Snapshot: config.Snapshot{
+			NameTemplate: "{{ .Env.PREFIX }}+{{ .Branch }}",
+		},
+	})
+	ctx.Env = map[string]string{"PREFIX": "1.0.0/rc"}
+	ctx.Git.Branch = "feature/x"
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Equal(t, "1.0.0/rc+feature-x", ctx.Version)
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
@@ -2,13 +2,19 @@ package teams
 
 import (
 	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
 
 	goteamsnotify "github.com/atc0005/go-teams-notify/v2"
 	"github.com/atc0005/go-teams-notify/v2/messagecard"
 	"github.com/caarlos0/env/v6"
 	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/httpclient"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/hashicorp/go-multierror"
 )
 
 const (
@@ -16,6 +22,7 @@ const (
 	defaultIcon            = "https://goreleaser.com/static/avatar.png"
 	defaultMessageTemplate = `{{ .ProjectName }} {{ .Tag }} is out! Check it out at {{ .ReleaseURL }}`
 	defaultMessageTitle    = `{{ .ProjectName }} {{ .Tag }} is out!`
+	defaultRetryBackoff    = time.Second
 )
 
 type Pipe struct{}
@@ -40,9 +47,35 @@ func (p Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Announce.Teams.Color == "" {
 		ctx.Config.Announce.Teams.Color = defaultColor
 	}
+	if ctx.Config.Announce.Teams.RetryBackoff == 0 {
+		ctx.Config.Announce.Teams.RetryBackoff = defaultRetryBackoff
+	}
+	if _, err := templateColor(ctx); err != nil {
+		return fmt.Errorf("teams: %w", err)
+	}
+	t := tmpl.New(ctx)
+	if err := t.Validate(ctx.Config.Announce.Teams.TitleTemplate); err != nil {
+		return fmt.Errorf("teams: invalid title template: %w", err)
+	}
+	if err := t.Validate(ctx.Config.Announce.Teams.MessageTemplate); err != nil {
+		return fmt.Errorf("teams: invalid message template: %w", err)
+	}
 	return nil
 }
 
+var hexColorRe = regexp.MustCompile(`^#?[0-9a-fA-F]{6}$`)
+
+func templateColor(ctx *context.Context) (string, error) {
+	color, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Teams.Color)
+	if err != nil {
+		return "", err
+	}
+	if !hexColorRe.MatchString(color) {
+		return "", fmt.Errorf("invalid color %q, must be an hexadecimal color code, e.g. %s", color, defaultColor)
+	}
+	return color, nil
+}
+
 func (p Pipe) Announce(ctx *context.Context) error {
 	title, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Teams.TitleTemplate)
 	if err != nil {
@@ -54,17 +87,34 @@ func (p Pipe) Announce(ctx *context.Context) error {
 		return fmt.Errorf("teams: %w", err)
 	}
 
-	var cfg Config
-	if err := env.Parse(&cfg); err != nil {
+	color, err := templateColor(ctx)
+	if err != nil {
+		return fmt.Errorf("teams: %w", err)
+	}
+
+	if ctx.AnnounceDryRun {
+		log.WithField("message", msg).Info("dry-run, not posting")
+		return nil
+	}
+
+	webhooks, err := webhookURLs(ctx)
+	if err != nil {
 		return fmt.Errorf("teams: %w", err)
 	}
 
 	log.Infof("posting: '%s'", msg)
 
 	client := goteamsnotify.NewTeamsClient()
+	if ctx.Config.Announce.Teams.ClientCert != "" || ctx.Config.Announce.Teams.ClientKey != "" || ctx.Config.Announce.Teams.Retries > 0 {
+		httpClient, err := newHTTPClient(ctx)
+		if err != nil {
+			return fmt.Errorf("teams: %w", err)
+		}
+		client.SetHTTPClient(httpClient)
+	}
 	msgCard := messagecard.NewMessageCard()
 	msgCard.Summary = title
-	msgCard.ThemeColor = ctx.Config.Announce.Teams.Color
+	msgCard.ThemeColor = color
 
 	messageCardSection := messagecard.NewSection()
 	messageCardSection.ActivityTitle = title
@@ -75,9 +125,58 @@ func (p Pipe) Announce(ctx *context.Context) error {
 	if err != nil {
 		return fmt.Errorf("teams: %w", err)
 	}
-	err = client.Send(cfg.Webhook, msgCard)
+
+	return sendAll(webhooks, func(webhook string) error {
+		return client.Send(webhook, msgCard)
+	})
+}
+
+// sendAll sends to all the given webhooks, even if some of them fail, and
+// returns all the errors combined.
+func sendAll(webhooks []string, send func(webhook string) error) error {
+	var result error
+	for _, webhook := range webhooks {
+		if err := send(webhook); err != nil {
+			result = multierror.Append(result, fmt.Errorf("teams: %w", err))
+		}
+	}
+	return result
+}
+
+// webhookURLs returns the webhook URLs to post to, read from the environment
+// variables listed in the config, or from TEAMS_WEBHOOK if none are listed.
+func webhookURLs(ctx *context.Context) ([]string, error) {
+	if len(ctx.Config.Announce.Teams.Webhooks) == 0 {
+		var cfg Config
+		if err := env.Parse(&cfg); err != nil {
+			return nil, err
+		}
+		return []string{cfg.Webhook}, nil
+	}
+
+	var result []string
+	for _, name := range ctx.Config.Announce.Teams.Webhooks {
+		webhook := os.Getenv(name)
+		if webhook == "" {
+			return nil, fmt.Errorf("environment variable %q should not be empty", name)
+		}
+		result = append(result, webhook)
+	}
+	return result, nil
+}
+
+func newHTTPClient(ctx *context.Context) (*http.Client, error) {
+	cert, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Teams.ClientCert)
 	if err != nil {
-		return fmt.Errorf("teams: %w", err)
+		return nil, err
 	}
-	return nil
+	key, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Teams.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	client, err := httpclient.New(false, cert, key)
+	if err != nil {
+		return nil, err
+	}
+	return httpclient.WithRetries(client, ctx.Config.Announce.Teams.Retries, ctx.Config.Announce.Teams.RetryBackoff), nil
 }
@@ -1,10 +1,12 @@
 package teams
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/hashicorp/go-multierror"
 	"github.com/stretchr/testify/require"
 )
 
@@ -16,6 +18,46 @@ func TestDefault(t *testing.T) {
 	ctx := context.New(config.Project{})
 	require.NoError(t, Pipe{}.Default(ctx))
 	require.Equal(t, ctx.Config.Announce.Teams.MessageTemplate, defaultMessageTemplate)
+	require.Equal(t, ctx.Config.Announce.Teams.Color, defaultColor)
+}
+
+func TestDefaultColor(t *testing.T) {
+	t.Run("templated", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Teams: config.Teams{
+					Color: "{{ .Env.COLOR }}",
+				},
+			},
+		})
+		ctx.Env["COLOR"] = "ff00aa"
+		require.NoError(t, Pipe{}.Default(ctx))
+		color, err := templateColor(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "ff00aa", color)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Teams: config.Teams{
+					Color: "#XYZXYZ",
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `teams: invalid color "#XYZXYZ", must be an hexadecimal color code, e.g. #2D313E`)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Teams: config.Teams{
+					Color: "{{ .Foo }",
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `teams: template: tmpl:1: unexpected "}" in operand`)
+	})
 }
 
 func TestAnnounceInvalidTemplate(t *testing.T) {
@@ -30,6 +72,45 @@ func TestAnnounceInvalidTemplate(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `teams: template: tmpl:1: unexpected "}" in operand`)
 }
 
+func TestDefaultInvalidTemplate(t *testing.T) {
+	t.Run("message", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Teams: config.Teams{
+					Enabled:         true,
+					MessageTemplate: "{{ .Foo }",
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `teams: invalid message template: template: tmpl:1: unexpected "}" in operand`)
+	})
+
+	t.Run("title", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Teams: config.Teams{
+					Enabled:       true,
+					TitleTemplate: "{{ .Foo }",
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), `teams: invalid title template: template: tmpl:1: unexpected "}" in operand`)
+	})
+}
+
+func TestDefaultTemplateFunctions(t *testing.T) {
+	ctx := context.New(config.Project{
+		TemplateFunctions: map[string]string{"lower": "tolower"},
+		Announce: config.Announce{
+			Teams: config.Teams{
+				Enabled:         true,
+				MessageTemplate: "{{ .ProjectName | lower }} is out",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+}
+
 func TestAnnounceMissingEnv(t *testing.T) {
 	ctx := context.New(config.Project{
 		Announce: config.Announce{
@@ -42,6 +123,110 @@ func TestAnnounceMissingEnv(t *testing.T) {
 	require.EqualError(t, Pipe{}.Announce(ctx), `teams: env: environment variable "TEAMS_WEBHOOK" should not be empty`)
 }
 
+func TestAnnounceMissingWebhooksEnv(t *testing.T) {
+	t.Setenv("TEAMS_WEBHOOK_OPS", "")
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Teams: config.Teams{
+				Enabled:  true,
+				Webhooks: []string{"TEAMS_WEBHOOK_OPS", "TEAMS_WEBHOOK_PLATFORM"},
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `teams: environment variable "TEAMS_WEBHOOK_OPS" should not be empty`)
+}
+
+func TestAnnounceInvalidClientCert(t *testing.T) {
+	t.Setenv("TEAMS_WEBHOOK", "https://example.com/webhook")
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Teams: config.Teams{
+				Enabled:    true,
+				ClientCert: "testdata/nope.pem",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), "teams: client_cert and client_key must be set together")
+}
+
+func TestWebhooks(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		t.Setenv("TEAMS_WEBHOOK", "https://example.com/default")
+		ctx := context.New(config.Project{})
+		hooks, err := webhookURLs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://example.com/default"}, hooks)
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		t.Setenv("TEAMS_WEBHOOK", "https://example.com/default")
+		t.Setenv("TEAMS_WEBHOOK_OPS", "https://example.com/ops")
+		t.Setenv("TEAMS_WEBHOOK_PLATFORM", "https://example.com/platform")
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Teams: config.Teams{
+					Webhooks: []string{"TEAMS_WEBHOOK_OPS", "TEAMS_WEBHOOK_PLATFORM"},
+				},
+			},
+		})
+		hooks, err := webhookURLs(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"https://example.com/ops", "https://example.com/platform"}, hooks)
+	})
+}
+
+func TestSendAll(t *testing.T) {
+	hooks := []string{"https://example.com/ops", "https://example.com/platform", "https://example.com/qa"}
+
+	t.Run("all succeed", func(t *testing.T) {
+		var sent []string
+		require.NoError(t, sendAll(hooks, func(webhook string) error {
+			sent = append(sent, webhook)
+			return nil
+		}))
+		require.Equal(t, hooks, sent)
+	})
+
+	t.Run("one fails", func(t *testing.T) {
+		var sent []string
+		err := sendAll(hooks, func(webhook string) error {
+			if webhook == "https://example.com/platform" {
+				return errors.New("platform is down")
+			}
+			sent = append(sent, webhook)
+			return nil
+		})
+		require.EqualError(t, err, "1 error occurred:\n\t* teams: platform is down\n\n")
+		require.Equal(t, []string{"https://example.com/ops", "https://example.com/qa"}, sent)
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		err := sendAll(hooks, func(webhook string) error {
+			return errors.New("down")
+		})
+		var merr *multierror.Error
+		require.ErrorAs(t, err, &merr)
+		require.Len(t, merr.Errors, 3)
+	})
+}
+
+func TestAnnounceDryRun(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Teams: config.Teams{
+				Enabled:         true,
+				MessageTemplate: "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
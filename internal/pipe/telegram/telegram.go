@@ -45,6 +45,11 @@ func (Pipe) Announce(ctx *context.Context) error {
 		return fmt.Errorf("telegram: %w", err)
 	}
 
+	if ctx.AnnounceDryRun {
+		log.WithField("message", msg).Info("dry-run, not posting")
+		return nil
+	}
+
 	var cfg Config
 	if err := env.Parse(&cfg); err != nil {
 		return fmt.Errorf("telegram: %w", err)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/caarlos0/go-shellwords"
 	"github.com/caarlos0/log"
@@ -150,6 +151,13 @@ func makeUniversalBinary(ctx *context.Context, opts *build.Options, unibin confi
 	if len(binaries) == 0 {
 		return pipe.Skip(fmt.Sprintf("no darwin binaries found with id %q", unibin.ID))
 	}
+	if err := checkArchs(unibin, binaries); err != nil {
+		if ctx.PartialBuild {
+			// e.g. --single-target, only one of the archs is built
+			return pipe.Skip(err.Error())
+		}
+		return err
+	}
 
 	log.WithField("id", unibin.ID).
 		WithField("binary", path).
@@ -240,6 +248,24 @@ func makeUniversalBinary(ctx *context.Context, opts *build.Options, unibin confi
 	return nil
 }
 
+// checkArchs ensures there are both darwin/amd64 and darwin/arm64 binaries to
+// combine, as a universal binary with a single architecture is useless.
+func checkArchs(unibin config.UniversalBinary, binaries []*artifact.Artifact) error {
+	found := map[string]bool{}
+	for _, bin := range binaries {
+		found[bin.Goarch] = true
+	}
+	for _, arch := range []string{"amd64", "arm64"} {
+		if !found[arch] {
+			return fmt.Errorf(
+				"universal binary %q requires both darwin/amd64 and darwin/arm64 binaries, but no darwin/%s binary was found in builds with ids %s",
+				unibin.ID, arch, strings.Join(unibin.IDs, ", "),
+			)
+		}
+	}
+	return nil
+}
+
 func filterFor(unibin config.UniversalBinary) artifact.Filter {
 	return artifact.And(
 		artifact.ByType(artifact.Binary),
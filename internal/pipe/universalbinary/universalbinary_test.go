@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -204,6 +205,29 @@ func TestRun(t *testing.T) {
 		},
 	})
 
+	ctx7 := context.New(config.Project{
+		Dist: dist,
+		UniversalBinaries: []config.UniversalBinary{
+			{
+				ID:           "foo",
+				IDs:          []string{"foo"},
+				NameTemplate: "foo",
+			},
+		},
+	})
+
+	ctx8 := context.New(config.Project{
+		Dist: dist,
+		UniversalBinaries: []config.UniversalBinary{
+			{
+				ID:           "bar",
+				IDs:          []string{"bar"},
+				NameTemplate: "foo",
+				Replace:      true,
+			},
+		},
+	})
+
 	for arch, path := range paths {
 		cmd := exec.Command("go", "build", "-o", path, src)
 		cmd.Env = append(os.Environ(), "GOOS=darwin", "GOARCH="+arch)
@@ -228,6 +252,21 @@ func TestRun(t *testing.T) {
 		ctx2.Artifacts.Add(&art)
 		ctx5.Artifacts.Add(&art)
 		ctx6.Artifacts.Add(&art)
+		ctx8.Artifacts.Add(&art)
+		ctx8.Artifacts.Add(&artifact.Artifact{
+			Name:   "fake",
+			Path:   path,
+			Goos:   "darwin",
+			Goarch: arch,
+			Type:   artifact.Binary,
+			Extra: map[string]interface{}{
+				artifact.ExtraBinary: "fake",
+				artifact.ExtraID:     "bar",
+			},
+		})
+		if arch == "amd64" {
+			ctx7.Artifacts.Add(&art)
+		}
 		ctx4.Artifacts.Add(&artifact.Artifact{
 			Name:   "fake",
 			Path:   path + "wrong",
@@ -267,6 +306,33 @@ func TestRun(t *testing.T) {
 		require.False(t, artifact.ExtraOr(*unis[0], artifact.ExtraReplaces, true))
 	})
 
+	t.Run("filtering by ids", func(t *testing.T) {
+		require.NoError(t, Pipe{}.Run(ctx8))
+		bins := ctx8.Artifacts.Filter(artifact.ByType(artifact.Binary)).List()
+		require.Len(t, bins, 2)
+		for _, bin := range bins {
+			require.Equal(t, "foo", bin.ID())
+		}
+		unis := ctx8.Artifacts.Filter(artifact.ByType(artifact.UniversalBinary)).List()
+		require.Len(t, unis, 1)
+		checkUniversalBinary(t, unis[0])
+		require.Equal(t, "bar", unis[0].ID())
+	})
+
+	t.Run("missing arch", func(t *testing.T) {
+		require.EqualError(t, Pipe{}.Run(ctx7), `universal binary "foo" requires both darwin/amd64 and darwin/arm64 binaries, but no darwin/arm64 binary was found in builds with ids foo`)
+		require.Empty(t, ctx7.Artifacts.Filter(artifact.ByType(artifact.UniversalBinary)).List())
+	})
+
+	t.Run("missing arch on partial build", func(t *testing.T) {
+		ctx7.PartialBuild = true
+		t.Cleanup(func() { ctx7.PartialBuild = false })
+		err := Pipe{}.Run(ctx7)
+		require.True(t, pipe.IsSkip(err))
+		require.EqualError(t, err, `universal binary "foo" requires both darwin/amd64 and darwin/arm64 binaries, but no darwin/arm64 binary was found in builds with ids foo`)
+		require.Empty(t, ctx7.Artifacts.Filter(artifact.ByType(artifact.UniversalBinary)).List())
+	})
+
 	t.Run("bad template", func(t *testing.T) {
 		testlib.RequireTemplateError(t, Pipe{}.Run(context.New(config.Project{
 			UniversalBinaries: []config.UniversalBinary{
@@ -0,0 +1,138 @@
+// Package upx compresses the built binaries with UPX.
+package upx
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/semerrgroup"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Pipe for UPX.
+type Pipe struct{}
+
+func (Pipe) String() string                 { return "upx" }
+func (Pipe) Skip(ctx *context.Context) bool { return len(ctx.Config.UPXs) == 0 }
+
+// Default sets the pipe defaults.
+func (Pipe) Default(ctx *context.Context) error {
+	for i := range ctx.Config.UPXs {
+		upx := &ctx.Config.UPXs[i]
+		if upx.Binary == "" {
+			upx.Binary = "upx"
+		}
+		if !validCompress(upx.Compress) {
+			return fmt.Errorf("invalid upx compress level: %s, valid options are 1-9 and best", upx.Compress)
+		}
+	}
+	return nil
+}
+
+func validCompress(s string) bool {
+	switch s {
+	case "", "1", "2", "3", "4", "5", "6", "7", "8", "9", "best":
+		return true
+	}
+	return false
+}
+
+// Run the pipe.
+func (Pipe) Run(ctx *context.Context) error {
+	// check all binaries first, so we don't return while compressions are
+	// still running.
+	for _, upx := range ctx.Config.UPXs {
+		if !upx.Enabled {
+			continue
+		}
+		if _, err := exec.LookPath(upx.Binary); err != nil {
+			return fmt.Errorf("upx binary not found: %w", err)
+		}
+	}
+
+	g := semerrgroup.New(ctx.Parallelism)
+	for _, upx := range ctx.Config.UPXs {
+		upx := upx
+		if !upx.Enabled {
+			log.Debug("upx is not enabled")
+			continue
+		}
+		for _, bin := range findBinaries(ctx, upx) {
+			bin := bin
+			if bin.Goos == "darwin" {
+				// UPX compressed macOS binaries are either rejected by UPX
+				// itself or fail to run on recent macOS versions.
+				log.WithField("binary", bin.Path).Warn("skipping upx on darwin binary")
+				continue
+			}
+			g.Go(func() error {
+				return compress(ctx, upx, bin)
+			})
+		}
+	}
+	return g.Wait()
+}
+
+// knownExceptions are the UPX errors that only mean that the binary can't be
+// compressed, in which case we warn instead of failing.
+var knownExceptions = []string{
+	"CantPackException",
+	"AlreadyPackedException",
+	"NotCompressibleException",
+	"UnknownExecutableFormatException",
+}
+
+func compress(ctx *context.Context, upx config.UPX, bin *artifact.Artifact) error {
+	args := []string{"--quiet"}
+	switch upx.Compress {
+	case "":
+	case "best":
+		args = append(args, "--best")
+	default:
+		args = append(args, "-"+upx.Compress)
+	}
+	if upx.LZMA {
+		args = append(args, "--lzma")
+	}
+	args = append(args, bin.Path)
+
+	log := log.WithField("binary", bin.Path)
+	log.WithField("args", args).Debug("running upx")
+	/* #nosec */
+	out, err := exec.CommandContext(ctx, upx.Binary, args...).CombinedOutput()
+	if err != nil {
+		for _, exception := range knownExceptions {
+			if strings.Contains(string(out), exception) {
+				log.WithField("exception", exception).Warn("could not compress binary")
+				return nil
+			}
+		}
+		return fmt.Errorf("failed to compress %s: %w: %s", bin.Path, err, string(out))
+	}
+	log.Info("compressed binary")
+	return nil
+}
+
+func findBinaries(ctx *context.Context, upx config.UPX) []*artifact.Artifact {
+	filters := []artifact.Filter{
+		artifact.Or(
+			artifact.ByType(artifact.Binary),
+			artifact.ByType(artifact.UniversalBinary),
+		),
+	}
+	if len(upx.IDs) > 0 {
+		filters = append(filters, artifact.ByIDs(upx.IDs...))
+	}
+	if len(upx.Goos) > 0 {
+		goosFilters := make([]artifact.Filter, 0, len(upx.Goos))
+		for _, goos := range upx.Goos {
+			goosFilters = append(goosFilters, artifact.ByGoos(goos))
+		}
+		filters = append(filters, artifact.Or(goosFilters...))
+	}
+	return ctx.Artifacts.Filter(artifact.And(filters...)).List()
+}
@@ -0,0 +1,200 @@
+package upx
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringer(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			UPXs: []config.UPX{{}},
+		})
+		require.False(t, Pipe{}.Skip(ctx))
+	})
+}
+
+func TestDefault(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			UPXs: []config.UPX{{}},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.Equal(t, "upx", ctx.Config.UPXs[0].Binary)
+	})
+
+	t.Run("invalid compress", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			UPXs: []config.UPX{{Compress: "10"}},
+		})
+		require.EqualError(t, Pipe{}.Default(ctx), "invalid upx compress level: 10, valid options are 1-9 and best")
+	})
+}
+
+// fakeUPX writes a fake upx binary that logs the arguments it was called with
+// and appends "packed" to the given file.
+// Files containing "cantpack" or "broken" make it fail.
+func fakeUPX(tb testing.TB, folder string) (string, string) {
+	tb.Helper()
+	logFile := filepath.Join(folder, "upx.log")
+	bin := filepath.Join(folder, "fakeupx")
+	script := fmt.Sprintf(`#!/bin/sh
+echo "$@" >> %[1]s
+for f; do :; done
+if grep -q cantpack "$f"; then
+	echo "upx: $f: CantPackException: can't pack new-exe"
+	exit 2
+fi
+if grep -q broken "$f"; then
+	echo "something bad happened"
+	exit 1
+fi
+echo packed >> "$f"
+`, logFile)
+	require.NoError(tb, os.WriteFile(bin, []byte(script), 0o755))
+	return bin, logFile
+}
+
+func addBinary(tb testing.TB, ctx *context.Context, folder, id, goos, goarch, content string) string {
+	tb.Helper()
+	path := filepath.Join(folder, id+"_"+goos+"_"+goarch, id)
+	require.NoError(tb, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(tb, os.WriteFile(path, []byte(content+"\n"), 0o755))
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   id,
+		Path:   path,
+		Goos:   goos,
+		Goarch: goarch,
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			artifact.ExtraID: id,
+		},
+	})
+	return path
+}
+
+func requireContent(tb testing.TB, path, expected string) {
+	tb.Helper()
+	bts, err := os.ReadFile(path)
+	require.NoError(tb, err)
+	require.Equal(tb, expected, string(bts))
+}
+
+func TestRun(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	bin, logFile := fakeUPX(t, folder)
+
+	ctx := context.New(config.Project{
+		UPXs: []config.UPX{
+			{
+				Enabled:  true,
+				Binary:   bin,
+				IDs:      []string{"foo"},
+				Goos:     []string{"linux", "windows", "darwin"},
+				Compress: "9",
+				LZMA:     true,
+			},
+			{
+				Enabled:  true,
+				Binary:   bin,
+				IDs:      []string{"bar"},
+				Compress: "best",
+			},
+			{
+				Enabled: false,
+				Binary:  bin,
+				IDs:     []string{"disabled"},
+			},
+		},
+	})
+	fooLinux := addBinary(t, ctx, folder, "foo", "linux", "amd64", "foo")
+	fooWindows := addBinary(t, ctx, folder, "foo", "windows", "arm64", "foo")
+	fooDarwin := addBinary(t, ctx, folder, "foo", "darwin", "arm64", "foo")
+	fooFreebsd := addBinary(t, ctx, folder, "foo", "freebsd", "amd64", "foo")
+	barLinux := addBinary(t, ctx, folder, "bar", "linux", "arm64", "bar")
+	barCantPack := addBinary(t, ctx, folder, "bar", "linux", "ppc64", "cantpack")
+	disabled := addBinary(t, ctx, folder, "disabled", "linux", "amd64", "disabled")
+
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	requireContent(t, fooLinux, "foo\npacked\n")
+	requireContent(t, fooWindows, "foo\npacked\n")
+	requireContent(t, barLinux, "bar\npacked\n")
+
+	// filtered out, skipped, or failed with a known exception
+	requireContent(t, fooDarwin, "foo\n")
+	requireContent(t, fooFreebsd, "foo\n")
+	requireContent(t, barCantPack, "cantpack\n")
+	requireContent(t, disabled, "disabled\n")
+
+	bts, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	calls := strings.Split(strings.TrimSpace(string(bts)), "\n")
+	require.ElementsMatch(t, []string{
+		"--quiet -9 --lzma " + fooLinux,
+		"--quiet -9 --lzma " + fooWindows,
+		"--quiet --best " + barLinux,
+		"--quiet --best " + barCantPack,
+	}, calls)
+
+	// no new artifacts are registered
+	require.Len(t, ctx.Artifacts.List(), 7)
+}
+
+func TestRunFailed(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	bin, _ := fakeUPX(t, folder)
+
+	ctx := context.New(config.Project{
+		UPXs: []config.UPX{{Enabled: true, Binary: bin}},
+	})
+	path := addBinary(t, ctx, folder, "foo", "linux", "amd64", "broken")
+
+	require.NoError(t, Pipe{}.Default(ctx))
+	err := Pipe{}.Run(ctx)
+	require.ErrorContains(t, err, "failed to compress "+path)
+	require.ErrorContains(t, err, "something bad happened")
+}
+
+func TestRunNoUPX(t *testing.T) {
+	ctx := context.New(config.Project{
+		UPXs: []config.UPX{{Enabled: true, Binary: "upx-does-not-exist"}},
+	})
+	require.ErrorContains(t, Pipe{}.Run(ctx), `upx binary not found: exec: "upx-does-not-exist"`)
+}
+
+func TestRunNoUPXAfterValid(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	bin, logFile := fakeUPX(t, folder)
+
+	ctx := context.New(config.Project{
+		UPXs: []config.UPX{
+			{Enabled: true, Binary: bin},
+			{Enabled: true, Binary: "upx-does-not-exist"},
+		},
+	})
+	path := addBinary(t, ctx, folder, "foo", "linux", "amd64", "foo")
+
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.ErrorContains(t, Pipe{}.Run(ctx), `upx binary not found: exec: "upx-does-not-exist"`)
+	requireContent(t, path, "foo\n")
+	require.NoFileExists(t, logFile)
+}
@@ -0,0 +1,129 @@
+// Package verify implements a Pipe that downloads the artifacts uploaded to
+// the release and checks them against the generated checksums.
+package verify
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/semerrgroup"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+var errNotSupported = errors.New("verifying uploaded artifacts is only supported on GitHub")
+
+// Pipe that verifies the uploaded artifacts.
+type Pipe struct{}
+
+func (Pipe) String() string { return "verifying uploaded artifacts" }
+func (Pipe) Skip(ctx *context.Context) bool {
+	return !ctx.Config.Release.VerifyChecksums || ctx.SkipPublish || ctx.ReleaseID == ""
+}
+
+// Run the pipe.
+func (Pipe) Run(ctx *context.Context) error {
+	c, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	return verify(ctx, c)
+}
+
+func verify(ctx *context.Context, cli client.Client) error {
+	downloader, ok := cli.(client.ReleaseAssetDownloader)
+	if !ok {
+		return errNotSupported
+	}
+
+	sums, err := readChecksums(ctx)
+	if err != nil {
+		return err
+	}
+	if len(sums) == 0 {
+		return pipe.Skip("no checksums to verify against")
+	}
+
+	uploaded := ctx.Artifacts.Filter(func(a *artifact.Artifact) bool {
+		return artifact.ExtraOr(*a, artifact.ExtraURL, "") != ""
+	}).List()
+
+	g := semerrgroup.New(ctx.Parallelism)
+	for _, a := range uploaded {
+		expected, ok := sums[a.Name]
+		if !ok {
+			continue
+		}
+		name := a.Name
+		g.Go(func() error {
+			return verifyOne(ctx, downloader, name, expected)
+		})
+	}
+	return g.Wait()
+}
+
+// readChecksums reads all the checksum files, returning the expected checksum
+// for each file name in them.
+func readChecksums(ctx *context.Context) (map[string]string, error) {
+	sums := map[string]string{}
+	for _, a := range ctx.Artifacts.Filter(artifact.ByType(artifact.Checksum)).List() {
+		f, err := os.Open(a.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checksums: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 2 {
+				continue
+			}
+			sums[fields[1]] = fields[0]
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read checksums: %s: %w", a.Path, err)
+		}
+	}
+	return sums, nil
+}
+
+func verifyOne(ctx *context.Context, downloader client.ReleaseAssetDownloader, name, expected string) error {
+	log := log.WithField("file", name)
+	log.Info("verifying")
+	rc, err := downloader.DownloadReleaseAsset(ctx, ctx.ReleaseID, name)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", name, err)
+	}
+	defer rc.Close()
+
+	tmp, err := os.CreateTemp("", "goreleaser-verify-*")
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, rc)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", name, err)
+	}
+
+	got, err := artifact.Artifact{Path: tmp.Name()}.Checksum(ctx.Config.Checksum.Algorithm)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %w", name, err)
+	}
+	if got != expected {
+		return fmt.Errorf("%s: checksum mismatch: expected %s, got %s", name, expected, got)
+	}
+	log.Debug("checksum matches")
+	return nil
+}
@@ -0,0 +1,171 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescription(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		ctx.ReleaseID = "1"
+		require.True(t, Pipe{}.Skip(ctx))
+	})
+
+	t.Run("skip publish", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Release: config.Release{VerifyChecksums: true},
+		})
+		ctx.ReleaseID = "1"
+		ctx.SkipPublish = true
+		require.True(t, Pipe{}.Skip(ctx))
+	})
+
+	t.Run("no release", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Release: config.Release{VerifyChecksums: true},
+		})
+		require.True(t, Pipe{}.Skip(ctx))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Release: config.Release{VerifyChecksums: true},
+		})
+		ctx.ReleaseID = "1"
+		require.False(t, Pipe{}.Skip(ctx))
+	})
+}
+
+func sha256sum(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestVerify(t *testing.T) {
+	contents := map[string]string{
+		"foo_linux_amd64.tar.gz":  "linux archive",
+		"foo_darwin_arm64.tar.gz": "darwin archive",
+	}
+
+	setup := func(tb testing.TB) *context.Context {
+		tb.Helper()
+		folder := tb.TempDir()
+		ctx := context.New(config.Project{
+			Dist: folder,
+			Release: config.Release{
+				VerifyChecksums: true,
+			},
+			Checksum: config.Checksum{
+				Algorithm: "sha256",
+			},
+		})
+		ctx.ReleaseID = "123"
+
+		var sums string
+		for name, content := range contents {
+			path := filepath.Join(folder, name)
+			require.NoError(tb, os.WriteFile(path, []byte(content), 0o644))
+			ctx.Artifacts.Add(&artifact.Artifact{
+				Name: name,
+				Path: path,
+				Type: artifact.UploadableArchive,
+				Extra: map[string]interface{}{
+					artifact.ExtraURL: "https://example.com/" + name,
+				},
+			})
+			sums += fmt.Sprintf("%s  %s\n", sha256sum(content), name)
+		}
+
+		// not uploaded, so it should not be verified.
+		sums += fmt.Sprintf("%s  %s\n", sha256sum("local"), "foo_linux_amd64")
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: "foo_linux_amd64",
+			Path: filepath.Join(folder, "foo_linux_amd64"),
+			Type: artifact.Binary,
+		})
+
+		checksums := filepath.Join(folder, "checksums.txt")
+		require.NoError(tb, os.WriteFile(checksums, []byte(sums), 0o644))
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: "checksums.txt",
+			Path: checksums,
+			Type: artifact.Checksum,
+			Extra: map[string]interface{}{
+				artifact.ExtraURL: "https://example.com/checksums.txt",
+			},
+		})
+		return ctx
+	}
+
+	t.Run("good", func(t *testing.T) {
+		ctx := setup(t)
+		cli := client.NewMock()
+		cli.ReleaseAssets = map[string]string{
+			"foo_linux_amd64.tar.gz":  contents["foo_linux_amd64.tar.gz"],
+			"foo_darwin_arm64.tar.gz": contents["foo_darwin_arm64.tar.gz"],
+		}
+		require.NoError(t, verify(ctx, cli))
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		ctx := setup(t)
+		cli := client.NewMock()
+		cli.ReleaseAssets = map[string]string{
+			"foo_linux_amd64.tar.gz":  contents["foo_linux_amd64.tar.gz"],
+			"foo_darwin_arm64.tar.gz": "corrupted",
+		}
+		require.EqualError(t, verify(ctx, cli), fmt.Sprintf(
+			"foo_darwin_arm64.tar.gz: checksum mismatch: expected %s, got %s",
+			sha256sum(contents["foo_darwin_arm64.tar.gz"]),
+			sha256sum("corrupted"),
+		))
+	})
+
+	t.Run("missing from release", func(t *testing.T) {
+		ctx := setup(t)
+		cli := client.NewMock()
+		cli.ReleaseAssets = map[string]string{
+			"foo_linux_amd64.tar.gz": contents["foo_linux_amd64.tar.gz"],
+		}
+		require.EqualError(t, verify(ctx, cli), "failed to verify foo_darwin_arm64.tar.gz: foo_darwin_arm64.tar.gz not found in release 123")
+	})
+
+	t.Run("no checksums", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		ctx.ReleaseID = "123"
+		err := verify(ctx, client.NewMock())
+		require.True(t, pipe.IsSkip(err), err)
+	})
+
+	t.Run("missing checksums file", func(t *testing.T) {
+		ctx := context.New(config.Project{})
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: "checksums.txt",
+			Path: filepath.Join(t.TempDir(), "checksums.txt"),
+			Type: artifact.Checksum,
+		})
+		require.ErrorContains(t, verify(ctx, client.NewMock()), "failed to read checksums")
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		ctx := setup(t)
+		cli := struct{ client.Client }{client.NewMock()}
+		require.ErrorIs(t, verify(ctx, cli), errNotSupported)
+	})
+}
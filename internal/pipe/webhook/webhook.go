@@ -1,16 +1,17 @@
 package webhook
 
 import (
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v6"
 	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/httpclient"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
@@ -24,6 +25,15 @@ const (
 	DefaultContentType     = "application/json; charset=utf-8"
 )
 
+const defaultRetryBackoff = time.Second
+
+var defaultExpectedStatusCodes = []int{
+	http.StatusOK,
+	http.StatusCreated,
+	http.StatusAccepted,
+	http.StatusNoContent,
+}
+
 type Pipe struct{}
 
 func (Pipe) String() string                 { return "webhook" }
@@ -41,6 +51,9 @@ func (p Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Announce.Webhook.ContentType == "" {
 		ctx.Config.Announce.Webhook.ContentType = DefaultContentType
 	}
+	if ctx.Config.Announce.Webhook.RetryBackoff == 0 {
+		ctx.Config.Announce.Webhook.RetryBackoff = defaultRetryBackoff
+	}
 	return nil
 }
 
@@ -71,22 +84,37 @@ func (p Pipe) Announce(ctx *context.Context) error {
 		return fmt.Errorf("webhook: %s", err)
 	}
 
-	log.Infof("posting: '%s'", msg)
-	customTransport := http.DefaultTransport.(*http.Transport).Clone()
+	if ctx.AnnounceDryRun {
+		log.WithField("url", endpointURL).WithField("message", msg).Info("dry-run, not posting")
+		return nil
+	}
 
-	customTransport.TLSClientConfig = &tls.Config{
-		InsecureSkipVerify: ctx.Config.Announce.Webhook.SkipTLSVerify,
+	contentType, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Webhook.ContentType)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
 	}
 
-	client := &http.Client{
-		Transport: customTransport,
+	headers := map[string]string{}
+	for key, value := range ctx.Config.Announce.Webhook.Headers {
+		value, err := tmpl.New(ctx).Apply(value)
+		if err != nil {
+			return fmt.Errorf("webhook: header %s: %w", key, err)
+		}
+		headers[key] = value
+	}
+
+	client, err := newClient(ctx)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
 	}
 
+	log.Infof("posting: '%s'", msg)
+
 	req, err := http.NewRequest(http.MethodPost, endpointURL.String(), strings.NewReader(msg))
 	if err != nil {
 		return fmt.Errorf("webhook: %w", err)
 	}
-	req.Header.Add(ContentTypeHeaderKey, ctx.Config.Announce.Webhook.ContentType)
+	req.Header.Add(ContentTypeHeaderKey, contentType)
 	req.Header.Add(UserAgentHeaderKey, UserAgentHeaderValue)
 
 	if cfg.BasicAuthHeader != "" {
@@ -97,7 +125,7 @@ func (p Pipe) Announce(ctx *context.Context) error {
 		req.Header.Add(AuthorizationHeaderKey, cfg.BearerTokenHeader)
 	}
 
-	for key, value := range ctx.Config.Announce.Webhook.Headers {
+	for key, value := range headers {
 		log.Debugf("Header Key %s / Value %s", key, value)
 		req.Header.Add(key, value)
 	}
@@ -107,13 +135,33 @@ func (p Pipe) Announce(ctx *context.Context) error {
 	}
 	defer resp.Body.Close()
 
-	switch resp.StatusCode {
-	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
-		log.Infof("Post OK: '%v'", resp.StatusCode)
-		body, _ := io.ReadAll(resp.Body)
-		log.Infof("Response : %v\n", string(body))
-		return nil
-	default:
-		return fmt.Errorf("request failed with status %v", resp.Status)
+	body, _ := io.ReadAll(resp.Body)
+	expectedStatusCodes := ctx.Config.Announce.Webhook.ExpectedStatusCodes
+	if len(expectedStatusCodes) == 0 {
+		expectedStatusCodes = defaultExpectedStatusCodes
+	}
+	for _, code := range expectedStatusCodes {
+		if resp.StatusCode == code {
+			log.Infof("Post OK: '%v'", resp.StatusCode)
+			log.Infof("Response : %v\n", string(body))
+			return nil
+		}
+	}
+	return fmt.Errorf("request failed with status %v: %s", resp.Status, string(body))
+}
+
+func newClient(ctx *context.Context) (*http.Client, error) {
+	cert, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Webhook.ClientCert)
+	if err != nil {
+		return nil, err
+	}
+	key, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Webhook.ClientKey)
+	if err != nil {
+		return nil, err
+	}
+	client, err := httpclient.New(ctx.Config.Announce.Webhook.SkipTLSVerify, cert, key)
+	if err != nil {
+		return nil, err
 	}
+	return httpclient.WithRetries(client, ctx.Config.Announce.Webhook.Retries, ctx.Config.Announce.Webhook.RetryBackoff), nil
 }
@@ -1,15 +1,19 @@
 package webhook
 
 import (
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
@@ -221,6 +225,171 @@ func TestAnnounceAdditionalHeadersWebhook(t *testing.T) {
 	require.NoError(t, Pipe{}.Announce(ctx))
 }
 
+func TestAnnounceTemplatedHeadersWebhook(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		require.Equal(t, "webhook-test", r.Header.Get("X-Project"))
+		require.Equal(t, "text/plain", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		ProjectName: "webhook-test",
+		Announce: config.Announce{
+			Webhook: config.Webhook{
+				EndpointURL:     srv.URL,
+				MessageTemplate: "{{ .ProjectName }}",
+				ContentType:     "{{ .Env.CONTENT_TYPE }}",
+				Headers: map[string]string{
+					"X-Project": "{{ .ProjectName }}",
+				},
+			},
+		},
+	})
+	ctx.Env["CONTENT_TYPE"] = "text/plain"
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceInvalidHeaderTemplate(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: config.Webhook{
+				EndpointURL: "https://example.com/webhook",
+				Headers: map[string]string{
+					"X-Project": "{{ .Foo }",
+				},
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Announce(ctx), `webhook: header X-Project: template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestAnnounceExpectedStatusCodes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte("queued"))
+	}))
+	defer srv.Close()
+
+	t.Run("default", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Webhook: config.Webhook{
+					EndpointURL: srv.URL,
+				},
+			},
+		})
+		require.NoError(t, Pipe{}.Announce(ctx))
+	})
+
+	t.Run("unexpected", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Webhook: config.Webhook{
+					EndpointURL:         srv.URL,
+					ExpectedStatusCodes: []int{http.StatusOK, http.StatusCreated},
+				},
+			},
+		})
+		require.EqualError(t, Pipe{}.Announce(ctx), "request failed with status 202 Accepted: queued")
+	})
+}
+
+func TestAnnounceClientCertWebhook(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		require.Len(t, r.TLS.PeerCertificates, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAnyClientCert,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	cert, key := testlib.ClientCert(t)
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: config.Webhook{
+				EndpointURL:   srv.URL,
+				SkipTLSVerify: true,
+				ClientCert:    cert,
+				ClientKey:     "{{ .Env.KEY_DIR }}/key.pem",
+			},
+		},
+	})
+	ctx.Env["KEY_DIR"] = filepath.Dir(key)
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceInvalidClientCertWebhook(t *testing.T) {
+	_, key := testlib.ClientCert(t)
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: config.Webhook{
+				EndpointURL: "https://example.com/webhook",
+				ClientCert:  "testdata/nope.pem",
+				ClientKey:   key,
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Announce(ctx), "webhook: failed to load client certificate: open testdata/nope.pem: no such file or directory")
+}
+
+func TestAnnounceRetriesWebhook(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer r.Body.Close()
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, "webhook-test", string(body))
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		ProjectName: "webhook-test",
+		Announce: config.Announce{
+			Webhook: config.Webhook{
+				EndpointURL:     srv.URL,
+				MessageTemplate: "{{ .ProjectName }}",
+				Retries:         2,
+				RetryBackoff:    time.Millisecond,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, 3, calls)
+}
+
+func TestAnnounceDryRun(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("should not post on dry-run")
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: config.Webhook{
+				Enabled:         true,
+				EndpointURL:     srv.URL,
+				MessageTemplate: "{{ .Tag }} is out",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.AnnounceDryRun = true
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+}
+
 func TestSkip(t *testing.T) {
 	t.Run("skip", func(t *testing.T) {
 		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
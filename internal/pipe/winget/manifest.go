@@ -0,0 +1,75 @@
+package winget
+
+type manifest interface {
+	manifestType() string
+}
+
+// Version is the winget version manifest.
+// more info: https://github.com/microsoft/winget-pkgs/blob/master/doc/manifest/schema/1.4.0/version.md
+type Version struct {
+	PackageIdentifier string `yaml:"PackageIdentifier"`
+	PackageVersion    string `yaml:"PackageVersion"`
+	DefaultLocale     string `yaml:"DefaultLocale"`
+	ManifestType      string `yaml:"ManifestType"`
+	ManifestVersion   string `yaml:"ManifestVersion"`
+}
+
+func (v Version) manifestType() string { return v.ManifestType }
+
+// Installer is the winget installer manifest.
+// more info: https://github.com/microsoft/winget-pkgs/blob/master/doc/manifest/schema/1.4.0/installer.md
+type Installer struct {
+	PackageIdentifier string          `yaml:"PackageIdentifier"`
+	PackageVersion    string          `yaml:"PackageVersion"`
+	InstallerLocale   string          `yaml:"InstallerLocale"`
+	ReleaseDate       string          `yaml:"ReleaseDate"`
+	Installers        []InstallerItem `yaml:"Installers"`
+	ManifestType      string          `yaml:"ManifestType"`
+	ManifestVersion   string          `yaml:"ManifestVersion"`
+}
+
+func (i Installer) manifestType() string { return i.ManifestType }
+
+// InstallerItem is a single installer, for a single architecture.
+type InstallerItem struct {
+	Architecture         string                `yaml:"Architecture"`
+	InstallerType        string                `yaml:"InstallerType"`
+	NestedInstallerType  string                `yaml:"NestedInstallerType,omitempty"`
+	NestedInstallerFiles []NestedInstallerFile `yaml:"NestedInstallerFiles,omitempty"`
+	Commands             []string              `yaml:"Commands,omitempty"`
+	InstallerURL         string                `yaml:"InstallerUrl"`
+	InstallerSha256      string                `yaml:"InstallerSha256"`
+	UpgradeBehavior      string                `yaml:"UpgradeBehavior"`
+}
+
+// NestedInstallerFile is a file inside a zip installer.
+type NestedInstallerFile struct {
+	RelativeFilePath     string `yaml:"RelativeFilePath"`
+	PortableCommandAlias string `yaml:"PortableCommandAlias,omitempty"`
+}
+
+// Locale is the winget default locale manifest.
+// more info: https://github.com/microsoft/winget-pkgs/blob/master/doc/manifest/schema/1.4.0/defaultLocale.md
+type Locale struct {
+	PackageIdentifier string   `yaml:"PackageIdentifier"`
+	PackageVersion    string   `yaml:"PackageVersion"`
+	PackageLocale     string   `yaml:"PackageLocale"`
+	Publisher         string   `yaml:"Publisher"`
+	PublisherURL      string   `yaml:"PublisherUrl,omitempty"`
+	Author            string   `yaml:"Author,omitempty"`
+	PackageName       string   `yaml:"PackageName"`
+	PackageURL        string   `yaml:"PackageUrl,omitempty"`
+	License           string   `yaml:"License"`
+	LicenseURL        string   `yaml:"LicenseUrl,omitempty"`
+	Copyright         string   `yaml:"Copyright,omitempty"`
+	ShortDescription  string   `yaml:"ShortDescription"`
+	Description       string   `yaml:"Description,omitempty"`
+	Moniker           string   `yaml:"Moniker,omitempty"`
+	Tags              []string `yaml:"Tags,omitempty"`
+	ReleaseNotes      string   `yaml:"ReleaseNotes,omitempty"`
+	ReleaseNotesURL   string   `yaml:"ReleaseNotesUrl,omitempty"`
+	ManifestType      string   `yaml:"ManifestType"`
+	ManifestVersion   string   `yaml:"ManifestVersion"`
+}
+
+func (l Locale) manifestType() string { return l.ManifestType }
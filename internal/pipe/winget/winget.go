@@ -0,0 +1,468 @@
+// Package winget implements Piper and Publisher, providing winget manifests
+// creation and upload to a repository (usually a fork of winget-pkgs).
+//
+// nolint:tagliatelle
+package winget
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/commitauthor"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/internal/yaml"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const (
+	wingetConfigExtra = "WingetConfig"
+	manifestVersion   = "1.4.0"
+	defaultLocale     = "en-US"
+)
+
+var (
+	// ErrNoWindowsArtifacts happens when there are no windows zip archives,
+	// exe binaries or msi installers to add to the manifest.
+	ErrNoWindowsArtifacts = errors.New("winget requires a windows zip archive, exe binary or msi installer")
+
+	errNoRepoName               = errors.New("winget.repository.name is required")
+	errNoPublisher              = errors.New("winget.publisher is required")
+	errNoPublisherURL           = errors.New("winget.publisher_url is required")
+	errNoLicense                = errors.New("winget.license is required")
+	errNoShortDescription       = errors.New("winget.short_description is required")
+	errInvalidPackageIdentifier = errors.New("winget.package_identifier is invalid, it should look like Publisher.Name")
+
+	// https://github.com/microsoft/winget-cli/blob/master/schemas/JSON/manifests/v1.4.0/manifest.version.1.4.0.json
+	packageIdentifierRegex = regexp.MustCompile(`^[^\.\s\\/:\*\?"<>\|\x01-\x1f]{1,32}(\.[^\.\s\\/:\*\?"<>\|\x01-\x1f]{1,32}){1,7}$`)
+)
+
+// Pipe for winget manifests creation and deployment.
+type Pipe struct{}
+
+func (Pipe) String() string                 { return "winget" }
+func (Pipe) Skip(ctx *context.Context) bool { return len(ctx.Config.Winget) == 0 }
+
+func (Pipe) Default(ctx *context.Context) error {
+	for i := range ctx.Config.Winget {
+		winget := &ctx.Config.Winget[i]
+
+		winget.CommitAuthor = commitauthor.Default(winget.CommitAuthor)
+		if err := client.CheckSigning(winget.CommitAuthor, winget.Repository); err != nil {
+			return err
+		}
+		if winget.CommitMessageTemplate == "" {
+			winget.CommitMessageTemplate = "New version: {{ .PackageIdentifier }} {{ .Version }}"
+		}
+		if winget.Name == "" {
+			winget.Name = ctx.Config.ProjectName
+		}
+		if winget.Goamd64 == "" {
+			winget.Goamd64 = "v1"
+		}
+		if err := client.DefaultPullRequestBase(&winget.PullRequest, "microsoft", "winget-pkgs"); err != nil {
+			return fmt.Errorf("winget: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (Pipe) Run(ctx *context.Context) error {
+	cli, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+
+	return runAll(ctx, cli)
+}
+
+func runAll(ctx *context.Context, cli client.Client) error {
+	for _, winget := range ctx.Config.Winget {
+		if err := doRun(ctx, winget, cli); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func doRun(ctx *context.Context, winget config.Winget, cl client.Client) error {
+	if winget.Repository.Name == "" {
+		return errNoRepoName
+	}
+
+	winget, err := templateFields(ctx, winget)
+	if err != nil {
+		return err
+	}
+	if err := validate(winget); err != nil {
+		return err
+	}
+	if winget.Path == "" {
+		winget.Path = path.Join(
+			"manifests",
+			strings.ToLower(winget.PackageIdentifier[:1]),
+			strings.ReplaceAll(winget.PackageIdentifier, ".", "/"),
+			ctx.Version,
+		)
+	}
+
+	filters := []artifact.Filter{
+		artifact.ByGoos("windows"),
+		artifact.Or(
+			artifact.And(
+				artifact.ByGoarch("amd64"),
+				artifact.ByGoamd64(winget.Goamd64),
+			),
+			artifact.ByGoarch("386"),
+			artifact.ByGoarch("arm64"),
+		),
+		artifact.Or(
+			artifact.And(
+				artifact.ByType(artifact.UploadableArchive),
+				artifact.ByFormats("zip"),
+			),
+			artifact.And(
+				artifact.ByType(artifact.UploadableBinary),
+				artifact.ByExt(".exe"),
+			),
+			artifact.ByExt(".msi"),
+		),
+	}
+	if len(winget.IDs) > 0 {
+		filters = append(filters, artifact.ByIDs(winget.IDs...))
+	}
+
+	artifacts := ctx.Artifacts.Filter(artifact.And(filters...)).List()
+	if len(artifacts) == 0 {
+		return ErrNoWindowsArtifacts
+	}
+
+	installers, err := installersFor(ctx, winget, cl, artifacts)
+	if err != nil {
+		return err
+	}
+
+	manifests := []struct {
+		name string
+		data manifest
+	}{
+		{
+			name: winget.PackageIdentifier + ".yaml",
+			data: Version{
+				PackageIdentifier: winget.PackageIdentifier,
+				PackageVersion:    ctx.Version,
+				DefaultLocale:     defaultLocale,
+				ManifestType:      "version",
+				ManifestVersion:   manifestVersion,
+			},
+		},
+		{
+			name: winget.PackageIdentifier + ".installer.yaml",
+			data: Installer{
+				PackageIdentifier: winget.PackageIdentifier,
+				PackageVersion:    ctx.Version,
+				InstallerLocale:   defaultLocale,
+				ReleaseDate:       ctx.Date.Format("2006-01-02"),
+				Installers:        installers,
+				ManifestType:      "installer",
+				ManifestVersion:   manifestVersion,
+			},
+		},
+		{
+			name: winget.PackageIdentifier + ".locale." + defaultLocale + ".yaml",
+			data: Locale{
+				PackageIdentifier: winget.PackageIdentifier,
+				PackageVersion:    ctx.Version,
+				PackageLocale:     defaultLocale,
+				Publisher:         winget.Publisher,
+				PublisherURL:      winget.PublisherURL,
+				Author:            winget.Author,
+				PackageName:       winget.Name,
+				PackageURL:        winget.Homepage,
+				License:           winget.License,
+				LicenseURL:        winget.LicenseURL,
+				Copyright:         winget.Copyright,
+				ShortDescription:  winget.ShortDescription,
+				Description:       winget.Description,
+				Moniker:           strings.ToLower(winget.Name),
+				Tags:              winget.Tags,
+				ReleaseNotes:      winget.ReleaseNotes,
+				ReleaseNotesURL:   winget.ReleaseNotesURL,
+				ManifestType:      "defaultLocale",
+				ManifestVersion:   manifestVersion,
+			},
+		},
+	}
+
+	folder := filepath.Join(ctx.Config.Dist, "winget", filepath.FromSlash(winget.Path))
+	if err := os.MkdirAll(folder, 0o755); err != nil {
+		return fmt.Errorf("failed to create winget manifests folder: %w", err)
+	}
+
+	for _, m := range manifests {
+		content, err := doBuildManifest(m.data)
+		if err != nil {
+			return err
+		}
+
+		filename := filepath.Join(folder, m.name)
+		log.WithField("manifest", filename).Info("writing")
+		if err := os.WriteFile(filename, []byte(content), 0o644); err != nil { //nolint: gosec
+			return fmt.Errorf("failed to write winget manifest: %w", err)
+		}
+
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: m.name,
+			Path: filename,
+			Type: artifact.WingetManifest,
+			Extra: map[string]interface{}{
+				artifact.ExtraID:  winget.PackageIdentifier,
+				wingetConfigExtra: winget,
+			},
+		})
+	}
+
+	return nil
+}
+
+func templateFields(ctx *context.Context, winget config.Winget) (config.Winget, error) {
+	t := tmpl.New(ctx)
+	for _, s := range []*string{
+		&winget.Name,
+		&winget.Publisher,
+		&winget.PublisherURL,
+		&winget.Author,
+		&winget.Copyright,
+		&winget.License,
+		&winget.LicenseURL,
+		&winget.ShortDescription,
+		&winget.Description,
+		&winget.Homepage,
+		&winget.ReleaseNotes,
+		&winget.ReleaseNotesURL,
+	} {
+		var err error
+		*s, err = t.Apply(*s)
+		if err != nil {
+			return config.Winget{}, err
+		}
+	}
+
+	if winget.PackageIdentifier == "" {
+		winget.PackageIdentifier = winget.Publisher + "." + winget.Name
+	}
+	id, err := t.Apply(winget.PackageIdentifier)
+	if err != nil {
+		return config.Winget{}, err
+	}
+	winget.PackageIdentifier = id
+
+	winget.Path, err = t.Apply(winget.Path)
+	if err != nil {
+		return config.Winget{}, err
+	}
+
+	return winget, nil
+}
+
+func validate(winget config.Winget) error {
+	if winget.Publisher == "" {
+		return errNoPublisher
+	}
+	if winget.PublisherURL == "" {
+		return errNoPublisherURL
+	}
+	if winget.License == "" {
+		return errNoLicense
+	}
+	if winget.ShortDescription == "" {
+		return errNoShortDescription
+	}
+	if !packageIdentifierRegex.MatchString(winget.PackageIdentifier) {
+		return fmt.Errorf("%w: %q", errInvalidPackageIdentifier, winget.PackageIdentifier)
+	}
+	return nil
+}
+
+func installersFor(ctx *context.Context, winget config.Winget, cl client.Client, artifacts []*artifact.Artifact) ([]InstallerItem, error) {
+	if winget.URLTemplate == "" {
+		url, err := cl.ReleaseURLTemplate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		winget.URLTemplate = url
+	}
+
+	result := make([]InstallerItem, 0, len(artifacts))
+	for _, art := range artifacts {
+		sum, err := art.Checksum("sha256")
+		if err != nil {
+			return nil, err
+		}
+
+		url, err := tmpl.New(ctx).WithArtifact(art).Apply(winget.URLTemplate)
+		if err != nil {
+			return nil, err
+		}
+
+		item := InstallerItem{
+			Architecture:    archFor(art.Goarch),
+			InstallerURL:    url,
+			InstallerSha256: sum,
+			UpgradeBehavior: "uninstallPrevious",
+		}
+
+		switch {
+		case strings.HasSuffix(art.Name, ".msi"):
+			item.InstallerType = "msi"
+		case art.Type == artifact.UploadableBinary:
+			item.InstallerType = "portable"
+			item.Commands = []string{strings.TrimSuffix(art.Name, ".exe")}
+		default:
+			item.InstallerType = "zip"
+			item.NestedInstallerType = "portable"
+			wrap := artifact.ExtraOr(*art, artifact.ExtraWrappedIn, "")
+			for _, bin := range artifact.ExtraOr(*art, artifact.ExtraBinaries, []string{}) {
+				item.NestedInstallerFiles = append(item.NestedInstallerFiles, NestedInstallerFile{
+					RelativeFilePath:     path.Join(wrap, bin),
+					PortableCommandAlias: strings.TrimSuffix(bin, ".exe"),
+				})
+			}
+		}
+
+		result = append(result, item)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Architecture < result[j].Architecture
+	})
+
+	return result, nil
+}
+
+func archFor(goarch string) string {
+	switch goarch {
+	case "386":
+		return "x86"
+	case "amd64":
+		return "x64"
+	default:
+		return goarch
+	}
+}
+
+func doBuildManifest(data manifest) (string, error) {
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("winget: failed to marshal yaml: %w", err)
+	}
+	header := fmt.Sprintf(
+		"# This file was generated by GoReleaser. DO NOT EDIT.\n# yaml-language-server: $schema=https://aka.ms/winget-manifest.%s.%s.schema.json\n",
+		data.manifestType(),
+		manifestVersion,
+	)
+	return header + string(out), nil
+}
+
+// Publish winget manifests.
+func (Pipe) Publish(ctx *context.Context) error {
+	cli, err := client.New(ctx)
+	if err != nil {
+		return err
+	}
+	return publishAll(ctx, cli)
+}
+
+func publishAll(ctx *context.Context, cli client.Client) error {
+	// manifests are grouped by package identifier, each group being the
+	// version, installer and locale manifests of a single package.
+	var ids []string
+	groups := map[string][]*artifact.Artifact{}
+	for _, manifest := range ctx.Artifacts.Filter(artifact.ByType(artifact.WingetManifest)).List() {
+		id := artifact.ExtraOr(*manifest, artifact.ExtraID, "")
+		if _, ok := groups[id]; !ok {
+			ids = append(ids, id)
+		}
+		groups[id] = append(groups[id], manifest)
+	}
+
+	skips := pipe.SkipMemento{}
+	for _, id := range ids {
+		err := doPublish(ctx, groups[id], cli)
+		if err != nil && pipe.IsSkip(err) {
+			skips.Remember(err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return skips.Evaluate()
+}
+
+func doPublish(ctx *context.Context, manifests []*artifact.Artifact, cl client.Client) error {
+	winget, err := artifact.Extra[config.Winget](*manifests[0], wingetConfigExtra)
+	if err != nil {
+		return err
+	}
+
+	cl, err = client.NewIfToken(ctx, cl, winget.Repository.Token)
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(winget.SkipUpload) == "true" {
+		return pipe.Skip("winget.skip_upload is set")
+	}
+
+	if strings.TrimSpace(winget.SkipUpload) == "auto" && ctx.Semver.Prerelease != "" {
+		return pipe.Skip("prerelease detected with 'auto' upload, skipping winget publish")
+	}
+
+	ref, err := client.TemplateRef(tmpl.New(ctx).Apply, winget.Repository)
+	if err != nil {
+		return err
+	}
+	winget.Repository = ref
+	repo := client.RepoFromRef(winget.Repository)
+
+	msg, err := tmpl.New(ctx).WithExtraFields(tmpl.Fields{
+		"PackageIdentifier": winget.PackageIdentifier,
+	}).Apply(winget.CommitMessageTemplate)
+	if err != nil {
+		return err
+	}
+
+	author, err := commitauthor.Get(ctx, winget.CommitAuthor)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range manifests {
+		content, err := os.ReadFile(manifest.Path)
+		if err != nil {
+			return err
+		}
+
+		gpath := path.Join(winget.Path, manifest.Name)
+		log.WithField("manifest", gpath).
+			WithField("repo", repo.String()).
+			Info("pushing")
+		if err := client.NewIfGitURL(cl, winget.Repository).CreateFile(ctx, author, repo, content, gpath, msg); err != nil {
+			return err
+		}
+	}
+
+	return client.OpenPullRequest(ctx, cl, winget.PullRequest, repo, msg)
+}
@@ -0,0 +1,333 @@
+package winget
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/golden"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescription(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestSkip(t *testing.T) {
+	t.Run("skip", func(t *testing.T) {
+		require.True(t, Pipe{}.Skip(context.New(config.Project{})))
+	})
+
+	t.Run("dont skip", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Winget: []config.Winget{{}},
+		})
+		require.False(t, Pipe{}.Skip(ctx))
+	})
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Winget:      []config.Winget{{}},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	winget := ctx.Config.Winget[0]
+	require.Equal(t, "foo", winget.Name)
+	require.Equal(t, "v1", winget.Goamd64)
+	require.NotEmpty(t, winget.CommitMessageTemplate)
+	require.NotEmpty(t, winget.CommitAuthor.Name)
+	require.NotEmpty(t, winget.CommitAuthor.Email)
+	require.Empty(t, winget.PullRequest.Base)
+}
+
+func TestDefaultSigningWithoutGit(t *testing.T) {
+	ctx := context.New(config.Project{
+		Winget: []config.Winget{
+			{
+				CommitAuthor: config.CommitAuthor{
+					Signing: config.CommitSigning{Enabled: true},
+				},
+			},
+		},
+	})
+	require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrSigningRequiresGit)
+}
+
+func TestDefaultPullRequestBase(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Winget: []config.Winget{{
+				PullRequest: config.PullRequest{Enabled: true},
+			}},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.Equal(t, config.RepoRef{
+			Owner: "microsoft",
+			Name:  "winget-pkgs",
+		}, ctx.Config.Winget[0].PullRequest.Base)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Winget: []config.Winget{{
+				PullRequest: config.PullRequest{
+					Enabled: true,
+					Base:    config.RepoRef{Owner: "someone"},
+				},
+			}},
+		})
+		require.ErrorIs(t, Pipe{}.Default(ctx), client.ErrNoPullRequestBase)
+	})
+}
+
+func validWinget() config.Winget {
+	return config.Winget{
+		Name:             "foo",
+		Publisher:        "Goreleaser",
+		PublisherURL:     "https://goreleaser.com",
+		License:          "MIT",
+		ShortDescription: "foo is a test package",
+		Homepage:         "https://goreleaser.com/foo",
+		Tags:             []string{"cli", "test"},
+		Repository: config.RepoRef{
+			Owner: "goreleaser",
+			Name:  "winget-pkgs",
+		},
+		PullRequest: config.PullRequest{
+			Enabled: true,
+			Base: config.RepoRef{
+				Owner:  "microsoft",
+				Name:   "winget-pkgs",
+				Branch: "master",
+			},
+		},
+	}
+}
+
+func createArtifacts(tb testing.TB, ctx *context.Context) {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "artifact")
+	f, err := os.Create(path)
+	require.NoError(tb, err)
+	require.NoError(tb, f.Close())
+
+	for _, arch := range []string{"amd64", "386", "arm64"} {
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name:    "foo_windows_" + arch + ".zip",
+			Path:    path,
+			Goos:    "windows",
+			Goarch:  arch,
+			Goamd64: "v1",
+			Type:    artifact.UploadableArchive,
+			Extra: map[string]interface{}{
+				artifact.ExtraID:        "foo",
+				artifact.ExtraFormat:    "zip",
+				artifact.ExtraWrappedIn: "",
+				artifact.ExtraBinaries:  []string{"foo.exe"},
+			},
+		})
+	}
+	// ignored: not a zip
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    "foo_windows_amd64.tar.gz",
+		Path:    path,
+		Goos:    "windows",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Type:    artifact.UploadableArchive,
+		Extra: map[string]interface{}{
+			artifact.ExtraID:     "foo",
+			artifact.ExtraFormat: "tar.gz",
+		},
+	})
+	// ignored: not windows
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    "foo_linux_amd64.zip",
+		Path:    path,
+		Goos:    "linux",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Type:    artifact.UploadableArchive,
+		Extra: map[string]interface{}{
+			artifact.ExtraID:     "foo",
+			artifact.ExtraFormat: "zip",
+		},
+	})
+}
+
+func newContext(tb testing.TB, winget config.Winget) *context.Context {
+	tb.Helper()
+	ctx := context.New(config.Project{
+		Dist:        tb.TempDir(),
+		ProjectName: "foo",
+		Winget:      []config.Winget{winget},
+	})
+	ctx.Git.CurrentTag = "v1.2.1"
+	ctx.Version = "1.2.1"
+	ctx.Date = time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	require.NoError(tb, Pipe{}.Default(ctx))
+	return ctx
+}
+
+func TestFullPipe(t *testing.T) {
+	ctx := newContext(t, validWinget())
+	createArtifacts(t, ctx)
+
+	cli := client.NewMock()
+	require.NoError(t, runAll(ctx, cli))
+
+	manifests := ctx.Artifacts.Filter(artifact.ByType(artifact.WingetManifest)).List()
+	require.Len(t, manifests, 3)
+
+	for name, manifest := range map[string]*artifact.Artifact{
+		"version":   manifests[0],
+		"installer": manifests[1],
+		"locale":    manifests[2],
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, filepath.Join(
+				ctx.Config.Dist, "winget", "manifests", "g", "Goreleaser", "foo", "1.2.1",
+			), filepath.Dir(manifest.Path))
+			golden.RequireEqualYaml(t, golden.RequireReadFile(t, manifest.Path))
+		})
+	}
+
+	require.NoError(t, publishAll(ctx, cli))
+	require.True(t, cli.CreatedFile)
+	require.Equal(t, "manifests/g/Goreleaser/foo/1.2.1/Goreleaser.foo.locale.en-US.yaml", cli.Path)
+	require.True(t, cli.OpenedPullRequest)
+	require.Equal(t, client.Repo{Owner: "microsoft", Name: "winget-pkgs", Branch: "master"}, cli.PullRequestBase)
+	require.Equal(t, client.Repo{Owner: "goreleaser", Name: "winget-pkgs"}, cli.PullRequestHead)
+	require.Equal(t, "New version: Goreleaser.foo 1.2.1", cli.PullRequestTitle)
+}
+
+func TestRunPipeBinaryAndCustomFields(t *testing.T) {
+	winget := validWinget()
+	winget.PackageIdentifier = "{{ .ProjectName }}.cli"
+	winget.Path = "manifests/f/foo/cli/{{ .Version }}"
+	winget.Description = "{{ .ProjectName }} does things"
+	ctx := newContext(t, winget)
+
+	path := filepath.Join(t.TempDir(), "foo.exe")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:    "foo.exe",
+		Path:    path,
+		Goos:    "windows",
+		Goarch:  "amd64",
+		Goamd64: "v1",
+		Type:    artifact.UploadableBinary,
+	})
+
+	require.NoError(t, runAll(ctx, client.NewMock()))
+
+	manifests := ctx.Artifacts.Filter(artifact.ByType(artifact.WingetManifest)).List()
+	require.Len(t, manifests, 3)
+	require.Equal(t, "foo.cli.yaml", manifests[0].Name)
+	require.Equal(t, "foo.cli.installer.yaml", manifests[1].Name)
+	require.Equal(t, "foo.cli.locale.en-US.yaml", manifests[2].Name)
+
+	installer := string(golden.RequireReadFile(t, manifests[1].Path))
+	require.Contains(t, installer, "InstallerType: portable")
+	require.Contains(t, installer, "- foo\n")
+	require.NotContains(t, installer, "NestedInstallerType")
+
+	locale := string(golden.RequireReadFile(t, manifests[2].Path))
+	require.Contains(t, locale, "Description: foo does things")
+}
+
+func TestRunPipeErrors(t *testing.T) {
+	for name, tt := range map[string]struct {
+		winget    func(w *config.Winget)
+		artifacts bool
+		err       error
+	}{
+		"no repository": {
+			winget:    func(w *config.Winget) { w.Repository.Name = "" },
+			artifacts: true,
+			err:       errNoRepoName,
+		},
+		"no publisher": {
+			winget:    func(w *config.Winget) { w.Publisher = "" },
+			artifacts: true,
+			err:       errNoPublisher,
+		},
+		"no publisher url": {
+			winget:    func(w *config.Winget) { w.PublisherURL = "" },
+			artifacts: true,
+			err:       errNoPublisherURL,
+		},
+		"no license": {
+			winget:    func(w *config.Winget) { w.License = "" },
+			artifacts: true,
+			err:       errNoLicense,
+		},
+		"no short description": {
+			winget:    func(w *config.Winget) { w.ShortDescription = "" },
+			artifacts: true,
+			err:       errNoShortDescription,
+		},
+		"invalid package identifier": {
+			winget:    func(w *config.Winget) { w.PackageIdentifier = "foo" },
+			artifacts: true,
+			err:       errInvalidPackageIdentifier,
+		},
+		"no artifacts": {
+			winget: func(w *config.Winget) {},
+			err:    ErrNoWindowsArtifacts,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			winget := validWinget()
+			tt.winget(&winget)
+			ctx := newContext(t, winget)
+			if tt.artifacts {
+				createArtifacts(t, ctx)
+			}
+			require.ErrorIs(t, runAll(ctx, client.NewMock()), tt.err)
+		})
+	}
+
+	t.Run("template error", func(t *testing.T) {
+		winget := validWinget()
+		winget.ShortDescription = "{{ .Nope }}"
+		ctx := newContext(t, winget)
+		createArtifacts(t, ctx)
+		testlib.RequireTemplateError(t, runAll(ctx, client.NewMock()))
+	})
+}
+
+func TestPublishSkipUpload(t *testing.T) {
+	winget := validWinget()
+	winget.SkipUpload = "true"
+	ctx := newContext(t, winget)
+	createArtifacts(t, ctx)
+
+	cli := client.NewMock()
+	require.NoError(t, runAll(ctx, cli))
+	testlib.AssertSkipped(t, publishAll(ctx, cli))
+	require.False(t, cli.CreatedFile)
+	require.False(t, cli.OpenedPullRequest)
+}
+
+func TestPublishNoPullRequest(t *testing.T) {
+	winget := validWinget()
+	winget.PullRequest.Enabled = false
+	ctx := newContext(t, winget)
+	createArtifacts(t, ctx)
+
+	cli := client.NewMock()
+	require.NoError(t, runAll(ctx, cli))
+	require.NoError(t, publishAll(ctx, cli))
+	require.True(t, cli.CreatedFile)
+	require.False(t, cli.OpenedPullRequest)
+}
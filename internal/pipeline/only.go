@@ -0,0 +1,152 @@
+package pipeline
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/pipe/defaults"
+	"github.com/goreleaser/goreleaser/internal/pipe/dist"
+	"github.com/goreleaser/goreleaser/internal/pipe/effectiveconfig"
+	"github.com/goreleaser/goreleaser/internal/pipe/env"
+	"github.com/goreleaser/goreleaser/internal/pipe/git"
+	"github.com/goreleaser/goreleaser/internal/pipe/gomod"
+	"github.com/goreleaser/goreleaser/internal/pipe/metadata"
+	"github.com/goreleaser/goreleaser/internal/pipe/publish"
+	"github.com/goreleaser/goreleaser/internal/pipe/semver"
+	"github.com/goreleaser/goreleaser/internal/pipe/snapshot"
+)
+
+// alwaysRun are the pipes that load the environment, git state, defaults,
+// etc, which run regardless of --only.
+// Every other pipe in the pipeline can be selected by its id.
+// nolint: gochecknoglobals
+var alwaysRun = []Piper{
+	env.Pipe{},
+	git.Pipe{},
+	semver.Pipe{},
+	defaults.Pipe{},
+	snapshot.Pipe{},
+	dist.Pipe{},
+	gomod.Pipe{},
+	effectiveconfig.Pipe{},
+	metadata.Pipe{},
+}
+
+// aliases maps the ids of pipes that are selected together with another one
+// to the id of the latter.
+// nolint: gochecknoglobals
+var aliases = map[string]string{
+	"prebuild":  "build",
+	"gomod":     "build",
+	"checksums": "checksum",
+}
+
+// dependencies lists, for each selectable id, the ids it needs to have run
+// before it in order to produce anything meaningful.
+// nolint: gochecknoglobals
+var dependencies = map[string][]string{
+	"universalbinary": {"build"},
+	"upx":             {"build"},
+	"archive":         {"build"},
+	"nfpm":            {"build"},
+	"snapcraft":       {"build"},
+	"notarize":        {"archive"},
+	"aur":             {"archive"},
+	"brew":            {"archive"},
+	"krew":            {"archive"},
+	"scoop":           {"archive"},
+	"winget":          {"archive"},
+	"nix":             {"archive"},
+	"chocolatey":      {"archive"},
+	"docker":          {"build"},
+	"verify":          {"publish"},
+	"announce":        {"publish"},
+	// publisher-only ids narrow down what the publish pipe does
+	"blob":             {"publish"},
+	"upload":           {"publish"},
+	"artifactory":      {"publish"},
+	"custompublishers": {"publish"},
+	"ko":               {"publish"},
+	"release":          {"publish"},
+	"milestone":        {"publish"},
+}
+
+// IDs returns the sorted list of pipe ids that can be used with Only, which
+// includes the ids of the publishers run by the publish pipe.
+func IDs() []string {
+	seen := map[string]bool{}
+	for _, p := range Pipeline {
+		if id, ok := idOf(p); ok {
+			seen[id] = true
+		}
+	}
+	for _, id := range publish.IDs() {
+		seen[id] = true
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Only filters the given pipeline so that, from the selectable pipes, only
+// the ones matching the given ids are kept.
+// Pipes that can't be selected are always kept.
+// Publisher ids are only validated here, the publish pipe narrows down its
+// publishers from the context.
+// Missing dependencies of the selected ids are logged as warnings.
+func Only(pipes []Piper, ids []string) ([]Piper, error) {
+	if len(ids) == 0 {
+		return pipes, nil
+	}
+
+	valid := map[string]bool{}
+	for _, id := range IDs() {
+		valid[id] = true
+	}
+	selected := map[string]bool{}
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if !valid[id] {
+			return nil, fmt.Errorf("invalid pipe id %q, valid ids are: %s", id, strings.Join(IDs(), ", "))
+		}
+		selected[id] = true
+	}
+
+	for _, id := range ids {
+		for _, dep := range dependencies[strings.TrimSpace(id)] {
+			if !selected[dep] {
+				log.Warnf("%s depends on %s, which will not run", id, dep)
+			}
+		}
+	}
+
+	var result []Piper
+	for _, p := range pipes {
+		id, ok := idOf(p)
+		if ok && !selected[id] {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// idOf returns the id of the given pipe, and false if it always runs.
+func idOf(p Piper) (string, bool) {
+	for _, always := range alwaysRun {
+		if always == p {
+			return "", false
+		}
+	}
+	id := pipe.ID(p)
+	if alias, ok := aliases[id]; ok {
+		return alias, true
+	}
+	return id, true
+}
@@ -0,0 +1,106 @@
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/pipe/archive"
+	"github.com/goreleaser/goreleaser/internal/pipe/build"
+	"github.com/goreleaser/goreleaser/internal/pipe/checksums"
+	"github.com/goreleaser/goreleaser/internal/pipe/defaults"
+	"github.com/goreleaser/goreleaser/internal/pipe/docker"
+	"github.com/goreleaser/goreleaser/internal/pipe/git"
+	"github.com/goreleaser/goreleaser/internal/pipe/gomod"
+	"github.com/goreleaser/goreleaser/internal/pipe/prebuild"
+	"github.com/goreleaser/goreleaser/internal/pipe/publish"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnly(t *testing.T) {
+	t.Run("no ids", func(t *testing.T) {
+		pipes, err := Only(Pipeline, nil)
+		require.NoError(t, err)
+		require.Equal(t, Pipeline, pipes)
+	})
+
+	t.Run("docker", func(t *testing.T) {
+		pipes, err := Only(Pipeline, []string{"docker"})
+		require.NoError(t, err)
+		require.Contains(t, pipes, docker.Pipe{})
+		require.Contains(t, pipes, git.Pipe{})
+		require.Contains(t, pipes, defaults.Pipe{})
+		require.NotContains(t, pipes, build.Pipe{})
+		require.NotContains(t, pipes, archive.Pipe{})
+		require.NotContains(t, pipes, publish.Pipe{})
+	})
+
+	t.Run("keeps order", func(t *testing.T) {
+		pipes, err := Only(Pipeline, []string{"publish", "docker", "build"})
+		require.NoError(t, err)
+		var idxBuild, idxDocker, idxPublish int
+		for i, p := range pipes {
+			switch p {
+			case build.Pipe{}:
+				idxBuild = i
+			case docker.Pipe{}:
+				idxDocker = i
+			case publish.Pipe{}:
+				idxPublish = i
+			}
+		}
+		require.Less(t, idxBuild, idxDocker)
+		require.Less(t, idxDocker, idxPublish)
+	})
+
+	t.Run("publisher id", func(t *testing.T) {
+		pipes, err := Only(Pipeline, []string{"docker", "release", "publish"})
+		require.NoError(t, err)
+		require.Contains(t, pipes, docker.Pipe{})
+		require.Contains(t, pipes, publish.Pipe{})
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		_, err := Only(Pipeline, []string{"docker", "nope"})
+		require.ErrorContains(t, err, `invalid pipe id "nope", valid ids are: `)
+	})
+}
+
+func TestIDs(t *testing.T) {
+	ids := IDs()
+	require.Contains(t, ids, "docker")
+	require.Contains(t, ids, "build")
+	require.Contains(t, ids, "release")
+	require.NotContains(t, ids, "prebuild")
+	require.NotContains(t, ids, "git")
+	require.IsIncreasing(t, ids)
+	for id, deps := range dependencies {
+		require.Contains(t, ids, id)
+		for _, dep := range deps {
+			require.Contains(t, ids, dep)
+		}
+	}
+}
+
+func TestIDOf(t *testing.T) {
+	for _, p := range alwaysRun {
+		_, ok := idOf(p)
+		require.False(t, ok, p.String())
+	}
+	for pipe, expected := range map[Piper]string{
+		prebuild.Pipe{}:   "build",
+		gomod.ProxyPipe{}: "build",
+		build.Pipe{}:      "build",
+		checksums.Pipe{}:  "checksum",
+		docker.Pipe{}:     "docker",
+		publish.Pipe{}:    "publish",
+	} {
+		id, ok := idOf(pipe)
+		require.True(t, ok, pipe.String())
+		require.Equal(t, expected, id)
+	}
+}
+
+func TestAlwaysRunPipesAreInPipeline(t *testing.T) {
+	for _, p := range alwaysRun {
+		require.Contains(t, Pipeline, p)
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/goreleaser/goreleaser/internal/pipe/announce"
 	"github.com/goreleaser/goreleaser/internal/pipe/archive"
+	"github.com/goreleaser/goreleaser/internal/pipe/attest"
 	"github.com/goreleaser/goreleaser/internal/pipe/aur"
 	"github.com/goreleaser/goreleaser/internal/pipe/before"
 	"github.com/goreleaser/goreleaser/internal/pipe/brew"
@@ -23,8 +24,11 @@ import (
 	"github.com/goreleaser/goreleaser/internal/pipe/krew"
 	"github.com/goreleaser/goreleaser/internal/pipe/metadata"
 	"github.com/goreleaser/goreleaser/internal/pipe/nfpm"
+	"github.com/goreleaser/goreleaser/internal/pipe/nix"
+	"github.com/goreleaser/goreleaser/internal/pipe/notarize"
 	"github.com/goreleaser/goreleaser/internal/pipe/prebuild"
 	"github.com/goreleaser/goreleaser/internal/pipe/publish"
+	"github.com/goreleaser/goreleaser/internal/pipe/release"
 	"github.com/goreleaser/goreleaser/internal/pipe/sbom"
 	"github.com/goreleaser/goreleaser/internal/pipe/scoop"
 	"github.com/goreleaser/goreleaser/internal/pipe/semver"
@@ -33,6 +37,9 @@ import (
 	"github.com/goreleaser/goreleaser/internal/pipe/snapshot"
 	"github.com/goreleaser/goreleaser/internal/pipe/sourcearchive"
 	"github.com/goreleaser/goreleaser/internal/pipe/universalbinary"
+	"github.com/goreleaser/goreleaser/internal/pipe/upx"
+	"github.com/goreleaser/goreleaser/internal/pipe/verify"
+	"github.com/goreleaser/goreleaser/internal/pipe/winget"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
@@ -73,6 +80,8 @@ var BuildPipeline = []Piper{
 	build.Pipe{},
 	// universal binary handling
 	universalbinary.Pipe{},
+	// compress binaries with upx
+	upx.Pipe{},
 }
 
 // BuildCmdPipeline is the pipeline run by goreleaser build.
@@ -95,26 +104,49 @@ var Pipeline = append(
 	snapcraft.Pipe{},
 	// create SBOMs of artifacts
 	sbom.Pipe{},
+	// notarize macOS artifacts
+	notarize.Pipe{},
 	// checksums of the files
 	checksums.Pipe{},
 	// sign artifacts
 	sign.Pipe{},
+	// attest artifacts
+	attest.Pipe{},
 	// create arch linux aur pkgbuild
 	aur.Pipe{},
 	// create brew tap
 	brew.Pipe{},
+	// create brew casks
+	brew.CaskPipe{},
 	// krew plugins
 	krew.Pipe{},
 	// create scoop buckets
 	scoop.Pipe{},
+	// create winget manifests
+	winget.Pipe{},
+	// create nix derivations and flakes
+	nix.Pipe{},
 	// create chocolatey pkg and publish
 	chocolatey.Pipe{},
 	// create and push docker images
 	docker.Pipe{},
 	// publishes artifacts
 	publish.Pipe{},
+	// verifies the uploaded artifacts against the checksums
+	verify.Pipe{},
 	// creates a metadata.json and an artifacts.json files in the dist folder
 	metadata.Pipe{},
 	// announce releases
 	announce.Pipe{},
 )
+
+// PublishDraftPipeline is the pipeline run by goreleaser release --publish-existing.
+// nolint: gochecknoglobals
+var PublishDraftPipeline = []Piper{
+	// load and validate environment variables
+	env.Pipe{},
+	// setup the defaults, so the release repository is known
+	defaults.Pipe{},
+	// publishes the draft release created by a previous run
+	release.DraftPipe{},
+}
@@ -0,0 +1,160 @@
+// Package signer holds the logic shared by the pipes running external
+// commands against artifacts, such as the signing and attestation ones.
+package signer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/caarlos0/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/gio"
+	"github.com/goreleaser/goreleaser/internal/logext"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+// Command is a command to run against an artifact.
+type Command struct {
+	// Name of the command, used in error messages, e.g. "sign".
+	Name      string
+	Cmd       string
+	Args      []string
+	Stdin     *string
+	StdinFile string
+	// Output shows the command output even when it succeeds.
+	Output bool
+}
+
+// Env returns the environment to run the commands against the given
+// artifact with, including the given templated env.
+func Env(ctx *context.Context, art *artifact.Artifact, extra []string) (context.Env, error) {
+	env := ctx.Env.Copy()
+	env["artifactName"] = art.Name
+	env["artifact"] = art.Path
+	env["artifactID"] = art.ID()
+	env["digest"] = artifact.ExtraOr(*art, artifact.ExtraDigest, "")
+
+	for _, s := range extra {
+		ts, err := tmpl.New(ctx).WithEnv(env).Apply(s)
+		if err != nil {
+			return nil, err
+		}
+		k, v, _ := strings.Cut(ts, "=")
+		env[k] = v
+	}
+	return env, nil
+}
+
+// Apply expands the given env in s and then applies it as a template.
+func Apply(ctx *context.Context, env context.Env, s string) (string, error) {
+	return tmpl.New(ctx).WithEnv(env).Apply(expand(s, env))
+}
+
+// Args applies all the given args.
+func Args(ctx *context.Context, env context.Env, args []string) ([]string, error) {
+	result := make([]string, 0, len(args))
+	for _, a := range args {
+		arg, err := Apply(ctx, env, a)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, arg)
+	}
+	return result, nil
+}
+
+// Path applies the given path, making it relative to the dist folder.
+func Path(ctx *context.Context, env context.Env, s string) (string, error) {
+	result, err := Apply(ctx, env, s)
+	if err != nil || result == "" {
+		return "", err
+	}
+	return relativeToDist(ctx.Config.Dist, result)
+}
+
+func relativeToDist(dist, f string) (string, error) {
+	af, err := filepath.Abs(f)
+	if err != nil {
+		return "", err
+	}
+	df, err := filepath.Abs(dist)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(af, df) {
+		return f, nil
+	}
+	return filepath.Join(dist, f), nil
+}
+
+// KeyPath applies and expands the given key, making sure the file exists.
+// Keys using a scheme, e.g. KMS URIs such as `awskms:///alias/foo`, are
+// returned as is.
+func KeyPath(ctx *context.Context, env context.Env, s string) (string, error) {
+	result, err := Apply(ctx, env, s)
+	if err != nil || result == "" {
+		return "", err
+	}
+	if strings.Contains(result, "://") {
+		return result, nil
+	}
+	result, err = homedir.Expand(result)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(result); err != nil {
+		return "", fmt.Errorf("invalid key: %w", err)
+	}
+	return result, nil
+}
+
+// Run runs the given command with the given env and already applied args.
+func Run(ctx *context.Context, env context.Env, c Command, args []string, fields log.Fields) error {
+	var stdin io.Reader
+	if c.Stdin != nil {
+		s, err := Apply(ctx, env, *c.Stdin)
+		if err != nil {
+			return err
+		}
+		stdin = strings.NewReader(s)
+	} else if c.StdinFile != "" {
+		f, err := os.Open(c.StdinFile)
+		if err != nil {
+			return fmt.Errorf("%s failed: cannot open file %s: %w", c.Name, c.StdinFile, err)
+		}
+		defer f.Close()
+
+		stdin = f
+	}
+
+	// The GoASTScanner flags this as a security risk.
+	// However, this works as intended. The nosec annotation
+	// tells the scanner to ignore this.
+	// #nosec
+	cmd := exec.CommandContext(ctx, c.Cmd, args...)
+	var b bytes.Buffer
+	w := gio.Safe(&b)
+	cmd.Stderr = io.MultiWriter(logext.NewConditionalWriter(fields, logext.Error, c.Output), w)
+	cmd.Stdout = io.MultiWriter(logext.NewConditionalWriter(fields, logext.Info, c.Output), w)
+	if stdin != nil {
+		cmd.Stdin = stdin
+	}
+	cmd.Env = env.Strings()
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %s failed: %w: %s", c.Name, c.Cmd, err, b.String())
+	}
+	return nil
+}
+
+func expand(s string, env map[string]string) string {
+	return os.Expand(s, func(key string) string {
+		return env[key]
+	})
+}
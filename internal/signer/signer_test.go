@@ -0,0 +1,126 @@
+package signer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnv(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Version = "1.0.0"
+	ctx.Env = map[string]string{"FOO": "foo"}
+	art := &artifact.Artifact{
+		Name: "bin",
+		Path: "dist/bin",
+		Extra: map[string]interface{}{
+			artifact.ExtraID:     "id",
+			artifact.ExtraDigest: "sha256:123",
+		},
+	}
+
+	env, err := Env(ctx, art, []string{"BAR={{ .Version }}", "BAZ={{ .Env.BAR }}-{{ .Env.artifactName }}"})
+	require.NoError(t, err)
+	require.Equal(t, context.Env{
+		"FOO":          "foo",
+		"BAR":          "1.0.0",
+		"BAZ":          "1.0.0-bin",
+		"artifact":     "dist/bin",
+		"artifactName": "bin",
+		"artifactID":   "id",
+		"digest":       "sha256:123",
+	}, env)
+
+	t.Run("template error", func(t *testing.T) {
+		_, err := Env(ctx, art, []string{"BAR={{ .Nope }}"})
+		testlib.RequireTemplateError(t, err)
+	})
+}
+
+func TestPath(t *testing.T) {
+	ctx := context.New(config.Project{Dist: "dist"})
+	env := context.Env{"artifact": "bin"}
+
+	for s, expected := range map[string]string{
+		"":                    "",
+		"${artifact}.sig":     filepath.Join("dist", "bin.sig"),
+		"dist/${artifact}":    "dist/bin",
+		"{{ .Env.artifact }}": filepath.Join("dist", "bin"),
+	} {
+		t.Run(s, func(t *testing.T) {
+			path, err := Path(ctx, env, s)
+			require.NoError(t, err)
+			require.Equal(t, expected, path)
+		})
+	}
+}
+
+func TestKeyPath(t *testing.T) {
+	ctx := context.New(config.Project{})
+
+	t.Run("empty", func(t *testing.T) {
+		key, err := KeyPath(ctx, context.Env{}, "")
+		require.NoError(t, err)
+		require.Empty(t, key)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "cosign.key")
+		require.NoError(t, os.WriteFile(path, []byte("key"), 0o600))
+		key, err := KeyPath(ctx, context.Env{"key": path}, "$key")
+		require.NoError(t, err)
+		require.Equal(t, path, key)
+	})
+
+	t.Run("kms", func(t *testing.T) {
+		key, err := KeyPath(ctx, context.Env{}, "awskms:///alias/foo")
+		require.NoError(t, err)
+		require.Equal(t, "awskms:///alias/foo", key)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := KeyPath(ctx, context.Env{}, filepath.Join(t.TempDir(), "nope.key"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestRun(t *testing.T) {
+	testlib.CheckPath(t, "sh")
+	ctx := context.New(config.Project{})
+
+	t.Run("success", func(t *testing.T) {
+		out := filepath.Join(t.TempDir(), "out")
+		stdin := "hello"
+		require.NoError(t, Run(ctx, context.Env{"OUT": out}, Command{
+			Name:  "test",
+			Cmd:   "sh",
+			Stdin: &stdin,
+		}, []string{"-c", `cat > "$OUT"`}, nil))
+		bts, err := os.ReadFile(out)
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(bts))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		err := Run(ctx, context.Env{}, Command{
+			Name: "test",
+			Cmd:  "sh",
+		}, []string{"-c", "echo nope && exit 1"}, nil)
+		require.ErrorContains(t, err, "test: sh failed: exit status 1: nope")
+	})
+
+	t.Run("missing stdin file", func(t *testing.T) {
+		err := Run(ctx, context.Env{}, Command{
+			Name:      "test",
+			Cmd:       "sh",
+			StdinFile: filepath.Join(t.TempDir(), "nope"),
+		}, nil, nil)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
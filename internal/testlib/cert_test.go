@@ -0,0 +1,14 @@
+package testlib
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCert(t *testing.T) {
+	cert, key := ClientCert(t)
+	_, err := tls.LoadX509KeyPair(cert, key)
+	require.NoError(t, err)
+}
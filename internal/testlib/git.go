@@ -2,6 +2,7 @@ package testlib
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/git"
@@ -43,6 +44,14 @@ func GitCommit(tb testing.TB, msg string) {
 	require.Contains(tb, out, "main", msg)
 }
 
+// GitCommitWithAuthor creates a git commit with the given author.
+func GitCommitWithAuthor(tb testing.TB, msg, name, email string) {
+	tb.Helper()
+	out, err := fakeGit("commit", "--allow-empty", "-m", msg, "--author", fmt.Sprintf("%s <%s>", name, email))
+	require.NoError(tb, err)
+	require.Contains(tb, out, "main", msg)
+}
+
 // GitTag creates a git tag.
 func GitTag(tb testing.TB, tag string) {
 	tb.Helper()
@@ -75,6 +84,16 @@ func GitAdd(tb testing.TB) {
 	require.Empty(tb, out)
 }
 
+// GitShallowClone clones the repository in the given folder with a depth of 1
+// into a new tempdir and cd into it.
+func GitShallowClone(tb testing.TB, from string) string {
+	tb.Helper()
+	folder := Mktmp(tb)
+	_, err := fakeGit("clone", "--depth", "1", "file://"+from, folder)
+	require.NoError(tb, err)
+	return folder
+}
+
 func fakeGit(args ...string) (string, error) {
 	allArgs := []string{
 		"-c", "user.name='GoReleaser'",
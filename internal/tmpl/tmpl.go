@@ -3,6 +3,7 @@ package tmpl
 
 import (
 	"bytes"
+	"encoding/base64"
 	"fmt"
 	"path/filepath"
 	"regexp"
@@ -20,7 +21,9 @@ import (
 
 // Template holds data that can be applied to a template string.
 type Template struct {
-	fields Fields
+	fields   Fields
+	funcs    template.FuncMap
+	funcsErr error
 }
 
 // Fields that will be available to the template engine.
@@ -79,6 +82,7 @@ const (
 func New(ctx *context.Context) *Template {
 	sv := ctx.Semver
 	rawVersionV := fmt.Sprintf("%d.%d.%d", sv.Major, sv.Minor, sv.Patch)
+	fm, err := funcMap(ctx.Config.TemplateFunctions)
 
 	return &Template{
 		fields: Fields{
@@ -111,6 +115,8 @@ func New(ctx *context.Context) *Template {
 			releaseNotes:    ctx.ReleaseNotes,
 			runtimeK:        ctx.Runtime,
 		},
+		funcs:    fm,
+		funcsErr: err,
 	}
 }
 
@@ -187,6 +193,7 @@ func buildOptsToFields(opts build.Options) Fields {
 		arch:   opts.Goarch,
 		arm:    opts.Goarm,
 		mips:   opts.Gomips,
+		amd64:  opts.Goamd64,
 	}
 }
 
@@ -199,28 +206,12 @@ func (t *Template) Bool(s string) (bool, error) {
 // Apply applies the given string against the Fields stored in the template.
 func (t *Template) Apply(s string) (string, error) {
 	var out bytes.Buffer
+	if t.funcsErr != nil {
+		return "", t.funcsErr
+	}
 	tmpl, err := template.New("tmpl").
 		Option("missingkey=error").
-		Funcs(template.FuncMap{
-			"replace": strings.ReplaceAll,
-			"split":   strings.Split,
-			"time": func(s string) string {
-				return time.Now().UTC().Format(s)
-			},
-			"tolower":       strings.ToLower,
-			"toupper":       strings.ToUpper,
-			"trim":          strings.TrimSpace,
-			"trimprefix":    strings.TrimPrefix,
-			"trimsuffix":    strings.TrimSuffix,
-			"title":         cases.Title(language.English).String,
-			"dir":           filepath.Dir,
-			"abs":           filepath.Abs,
-			"incmajor":      incMajor,
-			"incminor":      incMinor,
-			"incpatch":      incPatch,
-			"filter":        filter(false),
-			"reverseFilter": filter(true),
-		}).
+		Funcs(t.funcs).
 		Parse(s)
 	if err != nil {
 		return "", err
@@ -230,6 +221,80 @@ func (t *Template) Apply(s string) (string, error) {
 	return out.String(), err
 }
 
+// Validate checks that the given string is a valid template, without
+// applying it.
+func (t *Template) Validate(s string) error {
+	if t.funcsErr != nil {
+		return t.funcsErr
+	}
+	_, err := template.New("tmpl").Funcs(t.funcs).Parse(s)
+	return err
+}
+
+// ValidateFunctions checks that the given template_functions aliases are
+// valid.
+func ValidateFunctions(aliases map[string]string) error {
+	_, err := funcMap(aliases)
+	return err
+}
+
+var validFuncName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// funcMap returns the built-in functions plus the aliases configured in
+// template_functions.
+func funcMap(aliases map[string]string) (template.FuncMap, error) {
+	builtin := funcs()
+	if len(aliases) == 0 {
+		return builtin, nil
+	}
+	result := template.FuncMap{}
+	for name, fn := range builtin {
+		result[name] = fn
+	}
+	for alias, name := range aliases {
+		if !validFuncName.MatchString(alias) {
+			return nil, fmt.Errorf("template function %q: invalid name", alias)
+		}
+		if _, ok := builtin[alias]; ok {
+			return nil, fmt.Errorf("template function %q: cannot override a built-in function", alias)
+		}
+		fn, ok := builtin[name]
+		if !ok {
+			return nil, fmt.Errorf("template function %q: unknown built-in function %q", alias, name)
+		}
+		result[alias] = fn
+	}
+	return result, nil
+}
+
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		"replace": strings.ReplaceAll,
+		"split":   strings.Split,
+		"time": func(s string) string {
+			return time.Now().UTC().Format(s)
+		},
+		"tolower":       strings.ToLower,
+		"toupper":       strings.ToUpper,
+		"trim":          strings.TrimSpace,
+		"trimprefix":    strings.TrimPrefix,
+		"trimsuffix":    strings.TrimSuffix,
+		"title":         cases.Title(language.English).String,
+		"dir":           filepath.Dir,
+		"abs":           filepath.Abs,
+		"incmajor":      incMajor,
+		"incminor":      incMinor,
+		"incpatch":      incPatch,
+		"filter":        filter(false),
+		"reverseFilter": filter(true),
+		"b64enc":        b64enc,
+		"b64dec":        b64dec,
+		"semverCompare": semverCompare,
+		"date":          formatDate,
+		"dateInZone":    formatDateInZone,
+	}
+}
+
 type ExpectedSingleEnvErr struct{}
 
 func (e ExpectedSingleEnvErr) Error() string {
@@ -286,6 +351,72 @@ func incPatch(v string) string {
 	return prefix(v) + semver.MustParse(v).IncPatch().String()
 }
 
+func b64enc(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func b64dec(s string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("b64dec: %w", err)
+	}
+	return string(b), nil
+}
+
+// semverCompare checks whether the given version matches the given
+// constraint, e.g. `semverCompare ">= 1.2.0" .Version`.
+func semverCompare(constraint, version string) (bool, error) {
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Errorf("semverCompare: %w", err)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil {
+		return false, fmt.Errorf("semverCompare: %w", err)
+	}
+	return c.Check(v), nil
+}
+
+// formatDate formats the given date, which might be a RFC 3339 string (e.g.
+// .CommitDate), an Unix timestamp (e.g. .CommitTimestamp) or a time.Time, in
+// UTC using the given layout.
+func formatDate(layout string, date interface{}) (string, error) {
+	return formatDateInZone(layout, date, "UTC")
+}
+
+// formatDateInZone is like formatDate, but formats the date in the given
+// IANA time zone, e.g. "America/Sao_Paulo".
+func formatDateInZone(layout string, date interface{}, zone string) (string, error) {
+	t, err := toTime(date)
+	if err != nil {
+		return "", err
+	}
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return "", fmt.Errorf("invalid time zone %q: %w", zone, err)
+	}
+	return t.In(loc).Format(layout), nil
+}
+
+func toTime(date interface{}) (time.Time, error) {
+	switch d := date.(type) {
+	case time.Time:
+		return d, nil
+	case int64:
+		return time.Unix(d, 0), nil
+	case int:
+		return time.Unix(int64(d), 0), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, d)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: %w", d, err)
+		}
+		return t, nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid date %v: unsupported type %T", date, date)
+	}
+}
+
 func prefix(v string) string {
 	if v != "" && v[0] == 'v' {
 		return "v"
@@ -6,6 +6,7 @@ import (
 	"runtime"
 	"testing"
 	"text/template"
+	"time"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/pkg/config"
@@ -261,6 +262,26 @@ func TestFuncMap(t *testing.T) {
 			Name:     "abs",
 			Expected: filepath.Join(wd, "file"),
 		},
+		{
+			Template: `{{ b64enc "hello" }}`,
+			Name:     "b64enc",
+			Expected: "aGVsbG8=",
+		},
+		{
+			Template: `{{ b64dec "aGVsbG8=" }}`,
+			Name:     "b64dec",
+			Expected: "hello",
+		},
+		{
+			Template: `{{ if semverCompare ">= 1.2.0" .Tag }}new{{ else }}old{{ end }}`,
+			Name:     "semverCompare true",
+			Expected: "new",
+		},
+		{
+			Template: `{{ if semverCompare "< 1.2.0" .Tag }}old{{ else }}new{{ end }}`,
+			Name:     "semverCompare false",
+			Expected: "new",
+		},
 	} {
 		out, err := New(ctx).Apply(tc.Template)
 		require.NoError(t, err)
@@ -272,6 +293,111 @@ func TestFuncMap(t *testing.T) {
 	}
 }
 
+func TestDateFuncs(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Git.CommitDate = time.Date(2023, 1, 20, 22, 30, 0, 0, time.UTC)
+	for tmpl, expected := range map[string]string{
+		`{{ date "2006-01-02 15:04" .CommitDate }}`:                                    "2023-01-20 22:30",
+		`{{ date "2006-01-02 15:04" .CommitTimestamp }}`:                               "2023-01-20 22:30",
+		`{{ dateInZone "2006-01-02 15:04 MST" .CommitDate "UTC" }}`:                    "2023-01-20 22:30 UTC",
+		`{{ dateInZone "2006-01-02T15:04:05Z07:00" .CommitDate "America/Sao_Paulo" }}`: "2023-01-20T19:30:00-03:00",
+		`{{ dateInZone "20060102" .CommitTimestamp "Asia/Tokyo" }}`:                    "20230121",
+		`{{ .CommitTimestamp }}`:                                                       "1674253800",
+		`{{ printf "%d" .CommitTimestamp }}`:                                           "1674253800",
+	} {
+		t.Run(tmpl, func(t *testing.T) {
+			out, err := New(ctx).Apply(tmpl)
+			require.NoError(t, err)
+			require.Equal(t, expected, out)
+		})
+	}
+
+	t.Run("time.Time", func(t *testing.T) {
+		out, err := New(ctx).WithExtraFields(Fields{
+			"Foo": time.Date(2023, 1, 20, 22, 30, 0, 0, time.UTC),
+		}).Apply(`{{ date "2006" .Foo }}`)
+		require.NoError(t, err)
+		require.Equal(t, "2023", out)
+	})
+
+	for tmpl, expected := range map[string]string{
+		`{{ date "2006" "not a date" }}`:                  `invalid date "not a date"`,
+		`{{ date "2006" 1.5 }}`:                           `invalid date 1.5: unsupported type float64`,
+		`{{ dateInZone "2006" .CommitDate "Nope/Nope" }}`: `invalid time zone "Nope/Nope"`,
+	} {
+		t.Run(tmpl, func(t *testing.T) {
+			_, err := New(ctx).Apply(tmpl)
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
+
+func TestFuncMapErrors(t *testing.T) {
+	ctx := context.New(config.Project{})
+	for name, tmpl := range map[string]string{
+		"b64dec":                   `{{ b64dec "not base64!" }}`,
+		"semverCompare constraint": `{{ semverCompare "not a constraint" "1.2.3" }}`,
+		"semverCompare version":    `{{ semverCompare ">= 1.0" "not a version" }}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(ctx).Apply(tmpl)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestTemplateFunctions(t *testing.T) {
+	t.Run("aliases", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			TemplateFunctions: map[string]string{
+				"base64":      "b64enc",
+				"unbase64":    "b64dec",
+				"versionIs":   "semverCompare",
+				"stripPrefix": "trimprefix",
+			},
+		})
+		ctx.Git.CurrentTag = "v1.2.3"
+		for tmpl, expected := range map[string]string{
+			`{{ base64 "hello" }}`:                   "aGVsbG8=",
+			`{{ unbase64 "aGVsbG8=" }}`:              "hello",
+			`{{ versionIs "~1.2" .Tag }}`:            "true",
+			`{{ stripPrefix .Tag "v" }}`:             "1.2.3",
+			`{{ b64enc "hello" }} {{ tolower "A" }}`: "aGVsbG8= a",
+		} {
+			out, err := New(ctx).Apply(tmpl)
+			require.NoError(t, err)
+			require.Equal(t, expected, out)
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		aliases map[string]string
+		err     string
+	}{
+		"unknown": {
+			aliases: map[string]string{"foo": "exec"},
+			err:     `template function "foo": unknown built-in function "exec"`,
+		},
+		"override": {
+			aliases: map[string]string{"replace": "b64enc"},
+			err:     `template function "replace": cannot override a built-in function`,
+		},
+		"invalid name": {
+			aliases: map[string]string{"foo-bar": "b64enc"},
+			err:     `template function "foo-bar": invalid name`,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				TemplateFunctions: tc.aliases,
+			})
+			_, err := New(ctx).Apply("{{ .ProjectName }}")
+			require.EqualError(t, err, tc.err)
+			require.EqualError(t, ValidateFunctions(tc.aliases), tc.err)
+		})
+	}
+}
+
 func TestApplySingleEnvOnly(t *testing.T) {
 	ctx := context.New(config.Project{
 		Env: []string{
@@ -345,6 +471,16 @@ func TestInvalidTemplate(t *testing.T) {
 	require.EqualError(t, err, "template: tmpl:1: unexpected \"{\" in command")
 }
 
+func TestValidate(t *testing.T) {
+	ctx := context.New(config.Project{
+		TemplateFunctions: map[string]string{"lower": "tolower"},
+	})
+	require.NoError(t, New(ctx).Validate("{{ .Env.FOO | tolower }} {{ .Tag }}"))
+	require.NoError(t, New(ctx).Validate("{{ .Env.FOO | lower }}"))
+	require.EqualError(t, New(ctx).Validate("{{{.Foo}"), "template: tmpl:1: unexpected \"{\" in command")
+	require.EqualError(t, New(ctx).Validate("{{ nope .Foo }}"), `template: tmpl:1: function "nope" not defined`)
+}
+
 func TestEnvNotFound(t *testing.T) {
 	ctx := context.New(config.Project{})
 	ctx.Git.CurrentTag = "v1.2.4"
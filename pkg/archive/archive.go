@@ -4,11 +4,13 @@ package archive
 import (
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/goreleaser/goreleaser/pkg/archive/gzip"
 	"github.com/goreleaser/goreleaser/pkg/archive/tar"
 	"github.com/goreleaser/goreleaser/pkg/archive/targz"
 	"github.com/goreleaser/goreleaser/pkg/archive/tarxz"
+	"github.com/goreleaser/goreleaser/pkg/archive/tarzst"
 	"github.com/goreleaser/goreleaser/pkg/archive/zip"
 	"github.com/goreleaser/goreleaser/pkg/config"
 )
@@ -21,17 +23,36 @@ type Archive interface {
 
 // New archive.
 func New(w io.Writer, format string) (Archive, error) {
-	switch format {
-	case "tar.gz":
-		return targz.New(w), nil
-	case "tar":
-		return tar.New(w), nil
-	case "gz":
-		return gzip.New(w), nil
-	case "tar.xz":
-		return tarxz.New(w), nil
-	case "zip":
-		return zip.New(w), nil
+	return NewWithCompressionLevel(w, format, 0)
+}
+
+// NewWithCompressionLevel creates a new archive using the given compression
+// level, for the formats that support it.
+// A level of 0 means the format default.
+func NewWithCompressionLevel(w io.Writer, format string, level int) (Archive, error) {
+	newArchive, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("invalid archive format: %s", format)
 	}
-	return nil, fmt.Errorf("invalid archive format: %s", format)
+	return newArchive(w, level), nil
+}
+
+// Formats returns the supported archive formats, sorted.
+func Formats() []string {
+	result := make([]string, 0, len(formats))
+	for format := range formats {
+		result = append(result, format)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// nolint: gochecknoglobals
+var formats = map[string]func(w io.Writer, level int) Archive{
+	"tar.gz":  func(w io.Writer, _ int) Archive { return targz.New(w) },
+	"tar":     func(w io.Writer, _ int) Archive { return tar.New(w) },
+	"gz":      func(w io.Writer, _ int) Archive { return gzip.New(w) },
+	"tar.xz":  func(w io.Writer, _ int) Archive { return tarxz.New(w) },
+	"tar.zst": func(w io.Writer, level int) Archive { return tarzst.New(w, level) },
+	"zip":     func(w io.Writer, _ int) Archive { return zip.New(w) },
 }
@@ -16,7 +16,7 @@ func TestArchive(t *testing.T) {
 	require.NoError(t, empty.Close())
 	require.NoError(t, os.Mkdir(folder+"/folder-inside", 0o755))
 
-	for _, format := range []string{"tar.gz", "zip", "gz", "tar.xz", "tar"} {
+	for _, format := range []string{"tar.gz", "zip", "gz", "tar.xz", "tar.zst", "tar"} {
 		format := format
 		t.Run(format, func(t *testing.T) {
 			archive, err := New(io.Discard, format)
@@ -35,6 +35,10 @@ func TestArchive(t *testing.T) {
 		})
 	}
 
+	t.Run("formats", func(t *testing.T) {
+		require.Equal(t, []string{"gz", "tar", "tar.gz", "tar.xz", "tar.zst", "zip"}, Formats())
+	})
+
 	t.Run("7z", func(t *testing.T) {
 		_, err := New(io.Discard, "7z")
 		require.EqualError(t, err, "invalid archive format: 7z")
@@ -0,0 +1,45 @@
+// Package tarzst implements the Archive interface providing tar.zst archiving
+// and compression.
+package tarzst
+
+import (
+	"io"
+
+	"github.com/goreleaser/goreleaser/pkg/archive/tar"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Archive as tar.zst.
+type Archive struct {
+	zstw *zstd.Encoder
+	tw   *tar.Archive
+}
+
+// New tar.zst archive.
+// The level follows the zstd levels, from 1 to 22. 0 uses the default level.
+func New(target io.Writer, level int) Archive {
+	opts := []zstd.EOption{}
+	if level > 0 {
+		opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+	}
+	zstw, _ := zstd.NewWriter(target, opts...)
+	tw := tar.New(zstw)
+	return Archive{
+		zstw: zstw,
+		tw:   &tw,
+	}
+}
+
+// Close all closeables.
+func (a Archive) Close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.zstw.Close()
+}
+
+// Add file to the archive.
+func (a Archive) Add(f config.File) error {
+	return a.tw.Add(f)
+}
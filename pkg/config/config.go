@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -18,7 +19,11 @@ import (
 
 // Git configs.
 type Git struct {
-	TagSort string `yaml:"tag_sort,omitempty" json:"tag_sort,omitempty"`
+	TagSort   string `yaml:"tag_sort,omitempty" json:"tag_sort,omitempty"`
+	Unshallow bool   `yaml:"unshallow,omitempty" json:"unshallow,omitempty"`
+
+	PrereleaseSuffix  string `yaml:"prerelease_suffix,omitempty" json:"prerelease_suffix,omitempty"`
+	IgnorePrereleases bool   `yaml:"ignore_prereleases,omitempty" json:"ignore_prereleases,omitempty"`
 }
 
 // GitHubURLs holds the URLs to be used when using github enterprise.
@@ -27,6 +32,7 @@ type GitHubURLs struct {
 	Upload        string `yaml:"upload,omitempty" json:"upload,omitempty"`
 	Download      string `yaml:"download,omitempty" json:"download,omitempty"`
 	SkipTLSVerify bool   `yaml:"skip_tls_verify,omitempty" json:"skip_tls_verify,omitempty"`
+	CACert        string `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`
 }
 
 // GitLabURLs holds the URLs to be used when using gitlab ce/enterprise.
@@ -34,6 +40,7 @@ type GitLabURLs struct {
 	API                string `yaml:"api,omitempty" json:"api,omitempty"`
 	Download           string `yaml:"download,omitempty" json:"download,omitempty"`
 	SkipTLSVerify      bool   `yaml:"skip_tls_verify,omitempty" json:"skip_tls_verify,omitempty"`
+	CACert             string `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`
 	UsePackageRegistry bool   `yaml:"use_package_registry,omitempty" json:"use_package_registry,omitempty"`
 	UseJobToken        bool   `yaml:"use_job_token,omitempty" json:"use_job_token,omitempty"`
 }
@@ -43,6 +50,7 @@ type GiteaURLs struct {
 	API           string `yaml:"api,omitempty" json:"api,omitempty"`
 	Download      string `yaml:"download,omitempty" json:"download,omitempty"`
 	SkipTLSVerify bool   `yaml:"skip_tls_verify,omitempty" json:"skip_tls_verify,omitempty"`
+	CACert        string `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`
 }
 
 // Repo represents any kind of repo (github, gitlab, etc).
@@ -79,10 +87,20 @@ func (r Repo) isSCM() bool {
 // also require separate authentication
 // e.g. Homebrew Tap, Scoop bucket.
 type RepoRef struct {
-	Owner  string `yaml:"owner,omitempty" json:"owner,omitempty"`
-	Name   string `yaml:"name,omitempty" json:"name,omitempty"`
-	Token  string `yaml:"token,omitempty" json:"token,omitempty"`
-	Branch string `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Owner  string     `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Name   string     `yaml:"name,omitempty" json:"name,omitempty"`
+	Token  string     `yaml:"token,omitempty" json:"token,omitempty"`
+	Branch string     `yaml:"branch,omitempty" json:"branch,omitempty"`
+	Git    GitRepoRef `yaml:"git,omitempty" json:"git,omitempty"`
+}
+
+// GitRepoRef represents a repository to be pushed to over git, e.g. with a
+// deploy key instead of a token.
+type GitRepoRef struct {
+	URL        string `yaml:"url,omitempty" json:"url,omitempty"`
+	SSHCommand string `yaml:"ssh_command,omitempty" json:"ssh_command,omitempty"`
+	PrivateKey string `yaml:"private_key,omitempty" json:"private_key,omitempty"`
+	KnownHosts string `yaml:"known_hosts,omitempty" json:"known_hosts,omitempty"`
 }
 
 // HomebrewDependency represents Homebrew dependency.
@@ -110,6 +128,7 @@ func (a *HomebrewDependency) UnmarshalYAML(unmarshal func(interface{}) error) er
 
 	a.Name = dep.Name
 	a.Type = dep.Type
+	a.Version = dep.Version
 
 	return nil
 }
@@ -151,7 +170,16 @@ type AUR struct {
 	GitURL                string       `yaml:"git_url,omitempty" json:"git_url,omitempty"`
 	GitSSHCommand         string       `yaml:"git_ssh_command,omitempty" json:"git_ssh_command,omitempty"`
 	PrivateKey            string       `yaml:"private_key,omitempty" json:"private_key,omitempty"`
+	KnownHosts            string       `yaml:"known_hosts,omitempty" json:"known_hosts,omitempty"`
 	Goamd64               string       `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
+	Files                 []AURFile    `yaml:"files,omitempty" json:"files,omitempty"`
+}
+
+// AURFile is an additional file to be installed by an AUR package.
+type AURFile struct {
+	Source      string      `yaml:"src,omitempty" json:"src,omitempty"`
+	Destination string      `yaml:"dst,omitempty" json:"dst,omitempty"`
+	Mode        os.FileMode `yaml:"mode,omitempty" json:"mode,omitempty"`
 }
 
 // Homebrew contains the brew section.
@@ -179,7 +207,90 @@ type Homebrew struct {
 	IDs                   []string             `yaml:"ids,omitempty" json:"ids,omitempty"`
 	Goarm                 string               `yaml:"goarm,omitempty" json:"goarm,omitempty" jsonschema:"oneof_type=string;integer"`
 	Goamd64               string               `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
-	Service               string               `yaml:"service,omitempty" json:"service,omitempty"`
+	Service               HomebrewService      `yaml:"service,omitempty" json:"service,omitempty"`
+}
+
+// HomebrewService is the service block of a formula, used by `brew services`.
+type HomebrewService struct {
+	Run          string            `yaml:"run,omitempty" json:"run,omitempty"`
+	KeepAlive    bool              `yaml:"keep_alive,omitempty" json:"keep_alive,omitempty"`
+	LogPath      string            `yaml:"log_path,omitempty" json:"log_path,omitempty"`
+	ErrorLogPath string            `yaml:"error_log_path,omitempty" json:"error_log_path,omitempty"`
+	Environment  map[string]string `yaml:"environment,omitempty" json:"environment,omitempty"`
+
+	// Raw is the verbatim content of the service block, set when the service
+	// is given as a string.
+	Raw string `yaml:"-" json:"-"`
+}
+
+// type alias to prevent stack overflowing in the custom unmarshaler.
+type homebrewService HomebrewService
+
+// UnmarshalYAML is a custom unmarshaler that accepts the service either as
+// a raw block or as a structured one.
+func (a *HomebrewService) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var str string
+	if err := unmarshal(&str); err == nil {
+		*a = HomebrewService{Raw: str}
+		return nil
+	}
+
+	var svc homebrewService
+	if err := unmarshal(&svc); err != nil {
+		return err
+	}
+	*a = HomebrewService(svc)
+	return nil
+}
+
+// MarshalYAML keeps raw services as strings.
+func (a HomebrewService) MarshalYAML() (interface{}, error) {
+	if a.Raw != "" {
+		return a.Raw, nil
+	}
+	return homebrewService(a), nil
+}
+
+func (a HomebrewService) JSONSchema() *jsonschema.Schema {
+	reflector := jsonschema.Reflector{
+		ExpandedStruct: true,
+	}
+	schema := reflector.Reflect(&homebrewService{})
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{
+				Type: "string",
+			},
+			schema,
+		},
+	}
+}
+
+// HomebrewCask contains the homebrew cask section.
+type HomebrewCask struct {
+	Name                  string                `yaml:"name,omitempty" json:"name,omitempty"`
+	Tap                   RepoRef               `yaml:"tap,omitempty" json:"tap,omitempty"`
+	CommitAuthor          CommitAuthor          `yaml:"commit_author,omitempty" json:"commit_author,omitempty"`
+	CommitMessageTemplate string                `yaml:"commit_msg_template,omitempty" json:"commit_msg_template,omitempty"`
+	Folder                string                `yaml:"folder,omitempty" json:"folder,omitempty"`
+	App                   string                `yaml:"app,omitempty" json:"app,omitempty"`
+	Caveats               string                `yaml:"caveats,omitempty" json:"caveats,omitempty"`
+	Description           string                `yaml:"description,omitempty" json:"description,omitempty"`
+	Homepage              string                `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+	SkipUpload            string                `yaml:"skip_upload,omitempty" json:"skip_upload,omitempty" jsonschema:"oneof_type=string;boolean"`
+	URLTemplate           string                `yaml:"url_template,omitempty" json:"url_template,omitempty"`
+	IDs                   []string              `yaml:"ids,omitempty" json:"ids,omitempty"`
+	Uninstall             HomebrewCaskUninstall `yaml:"uninstall,omitempty" json:"uninstall,omitempty"`
+	Zap                   HomebrewCaskUninstall `yaml:"zap,omitempty" json:"zap,omitempty"`
+}
+
+// HomebrewCaskUninstall contains the homebrew cask uninstall and zap stanzas.
+type HomebrewCaskUninstall struct {
+	Launchctl []string `yaml:"launchctl,omitempty" json:"launchctl,omitempty"`
+	Quit      []string `yaml:"quit,omitempty" json:"quit,omitempty"`
+	LoginItem []string `yaml:"login_item,omitempty" json:"login_item,omitempty"`
+	Delete    []string `yaml:"delete,omitempty" json:"delete,omitempty"`
+	Trash     []string `yaml:"trash,omitempty" json:"trash,omitempty"`
 }
 
 // Krew contains the krew section.
@@ -197,25 +308,79 @@ type Krew struct {
 	Goarm                 string       `yaml:"goarm,omitempty" json:"goarm,omitempty" jsonschema:"oneof_type=string;integer"`
 	Goamd64               string       `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
 	SkipUpload            string       `yaml:"skip_upload,omitempty" json:"skip_upload,omitempty" jsonschema:"oneof_type=string;boolean"`
+
+	PullRequest PullRequest `yaml:"pull_request,omitempty" json:"pull_request,omitempty"`
+}
+
+// Nix contains the nix section.
+type Nix struct {
+	Name                  string       `yaml:"name,omitempty" json:"name,omitempty"`
+	Path                  string       `yaml:"path,omitempty" json:"path,omitempty"`
+	Format                string       `yaml:"format,omitempty" json:"format,omitempty" jsonschema:"enum=classic,enum=flake,default=classic"`
+	Repository            RepoRef      `yaml:"repository,omitempty" json:"repository,omitempty"`
+	CommitAuthor          CommitAuthor `yaml:"commit_author,omitempty" json:"commit_author,omitempty"`
+	CommitMessageTemplate string       `yaml:"commit_msg_template,omitempty" json:"commit_msg_template,omitempty"`
+	IDs                   []string     `yaml:"ids,omitempty" json:"ids,omitempty"`
+	Goamd64               string       `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
+	URLTemplate           string       `yaml:"url_template,omitempty" json:"url_template,omitempty"`
+	SkipUpload            string       `yaml:"skip_upload,omitempty" json:"skip_upload,omitempty" jsonschema:"oneof_type=string;boolean"`
+	Description           string       `yaml:"description,omitempty" json:"description,omitempty"`
+	Homepage              string       `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+	License               string       `yaml:"license,omitempty" json:"license,omitempty"`
+}
+
+// Winget contains the winget section.
+type Winget struct {
+	Name                  string       `yaml:"name,omitempty" json:"name,omitempty"`
+	PackageIdentifier     string       `yaml:"package_identifier,omitempty" json:"package_identifier,omitempty"`
+	Publisher             string       `yaml:"publisher,omitempty" json:"publisher,omitempty"`
+	PublisherURL          string       `yaml:"publisher_url,omitempty" json:"publisher_url,omitempty"`
+	Author                string       `yaml:"author,omitempty" json:"author,omitempty"`
+	Copyright             string       `yaml:"copyright,omitempty" json:"copyright,omitempty"`
+	License               string       `yaml:"license,omitempty" json:"license,omitempty"`
+	LicenseURL            string       `yaml:"license_url,omitempty" json:"license_url,omitempty"`
+	ShortDescription      string       `yaml:"short_description,omitempty" json:"short_description,omitempty"`
+	Description           string       `yaml:"description,omitempty" json:"description,omitempty"`
+	Homepage              string       `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+	ReleaseNotes          string       `yaml:"release_notes,omitempty" json:"release_notes,omitempty"`
+	ReleaseNotesURL       string       `yaml:"release_notes_url,omitempty" json:"release_notes_url,omitempty"`
+	Tags                  []string     `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Path                  string       `yaml:"path,omitempty" json:"path,omitempty"`
+	Repository            RepoRef      `yaml:"repository,omitempty" json:"repository,omitempty"`
+	PullRequest           PullRequest  `yaml:"pull_request,omitempty" json:"pull_request,omitempty"`
+	CommitAuthor          CommitAuthor `yaml:"commit_author,omitempty" json:"commit_author,omitempty"`
+	CommitMessageTemplate string       `yaml:"commit_msg_template,omitempty" json:"commit_msg_template,omitempty"`
+	IDs                   []string     `yaml:"ids,omitempty" json:"ids,omitempty"`
+	Goamd64               string       `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
+	URLTemplate           string       `yaml:"url_template,omitempty" json:"url_template,omitempty"`
+	SkipUpload            string       `yaml:"skip_upload,omitempty" json:"skip_upload,omitempty" jsonschema:"oneof_type=string;boolean"`
+}
+
+// PullRequest configures a pull request to be opened from a repository
+// (usually a fork) into Base.
+type PullRequest struct {
+	Enabled bool    `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Base    RepoRef `yaml:"base,omitempty" json:"base,omitempty"`
 }
 
 // Ko contains the ko section
 type Ko struct {
-	ID                  string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Build               string   `yaml:"build,omitempty" json:"build,omitempty"`
-	Main                string   `yaml:"main,omitempty" json:"main,omitempty"`
-	WorkingDir          string   `yaml:"working_dir,omitempty" json:"working_dir,omitempty"`
-	BaseImage           string   `yaml:"base_image,omitempty" json:"base_image,omitempty"`
-	Repository          string   `yaml:"repository,omitempty" json:"repository,omitempty"`
-	Platforms           []string `yaml:"platforms,omitempty" json:"platforms,omitempty"`
-	Tags                []string `yaml:"tags,omitempty" json:"tags,omitempty"`
-	SBOM                string   `yaml:"sbom,omitempty" json:"sbom,omitempty"`
-	Ldflags             []string `yaml:"ldflags,omitempty" json:"ldflags,omitempty"`
-	Flags               []string `yaml:"flags,omitempty" json:"flags,omitempty"`
-	Env                 []string `yaml:"env,omitempty" json:"env,omitempty"`
-	Bare                bool     `yaml:"bare,omitempty" json:"bare,omitempty"`
-	PreserveImportPaths bool     `yaml:"preserve_import_paths,omitempty" json:"preserve_import_paths,omitempty"`
-	BaseImportPaths     bool     `yaml:"base_import_paths,omitempty" json:"base_import_paths,omitempty"`
+	ID                  string            `yaml:"id,omitempty" json:"id,omitempty"`
+	Build               string            `yaml:"build,omitempty" json:"build,omitempty"`
+	Main                string            `yaml:"main,omitempty" json:"main,omitempty"`
+	WorkingDir          string            `yaml:"working_dir,omitempty" json:"working_dir,omitempty"`
+	BaseImage           string            `yaml:"base_image,omitempty" json:"base_image,omitempty"`
+	Repository          string            `yaml:"repository,omitempty" json:"repository,omitempty"`
+	Platforms           []string          `yaml:"platforms,omitempty" json:"platforms,omitempty"`
+	Tags                []string          `yaml:"tags,omitempty" json:"tags,omitempty"`
+	SBOM                string            `yaml:"sbom,omitempty" json:"sbom,omitempty"`
+	Ldflags             []string          `yaml:"ldflags,omitempty" json:"ldflags,omitempty"`
+	Flags               []string          `yaml:"flags,omitempty" json:"flags,omitempty"`
+	Env                 []string          `yaml:"env,omitempty" json:"env,omitempty"`
+	Bare                bool              `yaml:"bare,omitempty" json:"bare,omitempty"`
+	PreserveImportPaths bool              `yaml:"preserve_import_paths,omitempty" json:"preserve_import_paths,omitempty"`
+	BaseImportPaths     bool              `yaml:"base_import_paths,omitempty" json:"base_import_paths,omitempty"`
+	Labels              map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
 }
 
 // Scoop contains the scoop.sh section.
@@ -238,8 +403,18 @@ type Scoop struct {
 
 // CommitAuthor is the author of a Git commit.
 type CommitAuthor struct {
-	Name  string `yaml:"name,omitempty" json:"name,omitempty"`
-	Email string `yaml:"email,omitempty" json:"email,omitempty"`
+	Name    string        `yaml:"name,omitempty" json:"name,omitempty"`
+	Email   string        `yaml:"email,omitempty" json:"email,omitempty"`
+	Signing CommitSigning `yaml:"signing,omitempty" json:"signing,omitempty"`
+}
+
+// CommitSigning holds the settings to sign commits with git.
+type CommitSigning struct {
+	Enabled    bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Key        string `yaml:"key,omitempty" json:"key,omitempty"`
+	Program    string `yaml:"program,omitempty" json:"program,omitempty"`
+	Format     string `yaml:"format,omitempty" json:"format,omitempty" jsonschema:"enum=openpgp,enum=x509,enum=ssh,default=openpgp"`
+	Passphrase string `yaml:"passphrase,omitempty" json:"passphrase,omitempty"`
 }
 
 // BuildHooks define actions to run before and/or after something.
@@ -340,6 +515,8 @@ type Build struct {
 	Command         string          `yaml:"command,omitempty" json:"command,omitempty"`
 	NoUniqueDistDir bool            `yaml:"no_unique_dist_dir,omitempty" json:"no_unique_dist_dir,omitempty"`
 	NoMainCheck     bool            `yaml:"no_main_check,omitempty" json:"no_main_check,omitempty"`
+	BuildCacheDir   string          `yaml:"build_cache_dir,omitempty" json:"build_cache_dir,omitempty"`
+	VersionInfo     VersionInfo     `yaml:"versioninfo,omitempty" json:"versioninfo,omitempty"`
 	UnproxiedMain   string          `yaml:"-" json:"-"` // used by gomod.proxy
 	UnproxiedDir    string          `yaml:"-" json:"-"` // used by gomod.proxy
 
@@ -366,6 +543,20 @@ type BuildDetails struct {
 	Env       []string    `yaml:"env,omitempty" json:"env,omitempty"`
 }
 
+// VersionInfo configures the version info resource embedded in windows
+// binaries, generated with goversioninfo.
+type VersionInfo struct {
+	Enabled         bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Binary          string `yaml:"binary,omitempty" json:"binary,omitempty"`
+	CompanyName     string `yaml:"company_name,omitempty" json:"company_name,omitempty"`
+	ProductName     string `yaml:"product_name,omitempty" json:"product_name,omitempty"`
+	FileDescription string `yaml:"file_description,omitempty" json:"file_description,omitempty"`
+	LegalCopyright  string `yaml:"legal_copyright,omitempty" json:"legal_copyright,omitempty"`
+	FileVersion     string `yaml:"file_version,omitempty" json:"file_version,omitempty"`
+	ProductVersion  string `yaml:"product_version,omitempty" json:"product_version,omitempty"`
+	Icon            string `yaml:"icon,omitempty" json:"icon,omitempty"`
+}
+
 type BuildHookConfig struct {
 	Pre  Hooks `yaml:"pre,omitempty" json:"pre,omitempty"`
 	Post Hooks `yaml:"post,omitempty" json:"post,omitempty"`
@@ -434,6 +625,8 @@ func (bh Hook) JSONSchema() *jsonschema.Schema {
 // FormatOverride is used to specify a custom format for a specific GOOS.
 type FormatOverride struct {
 	Goos   string `yaml:"goos,omitempty" json:"goos,omitempty"`
+	Goarch string `yaml:"goarch,omitempty" json:"goarch,omitempty"`
+	Goarm  string `yaml:"goarm,omitempty" json:"goarm,omitempty" jsonschema:"oneof_type=string;integer"`
 	Format string `yaml:"format,omitempty" json:"format,omitempty"`
 }
 
@@ -443,6 +636,8 @@ type File struct {
 	Destination string   `yaml:"dst,omitempty" json:"dst,omitempty"`
 	StripParent bool     `yaml:"strip_parent,omitempty" json:"strip_parent,omitempty"`
 	Info        FileInfo `yaml:"info,omitempty" json:"info,omitempty"`
+	If          string   `yaml:"if,omitempty" json:"if,omitempty"`
+	Exclude     []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
 }
 
 // FileInfo is the file info of a file.
@@ -496,6 +691,16 @@ type UniversalBinary struct {
 	Hooks        BuildHookConfig `yaml:"hooks,omitempty" json:"hooks,omitempty"`
 }
 
+// UPX config used to compress binaries with UPX.
+type UPX struct {
+	Enabled  bool     `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	IDs      []string `yaml:"ids,omitempty" json:"ids,omitempty"`
+	Goos     []string `yaml:"goos,omitempty" json:"goos,omitempty"`
+	Binary   string   `yaml:"binary,omitempty" json:"binary,omitempty"`
+	Compress string   `yaml:"compress,omitempty" json:"compress,omitempty" jsonschema:"enum=1,enum=2,enum=3,enum=4,enum=5,enum=6,enum=7,enum=8,enum=9,enum=best,enum=,default="`
+	LZMA     bool     `yaml:"lzma,omitempty" json:"lzma,omitempty"`
+}
+
 // Archive config used for the archive.
 type Archive struct {
 	ID                        string            `yaml:"id,omitempty" json:"id,omitempty"`
@@ -511,6 +716,8 @@ type Archive struct {
 	Files                     []File            `yaml:"files,omitempty" json:"files,omitempty"`
 	Meta                      bool              `yaml:"meta,omitempty" json:"meta,omitempty"`
 	AllowDifferentBinaryCount bool              `yaml:"allow_different_binary_count,omitempty" json:"allow_different_binary_count,omitempty"`
+	IncludeManifest           bool              `yaml:"include_manifest,omitempty" json:"include_manifest,omitempty"`
+	CompressionLevel          int               `yaml:"compression_level,omitempty" json:"compression_level,omitempty"`
 }
 
 type ReleaseNotesMode string
@@ -524,31 +731,37 @@ const (
 
 // Release config used for the GitHub/GitLab release.
 type Release struct {
-	GitHub                 Repo        `yaml:"github,omitempty" json:"github,omitempty"`
-	GitLab                 Repo        `yaml:"gitlab,omitempty" json:"gitlab,omitempty"`
-	Gitea                  Repo        `yaml:"gitea,omitempty" json:"gitea,omitempty"`
-	Draft                  bool        `yaml:"draft,omitempty" json:"draft,omitempty"`
-	ReplaceExistingDraft   bool        `yaml:"replace_existing_draft,omitempty" json:"replace_existing_draft,omitempty"`
-	TargetCommitish        string      `yaml:"target_commitish,omitempty" json:"target_commitish,omitempty"`
-	Disable                string      `yaml:"disable,omitempty" json:"disable,omitempty" jsonschema:"oneof_type=string;boolean"`
-	SkipUpload             string      `yaml:"skip_upload,omitempty" json:"skip_upload,omitempty" jsonschema:"oneof_type=string;boolean"`
-	Prerelease             string      `yaml:"prerelease,omitempty" json:"prerelease,omitempty"`
-	NameTemplate           string      `yaml:"name_template,omitempty" json:"name_template,omitempty"`
-	IDs                    []string    `yaml:"ids,omitempty" json:"ids,omitempty"`
-	ExtraFiles             []ExtraFile `yaml:"extra_files,omitempty" json:"extra_files,omitempty"`
-	DiscussionCategoryName string      `yaml:"discussion_category_name,omitempty" json:"discussion_category_name,omitempty"`
-	Header                 string      `yaml:"header,omitempty" json:"header,omitempty"`
-	Footer                 string      `yaml:"footer,omitempty" json:"footer,omitempty"`
+	GitHub                 Repo              `yaml:"github,omitempty" json:"github,omitempty"`
+	GitLab                 Repo              `yaml:"gitlab,omitempty" json:"gitlab,omitempty"`
+	Gitea                  Repo              `yaml:"gitea,omitempty" json:"gitea,omitempty"`
+	Draft                  bool              `yaml:"draft,omitempty" json:"draft,omitempty"`
+	ReplaceExistingDraft   bool              `yaml:"replace_existing_draft,omitempty" json:"replace_existing_draft,omitempty"`
+	TargetCommitish        string            `yaml:"target_commitish,omitempty" json:"target_commitish,omitempty"`
+	Disable                string            `yaml:"disable,omitempty" json:"disable,omitempty" jsonschema:"oneof_type=string;boolean"`
+	SkipUpload             string            `yaml:"skip_upload,omitempty" json:"skip_upload,omitempty" jsonschema:"oneof_type=string;boolean"`
+	VerifyChecksums        bool              `yaml:"verify_checksums,omitempty" json:"verify_checksums,omitempty"`
+	Prerelease             string            `yaml:"prerelease,omitempty" json:"prerelease,omitempty"`
+	NameTemplate           string            `yaml:"name_template,omitempty" json:"name_template,omitempty"`
+	IDs                    []string          `yaml:"ids,omitempty" json:"ids,omitempty"`
+	ExtraFiles             []ExtraFile       `yaml:"extra_files,omitempty" json:"extra_files,omitempty"`
+	DiscussionCategoryName string            `yaml:"discussion_category_name,omitempty" json:"discussion_category_name,omitempty"`
+	Header                 string            `yaml:"header,omitempty" json:"header,omitempty"`
+	Footer                 string            `yaml:"footer,omitempty" json:"footer,omitempty"`
+	MakeLatest             string            `yaml:"make_latest,omitempty" json:"make_latest,omitempty" jsonschema:"oneof_type=string;boolean"`
+	ReleaseNotesTmpl       string            `yaml:"release_notes_tmpl,omitempty" json:"release_notes_tmpl,omitempty"`
+	ReleaseNotesPosition   string            `yaml:"release_notes_position,omitempty" json:"release_notes_position,omitempty" jsonschema:"enum=prepend,enum=append,default=prepend"`
+	GitLabLinkTypes        map[string]string `yaml:"gitlab_link_types,omitempty" json:"gitlab_link_types,omitempty"`
 
 	ReleaseNotesMode ReleaseNotesMode `yaml:"mode,omitempty" json:"mode,omitempty" jsonschema:"enum=keep-existing,enum=append,enum=prepend,enum=replace,default=keep-existing"`
 }
 
 // Milestone config used for VCS milestone.
 type Milestone struct {
-	Repo         Repo   `yaml:"repo,omitempty" json:"repo,omitempty"`
-	Close        bool   `yaml:"close,omitempty" json:"close,omitempty"`
-	FailOnError  bool   `yaml:"fail_on_error,omitempty" json:"fail_on_error,omitempty"`
-	NameTemplate string `yaml:"name_template,omitempty" json:"name_template,omitempty"`
+	Repo            Repo   `yaml:"repo,omitempty" json:"repo,omitempty"`
+	Close           bool   `yaml:"close,omitempty" json:"close,omitempty"`
+	FailOnError     bool   `yaml:"fail_on_error,omitempty" json:"fail_on_error,omitempty"`
+	NameTemplate    string `yaml:"name_template,omitempty" json:"name_template,omitempty"`
+	CreateIfMissing bool   `yaml:"create_if_missing,omitempty" json:"create_if_missing,omitempty"`
 }
 
 // ExtraFile on a release.
@@ -562,19 +775,61 @@ type NFPM struct {
 	NFPMOverridables `yaml:",inline" json:",inline"` // nolint: tagliatelle
 	Overrides        map[string]NFPMOverridables     `yaml:"overrides,omitempty" json:"overrides,omitempty"`
 
-	ID          string   `yaml:"id,omitempty" json:"id,omitempty"`
-	Builds      []string `yaml:"builds,omitempty" json:"builds,omitempty"`
-	Formats     []string `yaml:"formats,omitempty" json:"formats,omitempty"`
-	Section     string   `yaml:"section,omitempty" json:"section,omitempty"`
-	Priority    string   `yaml:"priority,omitempty" json:"priority,omitempty"`
-	Vendor      string   `yaml:"vendor,omitempty" json:"vendor,omitempty"`
-	Homepage    string   `yaml:"homepage,omitempty" json:"homepage,omitempty"`
-	Maintainer  string   `yaml:"maintainer,omitempty" json:"maintainer,omitempty"`
-	Description string   `yaml:"description,omitempty" json:"description,omitempty"`
-	License     string   `yaml:"license,omitempty" json:"license,omitempty"`
-	Bindir      string   `yaml:"bindir,omitempty" json:"bindir,omitempty"`
-	Changelog   string   `yaml:"changelog,omitempty" json:"changelog,omitempty"`
-	Meta        bool     `yaml:"meta,omitempty" json:"meta,omitempty"` // make package without binaries - only deps
+	ID                string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Builds            []string `yaml:"builds,omitempty" json:"builds,omitempty"`
+	Formats           []string `yaml:"formats,omitempty" json:"formats,omitempty"`
+	Section           string   `yaml:"section,omitempty" json:"section,omitempty"`
+	Priority          string   `yaml:"priority,omitempty" json:"priority,omitempty"`
+	Vendor            string   `yaml:"vendor,omitempty" json:"vendor,omitempty"`
+	Homepage          string   `yaml:"homepage,omitempty" json:"homepage,omitempty"`
+	Maintainer        string   `yaml:"maintainer,omitempty" json:"maintainer,omitempty"`
+	Description       string   `yaml:"description,omitempty" json:"description,omitempty"`
+	License           string   `yaml:"license,omitempty" json:"license,omitempty"`
+	Bindir            string   `yaml:"bindir,omitempty" json:"bindir,omitempty"`
+	Changelog         string   `yaml:"changelog,omitempty" json:"changelog,omitempty"`
+	GenerateChangelog bool     `yaml:"generate_changelog,omitempty" json:"generate_changelog,omitempty"`
+	Meta              bool     `yaml:"meta,omitempty" json:"meta,omitempty"` // make package without binaries - only deps
+
+	Systemd []NFPMSystemd `yaml:"systemd,omitempty" json:"systemd,omitempty"`
+}
+
+// NFPMSystemd is a systemd service, and optional timer, to be generated and
+// added to deb, rpm and archlinux packages.
+type NFPMSystemd struct {
+	Name        string            `yaml:"name,omitempty" json:"name,omitempty"`
+	Description string            `yaml:"description,omitempty" json:"description,omitempty"`
+	ExecStart   string            `yaml:"exec_start,omitempty" json:"exec_start,omitempty"`
+	User        string            `yaml:"user,omitempty" json:"user,omitempty"`
+	Restart     string            `yaml:"restart,omitempty" json:"restart,omitempty"`
+	Environment []string          `yaml:"environment,omitempty" json:"environment,omitempty"`
+	WantedBy    string            `yaml:"wanted_by,omitempty" json:"wanted_by,omitempty"`
+	Timer       *NFPMSystemdTimer `yaml:"timer,omitempty" json:"timer,omitempty"`
+}
+
+// NFPMSystemdTimer is a systemd timer that activates its service.
+type NFPMSystemdTimer struct {
+	OnCalendar      string `yaml:"on_calendar,omitempty" json:"on_calendar,omitempty"`
+	OnBootSec       string `yaml:"on_boot_sec,omitempty" json:"on_boot_sec,omitempty"`
+	OnUnitActiveSec string `yaml:"on_unit_active_sec,omitempty" json:"on_unit_active_sec,omitempty"`
+	Persistent      bool   `yaml:"persistent,omitempty" json:"persistent,omitempty"`
+}
+
+// NFPMContent is a file, directory or symlink to be added to the package.
+type NFPMContent struct {
+	Source      string               `yaml:"src,omitempty" json:"src,omitempty"`
+	Destination string               `yaml:"dst,omitempty" json:"dst,omitempty"`
+	Type        string               `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"enum=symlink,enum=ghost,enum=config,enum=config|noreplace,enum=dir,enum=,default="`
+	Packager    string               `yaml:"packager,omitempty" json:"packager,omitempty"`
+	FileInfo    *NFPMContentFileInfo `yaml:"file_info,omitempty" json:"file_info,omitempty"`
+}
+
+// NFPMContentFileInfo is the file info of a package content entry.
+// Owner, group and mode are templateable.
+type NFPMContentFileInfo struct {
+	Owner string    `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Group string    `yaml:"group,omitempty" json:"group,omitempty"`
+	Mode  string    `yaml:"mode,omitempty" json:"mode,omitempty" jsonschema:"oneof_type=string;integer"`
+	MTime time.Time `yaml:"mtime,omitempty" json:"mtime,omitempty"`
 }
 
 // NFPMScripts is used to specify maintainer scripts.
@@ -635,11 +890,12 @@ type NFPMDebSignature struct {
 
 // NFPMDeb is custom configs that are only available on deb packages.
 type NFPMDeb struct {
-	Scripts   NFPMDebScripts   `yaml:"scripts,omitempty" json:"scripts,omitempty"`
-	Triggers  NFPMDebTriggers  `yaml:"triggers,omitempty" json:"triggers,omitempty"`
-	Breaks    []string         `yaml:"breaks,omitempty" json:"breaks,omitempty"`
-	Signature NFPMDebSignature `yaml:"signature,omitempty" json:"signature,omitempty"`
-	Lintian   []string         `yaml:"lintian_overrides,omitempty" json:"lintian_overrides,omitempty"`
+	Compression string           `yaml:"compression,omitempty" json:"compression,omitempty"`
+	Scripts     NFPMDebScripts   `yaml:"scripts,omitempty" json:"scripts,omitempty"`
+	Triggers    NFPMDebTriggers  `yaml:"triggers,omitempty" json:"triggers,omitempty"`
+	Breaks      []string         `yaml:"breaks,omitempty" json:"breaks,omitempty"`
+	Signature   NFPMDebSignature `yaml:"signature,omitempty" json:"signature,omitempty"`
+	Lintian     []string         `yaml:"lintian_overrides,omitempty" json:"lintian_overrides,omitempty"`
 }
 
 type NFPMAPKScripts struct {
@@ -688,7 +944,7 @@ type NFPMOverridables struct {
 	Conflicts        []string          `yaml:"conflicts,omitempty" json:"conflicts,omitempty"`
 	Replaces         []string          `yaml:"replaces,omitempty" json:"replaces,omitempty"`
 	Provides         []string          `yaml:"provides,omitempty" json:"provides,omitempty"`
-	Contents         files.Contents    `yaml:"contents,omitempty" json:"contents,omitempty"`
+	Contents         []*NFPMContent    `yaml:"contents,omitempty" json:"contents,omitempty"`
 	Scripts          NFPMScripts       `yaml:"scripts,omitempty" json:"scripts,omitempty"`
 	RPM              NFPMRPM           `yaml:"rpm,omitempty" json:"rpm,omitempty"`
 	Deb              NFPMDeb           `yaml:"deb,omitempty" json:"deb,omitempty"`
@@ -696,6 +952,38 @@ type NFPMOverridables struct {
 	ArchLinux        NFPMArchLinux     `yaml:"archlinux,omitempty" json:"archlinux,omitempty"`
 }
 
+// Notarize config.
+type Notarize struct {
+	ID       string        `yaml:"id,omitempty" json:"id,omitempty"`
+	IDs      []string      `yaml:"ids,omitempty" json:"ids,omitempty"`
+	Cmd      string        `yaml:"cmd,omitempty" json:"cmd,omitempty"`
+	AppleID  string        `yaml:"apple_id,omitempty" json:"apple_id,omitempty"`
+	Password string        `yaml:"password,omitempty" json:"password,omitempty"`
+	TeamID   string        `yaml:"team_id,omitempty" json:"team_id,omitempty"`
+	Key      string        `yaml:"key,omitempty" json:"key,omitempty"`
+	KeyID    string        `yaml:"key_id,omitempty" json:"key_id,omitempty"`
+	Issuer   string        `yaml:"issuer,omitempty" json:"issuer,omitempty"`
+	Timeout  time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+
+	KeychainProfile string `yaml:"keychain_profile,omitempty" json:"keychain_profile,omitempty"`
+}
+
+// Attestation config.
+type Attestation struct {
+	ID         string   `yaml:"id,omitempty" json:"id,omitempty"`
+	Cmd        string   `yaml:"cmd,omitempty" json:"cmd,omitempty"`
+	Args       []string `yaml:"args,omitempty" json:"args,omitempty"`
+	Output     string   `yaml:"output,omitempty" json:"output,omitempty"`
+	Predicate  string   `yaml:"predicate,omitempty" json:"predicate,omitempty"`
+	Artifacts  string   `yaml:"artifacts,omitempty" json:"artifacts,omitempty" jsonschema:"enum=all,enum=checksum,enum=source,enum=package,enum=archive,enum=binary,enum=sbom,default=binary"`
+	IDs        []string `yaml:"ids,omitempty" json:"ids,omitempty"`
+	Stdin      *string  `yaml:"stdin,omitempty" json:"stdin,omitempty"`
+	StdinFile  string   `yaml:"stdin_file,omitempty" json:"stdin_file,omitempty"`
+	Env        []string `yaml:"env,omitempty" json:"env,omitempty"`
+	Key        string   `yaml:"key,omitempty" json:"key,omitempty"`
+	SkipUpload bool     `yaml:"skip_upload,omitempty" json:"skip_upload,omitempty"`
+}
+
 // SBOM config.
 type SBOM struct {
 	ID        string   `yaml:"id,omitempty" json:"id,omitempty"`
@@ -713,13 +1001,17 @@ type Sign struct {
 	Cmd         string   `yaml:"cmd,omitempty" json:"cmd,omitempty"`
 	Args        []string `yaml:"args,omitempty" json:"args,omitempty"`
 	Signature   string   `yaml:"signature,omitempty" json:"signature,omitempty"`
-	Artifacts   string   `yaml:"artifacts,omitempty" json:"artifacts,omitempty" jsonschema:"enum=all,enum=manifests,enum=images,enum=checksum,enum=source,enum=package,enum=archive,enum=binary,enum=sbom"`
+	Artifacts   string   `yaml:"artifacts,omitempty" json:"artifacts,omitempty" jsonschema:"enum=none,enum=all,enum=manifests,enum=images,enum=checksum,enum=source,enum=package,enum=archive,enum=binary,enum=sbom"`
 	IDs         []string `yaml:"ids,omitempty" json:"ids,omitempty"`
 	Stdin       *string  `yaml:"stdin,omitempty" json:"stdin,omitempty"`
 	StdinFile   string   `yaml:"stdin_file,omitempty" json:"stdin_file,omitempty"`
 	Env         []string `yaml:"env,omitempty" json:"env,omitempty"`
 	Certificate string   `yaml:"certificate,omitempty" json:"certificate,omitempty"`
+	Key         string   `yaml:"key,omitempty" json:"key,omitempty"`
 	Output      bool     `yaml:"output,omitempty" json:"output,omitempty"`
+	FulcioURL   string   `yaml:"fulcio_url,omitempty" json:"fulcio_url,omitempty"`
+	RekorURL    string   `yaml:"rekor_url,omitempty" json:"rekor_url,omitempty"`
+	OIDCIssuer  string   `yaml:"oidc_issuer,omitempty" json:"oidc_issuer,omitempty"`
 }
 
 // SnapcraftAppMetadata for the binaries that will be in the snap package.
@@ -784,6 +1076,7 @@ type Snapcraft struct {
 	Layout           map[string]SnapcraftLayoutMetadata `yaml:"layout,omitempty" json:"layout,omitempty"`
 	Apps             map[string]SnapcraftAppMetadata    `yaml:"apps,omitempty" json:"apps,omitempty"`
 	Plugs            map[string]interface{}             `yaml:"plugs,omitempty" json:"plugs,omitempty"`
+	Slots            map[string]interface{}             `yaml:"slots,omitempty" json:"slots,omitempty"`
 
 	Files []SnapcraftExtraFiles `yaml:"extra_files,omitempty" json:"extra_files,omitempty"`
 }
@@ -798,6 +1091,7 @@ type SnapcraftExtraFiles struct {
 // Snapshot config.
 type Snapshot struct {
 	NameTemplate string `yaml:"name_template,omitempty" json:"name_template,omitempty"`
+	Push         bool   `yaml:"push,omitempty" json:"push,omitempty"`
 }
 
 // Checksum config.
@@ -807,6 +1101,7 @@ type Checksum struct {
 	IDs          []string    `yaml:"ids,omitempty" json:"ids,omitempty"`
 	Disable      bool        `yaml:"disable,omitempty" json:"disable,omitempty"`
 	ExtraFiles   []ExtraFile `yaml:"extra_files,omitempty" json:"extra_files,omitempty"`
+	Split        bool        `yaml:"split,omitempty" json:"split,omitempty"`
 }
 
 // Docker image config.
@@ -818,12 +1113,33 @@ type Docker struct {
 	Goarm              string   `yaml:"goarm,omitempty" json:"goarm,omitempty" jsonschema:"oneof_type=string;integer"`
 	Goamd64            string   `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
 	Dockerfile         string   `yaml:"dockerfile,omitempty" json:"dockerfile,omitempty"`
+	Context            string   `yaml:"context,omitempty" json:"context,omitempty"`
 	ImageTemplates     []string `yaml:"image_templates,omitempty" json:"image_templates,omitempty"`
 	SkipPush           string   `yaml:"skip_push,omitempty" json:"skip_push,omitempty" jsonschema:"oneof_type=string;boolean"`
 	Files              []string `yaml:"extra_files,omitempty" json:"extra_files,omitempty"`
 	BuildFlagTemplates []string `yaml:"build_flag_templates,omitempty" json:"build_flag_templates,omitempty"`
 	PushFlags          []string `yaml:"push_flags,omitempty" json:"push_flags,omitempty"`
 	Use                string   `yaml:"use,omitempty" json:"use,omitempty"`
+
+	Secrets    []DockerSecret `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	SSH        []string       `yaml:"ssh,omitempty" json:"ssh,omitempty"`
+	Registries []string       `yaml:"registries,omitempty" json:"registries,omitempty"`
+
+	TestCommand []string      `yaml:"test_command,omitempty" json:"test_command,omitempty"`
+	TestTimeout time.Duration `yaml:"test_timeout,omitempty" json:"test_timeout,omitempty"`
+
+	Labels     map[string]string `yaml:"labels,omitempty" json:"labels,omitempty"`
+	AutoLabels bool              `yaml:"auto_labels,omitempty" json:"auto_labels,omitempty"`
+
+	SnapshotImageTemplates []string `yaml:"snapshot_image_templates,omitempty" json:"snapshot_image_templates,omitempty"`
+}
+
+// DockerSecret is a secret exposed to the docker build, to be mounted with
+// `RUN --mount=type=secret`.
+type DockerSecret struct {
+	ID  string `yaml:"id,omitempty" json:"id,omitempty"`
+	Src string `yaml:"src,omitempty" json:"src,omitempty"`
+	Env string `yaml:"env,omitempty" json:"env,omitempty"`
 }
 
 // DockerManifest config.
@@ -835,11 +1151,56 @@ type DockerManifest struct {
 	CreateFlags    []string `yaml:"create_flags,omitempty" json:"create_flags,omitempty"`
 	PushFlags      []string `yaml:"push_flags,omitempty" json:"push_flags,omitempty"`
 	Use            string   `yaml:"use,omitempty" json:"use,omitempty"`
+	Registries     []string `yaml:"registries,omitempty" json:"registries,omitempty"`
+
+	Annotations     map[string]string `yaml:"annotations,omitempty" json:"annotations,omitempty"`
+	AutoAnnotations bool              `yaml:"auto_annotations,omitempty" json:"auto_annotations,omitempty"`
 }
 
 // Filters config.
 type Filters struct {
-	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+	Exclude []ChangelogFilter `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// ChangelogFilter removes the commits matching the given regexp from the
+// changelog.
+type ChangelogFilter struct {
+	Regexp string `yaml:"regexp,omitempty" json:"regexp,omitempty"`
+	Match  string `yaml:"match,omitempty" json:"match,omitempty" jsonschema:"enum=subject,enum=body,enum=author,default=subject"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that allows simplified declarations
+// of filters as strings, which are matched against the commit subject.
+func (f *ChangelogFilter) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type t ChangelogFilter
+	var str string
+	if err := unmarshal(&str); err == nil {
+		*f = ChangelogFilter{Regexp: str}
+		return nil
+	}
+
+	var filter t
+	if err := unmarshal(&filter); err != nil {
+		return err
+	}
+	*f = ChangelogFilter(filter)
+	return nil
+}
+
+func (f ChangelogFilter) JSONSchema() *jsonschema.Schema {
+	type t ChangelogFilter
+	reflector := jsonschema.Reflector{
+		ExpandedStruct: true,
+	}
+	schema := reflector.Reflect(&t{})
+	return &jsonschema.Schema{
+		OneOf: []*jsonschema.Schema{
+			{
+				Type: "string",
+			},
+			schema,
+		},
+	}
 }
 
 // Changelog Config.
@@ -850,6 +1211,11 @@ type Changelog struct {
 	Use     string           `yaml:"use,omitempty" json:"use,omitempty" jsonschema:"enum=git,enum=github,enum=github-native,enum=gitlab,default=git"`
 	Groups  []ChangelogGroup `yaml:"groups,omitempty" json:"groups,omitempty"`
 	Abbrev  int              `yaml:"abbrev,omitempty" json:"abbrev,omitempty"`
+
+	UseConventionalScopes bool `yaml:"use_conventional_scopes,omitempty" json:"use_conventional_scopes,omitempty"`
+
+	IncludeContributors bool     `yaml:"include_contributors,omitempty" json:"include_contributors,omitempty"`
+	ExcludeContributors []string `yaml:"exclude_contributors,omitempty" json:"exclude_contributors,omitempty"`
 }
 
 // ChangelogGroup holds the grouping criteria for the changelog.
@@ -862,9 +1228,14 @@ type ChangelogGroup struct {
 // EnvFiles holds paths to files that contains environment variables
 // values like the github token for example.
 type EnvFiles struct {
-	GitHubToken string `yaml:"github_token,omitempty" json:"github_token,omitempty"`
-	GitLabToken string `yaml:"gitlab_token,omitempty" json:"gitlab_token,omitempty"`
-	GiteaToken  string `yaml:"gitea_token,omitempty" json:"gitea_token,omitempty"`
+	GitHubToken    string `yaml:"github_token,omitempty" json:"github_token,omitempty"`
+	GitLabToken    string `yaml:"gitlab_token,omitempty" json:"gitlab_token,omitempty"`
+	GiteaToken     string `yaml:"gitea_token,omitempty" json:"gitea_token,omitempty"`
+	GitHubTokenCmd string `yaml:"github_token_cmd,omitempty" json:"github_token_cmd,omitempty"`
+	GitLabTokenCmd string `yaml:"gitlab_token_cmd,omitempty" json:"gitlab_token_cmd,omitempty"`
+	GiteaTokenCmd  string `yaml:"gitea_token_cmd,omitempty" json:"gitea_token_cmd,omitempty"`
+
+	TokenCmdTimeout time.Duration `yaml:"token_cmd_timeout,omitempty" json:"token_cmd_timeout,omitempty"`
 }
 
 // Before config.
@@ -883,6 +1254,16 @@ type Blob struct {
 	IDs        []string    `yaml:"ids,omitempty" json:"ids,omitempty"`
 	Endpoint   string      `yaml:"endpoint,omitempty" json:"endpoint,omitempty"` // used for minio for example
 	ExtraFiles []ExtraFile `yaml:"extra_files,omitempty" json:"extra_files,omitempty"`
+
+	S3ForcePathStyle *bool  `yaml:"s3_force_path_style,omitempty" json:"s3_force_path_style,omitempty"`
+	ContentType      string `yaml:"content_type,omitempty" json:"content_type,omitempty"`
+	CacheControl     string `yaml:"cache_control,omitempty" json:"cache_control,omitempty"`
+	Parallelism      int    `yaml:"parallelism,omitempty" json:"parallelism,omitempty"`
+	PartSize         int64  `yaml:"part_size,omitempty" json:"part_size,omitempty"`
+	SSE              string `yaml:"sse,omitempty" json:"sse,omitempty" jsonschema:"enum=AES256,enum=aws:kms,default="`
+	SSEKMSKeyID      string `yaml:"sse_kms_key_id,omitempty" json:"sse_kms_key_id,omitempty"`
+
+	Metadata map[string]string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
 }
 
 // Upload configuration.
@@ -890,11 +1271,14 @@ type Upload struct {
 	Name               string            `yaml:"name,omitempty" json:"name,omitempty"`
 	IDs                []string          `yaml:"ids,omitempty" json:"ids,omitempty"`
 	Exts               []string          `yaml:"exts,omitempty" json:"exts,omitempty"`
+	Goos               []string          `yaml:"goos,omitempty" json:"goos,omitempty"`
+	Glob               string            `yaml:"glob,omitempty" json:"glob,omitempty"`
 	Target             string            `yaml:"target,omitempty" json:"target,omitempty"`
 	Username           string            `yaml:"username,omitempty" json:"username,omitempty"`
 	Mode               string            `yaml:"mode,omitempty" json:"mode,omitempty"`
 	Method             string            `yaml:"method,omitempty" json:"method,omitempty"`
 	ChecksumHeader     string            `yaml:"checksum_header,omitempty" json:"checksum_header,omitempty"`
+	ChecksumHeaders    map[string]string `yaml:"checksum_headers,omitempty" json:"checksum_headers,omitempty"`
 	ClientX509Cert     string            `yaml:"client_x509_cert,omitempty" json:"client_x509_cert,omitempty"`
 	ClientX509Key      string            `yaml:"client_x509_key,omitempty" json:"client_x509_key,omitempty"`
 	TrustedCerts       string            `yaml:"trusted_certificates,omitempty" json:"trusted_certificates,omitempty"`
@@ -902,6 +1286,7 @@ type Upload struct {
 	Signature          bool              `yaml:"signature,omitempty" json:"signature,omitempty"`
 	CustomArtifactName bool              `yaml:"custom_artifact_name,omitempty" json:"custom_artifact_name,omitempty"`
 	CustomHeaders      map[string]string `yaml:"custom_headers,omitempty" json:"custom_headers,omitempty"`
+	PartSize           int64             `yaml:"part_size,omitempty" json:"part_size,omitempty"`
 }
 
 // Publisher configuration.
@@ -928,15 +1313,19 @@ type Source struct {
 
 // Project includes all project configuration.
 type Project struct {
+	Includes        []Include        `yaml:"includes,omitempty" json:"includes,omitempty"`
 	ProjectName     string           `yaml:"project_name,omitempty" json:"project_name,omitempty"`
 	Env             []string         `yaml:"env,omitempty" json:"env,omitempty"`
 	Release         Release          `yaml:"release,omitempty" json:"release,omitempty"`
 	Milestones      []Milestone      `yaml:"milestones,omitempty" json:"milestones,omitempty"`
 	Brews           []Homebrew       `yaml:"brews,omitempty" json:"brews,omitempty"`
+	HomebrewCasks   []HomebrewCask   `yaml:"homebrew_casks,omitempty" json:"homebrew_casks,omitempty"`
 	AURs            []AUR            `yaml:"aurs,omitempty" json:"aurs,omitempty"`
 	Krews           []Krew           `yaml:"krews,omitempty" json:"krews,omitempty"`
 	Kos             []Ko             `yaml:"kos,omitempty" json:"kos,omitempty"`
 	Scoop           Scoop            `yaml:"scoop,omitempty" json:"scoop,omitempty"`
+	Winget          []Winget         `yaml:"winget,omitempty" json:"winget,omitempty"`
+	Nix             []Nix            `yaml:"nix,omitempty" json:"nix,omitempty"`
 	Builds          []Build          `yaml:"builds,omitempty" json:"builds,omitempty"`
 	Archives        []Archive        `yaml:"archives,omitempty" json:"archives,omitempty"`
 	NFPMs           []NFPM           `yaml:"nfpms,omitempty" json:"nfpms,omitempty"`
@@ -953,7 +1342,10 @@ type Project struct {
 	Dist            string           `yaml:"dist,omitempty" json:"dist,omitempty"`
 	Signs           []Sign           `yaml:"signs,omitempty" json:"signs,omitempty"`
 	DockerSigns     []Sign           `yaml:"docker_signs,omitempty" json:"docker_signs,omitempty"`
+	Attestations    []Attestation    `yaml:"attestations,omitempty" json:"attestations,omitempty"`
+	Notarize        []Notarize       `yaml:"notarize,omitempty" json:"notarize,omitempty"`
 	EnvFiles        EnvFiles         `yaml:"env_files,omitempty" json:"env_files,omitempty"`
+	ForceToken      string           `yaml:"force_token,omitempty" json:"force_token,omitempty" jsonschema:"enum=github,enum=gitlab,enum=gitea,enum=,default="`
 	Before          Before           `yaml:"before,omitempty" json:"before,omitempty"`
 	Source          Source           `yaml:"source,omitempty" json:"source,omitempty"`
 	GoMod           GoMod            `yaml:"gomod,omitempty" json:"gomod,omitempty"`
@@ -963,6 +1355,8 @@ type Project struct {
 	Git             Git              `yaml:"git,omitempty" json:"git,omitempty"`
 
 	UniversalBinaries []UniversalBinary `yaml:"universal_binaries,omitempty" json:"universal_binaries,omitempty"`
+	UPXs              []UPX             `yaml:"upx,omitempty" json:"upx,omitempty"`
+	TemplateFunctions map[string]string `yaml:"template_functions,omitempty" json:"template_functions,omitempty"`
 
 	// this is a hack ¯\_(ツ)_/¯
 	SingleBuild Build `yaml:"build,omitempty" json:"build,omitempty"`
@@ -985,27 +1379,41 @@ type GoMod struct {
 }
 
 type Announce struct {
-	Skip       string     `yaml:"skip,omitempty" json:"skip,omitempty" jsonschema:"oneof_type=string;boolean"`
-	Twitter    Twitter    `yaml:"twitter,omitempty" json:"twitter,omitempty"`
-	Mastodon   Mastodon   `yaml:"mastodon,omitempty" json:"mastodon,omitempty"`
-	Reddit     Reddit     `yaml:"reddit,omitempty" json:"reddit,omitempty"`
-	Slack      Slack      `yaml:"slack,omitempty" json:"slack,omitempty"`
-	Discord    Discord    `yaml:"discord,omitempty" json:"discord,omitempty"`
-	Teams      Teams      `yaml:"teams,omitempty" json:"teams,omitempty"`
-	SMTP       SMTP       `yaml:"smtp,omitempty" json:"smtp,omitempty"`
-	Mattermost Mattermost `yaml:"mattermost,omitempty" json:"mattermost,omitempty"`
-	LinkedIn   LinkedIn   `yaml:"linkedin,omitempty" json:"linkedin,omitempty"`
-	Telegram   Telegram   `yaml:"telegram,omitempty" json:"telegram,omitempty"`
-	Webhook    Webhook    `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	Skip        string      `yaml:"skip,omitempty" json:"skip,omitempty" jsonschema:"oneof_type=string;boolean"`
+	Twitter     Twitter     `yaml:"twitter,omitempty" json:"twitter,omitempty"`
+	Mastodon    Mastodon    `yaml:"mastodon,omitempty" json:"mastodon,omitempty"`
+	Reddit      Reddit      `yaml:"reddit,omitempty" json:"reddit,omitempty"`
+	Slack       Slack       `yaml:"slack,omitempty" json:"slack,omitempty"`
+	Discord     Discord     `yaml:"discord,omitempty" json:"discord,omitempty"`
+	Teams       Teams       `yaml:"teams,omitempty" json:"teams,omitempty"`
+	SMTP        SMTP        `yaml:"smtp,omitempty" json:"smtp,omitempty"`
+	Mattermost  Mattermost  `yaml:"mattermost,omitempty" json:"mattermost,omitempty"`
+	LinkedIn    LinkedIn    `yaml:"linkedin,omitempty" json:"linkedin,omitempty"`
+	Telegram    Telegram    `yaml:"telegram,omitempty" json:"telegram,omitempty"`
+	Webhook     Webhook     `yaml:"webhook,omitempty" json:"webhook,omitempty"`
+	Discussions Discussions `yaml:"discussions,omitempty" json:"discussions,omitempty"`
+}
+
+type Discussions struct {
+	Enabled         bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	Repo            Repo   `yaml:"repo,omitempty" json:"repo,omitempty"`
+	Category        string `yaml:"category,omitempty" json:"category,omitempty"`
+	TitleTemplate   string `yaml:"title_template,omitempty" json:"title_template,omitempty"`
+	MessageTemplate string `yaml:"message_template,omitempty" json:"message_template,omitempty"`
 }
 
 type Webhook struct {
-	Enabled         bool              `yaml:"enabled,omitempty" json:"enabled,omitempty"`
-	SkipTLSVerify   bool              `yaml:"skip_tls_verify,omitempty" json:"skip_tls_verify,omitempty"`
-	MessageTemplate string            `yaml:"message_template,omitempty" json:"message_template,omitempty"`
-	EndpointURL     string            `yaml:"endpoint_url,omitempty" json:"endpoint_url,omitempty"`
-	Headers         map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
-	ContentType     string            `yaml:"content_type,omitempty" json:"content_type,omitempty"`
+	Enabled             bool              `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	SkipTLSVerify       bool              `yaml:"skip_tls_verify,omitempty" json:"skip_tls_verify,omitempty"`
+	MessageTemplate     string            `yaml:"message_template,omitempty" json:"message_template,omitempty"`
+	EndpointURL         string            `yaml:"endpoint_url,omitempty" json:"endpoint_url,omitempty"`
+	Headers             map[string]string `yaml:"headers,omitempty" json:"headers,omitempty"`
+	ContentType         string            `yaml:"content_type,omitempty" json:"content_type,omitempty"`
+	ExpectedStatusCodes []int             `yaml:"expected_status_codes,omitempty" json:"expected_status_codes,omitempty"`
+	ClientCert          string            `yaml:"client_cert,omitempty" json:"client_cert,omitempty"`
+	ClientKey           string            `yaml:"client_key,omitempty" json:"client_key,omitempty"`
+	Retries             int               `yaml:"retries,omitempty" json:"retries,omitempty"`
+	RetryBackoff        time.Duration     `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`
 }
 
 type Twitter struct {
@@ -1017,6 +1425,7 @@ type Mastodon struct {
 	Enabled         bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
 	MessageTemplate string `yaml:"message_template,omitempty" json:"message_template,omitempty"`
 	Server          string `yaml:"server" json:"server"`
+	Visibility      string `yaml:"visibility,omitempty" json:"visibility,omitempty"`
 }
 
 type Reddit struct {
@@ -1040,19 +1449,26 @@ type Slack struct {
 }
 
 type Discord struct {
-	Enabled         bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
-	MessageTemplate string `yaml:"message_template,omitempty" json:"message_template,omitempty"`
-	Author          string `yaml:"author,omitempty" json:"author,omitempty"`
-	Color           string `yaml:"color,omitempty" json:"color,omitempty"`
-	IconURL         string `yaml:"icon_url,omitempty" json:"icon_url,omitempty"`
+	Enabled         bool          `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	MessageTemplate string        `yaml:"message_template,omitempty" json:"message_template,omitempty"`
+	Author          string        `yaml:"author,omitempty" json:"author,omitempty"`
+	Color           string        `yaml:"color,omitempty" json:"color,omitempty"`
+	IconURL         string        `yaml:"icon_url,omitempty" json:"icon_url,omitempty"`
+	Retries         int           `yaml:"retries,omitempty" json:"retries,omitempty"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`
 }
 
 type Teams struct {
-	Enabled         bool   `yaml:"enabled,omitempty" json:"enabled,omitempty"`
-	TitleTemplate   string `yaml:"title_template,omitempty" json:"title_template,omitempty"`
-	MessageTemplate string `yaml:"message_template,omitempty" json:"message_template,omitempty"`
-	Color           string `yaml:"color,omitempty" json:"color,omitempty"`
-	IconURL         string `yaml:"icon_url,omitempty" json:"icon_url,omitempty"`
+	Enabled         bool          `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+	TitleTemplate   string        `yaml:"title_template,omitempty" json:"title_template,omitempty"`
+	MessageTemplate string        `yaml:"message_template,omitempty" json:"message_template,omitempty"`
+	Color           string        `yaml:"color,omitempty" json:"color,omitempty"`
+	IconURL         string        `yaml:"icon_url,omitempty" json:"icon_url,omitempty"`
+	Webhooks        []string      `yaml:"webhooks,omitempty" json:"webhooks,omitempty"`
+	ClientCert      string        `yaml:"client_cert,omitempty" json:"client_cert,omitempty"`
+	ClientKey       string        `yaml:"client_key,omitempty" json:"client_key,omitempty"`
+	Retries         int           `yaml:"retries,omitempty" json:"retries,omitempty"`
+	RetryBackoff    time.Duration `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`
 }
 
 type Mattermost struct {
@@ -1097,15 +1513,28 @@ func Load(file string) (config Project, err error) {
 	}
 	defer f.Close()
 	log.WithField("file", file).Info("loading config file")
-	return LoadReader(f)
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		return config, err
+	}
+	return load(f, filepath.Dir(file), []string{abs})
 }
 
 // LoadReader config via io.Reader.
+// Relative includes are resolved from the current working directory.
 func LoadReader(fd io.Reader) (config Project, err error) {
+	return load(fd, ".", nil)
+}
+
+func load(fd io.Reader, dir string, stack []string) (config Project, err error) {
 	data, err := io.ReadAll(fd)
 	if err != nil {
 		return config, err
 	}
+	data, err = resolveIncludes(data, dir, stack)
+	if err != nil {
+		return config, err
+	}
 	err = yaml.UnmarshalStrict(data, &config)
 	log.WithField("config", config).Debug("loaded config file")
 	return config, err
@@ -1181,6 +1610,7 @@ type Chocolatey struct {
 	APIKey                   string                 `yaml:"api_key,omitempty" json:"api_key,omitempty"`
 	SourceRepo               string                 `yaml:"source_repo,omitempty" json:"source_repo,omitempty"`
 	Goamd64                  string                 `yaml:"goamd64,omitempty" json:"goamd64,omitempty"`
+	ChecksumType             string                 `yaml:"checksum_type,omitempty" json:"checksum_type,omitempty" jsonschema:"enum=md5,enum=sha1,enum=sha256,enum=sha512,default=sha256"`
 }
 
 // ChcolateyDependency represents Chocolatey dependency.
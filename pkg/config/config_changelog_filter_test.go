@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalChangelogFilters(t *testing.T) {
+	var actual Filters
+
+	err := yaml.UnmarshalStrict([]byte(`exclude:
+  - '^docs:'
+  - regexp: 'dependabot\[bot\]@'
+    match: author
+`), &actual)
+	require.NoError(t, err)
+	require.Equal(t, []ChangelogFilter{
+		{Regexp: "^docs:"},
+		{Regexp: `dependabot\[bot\]@`, Match: "author"},
+	}, actual.Exclude)
+}
+
+func TestUnmarshalChangelogFiltersInvalid(t *testing.T) {
+	var actual Filters
+
+	err := yaml.UnmarshalStrict([]byte(`exclude:
+  - nope: foo
+`), &actual)
+	require.Error(t, err)
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyEnvOverrides(t *testing.T) {
+	project := Project{
+		ProjectName: "foo",
+		Dist:        "dist",
+		Release: Release{
+			Draft:        false,
+			NameTemplate: "{{ .Tag }}",
+		},
+		Changelog: Changelog{
+			Abbrev: 7,
+		},
+		Builds: []Build{{ID: "foo"}},
+	}
+	require.NoError(t, ApplyEnvOverrides(&project, []string{
+		"GORELEASER_RELEASE_DRAFT=true",
+		"GORELEASER_RELEASE_NAME_TEMPLATE=v{{ .Version }}",
+		"GORELEASER_RELEASE_GITHUB_OWNER=goreleaser",
+		"GORELEASER_CHANGELOG_ABBREV=-1",
+		"GORELEASER_SOURCE_ENABLED=1",
+		"GORELEASER_ANNOUNCE_WEBHOOK_RETRY_BACKOFF=5s",
+		"GORELEASER_DIST=out=dir",
+		"GORELEASER_CURRENT_TAG=v1.0.0", // not a config field
+		"GORELEASER_BUILDS_ID=bar",      // fields in lists can't be overridden
+		"RELEASE_DRAFT=false",           // no prefix
+		"GORELEASER_PROJECT_NAME",       // no value
+	}))
+
+	require.Equal(t, "foo", project.ProjectName)
+	require.Equal(t, "out=dir", project.Dist)
+	require.True(t, project.Release.Draft)
+	require.Equal(t, "v{{ .Version }}", project.Release.NameTemplate)
+	require.Equal(t, "goreleaser", project.Release.GitHub.Owner)
+	require.Equal(t, -1, project.Changelog.Abbrev)
+	require.True(t, project.Source.Enabled)
+	require.Equal(t, 5*time.Second, project.Announce.Webhook.RetryBackoff)
+	require.Equal(t, "foo", project.Builds[0].ID)
+}
+
+func TestApplyEnvOverridesErrors(t *testing.T) {
+	for name, tt := range map[string]struct {
+		env string
+		err string
+	}{
+		"bool":     {"GORELEASER_RELEASE_DRAFT=nope", `invalid value for GORELEASER_RELEASE_DRAFT: strconv.ParseBool: parsing "nope": invalid syntax`},
+		"int":      {"GORELEASER_CHANGELOG_ABBREV=seven", `invalid value for GORELEASER_CHANGELOG_ABBREV: strconv.ParseInt: parsing "seven": invalid syntax`},
+		"duration": {"GORELEASER_ANNOUNCE_WEBHOOK_RETRY_BACKOFF=5", `invalid value for GORELEASER_ANNOUNCE_WEBHOOK_RETRY_BACKOFF: time: missing unit in duration "5"`},
+	} {
+		t.Run(name, func(t *testing.T) {
+			project := Project{}
+			require.EqualError(t, ApplyEnvOverrides(&project, []string{tt.env}), tt.err)
+		})
+	}
+}
+
+func TestEnvOverrideKeys(t *testing.T) {
+	keys := EnvOverrideKeys()
+	require.Contains(t, keys, "GORELEASER_PROJECT_NAME")
+	require.Contains(t, keys, "GORELEASER_RELEASE_DRAFT")
+	require.Contains(t, keys, "GORELEASER_RELEASE_GITHUB_OWNER")
+	require.Contains(t, keys, "GORELEASER_CHANGELOG_USE")
+	require.Contains(t, keys, "GORELEASER_FORCE_TOKEN")
+	require.NotContains(t, keys, "GORELEASER_BUILDS_ID")
+	require.NotContains(t, keys, "GORELEASER_ENV")
+	require.IsIncreasing(t, keys)
+}
@@ -38,6 +38,8 @@ brews:
   - bar
   - name: foobar
     type: optional
+  - name: openssl
+    version: "3"
 `
 		buf := strings.NewReader(conf)
 		prop, err := LoadReader(buf)
@@ -51,6 +53,9 @@ brews:
 			}, {
 				Name: "foobar",
 				Type: "optional",
+			}, {
+				Name:    "openssl",
+				Version: "3",
 			},
 		}, prop.Brews[0].Dependencies)
 	})
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalHomebrewService(t *testing.T) {
+	t.Run("string", func(t *testing.T) {
+		conf := `
+brews:
+- name: foo
+  service: |
+    run foo/bar
+    keep_alive true
+`
+		buf := strings.NewReader(conf)
+		prop, err := LoadReader(buf)
+
+		require.NoError(t, err)
+		require.Equal(t, HomebrewService{
+			Raw: "run foo/bar\nkeep_alive true\n",
+		}, prop.Brews[0].Service)
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		conf := `
+brews:
+- name: foo
+  service:
+    run: foo serve
+    keep_alive: true
+    log_path: log/foo.log
+    environment:
+      FOO: bar
+`
+		buf := strings.NewReader(conf)
+		prop, err := LoadReader(buf)
+
+		require.NoError(t, err)
+		require.Equal(t, HomebrewService{
+			Run:         "foo serve",
+			KeepAlive:   true,
+			LogPath:     "log/foo.log",
+			Environment: map[string]string{"FOO": "bar"},
+		}, prop.Brews[0].Service)
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		conf := `
+brews:
+- name: foo
+  service:
+    runn: foo
+`
+		buf := strings.NewReader(conf)
+		_, err := LoadReader(buf)
+
+		require.EqualError(t, err, "yaml: unmarshal errors:\n  line 5: field runn not found in type config.homebrewService")
+	})
+}
+
+func TestMarshalHomebrewService(t *testing.T) {
+	for name, svc := range map[string]HomebrewService{
+		"raw": {Raw: "run foo/bar\nkeep_alive true\n"},
+		"struct": {
+			Run:         "foo serve",
+			KeepAlive:   true,
+			Environment: map[string]string{"FOO": "bar"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			bts, err := yaml.Marshal(svc)
+			require.NoError(t, err)
+
+			var got HomebrewService
+			require.NoError(t, yaml.Unmarshal(bts, &got))
+			require.Equal(t, svc, got)
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(tb testing.TB, path, content string) {
+	tb.Helper()
+	require.NoError(tb, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(tb, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestLoadIncludes(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, ".goreleaser.yaml"), `
+includes:
+  - from_file:
+      path: ./config/dockers.yaml
+  - from_file:
+      path: ./config/release.yaml
+project_name: main
+release:
+  draft: true
+dockers:
+  - image_templates: ["main"]
+`)
+	writeFile(t, filepath.Join(dir, "config", "dockers.yaml"), `
+includes:
+  - from_file:
+      path: ./nested/nfpms.yaml
+project_name: dockers
+dockers:
+  - image_templates: ["dockers"]
+`)
+	writeFile(t, filepath.Join(dir, "config", "nested", "nfpms.yaml"), `
+nfpms:
+  - id: nested
+    maintainer: nested
+`)
+	writeFile(t, filepath.Join(dir, "config", "release.yaml"), `
+project_name: release
+release:
+  draft: false
+  prerelease: auto
+  name_template: from release
+`)
+
+	project, err := Load(filepath.Join(dir, ".goreleaser.yaml"))
+	require.NoError(t, err)
+
+	// the including file has precedence over the included ones, and later
+	// includes over previous ones
+	require.Equal(t, "main", project.ProjectName)
+	require.True(t, project.Release.Draft)
+	require.Equal(t, "auto", project.Release.Prerelease)
+	require.Equal(t, "from release", project.Release.NameTemplate)
+
+	// lists are concatenated, in include order
+	require.Len(t, project.Dockers, 2)
+	require.Equal(t, []string{"dockers"}, project.Dockers[0].ImageTemplates)
+	require.Equal(t, []string{"main"}, project.Dockers[1].ImageTemplates)
+
+	// nested includes are resolved relative to the including file
+	require.Len(t, project.NFPMs, 1)
+	require.Equal(t, "nested", project.NFPMs[0].ID)
+
+	require.Empty(t, project.Includes)
+}
+
+func TestLoadReaderIncludes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "brews.yaml")
+	writeFile(t, path, `
+brews:
+  - name: foo
+`)
+	project, err := LoadReader(strings.NewReader(`
+includes:
+  - from_file:
+      path: ` + path + `
+brews:
+  - name: bar
+`))
+	require.NoError(t, err)
+	require.Len(t, project.Brews, 2)
+	require.Equal(t, "foo", project.Brews[0].Name)
+	require.Equal(t, "bar", project.Brews[1].Name)
+}
+
+func TestLoadIncludesErrors(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		dir := t.TempDir()
+		main := filepath.Join(dir, ".goreleaser.yaml")
+		a := filepath.Join(dir, "a.yaml")
+		b := filepath.Join(dir, "b.yaml")
+		writeFile(t, main, "includes:\n  - from_file:\n      path: a.yaml\n")
+		writeFile(t, a, "includes:\n  - from_file:\n      path: b.yaml\n")
+		writeFile(t, b, "includes:\n  - from_file:\n      path: .goreleaser.yaml\n")
+		_, err := Load(main)
+		require.EqualError(t, err, "includes: cycle detected: "+strings.Join([]string{main, a, b, main}, " -> "))
+	})
+
+	t.Run("self", func(t *testing.T) {
+		dir := t.TempDir()
+		main := filepath.Join(dir, ".goreleaser.yaml")
+		writeFile(t, main, "includes:\n  - from_file:\n      path: .goreleaser.yaml\n")
+		_, err := Load(main)
+		require.ErrorContains(t, err, "includes: cycle detected")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		dir := t.TempDir()
+		main := filepath.Join(dir, ".goreleaser.yaml")
+		writeFile(t, main, "includes:\n  - from_file:\n      path: nope.yaml\n")
+		_, err := Load(main)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("missing path", func(t *testing.T) {
+		_, err := LoadReader(strings.NewReader("includes:\n  - from_file: {}\n"))
+		require.EqualError(t, err, "includes: from_file.path is required")
+	})
+
+	t.Run("invalid include", func(t *testing.T) {
+		_, err := LoadReader(strings.NewReader("includes:\n  - from_nope:\n      path: a.yaml\n"))
+		require.ErrorContains(t, err, "field from_nope not found")
+	})
+
+	t.Run("unknown field in included file", func(t *testing.T) {
+		dir := t.TempDir()
+		main := filepath.Join(dir, ".goreleaser.yaml")
+		writeFile(t, main, "includes:\n  - from_file:\n      path: a.yaml\n")
+		writeFile(t, filepath.Join(dir, "a.yaml"), "nope: true\n")
+		_, err := Load(main)
+		require.ErrorContains(t, err, "field nope not found in type config.Project")
+	})
+}
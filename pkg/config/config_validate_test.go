@@ -0,0 +1,82 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		require.NoError(t, Validate(Project{}))
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		project, err := LoadReader(strings.NewReader(`
+project_name: foo
+changelog:
+  use: github-native
+  sort: asc
+blobs:
+  - bucket: foo
+    provider: s3
+    sse: aws:kms
+nix:
+  - name: foo
+    format: flake
+upx:
+  - enabled: true
+    compress: best
+`))
+		require.NoError(t, err)
+		require.NoError(t, Validate(project))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		project, err := LoadReader(strings.NewReader(`
+project_name: foo
+changelog:
+  use: bitbucket
+  sort: random
+blobs:
+  - bucket: foo
+    provider: s3
+  - bucket: bar
+    provider: s3
+    sse: AES128
+upx:
+  - enabled: true
+    compress: "10"
+`))
+		require.NoError(t, err)
+		err = Validate(project)
+		require.Error(t, err)
+
+		var verr ValidationError
+		require.ErrorAs(t, err, &verr)
+		require.Equal(t, []string{
+			`blobs[1].sse: invalid value "AES128", must be one of: AES256, aws:kms`,
+			`changelog.sort: invalid value "random", must be one of: asc, desc`,
+			`changelog.use: invalid value "bitbucket", must be one of: git, github, github-native, gitlab`,
+			`upx[0].compress: invalid value "10", must be one of: 1, 2, 3, 4, 5, 6, 7, 8, 9, best`,
+		}, verr.Errors)
+		require.True(t, strings.HasPrefix(err.Error(), "invalid configuration:\n"))
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		_, err := LoadReader(strings.NewReader(`
+changelog:
+  nope: true
+`))
+		require.ErrorContains(t, err, "field nope not found")
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		_, err := LoadReader(strings.NewReader(`
+changelog:
+  abbrev: foo
+`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvOverridePrefix is the prefix of the environment variables overriding
+// configuration fields.
+const EnvOverridePrefix = "GORELEASER_"
+
+// EnvOverrideKeys returns the names of all the environment variables that can
+// override configuration fields, sorted.
+//
+// Each name is EnvOverridePrefix followed by the upper-cased YAML path of the
+// field, joined with underscores, e.g. GORELEASER_RELEASE_DRAFT for
+// release.draft.
+// Only scalar fields (strings, booleans, numbers and durations) not nested in
+// lists nor maps can be overridden. Should two fields have the same key, the
+// first one in declaration order is used.
+func EnvOverrideKeys() []string {
+	fields := map[string][]int{}
+	envOverrideFields(reflect.TypeOf(Project{}), EnvOverridePrefix, nil, fields)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplyEnvOverrides overrides the fields of the given project with the values
+// of the matching environment variables, given in the "key=value" form, as
+// returned by os.Environ.
+// Environment variables have precedence over the values set in the
+// configuration file.
+// Variables with the EnvOverridePrefix that do not match any field are
+// ignored.
+func ApplyEnvOverrides(project *Project, env []string) error {
+	fields := map[string][]int{}
+	envOverrideFields(reflect.TypeOf(Project{}), EnvOverridePrefix, nil, fields)
+
+	v := reflect.ValueOf(project).Elem()
+	for _, e := range env {
+		key, value, ok := strings.Cut(e, "=")
+		if !ok || !strings.HasPrefix(key, EnvOverridePrefix) {
+			continue
+		}
+		index, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if err := setField(fieldByIndex(v, index), value); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// envOverrideFields maps the keys of the overridable fields of the given
+// struct type to their indexes.
+func envOverrideFields(t reflect.Type, prefix string, index []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+		name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := prefix + strings.ToUpper(name)
+		if strings.Contains(opts, "inline") {
+			key = strings.TrimSuffix(prefix, "_")
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+
+		ft := field.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}):
+			envOverrideFields(ft, key+"_", fieldIndex, fields)
+		case isScalar(ft):
+			if _, ok := fields[key]; !ok {
+				fields[key] = fieldIndex
+			}
+		}
+	}
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	default:
+		return false
+	}
+}
+
+// fieldByIndex returns the field with the given index, allocating nil
+// pointers along the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func setField(v reflect.Value, value string) error {
+	if v.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(i)
+	}
+	return nil
+}
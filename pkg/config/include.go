@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/internal/yaml"
+)
+
+// Include is a configuration file to be merged into the current one.
+type Include struct {
+	FromFile IncludeFromFile `yaml:"from_file,omitempty" json:"from_file,omitempty"`
+}
+
+// IncludeFromFile is a configuration file in the local file system.
+type IncludeFromFile struct {
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+}
+
+// resolveIncludes merges the files listed in the `includes` section of the
+// given configuration into it, recursively.
+//
+// Files are merged in the order they are declared, and then the including
+// file is merged on top of them: mappings are merged key by key, lists are
+// concatenated, and any other value overrides the previous one.
+// Relative paths are resolved from the directory of the including file.
+func resolveIncludes(data []byte, dir string, stack []string) ([]byte, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		// let the strict unmarshalling of the project report it
+		return data, nil //nolint:nilerr
+	}
+	if _, ok := raw["includes"]; !ok {
+		return data, nil
+	}
+	merged, err := mergeIncludes(raw, dir, stack)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(merged)
+}
+
+func mergeIncludes(raw map[string]interface{}, dir string, stack []string) (map[string]interface{}, error) {
+	if raw == nil {
+		return map[string]interface{}{}, nil
+	}
+	includes, err := decodeIncludes(raw["includes"])
+	if err != nil {
+		return nil, err
+	}
+	delete(raw, "includes")
+
+	result := map[string]interface{}{}
+	for _, include := range includes {
+		path := include.FromFile.Path
+		if path == "" {
+			return nil, fmt.Errorf("includes: from_file.path is required")
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("includes: %w", err)
+		}
+		for _, s := range stack {
+			if s == abs {
+				return nil, fmt.Errorf("includes: cycle detected: %s", strings.Join(append(stack, abs), " -> "))
+			}
+		}
+
+		bts, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("includes: %w", err)
+		}
+		var included map[string]interface{}
+		if err := yaml.Unmarshal(bts, &included); err != nil {
+			return nil, fmt.Errorf("includes: %s: %w", include.FromFile.Path, err)
+		}
+		included, err = mergeIncludes(included, filepath.Dir(path), append(stack, abs))
+		if err != nil {
+			return nil, err
+		}
+		result = merge(result, included)
+	}
+	return merge(result, raw), nil
+}
+
+func decodeIncludes(raw interface{}) ([]Include, error) {
+	bts, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("includes: %w", err)
+	}
+	var includes []Include
+	if err := yaml.UnmarshalStrict(bts, &includes); err != nil {
+		return nil, fmt.Errorf("includes: %w", err)
+	}
+	return includes, nil
+}
+
+// merge merges src into dst, src taking precedence.
+func merge(dst, src map[string]interface{}) map[string]interface{} {
+	for k, sv := range src {
+		switch sv := sv.(type) {
+		case map[string]interface{}:
+			if dv, ok := dst[k].(map[string]interface{}); ok {
+				dst[k] = merge(dv, sv)
+				continue
+			}
+		case []interface{}:
+			if dv, ok := dst[k].([]interface{}); ok {
+				dst[k] = append(dv, sv...)
+				continue
+			}
+		}
+		dst[k] = src[k]
+	}
+	return dst
+}
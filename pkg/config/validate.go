@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ValidationError is returned by Validate, listing all the invalid fields
+// found in a project.
+type ValidationError struct {
+	Errors []string
+}
+
+func (e ValidationError) Error() string {
+	return "invalid configuration:\n" + strings.Join(e.Errors, "\n")
+}
+
+// Validate checks the given project against the constraints of GoReleaser's
+// JSON schema that YAML unmarshalling does not enforce, e.g. fields that only
+// accept a limited set of values.
+//
+// Unknown fields are already reported by Load and LoadReader, so a project
+// loaded with them and validated with this has no unknown nor invalid fields.
+func Validate(project Project) error {
+	var errs []string
+	validate(reflect.ValueOf(project), "", &errs)
+	if len(errs) > 0 {
+		return ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func validate(v reflect.Value, path string, errs *[]string) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			validate(v.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue // unexported
+			}
+			name, opts, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			if strings.Contains(opts, "inline") {
+				fieldPath = path
+			}
+			fv := v.Field(i)
+			if fv.Kind() == reflect.String {
+				validateEnum(fv.String(), field.Tag.Get("jsonschema"), fieldPath, errs)
+				continue
+			}
+			validate(fv, fieldPath, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validate(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			validate(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), errs)
+		}
+	}
+}
+
+// validateEnum checks that the value is one of the enum values of the given
+// jsonschema tag, if any. Empty values are always valid, as they are set to
+// their defaults later on.
+func validateEnum(value, tag, path string, errs *[]string) {
+	if value == "" || tag == "" {
+		return
+	}
+	var enum []string
+	for _, opt := range strings.Split(tag, ",") {
+		if strings.HasPrefix(opt, "enum=") {
+			enum = append(enum, strings.TrimPrefix(opt, "enum="))
+		}
+	}
+	if len(enum) == 0 {
+		return
+	}
+	for _, e := range enum {
+		if value == e {
+			return
+		}
+	}
+	var valid []string
+	for _, e := range enum {
+		if e != "" {
+			valid = append(valid, e)
+		}
+	}
+	*errs = append(*errs, fmt.Sprintf("%s: invalid value %q, must be one of: %s", path, value, strings.Join(valid, ", ")))
+}
@@ -80,6 +80,7 @@ type Context struct {
 	Date               time.Time
 	Artifacts          artifact.Artifacts
 	ReleaseURL         string
+	ReleaseID          string
 	ReleaseNotes       string
 	ReleaseNotesFile   string
 	ReleaseNotesTmpl   string
@@ -93,12 +94,15 @@ type Context struct {
 	SkipPostBuildHooks bool
 	SkipPublish        bool
 	SkipAnnounce       bool
+	AnnounceDryRun     bool
 	SkipSign           bool
 	SkipValidate       bool
 	SkipSBOMCataloging bool
 	SkipKo             bool
 	SkipDocker         bool
 	SkipBefore         bool
+	Only               []string
+	PartialBuild       bool
 	Clean              bool
 	PreRelease         bool
 	Deprecated         bool